@@ -9,6 +9,7 @@ require (
 	github.com/rs/zerolog v1.34.0
 	golang.org/x/crypto v0.47.0
 	google.golang.org/api v0.265.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
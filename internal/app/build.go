@@ -0,0 +1,31 @@
+package app
+
+import (
+	"runtime/debug"
+	"sync"
+)
+
+// BuildIdentifier returns an identifier for the running binary, e.g.
+// "torn_rw_stats@1a2b3c4d5e6f", from the module path and the VCS revision or module
+// version embedded at build time. Returns an empty string if no build info is available.
+var BuildIdentifier = sync.OnceValue(func() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok || info.Main.Path == "" {
+		return ""
+	}
+
+	for _, setting := range info.Settings {
+		if setting.Key == "vcs.revision" {
+			revision := setting.Value
+			if len(revision) > 12 {
+				revision = revision[:12]
+			}
+			return info.Main.Path + "@" + revision
+		}
+	}
+
+	if info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Path + "@" + info.Main.Version
+	}
+	return info.Main.Path
+})
@@ -2,7 +2,11 @@ package app
 
 import (
 	"fmt"
+	"math"
 	"os"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -23,47 +27,358 @@ type Config struct {
 	BigQueryProjectID string
 	BigQueryDatasetID string
 	BigQueryTableID   string
+
+	// StatusDeltaExport additionally deploys a JSON containing only members whose
+	// status changed since the previous export
+	StatusDeltaExport bool
+
+	// WaitOnOverlap makes a processing cycle wait for an in-flight cycle to finish
+	// instead of skipping when the two overlap
+	WaitOnOverlap bool
+
+	// SplitRecordsByDirection writes outgoing and incoming attacks to separate
+	// "Outgoing - {id}" and "Incoming - {id}" sheets instead of the combined records sheet
+	SplitRecordsByDirection bool
+
+	// War state polling overrides; zero keeps the built-in defaults
+	ActiveWarInterval time.Duration // How often to poll during an active war
+	PreWarInterval    time.Duration // How often to poll while a war is scheduled
+	PostWarWindow     time.Duration // How long after a war ends it still counts as recent
+
+	// HalfCreditWinRate counts stalemates and escapes as half a win in the headline win rate
+	HalfCreditWinRate bool
+
+	// DetectRevives marks hospital exits well before the expected release as "Revived"
+	// in the Changed States sheet
+	DetectRevives bool
+
+	// CSVExportDir enables writing each war's attack records to records_{warID}.csv
+	// in this directory when set
+	CSVExportDir string
+
+	// MaxStateChangesPerCycle caps the Changed States rows written per cycle;
+	// zero uses the built-in default and a negative value disables the cap
+	MaxStateChangesPerCycle int
+
+	// RespectTrendWindow is the bucket size for the per-war respect trend sheet;
+	// zero uses the built-in default of one hour
+	RespectTrendWindow time.Duration
+
+	// APIRequestsPerMinute throttles Torn API requests; zero uses the built-in
+	// default of 90, safely under Torn's per-key limit
+	APIRequestsPerMinute int
+
+	// WatchFactionIDs lists rival factions whose wars against anyone are reported;
+	// WatchInterval is how often each is polled (zero uses the built-in default)
+	WatchFactionIDs []int
+	WatchInterval   time.Duration
+
+	// Weekly matchmaking schedule in UTC, used to time checks while there are no wars.
+	// MatchmakingScheduleSet marks the fields as filled in, so Sunday 00:00 is a valid
+	// schedule rather than the zero value; when false the Tuesday 12:05 default is used.
+	MatchmakingWeekday     time.Weekday
+	MatchmakingHour        int
+	MatchmakingMinute      int
+	MatchmakingScheduleSet bool
+
+	// MaxStateChangeRows caps the data rows kept in the Changed States sheet, pruning
+	// the oldest after each append; zero uses the built-in default of 40000
+	MaxStateChangeRows int
+
+	// ExcludedMemberIDs lists members (e.g. our spies in enemy factions) left out of
+	// the Status v2 sheets and JSON exports for every faction
+	ExcludedMemberIDs []int
+
+	// MemberTravelReductions maps member IDs to a travel time reduction in percent,
+	// for members known to travel faster than the defaults (e.g. private islands)
+	MemberTravelReductions map[int]float64
+
+	// HealthPort enables the /healthz HTTP endpoint on this port; zero disables it
+	HealthPort int
+
+	// MaxConcurrentFactions bounds how many factions are processed in parallel
+	// for state tracking and Status v2 sheets
+	MaxConcurrentFactions int
+
+	// IncrementalFetchBuffer is how far before the latest stored attack incremental
+	// fetches start; zero uses the built-in default of one hour
+	IncrementalFetchBuffer time.Duration
+
+	// MinEnemyLevel leaves enemy members below this level out of the Status v2
+	// sheets and JSON exports; zero tracks every member
+	MinEnemyLevel int
+
+	// SuspiciousGapThreshold is the gap between consecutive attacks during an active
+	// war that is logged as a likely missed page; zero uses the built-in default of 2h
+	SuspiciousGapThreshold time.Duration
+
+	// RespectDecimalPlaces is how many decimal places respect is written with in
+	// the attack records sheets (0-4, default 2)
+	RespectDecimalPlaces int
+
+	// OurFactionID skips the own-faction API lookup when set; zero detects it from
+	// the API key's faction
+	OurFactionID int
+
+	// MaintenanceWindows are daily UTC windows during which war processing is skipped
+	MaintenanceWindows []MaintenanceWindow
+
+	// FullRescanInterval periodically re-fetches a war's attacks in full, even when
+	// records exist, to heal gaps left by incremental updates; zero disables it
+	FullRescanInterval time.Duration
+
+	// LocationCacheTTL is how long a member's last resolved location is reused when
+	// their status is ambiguous; zero uses the built-in default of 15 minutes
+	LocationCacheTTL time.Duration
+
+	// StatusV2History also appends every Status v2 update as a timestamped snapshot
+	// to a "Status History - {factionID}" sheet
+	StatusV2History bool
+
+	// StatusHistoryMaxRows caps the data rows kept in a status history sheet;
+	// zero uses the built-in default of 20000
+	StatusHistoryMaxRows int
+
+	// SummaryJSONDir enables writing each war's summary to summary_{warID}.json
+	// in this directory
+	SummaryJSONDir string
+
+	// DeployRetries is how many times a failed or truncated JSON deployment is
+	// retried (0-10, default 2)
+	DeployRetries int
+
+	// WinResults and LossResults override which attack results count as wins and
+	// losses in war summaries; unset keeps the built-in classification
+	WinResults  []string
+	LossResults []string
+
+	// TrackCountdownDrift appends the drift between the API-derived and calculated
+	// travel countdowns of every traveling member to a "Countdown Drift" sheet
+	TrackCountdownDrift bool
+
+	// IncludeInternalAttacks keeps attacks between two of our own members, e.g.
+	// training spars, in the records and summaries; they are dropped by default
+	IncludeInternalAttacks bool
+
+	// ChainBreakThreshold is the enemy chain length above which a drop to near zero
+	// is reported as a broken chain; zero uses the built-in default of 100
+	ChainBreakThreshold int
+
+	// AlertsSheet also appends alerts such as broken enemy chains or watched factions
+	// entering a war to an "Alerts" sheet
+	AlertsSheet bool
+	// RecordsColumnOrder reorders or subsets the attack records sheet columns by header
+	// name; empty keeps the full default layout
+	RecordsColumnOrder []string
+	// DisplayTimezone is the IANA timezone sheet dates and times are written in;
+	// Unix timestamp columns stay UTC
+	DisplayTimezone string
+	// ParticipationDeviation is how far in percent a member's outgoing attack count may
+	// stray from the faction mean before the Participation Flags sheet lists them
+	ParticipationDeviation int
+	// SheetWriteBatchSize is how many attack record rows are written per Sheets request
+	SheetWriteBatchSize int
+	// WarEndGracePeriod keeps a war that reported an end this recently in ActiveWar,
+	// since the API occasionally reverses a reported end; zero flips to PostWar at once
+	WarEndGracePeriod time.Duration
+	// TrackOwnFactionStatus includes our own faction in state change tracking and
+	// Status v2; false tracks enemy factions only
+	TrackOwnFactionStatus bool
+	// AttackPaceFloor is the outgoing attacks per hour below which an active war logs a
+	// low attack pace warning; zero disables the check
+	AttackPaceFloor int
+	// AttackPaceWindow is the rolling window the attack pace is measured over; zero uses
+	// the built-in default of one hour
+	AttackPaceWindow time.Duration
+	// FetchMaxPages caps how many pages a paginated attack fetch requests; hitting the
+	// cap leaves the older attacks to be fetched in later cycles
+	FetchMaxPages int
+	// MemberNotesSheet is the sheet of member ID and note rows whose notes are added to
+	// matching members in the Status v2 JSON export; empty disables notes
+	MemberNotesSheet string
+	// MinReprocessInterval skips processing a war again until this long after it was
+	// last processed; zero processes every war each cycle
+	MinReprocessInterval time.Duration
+	// ReprocessStateFile is the local file recording when each war was last processed,
+	// so the cooldown survives restarts; empty keeps the record in memory only
+	ReprocessStateFile string
+	// MetricsPort enables the Prometheus /metrics HTTP endpoint on this port; zero disables it
+	MetricsPort int
+	// PinnedWarID forces this war to be the selected war whenever it appears in the
+	// wars response, overriding the active > pre > post priority; zero disables pinning
+	PinnedWarID int
+	// OurFactionName identifies our side of a war by faction name when our faction ID
+	// cannot be detected from the API, e.g. on shared keys; empty disables the fallback
+	OurFactionName string
+	// RawDumpDir enables writing raw Torn API responses, with the API key redacted, to
+	// timestamped files in this directory, at most once a minute per endpoint; empty disables dumps
+	RawDumpDir string
+	// InterFactionDelay is waited between starting each faction in state tracking and
+	// Status v2 processing to smooth API load; zero starts factions back-to-back
+	InterFactionDelay time.Duration
+	// JSONArchiveDir enables keeping a timestamped copy of each Status v2 JSON export
+	// in this directory; empty disables archiving
+	JSONArchiveDir string
+	// JSONArchiveRetention is how many archived exports are kept per faction; older
+	// ones are pruned
+	JSONArchiveRetention int
+	// TargetLevelWeight, TargetOnlineWeight and TargetIdleWeight score enemy members in
+	// the target priority sheet: points per level, plus points for being online or idle
+	TargetLevelWeight  int
+	TargetOnlineWeight int
+	TargetIdleWeight   int
+	// PopulationProgressFile keeps how far each war's interrupted full population got,
+	// so a restart fetches only the missing older attacks; empty keeps it in memory
+	PopulationProgressFile string
 }
 
-// SetupEnvironment loads .env file and configures zerolog output and log level.
-func SetupEnvironment() {
-	// Load .env file if it exists
-	err := godotenv.Load()
+// DefaultMaxConcurrentFactions is the number of factions processed in parallel
+// when MAX_CONCURRENT_FACTIONS is unset
+const DefaultMaxConcurrentFactions = 3
 
-	// Configure logging
-	if os.Getenv("ENV") == "production" {
-		zerolog.TimeFieldFormat = zerolog.TimeFormatUnix
-		log.Logger = log.Output(os.Stderr)
-	} else {
-		log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr, TimeFormat: time.RFC3339})
+// DefaultJSONArchiveRetention is how many archived Status v2 exports are kept per
+// faction when JSON_ARCHIVE_RETENTION is unset
+const DefaultJSONArchiveRetention = 24
+
+// Default target priority weights: points per enemy level and for being online or idle
+const (
+	DefaultTargetLevelWeight  = 1
+	DefaultTargetOnlineWeight = 50
+	DefaultTargetIdleWeight   = 20
+)
+
+// DefaultFetchMaxPages is how many pages a paginated attack fetch may request when
+// FETCH_MAX_PAGES is unset
+const DefaultFetchMaxPages = 100
+
+// DefaultRespectDecimalPlaces is how many decimal places respect is written with in
+// attack records when RESPECT_DECIMAL_PLACES is unset
+const DefaultRespectDecimalPlaces = 2
+
+// DefaultSheetWriteBatchSize is how many attack record rows are written per request
+// when SHEET_WRITE_BATCH_SIZE is unset
+const DefaultSheetWriteBatchSize = 1000
+
+// DefaultDeployRetries is how many times a failed or truncated deployment is retried
+// when DEPLOY_RETRIES is unset
+const DefaultDeployRetries = 2
+
+// DefaultParticipationDeviation is the percentage deviation from the faction mean
+// attack count that gets a member flagged when PARTICIPATION_DEVIATION is unset
+const DefaultParticipationDeviation = 50
+
+// DefaultDisplayTimezone is the timezone sheet dates and times are written in when
+// DISPLAY_TIMEZONE is unset
+const DefaultDisplayTimezone = "UTC"
+
+// DisplayLocation resolves a display timezone name, falling back to UTC when it is
+// empty or unknown. LoadConfig rejects unknown names, so the fallback only applies
+// to hand-built configs.
+func DisplayLocation(timezone string) *time.Location {
+	if timezone == "" {
+		return time.UTC
 	}
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
 
-	levelStr := strings.ToLower(os.Getenv("LOGLEVEL"))
-	switch levelStr {
+// Log output formats for LOG_FORMAT
+const (
+	LogFormatJSON    = "json"
+	LogFormatConsole = "console"
+)
+
+// ParseLogLevel maps a LOGLEVEL value to a zerolog level. An empty value defaults to
+// warn in production and info otherwise; an unknown value defaults to info and
+// reports false so the caller can warn about it.
+func ParseLogLevel(value string, production bool) (zerolog.Level, bool) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
 	case "debug":
-		zerolog.SetGlobalLevel(zerolog.DebugLevel)
+		return zerolog.DebugLevel, true
 	case "info":
-		zerolog.SetGlobalLevel(zerolog.InfoLevel)
+		return zerolog.InfoLevel, true
 	case "warn", "warning":
-		zerolog.SetGlobalLevel(zerolog.WarnLevel)
+		return zerolog.WarnLevel, true
 	case "error":
-		zerolog.SetGlobalLevel(zerolog.ErrorLevel)
+		return zerolog.ErrorLevel, true
 	case "fatal":
-		zerolog.SetGlobalLevel(zerolog.FatalLevel)
+		return zerolog.FatalLevel, true
 	case "panic":
-		zerolog.SetGlobalLevel(zerolog.PanicLevel)
+		return zerolog.PanicLevel, true
 	case "disabled":
-		zerolog.SetGlobalLevel(zerolog.Disabled)
+		return zerolog.Disabled, true
 	case "":
 		// Default based on environment
-		if os.Getenv("ENV") == "production" {
-			zerolog.SetGlobalLevel(zerolog.WarnLevel)
-		} else {
-			zerolog.SetGlobalLevel(zerolog.InfoLevel)
+		if production {
+			return zerolog.WarnLevel, true
 		}
+		return zerolog.InfoLevel, true
 	default:
-		zerolog.SetGlobalLevel(zerolog.InfoLevel)
-		log.Warn().Msgf("Unknown LOGLEVEL '%s', defaulting to info.", levelStr)
+		return zerolog.InfoLevel, false
+	}
+}
+
+// ParseLogFormat maps a LOG_FORMAT value to LogFormatJSON or LogFormatConsole. An empty
+// or unknown value defaults to JSON in production and console otherwise; an unknown
+// value reports false so the caller can warn about it.
+func ParseLogFormat(value string, production bool) (string, bool) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case LogFormatJSON:
+		return LogFormatJSON, true
+	case LogFormatConsole:
+		return LogFormatConsole, true
+	}
+
+	format := LogFormatConsole
+	if production {
+		format = LogFormatJSON
+	}
+	return format, value == ""
+}
+
+// Default weekly matchmaking schedule: Tuesday 12:05 UTC
+const (
+	DefaultMatchmakingWeekday = time.Tuesday
+	DefaultMatchmakingHour    = 12
+	DefaultMatchmakingMinute  = 5
+)
+
+// SetupEnvironment loads the .env file, then the config file at configFile when one is
+// given, and configures zerolog output and log level. Neither file overrides variables
+// already set, so the environment wins over .env, which wins over the config file.
+// Config file keys that match no setting are returned for the caller to warn about.
+func SetupEnvironment(configFile string) ([]string, error) {
+	// Load .env file if it exists
+	err := godotenv.Load()
+
+	// Apply the config file before configuring logging so its log settings take effect
+	var unknownConfigKeys []string
+	var configFileErr error
+	if configFile != "" {
+		unknownConfigKeys, configFileErr = ApplyConfigFile(configFile)
+	}
+
+	// Configure logging
+	production := os.Getenv("ENV") == "production"
+	format, formatOK := ParseLogFormat(os.Getenv("LOG_FORMAT"), production)
+	if format == LogFormatJSON {
+		zerolog.TimeFieldFormat = zerolog.TimeFormatUnix
+		log.Logger = log.Output(os.Stderr)
+	} else {
+		log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr, TimeFormat: time.RFC3339})
+	}
+
+	levelStr := os.Getenv("LOGLEVEL")
+	level, levelOK := ParseLogLevel(levelStr, production)
+	zerolog.SetGlobalLevel(level)
+	if !levelOK {
+		log.Warn().Msgf("Unknown LOGLEVEL '%s', defaulting to info.", strings.ToLower(levelStr))
+	}
+	if !formatOK {
+		log.Warn().Msgf("Unknown LOG_FORMAT '%s', defaulting to %s.", os.Getenv("LOG_FORMAT"), format)
 	}
 
 	// wait until now to report on the .env file so we have the chance to set up logging first
@@ -72,6 +387,8 @@ func SetupEnvironment() {
 	} else {
 		log.Debug().Msg("No .env file found or error loading .env file; proceeding with existing environment variables.")
 	}
+
+	return unknownConfigKeys, configFileErr
 }
 
 // LoadConfig loads configuration from environment variables
@@ -81,9 +398,9 @@ func LoadConfig() (*Config, error) {
 		return nil, fmt.Errorf("TORN_API_KEY environment variable is required")
 	}
 
-	spreadsheetID := os.Getenv("SPREADSHEET_ID")
-	if spreadsheetID == "" {
-		return nil, fmt.Errorf("SPREADSHEET_ID environment variable is required")
+	spreadsheetID, err := NormalizeSpreadsheetID(os.Getenv("SPREADSHEET_ID"))
+	if err != nil {
+		return nil, err
 	}
 
 	credentialsFile := os.Getenv("GOOGLE_CREDENTIALS_FILE")
@@ -92,6 +409,7 @@ func LoadConfig() (*Config, error) {
 	}
 
 	deployURL := os.Getenv("DEPLOY_URL")
+	csvExportDir := os.Getenv("CSV_EXPORT_DIR")
 
 	bigQueryProjectID := os.Getenv("BIGQUERY_PROJECT_ID")
 	bigQueryDatasetID := os.Getenv("BIGQUERY_DATASET_ID")
@@ -100,17 +418,580 @@ func LoadConfig() (*Config, error) {
 		bigQueryTableID = "state_changes"
 	}
 
+	statusDeltaExport, err := getEnvBool("STATUS_DELTA_EXPORT")
+	if err != nil {
+		return nil, err
+	}
+
+	waitOnOverlap, err := getEnvBool("WAIT_ON_OVERLAP")
+	if err != nil {
+		return nil, err
+	}
+
+	splitRecordsByDirection, err := getEnvBool("SPLIT_RECORDS_BY_DIRECTION")
+	if err != nil {
+		return nil, err
+	}
+
+	activeWarInterval, err := getEnvDuration("ACTIVE_WAR_INTERVAL")
+	if err != nil {
+		return nil, err
+	}
+
+	preWarInterval, err := getEnvDuration("PRE_WAR_INTERVAL")
+	if err != nil {
+		return nil, err
+	}
+
+	postWarWindow, err := getEnvDuration("POST_WAR_WINDOW")
+	if err != nil {
+		return nil, err
+	}
+
+	halfCreditWinRate, err := getEnvBool("HALF_CREDIT_WIN_RATE")
+	if err != nil {
+		return nil, err
+	}
+
+	detectRevives, err := getEnvBool("DETECT_REVIVES")
+	if err != nil {
+		return nil, err
+	}
+
+	maxStateChangesPerCycle, err := getEnvInt("MAX_STATE_CHANGES_PER_CYCLE")
+	if err != nil {
+		return nil, err
+	}
+
+	respectTrendWindow, err := getEnvDuration("RESPECT_TREND_WINDOW")
+	if err != nil {
+		return nil, err
+	}
+
+	apiRequestsPerMinute, err := getEnvInt("API_REQUESTS_PER_MINUTE")
+	if err != nil {
+		return nil, err
+	}
+
+	watchFactionIDs, err := getEnvIntList("WATCH_FACTION_IDS")
+	if err != nil {
+		return nil, err
+	}
+
+	watchInterval, err := getEnvDuration("WATCH_INTERVAL")
+	if err != nil {
+		return nil, err
+	}
+
+	matchmakingWeekday, err := getEnvWeekday("MATCHMAKING_WEEKDAY", DefaultMatchmakingWeekday)
+	if err != nil {
+		return nil, err
+	}
+
+	matchmakingHour, err := getEnvIntInRange("MATCHMAKING_HOUR", DefaultMatchmakingHour, 0, 23)
+	if err != nil {
+		return nil, err
+	}
+
+	matchmakingMinute, err := getEnvIntInRange("MATCHMAKING_MINUTE", DefaultMatchmakingMinute, 0, 59)
+	if err != nil {
+		return nil, err
+	}
+
+	maxStateChangeRows, err := getEnvInt("MAX_STATE_CHANGE_ROWS")
+	if err != nil {
+		return nil, err
+	}
+
+	excludedMemberIDs, err := getEnvIntList("EXCLUDED_MEMBER_IDS")
+	if err != nil {
+		return nil, err
+	}
+
+	memberTravelReductions, err := getEnvPercentMap("MEMBER_TRAVEL_REDUCTIONS")
+	if err != nil {
+		return nil, err
+	}
+
+	healthPort, err := getEnvIntInRange("HEALTH_PORT", 0, 0, 65535)
+	if err != nil {
+		return nil, err
+	}
+
+	maxConcurrentFactions, err := getEnvIntInRange("MAX_CONCURRENT_FACTIONS", DefaultMaxConcurrentFactions, 1, 20)
+	if err != nil {
+		return nil, err
+	}
+
+	incrementalFetchBuffer, err := getEnvDuration("INCREMENTAL_FETCH_BUFFER")
+	if err != nil {
+		return nil, err
+	}
+
+	minEnemyLevel, err := getEnvIntInRange("MIN_ENEMY_LEVEL", 0, 0, 100)
+	if err != nil {
+		return nil, err
+	}
+
+	suspiciousGapThreshold, err := getEnvDuration("SUSPICIOUS_GAP_THRESHOLD")
+	if err != nil {
+		return nil, err
+	}
+
+	respectDecimalPlaces, err := getEnvIntInRange("RESPECT_DECIMAL_PLACES", DefaultRespectDecimalPlaces, 0, 4)
+	if err != nil {
+		return nil, err
+	}
+
+	ourFactionID, err := getEnvIntInRange("OUR_FACTION_ID", 0, 0, math.MaxInt32)
+	if err != nil {
+		return nil, err
+	}
+
+	maintenanceWindows, err := ParseMaintenanceWindows(os.Getenv("MAINTENANCE_WINDOWS"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid MAINTENANCE_WINDOWS value: %w", err)
+	}
+
+	fullRescanInterval, err := getEnvDuration("FULL_RESCAN_INTERVAL")
+	if err != nil {
+		return nil, err
+	}
+
+	locationCacheTTL, err := getEnvDuration("LOCATION_CACHE_TTL")
+	if err != nil {
+		return nil, err
+	}
+
+	statusV2History, err := getEnvBool("STATUS_V2_HISTORY")
+	if err != nil {
+		return nil, err
+	}
+
+	statusHistoryMaxRows, err := getEnvInt("STATUS_HISTORY_MAX_ROWS")
+	if err != nil {
+		return nil, err
+	}
+
+	summaryJSONDir := os.Getenv("SUMMARY_JSON_DIR")
+
+	deployRetries, err := getEnvIntInRange("DEPLOY_RETRIES", DefaultDeployRetries, 0, 10)
+	if err != nil {
+		return nil, err
+	}
+
+	winResults := getEnvStringList("WIN_RESULTS")
+	lossResults := getEnvStringList("LOSS_RESULTS")
+	for _, result := range winResults {
+		if slices.Contains(lossResults, result) {
+			return nil, fmt.Errorf("attack result %q is listed in both WIN_RESULTS and LOSS_RESULTS", result)
+		}
+	}
+
+	trackCountdownDrift, err := getEnvBool("TRACK_COUNTDOWN_DRIFT")
+	if err != nil {
+		return nil, err
+	}
+
+	includeInternalAttacks, err := getEnvBool("INCLUDE_INTERNAL_ATTACKS")
+	if err != nil {
+		return nil, err
+	}
+
+	chainBreakThreshold, err := getEnvInt("CHAIN_BREAK_THRESHOLD")
+	if err != nil {
+		return nil, err
+	}
+
+	alertsSheet, err := getEnvBool("ALERTS_SHEET")
+	if err != nil {
+		return nil, err
+	}
+
+	recordsColumnOrder := getEnvStringList("RECORDS_COLUMN_ORDER")
+
+	displayTimezone := os.Getenv("DISPLAY_TIMEZONE")
+	if displayTimezone == "" {
+		displayTimezone = DefaultDisplayTimezone
+	}
+	if _, err := time.LoadLocation(displayTimezone); err != nil {
+		return nil, fmt.Errorf("invalid DISPLAY_TIMEZONE %q: %w", displayTimezone, err)
+	}
+
+	participationDeviation, err := getEnvIntInRange("PARTICIPATION_DEVIATION", DefaultParticipationDeviation, 1, 1000)
+	if err != nil {
+		return nil, err
+	}
+
+	sheetWriteBatchSize, err := getEnvIntInRange("SHEET_WRITE_BATCH_SIZE", DefaultSheetWriteBatchSize, 1, 10000)
+	if err != nil {
+		return nil, err
+	}
+
+	warEndGracePeriod, err := getEnvDuration("WAR_END_GRACE_PERIOD")
+	if err != nil {
+		return nil, err
+	}
+
+	trackOwnFactionStatus := true
+	if os.Getenv("TRACK_OWN_FACTION_STATUS") != "" {
+		trackOwnFactionStatus, err = getEnvBool("TRACK_OWN_FACTION_STATUS")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	attackPaceFloor, err := getEnvIntInRange("ATTACK_PACE_FLOOR", 0, 0, 10000)
+	if err != nil {
+		return nil, err
+	}
+
+	attackPaceWindow, err := getEnvDuration("ATTACK_PACE_WINDOW")
+	if err != nil {
+		return nil, err
+	}
+
+	fetchMaxPages, err := getEnvIntInRange("FETCH_MAX_PAGES", DefaultFetchMaxPages, 1, 10000)
+	if err != nil {
+		return nil, err
+	}
+
+	minReprocessInterval, err := getEnvDuration("MIN_REPROCESS_INTERVAL")
+	if err != nil {
+		return nil, err
+	}
+
+	metricsPort, err := getEnvIntInRange("METRICS_PORT", 0, 0, 65535)
+	if err != nil {
+		return nil, err
+	}
+	if metricsPort != 0 && metricsPort == healthPort {
+		return nil, fmt.Errorf("METRICS_PORT and HEALTH_PORT must differ, both are %d", metricsPort)
+	}
+
+	pinnedWarID, err := getEnvIntInRange("PINNED_WAR_ID", 0, 0, math.MaxInt32)
+	if err != nil {
+		return nil, err
+	}
+
+	interFactionDelay, err := getEnvDuration("INTER_FACTION_DELAY")
+	if err != nil {
+		return nil, err
+	}
+
+	jsonArchiveRetention, err := getEnvIntInRange("JSON_ARCHIVE_RETENTION", DefaultJSONArchiveRetention, 1, 10000)
+	if err != nil {
+		return nil, err
+	}
+
+	targetLevelWeight, err := getEnvIntInRange("TARGET_LEVEL_WEIGHT", DefaultTargetLevelWeight, 0, 1000)
+	if err != nil {
+		return nil, err
+	}
+	targetOnlineWeight, err := getEnvIntInRange("TARGET_ONLINE_WEIGHT", DefaultTargetOnlineWeight, 0, 1000)
+	if err != nil {
+		return nil, err
+	}
+	targetIdleWeight, err := getEnvIntInRange("TARGET_IDLE_WEIGHT", DefaultTargetIdleWeight, 0, 1000)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Config{
-		TornAPIKey:        apiKey,
-		SpreadsheetID:     spreadsheetID,
-		CredentialsFile:   credentialsFile,
-		DeployURL:         deployURL,
-		BigQueryProjectID: bigQueryProjectID,
-		BigQueryDatasetID: bigQueryDatasetID,
-		BigQueryTableID:   bigQueryTableID,
+		TornAPIKey:              apiKey,
+		SpreadsheetID:           spreadsheetID,
+		CredentialsFile:         credentialsFile,
+		DeployURL:               deployURL,
+		BigQueryProjectID:       bigQueryProjectID,
+		BigQueryDatasetID:       bigQueryDatasetID,
+		BigQueryTableID:         bigQueryTableID,
+		StatusDeltaExport:       statusDeltaExport,
+		WaitOnOverlap:           waitOnOverlap,
+		SplitRecordsByDirection: splitRecordsByDirection,
+		ActiveWarInterval:       activeWarInterval,
+		PreWarInterval:          preWarInterval,
+		PostWarWindow:           postWarWindow,
+		HalfCreditWinRate:       halfCreditWinRate,
+		DetectRevives:           detectRevives,
+		CSVExportDir:            csvExportDir,
+		MaxStateChangesPerCycle: maxStateChangesPerCycle,
+		RespectTrendWindow:      respectTrendWindow,
+		APIRequestsPerMinute:    apiRequestsPerMinute,
+		WatchFactionIDs:         watchFactionIDs,
+		WatchInterval:           watchInterval,
+		MatchmakingWeekday:      matchmakingWeekday,
+		MatchmakingHour:         matchmakingHour,
+		MatchmakingMinute:       matchmakingMinute,
+		MatchmakingScheduleSet:  true,
+		MaxStateChangeRows:      maxStateChangeRows,
+		ExcludedMemberIDs:       excludedMemberIDs,
+		MemberTravelReductions:  memberTravelReductions,
+		HealthPort:              healthPort,
+		MaxConcurrentFactions:   maxConcurrentFactions,
+		IncrementalFetchBuffer:  incrementalFetchBuffer,
+		MinEnemyLevel:           minEnemyLevel,
+		SuspiciousGapThreshold:  suspiciousGapThreshold,
+		RespectDecimalPlaces:    respectDecimalPlaces,
+		OurFactionID:            ourFactionID,
+		MaintenanceWindows:      maintenanceWindows,
+		FullRescanInterval:      fullRescanInterval,
+		LocationCacheTTL:        locationCacheTTL,
+		StatusV2History:         statusV2History,
+		StatusHistoryMaxRows:    statusHistoryMaxRows,
+		SummaryJSONDir:          summaryJSONDir,
+		DeployRetries:           deployRetries,
+		WinResults:              winResults,
+		LossResults:             lossResults,
+		TrackCountdownDrift:     trackCountdownDrift,
+		IncludeInternalAttacks:  includeInternalAttacks,
+		ChainBreakThreshold:     chainBreakThreshold,
+		AlertsSheet:             alertsSheet,
+		RecordsColumnOrder:      recordsColumnOrder,
+		DisplayTimezone:         displayTimezone,
+		ParticipationDeviation:  participationDeviation,
+		SheetWriteBatchSize:     sheetWriteBatchSize,
+		WarEndGracePeriod:       warEndGracePeriod,
+		TrackOwnFactionStatus:   trackOwnFactionStatus,
+		AttackPaceFloor:         attackPaceFloor,
+		AttackPaceWindow:        attackPaceWindow,
+		FetchMaxPages:           fetchMaxPages,
+		MemberNotesSheet:        os.Getenv("MEMBER_NOTES_SHEET"),
+		MinReprocessInterval:    minReprocessInterval,
+		ReprocessStateFile:      os.Getenv("REPROCESS_STATE_FILE"),
+		MetricsPort:             metricsPort,
+		PinnedWarID:             pinnedWarID,
+		OurFactionName:          strings.TrimSpace(os.Getenv("OUR_FACTION_NAME")),
+		RawDumpDir:              os.Getenv("RAW_DUMP_DIR"),
+		InterFactionDelay:       interFactionDelay,
+		JSONArchiveDir:          os.Getenv("JSON_ARCHIVE_DIR"),
+		JSONArchiveRetention:    jsonArchiveRetention,
+		TargetLevelWeight:       targetLevelWeight,
+		TargetOnlineWeight:      targetOnlineWeight,
+		TargetIdleWeight:        targetIdleWeight,
+		PopulationProgressFile:  os.Getenv("POPULATION_PROGRESS_FILE"),
 	}, nil
 }
 
+// getEnvBool parses an optional boolean environment variable, returning false when unset
+func getEnvBool(key string) (bool, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return false, nil
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s value %q: %w", key, value, err)
+	}
+	return parsed, nil
+}
+
+// getEnvInt parses an optional integer environment variable, returning zero when unset
+func getEnvInt(key string) (int, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return 0, nil
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s value %q: %w", key, value, err)
+	}
+	return parsed, nil
+}
+
+// getEnvIntInRange parses an optional integer environment variable that must lie within
+// [min, max], returning fallback when unset
+func getEnvIntInRange(key string, fallback, min, max int) (int, error) {
+	if os.Getenv(key) == "" {
+		return fallback, nil
+	}
+
+	parsed, err := getEnvInt(key)
+	if err != nil {
+		return 0, err
+	}
+	if parsed < min || parsed > max {
+		return 0, fmt.Errorf("invalid %s value %d: must be between %d and %d", key, parsed, min, max)
+	}
+	return parsed, nil
+}
+
+// getEnvWeekday parses an optional weekday name environment variable (e.g. "Thursday"),
+// returning fallback when unset
+func getEnvWeekday(key string, fallback time.Weekday) (time.Weekday, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.EqualFold(value, day.String()) {
+			return day, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid %s value %q: must be a weekday name such as Tuesday", key, value)
+}
+
+// getEnvIntList parses an optional comma-separated list of integers (e.g. "123,456"),
+// returning nil when unset
+func getEnvIntList(key string) ([]int, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil, nil
+	}
+
+	var parsed []int
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		number, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s value %q: %w", key, value, err)
+		}
+		parsed = append(parsed, number)
+	}
+	return parsed, nil
+}
+
+// getEnvStringList parses an optional comma-separated list of strings, returning nil when unset
+func getEnvStringList(key string) []string {
+	var parsed []string
+	for _, part := range strings.Split(os.Getenv(key), ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			parsed = append(parsed, part)
+		}
+	}
+	return parsed
+}
+
+// getEnvPercentMap parses an optional comma-separated list of id:percent pairs
+// (e.g. "123:25,456:10") with percentages in [0, 100), returning nil when unset
+func getEnvPercentMap(key string) (map[int]float64, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil, nil
+	}
+
+	parsed := make(map[int]float64)
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		idStr, percentStr, found := strings.Cut(part, ":")
+		if !found {
+			return nil, fmt.Errorf("invalid %s entry %q: expected id:percent", key, part)
+		}
+		id, err := strconv.Atoi(strings.TrimSpace(idStr))
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s entry %q: %w", key, part, err)
+		}
+		percent, err := strconv.ParseFloat(strings.TrimSpace(percentStr), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s entry %q: %w", key, part, err)
+		}
+		if percent < 0 || percent >= 100 {
+			return nil, fmt.Errorf("invalid %s entry %q: percent must be in [0, 100)", key, part)
+		}
+		parsed[id] = percent
+	}
+	return parsed, nil
+}
+
+// getEnvDuration parses an optional duration environment variable (e.g. "30s"), returning zero when unset
+func getEnvDuration(key string) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return 0, nil
+	}
+
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s value %q: %w", key, value, err)
+	}
+	if parsed < 0 {
+		return 0, fmt.Errorf("invalid %s value %q: must not be negative", key, value)
+	}
+	return parsed, nil
+}
+
+// AttackTimeRange is an explicit window for a one-shot attack fetch (--from and --to)
+type AttackTimeRange struct {
+	From time.Time
+	To   time.Time
+}
+
+// ParseAttackTimeRange parses the RFC3339 --from and --to flag values. It returns nil
+// when neither is set, and an error when only one is set, either fails to parse, or
+// the range does not end after it starts.
+func ParseAttackTimeRange(from, to string) (*AttackTimeRange, error) {
+	if from == "" && to == "" {
+		return nil, nil
+	}
+	if from == "" || to == "" {
+		return nil, fmt.Errorf("--from and --to must be used together")
+	}
+
+	fromTime, err := time.Parse(time.RFC3339, from)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --from %q, expected RFC3339 (e.g. 2024-01-02T15:04:05Z): %w", from, err)
+	}
+	toTime, err := time.Parse(time.RFC3339, to)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --to %q, expected RFC3339 (e.g. 2024-01-02T15:04:05Z): %w", to, err)
+	}
+	if !toTime.After(fromTime) {
+		return nil, fmt.Errorf("--to %s must be after --from %s", to, from)
+	}
+
+	return &AttackTimeRange{From: fromTime, To: toTime}, nil
+}
+
+// Google Sheets IDs are URL-safe base64, normally 44 characters long
+const (
+	minSpreadsheetIDLength = 30
+	maxSpreadsheetIDLength = 60
+)
+
+var (
+	spreadsheetIDChars = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+	spreadsheetURLID   = regexp.MustCompile(`/spreadsheets/d/([^/?#]+)`)
+)
+
+// NormalizeSpreadsheetID validates a SPREADSHEET_ID value, reducing a pasted
+// spreadsheet link (https://docs.google.com/spreadsheets/d/<id>/edit) to the bare ID.
+// The error lists every problem found so a misconfiguration is clear at startup.
+func NormalizeSpreadsheetID(value string) (string, error) {
+	id := strings.TrimSpace(value)
+	if id == "" {
+		return "", fmt.Errorf("SPREADSHEET_ID environment variable is required")
+	}
+
+	if match := spreadsheetURLID.FindStringSubmatch(id); match != nil {
+		id = match[1]
+	} else if strings.Contains(id, "://") {
+		return "", fmt.Errorf("invalid SPREADSHEET_ID %q: URL is not a Google Sheets link (expected .../spreadsheets/d/<id>/...)", value)
+	}
+
+	var problems []string
+	if len(id) < minSpreadsheetIDLength || len(id) > maxSpreadsheetIDLength {
+		problems = append(problems, fmt.Sprintf("length %d is outside %d-%d characters", len(id), minSpreadsheetIDLength, maxSpreadsheetIDLength))
+	}
+	if !spreadsheetIDChars.MatchString(id) {
+		problems = append(problems, "contains characters other than letters, digits, '-' and '_'")
+	}
+	if len(problems) > 0 {
+		return "", fmt.Errorf("invalid SPREADSHEET_ID %q: %s", value, strings.Join(problems, "; "))
+	}
+
+	return id, nil
+}
+
 // GetRequiredEnv gets an environment variable or panics if not found
 func GetRequiredEnv(key string) string {
 	value := os.Getenv(key)
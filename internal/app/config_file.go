@@ -0,0 +1,147 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// configFileKeys lists the environment variables a config file may set. Keys in the
+// file are matched case-insensitively with dashes treated as underscores, so both
+// TORN_API_KEY and torn-api-key set TORN_API_KEY.
+var configFileKeys = []string{
+	"TORN_API_KEY", "SPREADSHEET_ID", "GOOGLE_CREDENTIALS_FILE",
+	"DEPLOY_URL", "DEPLOY_RETRIES",
+	"CSV_EXPORT_DIR", "SUMMARY_JSON_DIR",
+	"STATUS_DELTA_EXPORT", "EXCLUDED_MEMBER_IDS", "MEMBER_TRAVEL_REDUCTIONS", "LOCATION_CACHE_TTL",
+	"MEMBER_NOTES_SHEET", "JSON_ARCHIVE_DIR", "JSON_ARCHIVE_RETENTION",
+	"TRACK_COUNTDOWN_DRIFT", "STATUS_V2_HISTORY", "STATUS_HISTORY_MAX_ROWS",
+	"MAINTENANCE_WINDOWS", "OUR_FACTION_ID", "OUR_FACTION_NAME", "API_REQUESTS_PER_MINUTE", "WAIT_ON_OVERLAP",
+	"SPLIT_RECORDS_BY_DIRECTION", "HEALTH_PORT", "METRICS_PORT", "MAX_CONCURRENT_FACTIONS", "INTER_FACTION_DELAY",
+	"CHAIN_BREAK_THRESHOLD", "ALERTS_SHEET", "MIN_ENEMY_LEVEL",
+	"TARGET_LEVEL_WEIGHT", "TARGET_ONLINE_WEIGHT", "TARGET_IDLE_WEIGHT",
+	"ACTIVE_WAR_INTERVAL", "PRE_WAR_INTERVAL", "POST_WAR_WINDOW", "WAR_END_GRACE_PERIOD",
+	"MATCHMAKING_WEEKDAY", "MATCHMAKING_HOUR", "MATCHMAKING_MINUTE",
+	"HALF_CREDIT_WIN_RATE", "RESPECT_TREND_WINDOW", "INCREMENTAL_FETCH_BUFFER", "FULL_RESCAN_INTERVAL",
+	"FETCH_MAX_PAGES",
+	"MIN_REPROCESS_INTERVAL", "REPROCESS_STATE_FILE", "PINNED_WAR_ID", "POPULATION_PROGRESS_FILE",
+	"RESPECT_DECIMAL_PLACES", "DISPLAY_TIMEZONE", "SHEET_WRITE_BATCH_SIZE", "RECORDS_COLUMN_ORDER",
+	"INCLUDE_INTERNAL_ATTACKS", "WIN_RESULTS", "LOSS_RESULTS", "PARTICIPATION_DEVIATION",
+	"SUSPICIOUS_GAP_THRESHOLD", "ATTACK_PACE_FLOOR", "ATTACK_PACE_WINDOW",
+	"DETECT_REVIVES", "MAX_STATE_CHANGES_PER_CYCLE", "MAX_STATE_CHANGE_ROWS", "TRACK_OWN_FACTION_STATUS",
+	"WATCH_FACTION_IDS", "WATCH_INTERVAL",
+	"BIGQUERY_PROJECT_ID", "BIGQUERY_DATASET_ID", "BIGQUERY_TABLE_ID",
+	"ENV", "LOGLEVEL", "LOG_FORMAT", "RAW_DUMP_DIR",
+}
+
+// ApplyConfigFile reads a YAML or JSON config file and exports each of its settings as
+// the matching environment variable, so LoadConfig picks them up like any other setting.
+// Variables already present in the environment are left alone, letting the environment
+// override the file; SetupEnvironment loads .env first so it overrides the file too. Keys that match no setting are
+// skipped and returned so the caller can warn about them once logging is configured.
+func ApplyConfigFile(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	values, unknown, err := ParseConfigFile(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	for key, value := range values {
+		if _, set := os.LookupEnv(key); set {
+			continue
+		}
+		if err := os.Setenv(key, value); err != nil {
+			return nil, fmt.Errorf("failed to set %s from config file: %w", key, err)
+		}
+	}
+
+	return unknown, nil
+}
+
+// ParseConfigFile converts YAML or JSON config data into environment variable values,
+// returning the values keyed by variable name and the sorted list of unknown keys.
+// Lists are joined with commas and maps become comma-separated key:value pairs,
+// matching the formats the environment variables accept.
+func ParseConfigFile(data []byte) (map[string]string, []string, error) {
+	// JSON is valid YAML, so one decoder handles both formats
+	var raw map[string]interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, nil, err
+	}
+
+	known := make(map[string]bool, len(configFileKeys))
+	for _, key := range configFileKeys {
+		known[key] = true
+	}
+
+	values := make(map[string]string)
+	var unknown []string
+	for key, value := range raw {
+		envKey := strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(key), "-", "_"))
+		if !known[envKey] {
+			unknown = append(unknown, key)
+			continue
+		}
+		if value == nil {
+			continue
+		}
+
+		formatted, err := formatConfigValue(value)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid %s value: %w", key, err)
+		}
+		values[envKey] = formatted
+	}
+
+	sort.Strings(unknown)
+	return values, unknown, nil
+}
+
+// formatConfigValue renders a decoded config value in its environment variable form
+func formatConfigValue(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case []interface{}:
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			part, err := formatConfigValue(item)
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, part)
+		}
+		return strings.Join(parts, ","), nil
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		parts := make([]string, 0, len(v))
+		for _, key := range keys {
+			part, err := formatConfigValue(v[key])
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, key+":"+part)
+		}
+		return strings.Join(parts, ","), nil
+	case map[interface{}]interface{}:
+		converted := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			converted[fmt.Sprint(key)] = item
+		}
+		return formatConfigValue(converted)
+	case string, bool, int, int64, uint64, float64:
+		return fmt.Sprint(v), nil
+	default:
+		return "", fmt.Errorf("unsupported value %v", value)
+	}
+}
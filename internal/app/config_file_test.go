@@ -0,0 +1,128 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestApplyConfigFile(t *testing.T) {
+	const sampleYAML = `
+torn_api_key: file_api_key
+spreadsheet_id: 1BxiMVs0XRA5nFMdKvBdBZjgmUUqptlbs74OgvE2upms
+active-war-interval: 30s
+half_credit_win_rate: true
+respect_decimal_places: 3
+watch_faction_ids: [111, 222]
+member_travel_reductions:
+  123: 25
+  456: 10
+colour: blue
+`
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(sampleYAML), 0o600); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	// Register every key the file sets so t.Setenv restores the environment afterwards
+	for _, key := range []string{"TORN_API_KEY", "SPREADSHEET_ID", "ACTIVE_WAR_INTERVAL", "HALF_CREDIT_WIN_RATE",
+		"RESPECT_DECIMAL_PLACES", "WATCH_FACTION_IDS", "MEMBER_TRAVEL_REDUCTIONS"} {
+		t.Setenv(key, "")
+		os.Unsetenv(key)
+	}
+	t.Setenv("RESPECT_DECIMAL_PLACES", "1")
+
+	unknown, err := ApplyConfigFile(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(unknown, []string{"colour"}) {
+		t.Errorf("Expected unknown keys [colour], got %v", unknown)
+	}
+
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if config.TornAPIKey != "file_api_key" {
+		t.Errorf("Expected API key from file, got %s", config.TornAPIKey)
+	}
+	if config.SpreadsheetID != "1BxiMVs0XRA5nFMdKvBdBZjgmUUqptlbs74OgvE2upms" {
+		t.Errorf("Expected spreadsheet ID from file, got %s", config.SpreadsheetID)
+	}
+	if config.ActiveWarInterval != 30*time.Second {
+		t.Errorf("Expected 30s active war interval, got %v", config.ActiveWarInterval)
+	}
+	if !config.HalfCreditWinRate {
+		t.Error("Expected half-credit win rate enabled from file")
+	}
+	if !reflect.DeepEqual(config.WatchFactionIDs, []int{111, 222}) {
+		t.Errorf("Expected watch faction IDs [111 222], got %v", config.WatchFactionIDs)
+	}
+	if !reflect.DeepEqual(config.MemberTravelReductions, map[int]float64{123: 25, 456: 10}) {
+		t.Errorf("Unexpected member travel reductions: %v", config.MemberTravelReductions)
+	}
+	// The environment overrides the file
+	if config.RespectDecimalPlaces != 1 {
+		t.Errorf("Expected RESPECT_DECIMAL_PLACES from the environment (1), got %d", config.RespectDecimalPlaces)
+	}
+}
+
+func TestSetupEnvironmentConfigFilePrecedence(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+
+	configFile := filepath.Join(dir, "config.yaml")
+	config := "torn_api_key: file_api_key\nspreadsheet_id: file_sheet\nraw_dump_dir: file_dumps\n"
+	if err := os.WriteFile(configFile, []byte(config), 0o600); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".env"), []byte("TORN_API_KEY=dotenv_api_key\nSPREADSHEET_ID=dotenv_sheet\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write .env file: %v", err)
+	}
+
+	for _, key := range []string{"TORN_API_KEY", "SPREADSHEET_ID", "RAW_DUMP_DIR"} {
+		t.Setenv(key, "")
+		os.Unsetenv(key)
+	}
+	t.Setenv("SPREADSHEET_ID", "env_sheet")
+
+	if _, err := SetupEnvironment(configFile); err != nil {
+		t.Fatalf("SetupEnvironment() returned unexpected error: %v", err)
+	}
+
+	// The environment beats .env, which beats the config file
+	expected := map[string]string{
+		"SPREADSHEET_ID": "env_sheet",
+		"TORN_API_KEY":   "dotenv_api_key",
+		"RAW_DUMP_DIR":   "file_dumps",
+	}
+	for key, want := range expected {
+		if got := os.Getenv(key); got != want {
+			t.Errorf("Expected %s=%q, got %q", key, want, got)
+		}
+	}
+}
+
+func TestParseConfigFile(t *testing.T) {
+	values, unknown, err := ParseConfigFile([]byte(`{"TORN_API_KEY": "json_key", "win_results": ["Attacked", "Mugged"], "extra": 1}`))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := map[string]string{"TORN_API_KEY": "json_key", "WIN_RESULTS": "Attacked,Mugged"}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("Expected %v, got %v", expected, values)
+	}
+	if !reflect.DeepEqual(unknown, []string{"extra"}) {
+		t.Errorf("Expected unknown keys [extra], got %v", unknown)
+	}
+
+	if _, _, err := ParseConfigFile([]byte("torn_api_key: [unterminated")); err == nil {
+		t.Error("Expected error for malformed config file")
+	}
+}
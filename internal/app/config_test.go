@@ -2,12 +2,17 @@ package app
 
 import (
 	"os"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/rs/zerolog"
 )
 
+// testSpreadsheetID is a well-formed Google Sheets ID
+const testSpreadsheetID = "1BxiMVs0XRA5nFMdKvBdBZjgmUUqptlbs74OgvE2upms"
+
 func TestLoadConfig(t *testing.T) {
 	// Save original environment
 	originalTornAPIKey := os.Getenv("TORN_API_KEY")
@@ -23,7 +28,7 @@ func TestLoadConfig(t *testing.T) {
 
 	t.Run("ValidConfiguration", func(t *testing.T) {
 		os.Setenv("TORN_API_KEY", "test_api_key")
-		os.Setenv("SPREADSHEET_ID", "test_spreadsheet_id")
+		os.Setenv("SPREADSHEET_ID", testSpreadsheetID)
 		os.Setenv("GOOGLE_CREDENTIALS_FILE", "test_credentials.json")
 
 		config, err := LoadConfig()
@@ -36,8 +41,8 @@ func TestLoadConfig(t *testing.T) {
 			t.Errorf("Expected TornAPIKey to be 'test_api_key', got '%s'", config.TornAPIKey)
 		}
 
-		if config.SpreadsheetID != "test_spreadsheet_id" {
-			t.Errorf("Expected SpreadsheetID to be 'test_spreadsheet_id', got '%s'", config.SpreadsheetID)
+		if config.SpreadsheetID != testSpreadsheetID {
+			t.Errorf("Expected SpreadsheetID to be '%s', got '%s'", testSpreadsheetID, config.SpreadsheetID)
 		}
 
 		if config.CredentialsFile != "test_credentials.json" {
@@ -47,7 +52,7 @@ func TestLoadConfig(t *testing.T) {
 
 	t.Run("DefaultCredentialsFile", func(t *testing.T) {
 		os.Setenv("TORN_API_KEY", "test_api_key")
-		os.Setenv("SPREADSHEET_ID", "test_spreadsheet_id")
+		os.Setenv("SPREADSHEET_ID", testSpreadsheetID)
 		os.Unsetenv("GOOGLE_CREDENTIALS_FILE")
 
 		config, err := LoadConfig()
@@ -63,7 +68,7 @@ func TestLoadConfig(t *testing.T) {
 
 	t.Run("MissingTornAPIKey", func(t *testing.T) {
 		os.Unsetenv("TORN_API_KEY")
-		os.Setenv("SPREADSHEET_ID", "test_spreadsheet_id")
+		os.Setenv("SPREADSHEET_ID", testSpreadsheetID)
 
 		_, err := LoadConfig()
 
@@ -76,6 +81,752 @@ func TestLoadConfig(t *testing.T) {
 		}
 	})
 
+	t.Run("StatusDeltaExport", func(t *testing.T) {
+		os.Setenv("TORN_API_KEY", "test_api_key")
+		os.Setenv("SPREADSHEET_ID", testSpreadsheetID)
+		os.Setenv("STATUS_DELTA_EXPORT", "true")
+		defer os.Unsetenv("STATUS_DELTA_EXPORT")
+
+		config, err := LoadConfig()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if !config.StatusDeltaExport {
+			t.Error("Expected StatusDeltaExport to be true")
+		}
+
+		os.Setenv("STATUS_DELTA_EXPORT", "sometimes")
+		if _, err := LoadConfig(); err == nil || !strings.Contains(err.Error(), "STATUS_DELTA_EXPORT") {
+			t.Errorf("Expected error mentioning STATUS_DELTA_EXPORT, got %v", err)
+		}
+	})
+
+	t.Run("WaitOnOverlap", func(t *testing.T) {
+		os.Setenv("TORN_API_KEY", "test_api_key")
+		os.Setenv("SPREADSHEET_ID", testSpreadsheetID)
+		os.Setenv("WAIT_ON_OVERLAP", "true")
+		defer os.Unsetenv("WAIT_ON_OVERLAP")
+
+		config, err := LoadConfig()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if !config.WaitOnOverlap {
+			t.Error("Expected WaitOnOverlap to be true")
+		}
+	})
+
+	t.Run("SplitRecordsByDirection", func(t *testing.T) {
+		os.Setenv("TORN_API_KEY", "test_api_key")
+		os.Setenv("SPREADSHEET_ID", testSpreadsheetID)
+		os.Setenv("SPLIT_RECORDS_BY_DIRECTION", "1")
+		defer os.Unsetenv("SPLIT_RECORDS_BY_DIRECTION")
+
+		config, err := LoadConfig()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if !config.SplitRecordsByDirection {
+			t.Error("Expected SplitRecordsByDirection to be true")
+		}
+	})
+
+	t.Run("WarIntervals", func(t *testing.T) {
+		os.Setenv("TORN_API_KEY", "test_api_key")
+		os.Setenv("SPREADSHEET_ID", testSpreadsheetID)
+		os.Setenv("ACTIVE_WAR_INTERVAL", "30s")
+		os.Setenv("POST_WAR_WINDOW", "2h")
+		defer os.Unsetenv("ACTIVE_WAR_INTERVAL")
+		defer os.Unsetenv("POST_WAR_WINDOW")
+
+		config, err := LoadConfig()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if config.ActiveWarInterval != 30*time.Second {
+			t.Errorf("Expected ActiveWarInterval 30s, got %v", config.ActiveWarInterval)
+		}
+		if config.PreWarInterval != 0 {
+			t.Errorf("Expected unset PreWarInterval to be zero, got %v", config.PreWarInterval)
+		}
+		if config.PostWarWindow != 2*time.Hour {
+			t.Errorf("Expected PostWarWindow 2h, got %v", config.PostWarWindow)
+		}
+
+		os.Setenv("ACTIVE_WAR_INTERVAL", "soon")
+		if _, err := LoadConfig(); err == nil || !strings.Contains(err.Error(), "ACTIVE_WAR_INTERVAL") {
+			t.Errorf("Expected error mentioning ACTIVE_WAR_INTERVAL, got %v", err)
+		}
+	})
+
+	t.Run("WatchFactionIDs", func(t *testing.T) {
+		os.Setenv("TORN_API_KEY", "test_api_key")
+		os.Setenv("SPREADSHEET_ID", testSpreadsheetID)
+		os.Setenv("WATCH_FACTION_IDS", "123, 456,")
+		defer os.Unsetenv("WATCH_FACTION_IDS")
+
+		config, err := LoadConfig()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if len(config.WatchFactionIDs) != 2 || config.WatchFactionIDs[0] != 123 || config.WatchFactionIDs[1] != 456 {
+			t.Errorf("Expected WatchFactionIDs [123 456], got %v", config.WatchFactionIDs)
+		}
+
+		os.Setenv("WATCH_FACTION_IDS", "123,rivals")
+		if _, err := LoadConfig(); err == nil || !strings.Contains(err.Error(), "WATCH_FACTION_IDS") {
+			t.Errorf("Expected error mentioning WATCH_FACTION_IDS, got %v", err)
+		}
+	})
+
+	t.Run("ExcludedMemberIDs", func(t *testing.T) {
+		os.Setenv("TORN_API_KEY", "test_api_key")
+		os.Setenv("SPREADSHEET_ID", testSpreadsheetID)
+		os.Setenv("EXCLUDED_MEMBER_IDS", "111,222")
+		defer os.Unsetenv("EXCLUDED_MEMBER_IDS")
+
+		config, err := LoadConfig()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if len(config.ExcludedMemberIDs) != 2 || config.ExcludedMemberIDs[0] != 111 || config.ExcludedMemberIDs[1] != 222 {
+			t.Errorf("Expected ExcludedMemberIDs [111 222], got %v", config.ExcludedMemberIDs)
+		}
+	})
+
+	t.Run("MemberTravelReductions", func(t *testing.T) {
+		os.Setenv("TORN_API_KEY", "test_api_key")
+		os.Setenv("SPREADSHEET_ID", testSpreadsheetID)
+		os.Setenv("MEMBER_TRAVEL_REDUCTIONS", "123:25, 456:12.5")
+		defer os.Unsetenv("MEMBER_TRAVEL_REDUCTIONS")
+
+		config, err := LoadConfig()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if len(config.MemberTravelReductions) != 2 || config.MemberTravelReductions[123] != 25 || config.MemberTravelReductions[456] != 12.5 {
+			t.Errorf("Expected reductions map[123:25 456:12.5], got %v", config.MemberTravelReductions)
+		}
+
+		for _, invalid := range []string{"123", "abc:25", "123:100", "123:-5"} {
+			os.Setenv("MEMBER_TRAVEL_REDUCTIONS", invalid)
+			if _, err := LoadConfig(); err == nil || !strings.Contains(err.Error(), "MEMBER_TRAVEL_REDUCTIONS") {
+				t.Errorf("Expected error mentioning MEMBER_TRAVEL_REDUCTIONS for %q, got %v", invalid, err)
+			}
+		}
+	})
+
+	t.Run("MaxConcurrentFactions", func(t *testing.T) {
+		os.Setenv("TORN_API_KEY", "test_api_key")
+		os.Setenv("SPREADSHEET_ID", testSpreadsheetID)
+
+		config, err := LoadConfig()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if config.MaxConcurrentFactions != DefaultMaxConcurrentFactions {
+			t.Errorf("Expected default MaxConcurrentFactions %d, got %d", DefaultMaxConcurrentFactions, config.MaxConcurrentFactions)
+		}
+
+		os.Setenv("MAX_CONCURRENT_FACTIONS", "5")
+		defer os.Unsetenv("MAX_CONCURRENT_FACTIONS")
+		config, err = LoadConfig()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if config.MaxConcurrentFactions != 5 {
+			t.Errorf("Expected MaxConcurrentFactions 5, got %d", config.MaxConcurrentFactions)
+		}
+
+		os.Setenv("MAX_CONCURRENT_FACTIONS", "0")
+		if _, err := LoadConfig(); err == nil {
+			t.Error("Expected error for MAX_CONCURRENT_FACTIONS=0")
+		}
+	})
+
+	t.Run("IncrementalFetchBuffer", func(t *testing.T) {
+		os.Setenv("TORN_API_KEY", "test_api_key")
+		os.Setenv("SPREADSHEET_ID", testSpreadsheetID)
+		os.Setenv("INCREMENTAL_FETCH_BUFFER", "3h")
+		defer os.Unsetenv("INCREMENTAL_FETCH_BUFFER")
+
+		config, err := LoadConfig()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if config.IncrementalFetchBuffer != 3*time.Hour {
+			t.Errorf("Expected IncrementalFetchBuffer 3h, got %v", config.IncrementalFetchBuffer)
+		}
+
+		os.Setenv("INCREMENTAL_FETCH_BUFFER", "soon")
+		if _, err := LoadConfig(); err == nil || !strings.Contains(err.Error(), "INCREMENTAL_FETCH_BUFFER") {
+			t.Errorf("Expected error mentioning INCREMENTAL_FETCH_BUFFER, got %v", err)
+		}
+	})
+
+	t.Run("MinEnemyLevel", func(t *testing.T) {
+		os.Setenv("TORN_API_KEY", "test_api_key")
+		os.Setenv("SPREADSHEET_ID", testSpreadsheetID)
+		os.Setenv("MIN_ENEMY_LEVEL", "40")
+		defer os.Unsetenv("MIN_ENEMY_LEVEL")
+
+		config, err := LoadConfig()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if config.MinEnemyLevel != 40 {
+			t.Errorf("Expected MinEnemyLevel 40, got %d", config.MinEnemyLevel)
+		}
+
+		os.Setenv("MIN_ENEMY_LEVEL", "101")
+		if _, err := LoadConfig(); err == nil {
+			t.Error("Expected error for MIN_ENEMY_LEVEL=101")
+		}
+	})
+
+	t.Run("SuspiciousGapThreshold", func(t *testing.T) {
+		os.Setenv("TORN_API_KEY", "test_api_key")
+		os.Setenv("SPREADSHEET_ID", testSpreadsheetID)
+		os.Setenv("SUSPICIOUS_GAP_THRESHOLD", "90m")
+		defer os.Unsetenv("SUSPICIOUS_GAP_THRESHOLD")
+
+		config, err := LoadConfig()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if config.SuspiciousGapThreshold != 90*time.Minute {
+			t.Errorf("Expected SuspiciousGapThreshold 90m, got %v", config.SuspiciousGapThreshold)
+		}
+	})
+
+	t.Run("RespectDecimalPlaces", func(t *testing.T) {
+		os.Setenv("TORN_API_KEY", "test_api_key")
+		os.Setenv("SPREADSHEET_ID", testSpreadsheetID)
+
+		config, err := LoadConfig()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if config.RespectDecimalPlaces != DefaultRespectDecimalPlaces {
+			t.Errorf("Expected default RespectDecimalPlaces %d, got %d", DefaultRespectDecimalPlaces, config.RespectDecimalPlaces)
+		}
+
+		os.Setenv("RESPECT_DECIMAL_PLACES", "3")
+		defer os.Unsetenv("RESPECT_DECIMAL_PLACES")
+		config, err = LoadConfig()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if config.RespectDecimalPlaces != 3 {
+			t.Errorf("Expected RespectDecimalPlaces 3, got %d", config.RespectDecimalPlaces)
+		}
+
+		os.Setenv("RESPECT_DECIMAL_PLACES", "5")
+		if _, err := LoadConfig(); err == nil {
+			t.Error("Expected error for RESPECT_DECIMAL_PLACES=5")
+		}
+	})
+
+	t.Run("OurFactionID", func(t *testing.T) {
+		os.Setenv("TORN_API_KEY", "test_api_key")
+		os.Setenv("SPREADSHEET_ID", testSpreadsheetID)
+		os.Setenv("OUR_FACTION_ID", "12345")
+		defer os.Unsetenv("OUR_FACTION_ID")
+
+		config, err := LoadConfig()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if config.OurFactionID != 12345 {
+			t.Errorf("Expected OurFactionID 12345, got %d", config.OurFactionID)
+		}
+
+		os.Setenv("OUR_FACTION_ID", "-1")
+		if _, err := LoadConfig(); err == nil {
+			t.Error("Expected error for OUR_FACTION_ID=-1")
+		}
+	})
+
+	t.Run("MaintenanceWindows", func(t *testing.T) {
+		os.Setenv("TORN_API_KEY", "test_api_key")
+		os.Setenv("SPREADSHEET_ID", testSpreadsheetID)
+		os.Setenv("MAINTENANCE_WINDOWS", "03:00-03:30,23:45-00:15")
+		defer os.Unsetenv("MAINTENANCE_WINDOWS")
+
+		config, err := LoadConfig()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(config.MaintenanceWindows) != 2 {
+			t.Errorf("Expected 2 maintenance windows, got %+v", config.MaintenanceWindows)
+		}
+
+		os.Setenv("MAINTENANCE_WINDOWS", "03:00")
+		if _, err := LoadConfig(); err == nil || !strings.Contains(err.Error(), "MAINTENANCE_WINDOWS") {
+			t.Errorf("Expected error mentioning MAINTENANCE_WINDOWS, got %v", err)
+		}
+	})
+
+	t.Run("FullRescanInterval", func(t *testing.T) {
+		os.Setenv("TORN_API_KEY", "test_api_key")
+		os.Setenv("SPREADSHEET_ID", testSpreadsheetID)
+		os.Setenv("FULL_RESCAN_INTERVAL", "6h")
+		defer os.Unsetenv("FULL_RESCAN_INTERVAL")
+
+		config, err := LoadConfig()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if config.FullRescanInterval != 6*time.Hour {
+			t.Errorf("Expected FullRescanInterval 6h, got %v", config.FullRescanInterval)
+		}
+	})
+
+	t.Run("LocationCacheTTL", func(t *testing.T) {
+		os.Setenv("TORN_API_KEY", "test_api_key")
+		os.Setenv("SPREADSHEET_ID", testSpreadsheetID)
+		os.Setenv("LOCATION_CACHE_TTL", "5m")
+		defer os.Unsetenv("LOCATION_CACHE_TTL")
+
+		config, err := LoadConfig()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if config.LocationCacheTTL != 5*time.Minute {
+			t.Errorf("Expected LocationCacheTTL 5m, got %v", config.LocationCacheTTL)
+		}
+	})
+
+	t.Run("StatusV2History", func(t *testing.T) {
+		os.Setenv("TORN_API_KEY", "test_api_key")
+		os.Setenv("SPREADSHEET_ID", testSpreadsheetID)
+		os.Setenv("STATUS_V2_HISTORY", "true")
+		os.Setenv("STATUS_HISTORY_MAX_ROWS", "5000")
+		defer os.Unsetenv("STATUS_V2_HISTORY")
+		defer os.Unsetenv("STATUS_HISTORY_MAX_ROWS")
+
+		config, err := LoadConfig()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !config.StatusV2History || config.StatusHistoryMaxRows != 5000 {
+			t.Errorf("Expected StatusV2History with 5000 rows, got %v with %d",
+				config.StatusV2History, config.StatusHistoryMaxRows)
+		}
+	})
+
+	t.Run("DeployRetries", func(t *testing.T) {
+		os.Setenv("TORN_API_KEY", "test_api_key")
+		os.Setenv("SPREADSHEET_ID", testSpreadsheetID)
+
+		config, err := LoadConfig()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if config.DeployRetries != DefaultDeployRetries {
+			t.Errorf("Expected default DeployRetries %d, got %d", DefaultDeployRetries, config.DeployRetries)
+		}
+
+		os.Setenv("DEPLOY_RETRIES", "11")
+		defer os.Unsetenv("DEPLOY_RETRIES")
+		if _, err := LoadConfig(); err == nil {
+			t.Error("Expected error for DEPLOY_RETRIES above 10")
+		}
+	})
+
+	t.Run("WinLossResults", func(t *testing.T) {
+		os.Setenv("TORN_API_KEY", "test_api_key")
+		os.Setenv("SPREADSHEET_ID", testSpreadsheetID)
+		os.Setenv("WIN_RESULTS", "Hospitalized, Looted")
+		os.Setenv("LOSS_RESULTS", "Lost")
+		defer os.Unsetenv("WIN_RESULTS")
+		defer os.Unsetenv("LOSS_RESULTS")
+
+		config, err := LoadConfig()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !reflect.DeepEqual(config.WinResults, []string{"Hospitalized", "Looted"}) || !reflect.DeepEqual(config.LossResults, []string{"Lost"}) {
+			t.Errorf("Unexpected results: wins %v, losses %v", config.WinResults, config.LossResults)
+		}
+
+		os.Setenv("LOSS_RESULTS", "Lost,Looted")
+		if _, err := LoadConfig(); err == nil {
+			t.Error("Expected error for a result listed as both a win and a loss")
+		}
+	})
+
+	t.Run("RecordsColumnOrder", func(t *testing.T) {
+		os.Setenv("TORN_API_KEY", "test_api_key")
+		os.Setenv("SPREADSHEET_ID", testSpreadsheetID)
+		os.Setenv("RECORDS_COLUMN_ORDER", "Code, Started,Result")
+		defer os.Unsetenv("RECORDS_COLUMN_ORDER")
+
+		config, err := LoadConfig()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !reflect.DeepEqual(config.RecordsColumnOrder, []string{"Code", "Started", "Result"}) {
+			t.Errorf("Unexpected records column order: %v", config.RecordsColumnOrder)
+		}
+	})
+
+	t.Run("DisplayTimezone", func(t *testing.T) {
+		os.Setenv("TORN_API_KEY", "test_api_key")
+		os.Setenv("SPREADSHEET_ID", testSpreadsheetID)
+		defer os.Unsetenv("DISPLAY_TIMEZONE")
+
+		config, err := LoadConfig()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if config.DisplayTimezone != DefaultDisplayTimezone {
+			t.Errorf("Expected default timezone %s, got %s", DefaultDisplayTimezone, config.DisplayTimezone)
+		}
+
+		os.Setenv("DISPLAY_TIMEZONE", "Not/AZone")
+		if _, err := LoadConfig(); err == nil {
+			t.Error("Expected error for an unknown DISPLAY_TIMEZONE")
+		}
+	})
+
+	t.Run("WarEndGracePeriod", func(t *testing.T) {
+		os.Setenv("TORN_API_KEY", "test_api_key")
+		os.Setenv("SPREADSHEET_ID", testSpreadsheetID)
+		defer os.Unsetenv("WAR_END_GRACE_PERIOD")
+
+		config, err := LoadConfig()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if config.WarEndGracePeriod != 0 {
+			t.Errorf("Expected no grace period by default, got %v", config.WarEndGracePeriod)
+		}
+
+		os.Setenv("WAR_END_GRACE_PERIOD", "2m")
+		config, err = LoadConfig()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if config.WarEndGracePeriod != 2*time.Minute {
+			t.Errorf("Expected 2m grace period, got %v", config.WarEndGracePeriod)
+		}
+
+		os.Setenv("WAR_END_GRACE_PERIOD", "soon")
+		if _, err := LoadConfig(); err == nil {
+			t.Error("Expected error for an invalid WAR_END_GRACE_PERIOD")
+		}
+	})
+
+	t.Run("TrackOwnFactionStatus", func(t *testing.T) {
+		os.Setenv("TORN_API_KEY", "test_api_key")
+		os.Setenv("SPREADSHEET_ID", testSpreadsheetID)
+		defer os.Unsetenv("TRACK_OWN_FACTION_STATUS")
+
+		config, err := LoadConfig()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !config.TrackOwnFactionStatus {
+			t.Error("Expected our own faction tracked by default")
+		}
+
+		os.Setenv("TRACK_OWN_FACTION_STATUS", "false")
+		config, err = LoadConfig()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if config.TrackOwnFactionStatus {
+			t.Error("Expected TRACK_OWN_FACTION_STATUS=false to disable own faction tracking")
+		}
+
+		os.Setenv("TRACK_OWN_FACTION_STATUS", "sometimes")
+		if _, err := LoadConfig(); err == nil {
+			t.Error("Expected error for an invalid TRACK_OWN_FACTION_STATUS")
+		}
+	})
+
+	t.Run("AttackPace", func(t *testing.T) {
+		os.Setenv("TORN_API_KEY", "test_api_key")
+		os.Setenv("SPREADSHEET_ID", testSpreadsheetID)
+		defer os.Unsetenv("ATTACK_PACE_FLOOR")
+		defer os.Unsetenv("ATTACK_PACE_WINDOW")
+
+		config, err := LoadConfig()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if config.AttackPaceFloor != 0 || config.AttackPaceWindow != 0 {
+			t.Errorf("Expected attack pace check disabled by default, got floor %d window %v", config.AttackPaceFloor, config.AttackPaceWindow)
+		}
+
+		os.Setenv("ATTACK_PACE_FLOOR", "20")
+		os.Setenv("ATTACK_PACE_WINDOW", "30m")
+		config, err = LoadConfig()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if config.AttackPaceFloor != 20 || config.AttackPaceWindow != 30*time.Minute {
+			t.Errorf("Expected floor 20 over 30m, got floor %d window %v", config.AttackPaceFloor, config.AttackPaceWindow)
+		}
+
+		os.Setenv("ATTACK_PACE_FLOOR", "-1")
+		if _, err := LoadConfig(); err == nil {
+			t.Error("Expected error for a negative ATTACK_PACE_FLOOR")
+		}
+	})
+
+	t.Run("FetchPagination", func(t *testing.T) {
+		os.Setenv("TORN_API_KEY", "test_api_key")
+		os.Setenv("SPREADSHEET_ID", testSpreadsheetID)
+		defer os.Unsetenv("FETCH_MAX_PAGES")
+
+		config, err := LoadConfig()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if config.FetchMaxPages != DefaultFetchMaxPages {
+			t.Errorf("Expected default max pages %d, got %d", DefaultFetchMaxPages, config.FetchMaxPages)
+		}
+
+		os.Setenv("FETCH_MAX_PAGES", "10")
+		config, err = LoadConfig()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if config.FetchMaxPages != 10 {
+			t.Errorf("Expected max pages 10, got %d", config.FetchMaxPages)
+		}
+
+		os.Setenv("FETCH_MAX_PAGES", "0")
+		if _, err := LoadConfig(); err == nil {
+			t.Error("Expected error for FETCH_MAX_PAGES below 1")
+		}
+	})
+
+	t.Run("MinReprocessInterval", func(t *testing.T) {
+		os.Setenv("TORN_API_KEY", "test_api_key")
+		os.Setenv("SPREADSHEET_ID", testSpreadsheetID)
+		defer os.Unsetenv("MIN_REPROCESS_INTERVAL")
+		defer os.Unsetenv("REPROCESS_STATE_FILE")
+
+		config, err := LoadConfig()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if config.MinReprocessInterval != 0 || config.ReprocessStateFile != "" {
+			t.Errorf("Expected reprocess cooldown disabled by default, got %v %q", config.MinReprocessInterval, config.ReprocessStateFile)
+		}
+
+		os.Setenv("MIN_REPROCESS_INTERVAL", "5m")
+		os.Setenv("REPROCESS_STATE_FILE", "reprocess_state.json")
+		config, err = LoadConfig()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if config.MinReprocessInterval != 5*time.Minute || config.ReprocessStateFile != "reprocess_state.json" {
+			t.Errorf("Unexpected reprocess settings: %v %q", config.MinReprocessInterval, config.ReprocessStateFile)
+		}
+
+		os.Setenv("MIN_REPROCESS_INTERVAL", "-1m")
+		if _, err := LoadConfig(); err == nil {
+			t.Error("Expected error for negative MIN_REPROCESS_INTERVAL")
+		}
+	})
+
+	t.Run("MetricsPort", func(t *testing.T) {
+		os.Setenv("TORN_API_KEY", "test_api_key")
+		os.Setenv("SPREADSHEET_ID", testSpreadsheetID)
+		defer os.Unsetenv("METRICS_PORT")
+		defer os.Unsetenv("HEALTH_PORT")
+
+		os.Setenv("METRICS_PORT", "9100")
+		config, err := LoadConfig()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if config.MetricsPort != 9100 {
+			t.Errorf("Expected metrics port 9100, got %d", config.MetricsPort)
+		}
+
+		os.Setenv("HEALTH_PORT", "9100")
+		if _, err := LoadConfig(); err == nil {
+			t.Error("Expected error when METRICS_PORT matches HEALTH_PORT")
+		}
+	})
+
+	t.Run("PinnedWarID", func(t *testing.T) {
+		os.Setenv("TORN_API_KEY", "test_api_key")
+		os.Setenv("SPREADSHEET_ID", testSpreadsheetID)
+		defer os.Unsetenv("PINNED_WAR_ID")
+
+		os.Setenv("PINNED_WAR_ID", "24680")
+		config, err := LoadConfig()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if config.PinnedWarID != 24680 {
+			t.Errorf("Expected pinned war ID 24680, got %d", config.PinnedWarID)
+		}
+
+		os.Setenv("PINNED_WAR_ID", "-1")
+		if _, err := LoadConfig(); err == nil {
+			t.Error("Expected error for negative PINNED_WAR_ID")
+		}
+	})
+
+	t.Run("OurFactionName", func(t *testing.T) {
+		os.Setenv("TORN_API_KEY", "test_api_key")
+		os.Setenv("SPREADSHEET_ID", testSpreadsheetID)
+		defer os.Unsetenv("OUR_FACTION_NAME")
+
+		os.Setenv("OUR_FACTION_NAME", "  Iron Wolves ")
+		config, err := LoadConfig()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if config.OurFactionName != "Iron Wolves" {
+			t.Errorf("Expected trimmed faction name \"Iron Wolves\", got %q", config.OurFactionName)
+		}
+	})
+
+	t.Run("InterFactionDelay", func(t *testing.T) {
+		os.Setenv("TORN_API_KEY", "test_api_key")
+		os.Setenv("SPREADSHEET_ID", testSpreadsheetID)
+		defer os.Unsetenv("INTER_FACTION_DELAY")
+
+		config, err := LoadConfig()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if config.InterFactionDelay != 0 {
+			t.Errorf("Expected no inter-faction delay by default, got %v", config.InterFactionDelay)
+		}
+
+		os.Setenv("INTER_FACTION_DELAY", "2s")
+		config, err = LoadConfig()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if config.InterFactionDelay != 2*time.Second {
+			t.Errorf("Expected 2s inter-faction delay, got %v", config.InterFactionDelay)
+		}
+
+		os.Setenv("INTER_FACTION_DELAY", "-1s")
+		if _, err := LoadConfig(); err == nil {
+			t.Error("Expected error for negative INTER_FACTION_DELAY")
+		}
+	})
+
+	t.Run("JSONArchive", func(t *testing.T) {
+		os.Setenv("TORN_API_KEY", "test_api_key")
+		os.Setenv("SPREADSHEET_ID", testSpreadsheetID)
+		defer os.Unsetenv("JSON_ARCHIVE_DIR")
+		defer os.Unsetenv("JSON_ARCHIVE_RETENTION")
+
+		config, err := LoadConfig()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if config.JSONArchiveDir != "" || config.JSONArchiveRetention != DefaultJSONArchiveRetention {
+			t.Errorf("Expected archiving disabled with default retention, got %q and %d", config.JSONArchiveDir, config.JSONArchiveRetention)
+		}
+
+		os.Setenv("JSON_ARCHIVE_DIR", "archive")
+		os.Setenv("JSON_ARCHIVE_RETENTION", "3")
+		config, err = LoadConfig()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if config.JSONArchiveDir != "archive" || config.JSONArchiveRetention != 3 {
+			t.Errorf("Expected archive dir \"archive\" keeping 3, got %q and %d", config.JSONArchiveDir, config.JSONArchiveRetention)
+		}
+
+		os.Setenv("JSON_ARCHIVE_RETENTION", "0")
+		if _, err := LoadConfig(); err == nil {
+			t.Error("Expected error for zero JSON_ARCHIVE_RETENTION")
+		}
+	})
+
+	t.Run("TargetWeights", func(t *testing.T) {
+		os.Setenv("TORN_API_KEY", "test_api_key")
+		os.Setenv("SPREADSHEET_ID", testSpreadsheetID)
+		defer os.Unsetenv("TARGET_LEVEL_WEIGHT")
+		defer os.Unsetenv("TARGET_ONLINE_WEIGHT")
+
+		config, err := LoadConfig()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if config.TargetLevelWeight != DefaultTargetLevelWeight || config.TargetOnlineWeight != DefaultTargetOnlineWeight || config.TargetIdleWeight != DefaultTargetIdleWeight {
+			t.Errorf("Expected default target weights, got %d/%d/%d", config.TargetLevelWeight, config.TargetOnlineWeight, config.TargetIdleWeight)
+		}
+
+		os.Setenv("TARGET_LEVEL_WEIGHT", "3")
+		os.Setenv("TARGET_ONLINE_WEIGHT", "0")
+		config, err = LoadConfig()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if config.TargetLevelWeight != 3 || config.TargetOnlineWeight != 0 {
+			t.Errorf("Expected level weight 3 and online weight 0, got %d and %d", config.TargetLevelWeight, config.TargetOnlineWeight)
+		}
+
+		os.Setenv("TARGET_LEVEL_WEIGHT", "-1")
+		if _, err := LoadConfig(); err == nil {
+			t.Error("Expected error for negative TARGET_LEVEL_WEIGHT")
+		}
+	})
+
+	t.Run("MatchmakingSchedule", func(t *testing.T) {
+		os.Setenv("TORN_API_KEY", "test_api_key")
+		os.Setenv("SPREADSHEET_ID", testSpreadsheetID)
+
+		config, err := LoadConfig()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if config.MatchmakingWeekday != time.Tuesday || config.MatchmakingHour != 12 || config.MatchmakingMinute != 5 {
+			t.Errorf("Expected default Tuesday 12:05, got %s %02d:%02d",
+				config.MatchmakingWeekday, config.MatchmakingHour, config.MatchmakingMinute)
+		}
+		if !config.MatchmakingScheduleSet {
+			t.Error("Expected the loaded matchmaking schedule to be marked as set")
+		}
+
+		os.Setenv("MATCHMAKING_WEEKDAY", "thursday")
+		os.Setenv("MATCHMAKING_HOUR", "9")
+		defer os.Unsetenv("MATCHMAKING_WEEKDAY")
+		defer os.Unsetenv("MATCHMAKING_HOUR")
+
+		config, err = LoadConfig()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if config.MatchmakingWeekday != time.Thursday || config.MatchmakingHour != 9 || config.MatchmakingMinute != 5 {
+			t.Errorf("Expected Thursday 09:05, got %s %02d:%02d",
+				config.MatchmakingWeekday, config.MatchmakingHour, config.MatchmakingMinute)
+		}
+
+		os.Setenv("MATCHMAKING_HOUR", "24")
+		if _, err := LoadConfig(); err == nil || !strings.Contains(err.Error(), "MATCHMAKING_HOUR") {
+			t.Errorf("Expected error mentioning MATCHMAKING_HOUR, got %v", err)
+		}
+
+		os.Setenv("MATCHMAKING_HOUR", "9")
+		os.Setenv("MATCHMAKING_WEEKDAY", "Caturday")
+		if _, err := LoadConfig(); err == nil || !strings.Contains(err.Error(), "MATCHMAKING_WEEKDAY") {
+			t.Errorf("Expected error mentioning MATCHMAKING_WEEKDAY, got %v", err)
+		}
+	})
+
 	t.Run("MissingSpreadsheetID", func(t *testing.T) {
 		os.Setenv("TORN_API_KEY", "test_api_key")
 		os.Unsetenv("SPREADSHEET_ID")
@@ -131,7 +882,9 @@ func TestSetupEnvironment(t *testing.T) {
 			setOrUnset("ENV", tc.env)
 			setOrUnset("LOGLEVEL", tc.logLevel)
 
-			SetupEnvironment()
+			if _, err := SetupEnvironment(""); err != nil {
+				t.Fatalf("SetupEnvironment() returned unexpected error: %v", err)
+			}
 
 			if zerolog.GlobalLevel() != tc.expectedLevel {
 				t.Errorf("Expected log level %v, got %v", tc.expectedLevel, zerolog.GlobalLevel())
@@ -140,6 +893,132 @@ func TestSetupEnvironment(t *testing.T) {
 	}
 }
 
+func TestParseLogLevel(t *testing.T) {
+	tests := []struct {
+		name       string
+		value      string
+		production bool
+		expected   zerolog.Level
+		expectOK   bool
+	}{
+		{name: "debug", value: "debug", expected: zerolog.DebugLevel, expectOK: true},
+		{name: "warn", value: "WARN", expected: zerolog.WarnLevel, expectOK: true},
+		{name: "empty in production", value: "", production: true, expected: zerolog.WarnLevel, expectOK: true},
+		{name: "invalid defaults to info", value: "loud", production: true, expected: zerolog.InfoLevel, expectOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			level, ok := ParseLogLevel(tt.value, tt.production)
+			if level != tt.expected || ok != tt.expectOK {
+				t.Errorf("ParseLogLevel(%q) = %v, %v; expected %v, %v", tt.value, level, ok, tt.expected, tt.expectOK)
+			}
+		})
+	}
+}
+
+func TestParseLogFormat(t *testing.T) {
+	tests := []struct {
+		name       string
+		value      string
+		production bool
+		expected   string
+		expectOK   bool
+	}{
+		{name: "json in development", value: "json", expected: LogFormatJSON, expectOK: true},
+		{name: "console in production", value: "Console", production: true, expected: LogFormatConsole, expectOK: true},
+		{name: "empty in production", value: "", production: true, expected: LogFormatJSON, expectOK: true},
+		{name: "empty in development", value: "", expected: LogFormatConsole, expectOK: true},
+		{name: "invalid", value: "xml", expected: LogFormatConsole, expectOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			format, ok := ParseLogFormat(tt.value, tt.production)
+			if format != tt.expected || ok != tt.expectOK {
+				t.Errorf("ParseLogFormat(%q) = %q, %v; expected %q, %v", tt.value, format, ok, tt.expected, tt.expectOK)
+			}
+		})
+	}
+}
+
+func TestParseAttackTimeRange(t *testing.T) {
+	tests := []struct {
+		name      string
+		from      string
+		to        string
+		expectNil bool
+		expectErr bool
+	}{
+		{name: "neither set", expectNil: true},
+		{name: "both set", from: "2024-01-02T10:00:00Z", to: "2024-01-02T12:00:00Z"},
+		{name: "only from", from: "2024-01-02T10:00:00Z", expectErr: true},
+		{name: "only to", to: "2024-01-02T12:00:00Z", expectErr: true},
+		{name: "invalid from", from: "2024-01-02 10:00", to: "2024-01-02T12:00:00Z", expectErr: true},
+		{name: "invalid to", from: "2024-01-02T10:00:00Z", to: "yesterday", expectErr: true},
+		{name: "to before from", from: "2024-01-02T12:00:00Z", to: "2024-01-02T10:00:00Z", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			timeRange, err := ParseAttackTimeRange(tt.from, tt.to)
+			if tt.expectErr {
+				if err == nil {
+					t.Fatalf("Expected error, got range %+v", timeRange)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if tt.expectNil {
+				if timeRange != nil {
+					t.Errorf("Expected nil range, got %+v", timeRange)
+				}
+				return
+			}
+			if timeRange.To.Sub(timeRange.From) != 2*time.Hour {
+				t.Errorf("Expected a 2h range, got %v to %v", timeRange.From, timeRange.To)
+			}
+		})
+	}
+}
+
+func TestNormalizeSpreadsheetID(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		expected  string
+		expectErr string
+	}{
+		{name: "bare ID", value: testSpreadsheetID, expected: testSpreadsheetID},
+		{name: "bare ID with whitespace", value: "  " + testSpreadsheetID + "\n", expected: testSpreadsheetID},
+		{name: "full URL", value: "https://docs.google.com/spreadsheets/d/" + testSpreadsheetID + "/edit#gid=0", expected: testSpreadsheetID},
+		{name: "URL without path suffix", value: "https://docs.google.com/spreadsheets/d/" + testSpreadsheetID, expected: testSpreadsheetID},
+		{name: "empty", value: "", expectErr: "required"},
+		{name: "too short with invalid characters", value: "my sheet!", expectErr: "length 9 is outside 30-60 characters; contains characters"},
+		{name: "non-Sheets URL", value: "https://example.com/" + testSpreadsheetID, expectErr: "not a Google Sheets link"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := NormalizeSpreadsheetID(tt.value)
+			if tt.expectErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectErr) {
+					t.Fatalf("Expected error containing %q, got %v", tt.expectErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if id != tt.expected {
+				t.Errorf("Expected ID %q, got %q", tt.expected, id)
+			}
+		})
+	}
+}
+
 func TestGetRequiredEnv(t *testing.T) {
 	// Save original environment
 	originalValue := os.Getenv("TEST_REQUIRED_VAR")
@@ -0,0 +1,97 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MaintenanceWindow is a daily UTC window, as HH:MM times, during which processing is
+// skipped. A window whose end is before its start crosses midnight.
+type MaintenanceWindow struct {
+	Start string
+	End   string
+}
+
+// ParseMaintenanceWindows parses a comma-separated list of HH:MM-HH:MM UTC windows
+// (e.g. "03:00-03:30,23:45-00:15"), returning nil for an empty value
+func ParseMaintenanceWindows(value string) ([]MaintenanceWindow, error) {
+	var windows []MaintenanceWindow
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		start, end, found := strings.Cut(part, "-")
+		if !found {
+			return nil, fmt.Errorf("invalid maintenance window %q: expected HH:MM-HH:MM", part)
+		}
+
+		window := MaintenanceWindow{Start: strings.TrimSpace(start), End: strings.TrimSpace(end)}
+		startMinute, err := parseClockMinute(window.Start)
+		if err != nil {
+			return nil, fmt.Errorf("invalid maintenance window %q: %w", part, err)
+		}
+		endMinute, err := parseClockMinute(window.End)
+		if err != nil {
+			return nil, fmt.Errorf("invalid maintenance window %q: %w", part, err)
+		}
+		if startMinute == endMinute {
+			return nil, fmt.Errorf("invalid maintenance window %q: start and end must differ", part)
+		}
+
+		windows = append(windows, window)
+	}
+	return windows, nil
+}
+
+// ActiveMaintenanceWindow reports whether now falls inside any of the windows and, if
+// so, when that window ends. Windows include their start minute and exclude their end.
+func ActiveMaintenanceWindow(windows []MaintenanceWindow, now time.Time) (time.Time, bool) {
+	now = now.UTC()
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	sinceMidnight := now.Sub(midnight)
+
+	for _, window := range windows {
+		startMinute, err := parseClockMinute(window.Start)
+		if err != nil {
+			continue
+		}
+		endMinute, err := parseClockMinute(window.End)
+		if err != nil {
+			continue
+		}
+
+		start := time.Duration(startMinute) * time.Minute
+		end := time.Duration(endMinute) * time.Minute
+
+		var inside bool
+		if start < end {
+			inside = sinceMidnight >= start && sinceMidnight < end
+		} else {
+			// Crosses midnight: either the late part of today or the early part of tomorrow
+			inside = sinceMidnight >= start || sinceMidnight < end
+		}
+		if !inside {
+			continue
+		}
+
+		windowEnd := midnight.Add(end)
+		if !windowEnd.After(now) {
+			windowEnd = windowEnd.Add(24 * time.Hour)
+		}
+		return windowEnd, true
+	}
+
+	return time.Time{}, false
+}
+
+// parseClockMinute parses an HH:MM time of day into minutes since midnight
+func parseClockMinute(value string) (int, error) {
+	parsed, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q: expected HH:MM", value)
+	}
+	return parsed.Hour()*60 + parsed.Minute(), nil
+}
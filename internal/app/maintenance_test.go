@@ -0,0 +1,61 @@
+package app
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseMaintenanceWindows(t *testing.T) {
+	windows, err := ParseMaintenanceWindows("03:00-03:30, 23:45-00:15")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(windows) != 2 || windows[1] != (MaintenanceWindow{Start: "23:45", End: "00:15"}) {
+		t.Errorf("Unexpected windows: %+v", windows)
+	}
+
+	for _, invalid := range []string{"03:00", "3pm-4pm", "03:00-24:30", "05:00-05:00"} {
+		if _, err := ParseMaintenanceWindows(invalid); err == nil {
+			t.Errorf("Expected error for %q", invalid)
+		}
+	}
+}
+
+func TestActiveMaintenanceWindow(t *testing.T) {
+	windows := []MaintenanceWindow{
+		{Start: "03:00", End: "03:30"},
+		{Start: "23:45", End: "00:15"},
+	}
+	day := func(hour, minute int) time.Time {
+		return time.Date(2025, 1, 1, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name        string
+		now         time.Time
+		expectedIn  bool
+		expectedEnd time.Time
+	}{
+		{name: "before window", now: day(2, 59), expectedIn: false},
+		{name: "at window start", now: day(3, 0), expectedIn: true, expectedEnd: day(3, 30)},
+		{name: "inside window", now: day(3, 29), expectedIn: true, expectedEnd: day(3, 30)},
+		{name: "at window end", now: day(3, 30), expectedIn: false},
+		{name: "midnight window before midnight", now: day(23, 50), expectedIn: true, expectedEnd: day(24, 15)},
+		{name: "midnight window after midnight", now: day(0, 5), expectedIn: true, expectedEnd: day(0, 15)},
+		{name: "midnight window end", now: day(0, 15), expectedIn: false},
+		{name: "just before midnight window", now: day(23, 44), expectedIn: false},
+		{name: "non-UTC time is converted", now: day(3, 10).In(time.FixedZone("EST", -5*3600)), expectedIn: true, expectedEnd: day(3, 30)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			end, in := ActiveMaintenanceWindow(windows, tt.now)
+			if in != tt.expectedIn {
+				t.Fatalf("ActiveMaintenanceWindow(%v) in = %v, want %v", tt.now, in, tt.expectedIn)
+			}
+			if in && !end.Equal(tt.expectedEnd) {
+				t.Errorf("ActiveMaintenanceWindow(%v) end = %v, want %v", tt.now, end, tt.expectedEnd)
+			}
+		})
+	}
+}
@@ -79,17 +79,44 @@ type AttackResponse struct {
 	Attacks []Attack `json:"attacks"`
 }
 
+// War types, named after the WarResponse slice a war came from
+const (
+	WarTypeRanked    = "ranked"
+	WarTypeRaid      = "raid"
+	WarTypeTerritory = "territory"
+)
+
 // SheetConfig represents configuration for a war's sheets
 type SheetConfig struct {
 	WarID          int
+	WarType        string // WarTypeRanked, WarTypeRaid or WarTypeTerritory
 	SummaryTabName string
 	RecordsTabName string
 	SpreadsheetID  string
+
+	// Direction-split records sheets; empty unless records are split by direction
+	OutgoingTabName string
+	IncomingTabName string
+}
+
+// IsSplitByDirection reports whether attack records are written to separate
+// outgoing and incoming sheets
+func (c *SheetConfig) IsSplitByDirection() bool {
+	return c.OutgoingTabName != "" && c.IncomingTabName != ""
+}
+
+// AllRecordsTabNames returns every sheet that may hold attack records for the war
+func (c *SheetConfig) AllRecordsTabNames() []string {
+	if !c.IsSplitByDirection() {
+		return []string{c.RecordsTabName}
+	}
+	return []string{c.RecordsTabName, c.OutgoingTabName, c.IncomingTabName}
 }
 
 // WarSummary represents aggregated war statistics
 type WarSummary struct {
 	WarID         int
+	WarType       string // WarTypeRanked, WarTypeRaid or WarTypeTerritory
 	WarName       string
 	StartTime     time.Time
 	EndTime       *time.Time
@@ -99,9 +126,86 @@ type WarSummary struct {
 	TotalAttacks  int
 	AttacksWon    int
 	AttacksLost   int
+	AttacksDrawn  int
 	RespectGained float64
 	RespectLost   float64
+	NetRespect    float64 // RespectGained - RespectLost; negative when the enemy is ahead
 	LastUpdated   time.Time
+
+	// Win rates as percentages; WinRate is the configured headline rate
+	WinRate           float64
+	StrictWinRate     float64
+	HalfCreditWinRate float64
+
+	// Attacks made against us and the percentage we defended successfully
+	DefensiveAttacks     int
+	DefensiveSuccessRate float64
+
+	// Raid wars only: the target score and our score as a percentage of it
+	TargetScore     int
+	ProgressPercent float64
+
+	// Mean of each modifier across our outgoing attacks
+	AverageModifiers AttackModifiers
+
+	// Our outgoing attacks that received an overseas bonus
+	OverseasAttacks int
+
+	// Our outgoing attacks that were interrupted, e.g. the target was hospitalized by someone else
+	InterruptedAttacks int
+
+	// Attacks split by direction relative to our faction
+	OutgoingAttacks         int
+	IncomingAttacks         int
+	UnknownDirectionAttacks int
+
+	// Successful attacks at our average respect per hit needed to pass the enemy's
+	// score; 0 when we are ahead, -1 when we have no successful attacks to average
+	AttacksToOvertake int
+
+	// Our outgoing attacks counted by result, e.g. "Hospitalized" or "Mugged"
+	OutcomeCounts map[string]int
+
+	// Mean time our hospitalizing outgoing attacks kept the target in hospital, from
+	// stays observed while the targets were still in hospital; 0 when none were seen
+	AvgHospitalTimeInflicted time.Duration
+}
+
+// WarSummaryJSON is the JSON export of a WarSummary; timestamps are RFC3339 and End
+// is null while the war is still active
+type WarSummaryJSON struct {
+	WarID                   int             `json:"war_id"`
+	WarType                 string          `json:"war_type"`
+	WarName                 string          `json:"war_name"`
+	Status                  string          `json:"status"`
+	Start                   string          `json:"start"`
+	End                     *string         `json:"end"`
+	LastUpdated             string          `json:"last_updated"`
+	OurFaction              Faction         `json:"our_faction"`
+	EnemyFaction            Faction         `json:"enemy_faction"`
+	TotalAttacks            int             `json:"total_attacks"`
+	AttacksWon              int             `json:"attacks_won"`
+	AttacksLost             int             `json:"attacks_lost"`
+	AttacksDrawn            int             `json:"attacks_drawn"`
+	RespectGained           float64         `json:"respect_gained"`
+	RespectLost             float64         `json:"respect_lost"`
+	NetRespect              float64         `json:"net_respect"`
+	WinRate                 float64         `json:"win_rate"`
+	StrictWinRate           float64         `json:"strict_win_rate"`
+	HalfCreditWinRate       float64         `json:"half_credit_win_rate"`
+	DefensiveAttacks        int             `json:"defensive_attacks"`
+	DefensiveSuccessRate    float64         `json:"defensive_success_rate"`
+	TargetScore             int             `json:"target_score"`
+	ProgressPercent         float64         `json:"progress_percent"`
+	AverageModifiers        AttackModifiers `json:"average_modifiers"`
+	OverseasAttacks         int             `json:"overseas_attacks"`
+	InterruptedAttacks      int             `json:"interrupted_attacks"`
+	OutgoingAttacks         int             `json:"outgoing_attacks"`
+	IncomingAttacks         int             `json:"incoming_attacks"`
+	UnknownDirectionAttacks int             `json:"unknown_direction_attacks"`
+	AttacksToOvertake       *int            `json:"attacks_to_overtake,omitempty"` // nil when unknown
+	OutcomeCounts           map[string]int  `json:"outcome_counts"`
+	AvgHospitalSeconds      float64         `json:"avg_hospital_time_inflicted_seconds,omitempty"`
 }
 
 // AttackRecord represents a single attack for the records sheet
@@ -136,8 +240,98 @@ type AttackRecord struct {
 	ModifierOverseas    float64
 	ModifierChain       float64
 	ModifierWarlord     float64
-	FinishingHitName    string
-	FinishingHitValue   float64
+	FinishingHitName    string  // First finishing hit effect, kept for sheet compatibility
+	FinishingHitValue   float64 // First finishing hit effect value
+	FinishingHitEffects []FinishingHitEffect
+	LevelDifference     int // Attacker level minus defender level; negative when hitting up
+
+	// Faction outside the war on the other side of an enemy attack we weren't part of
+	// (e.g. an ally assisting us); empty otherwise
+	ThirdPartyFactionName string
+
+	// Faction tags resolved from faction info, telling apart factions with similar
+	// names; empty when a side has no faction or its tag could not be fetched
+	AttackerFactionTag string
+	DefenderFactionTag string
+
+	// The war's chronologically first and last attacks, for war recaps
+	IsFirstBlood bool
+	IsLastHit    bool
+}
+
+// MemberLeaderboardEntry represents one of our members' aggregated war hits for the leaderboard sheet
+type MemberLeaderboardEntry struct {
+	MemberID      int
+	MemberName    string
+	AttacksMade   int
+	AttacksWon    int
+	RespectGained float64
+	AvgFairFight  float64
+}
+
+// WarDashboardRow summarizes one active war for the combined dashboard sheet
+type WarDashboardRow struct {
+	WarID       int
+	WarType     string
+	EnemyName   string
+	OurScore    int
+	EnemyScore  int
+	AttacksWon  int
+	AttacksLost int
+	Status      string
+}
+
+// IncomingThreatEntry represents one enemy member's aggregated attacks on us for the incoming threats sheet
+type IncomingThreatEntry struct {
+	AttackerID        int
+	AttackerName      string
+	AttacksAgainstUs  int
+	RespectTheyGained float64
+}
+
+// Participation flags for members whose outgoing attacks deviate from the faction mean
+const (
+	ParticipationHigh = "High"
+	ParticipationLow  = "Low"
+)
+
+// ParticipationFlag represents one of our members whose outgoing attack count deviates
+// from the faction mean by more than the configured percentage
+type ParticipationFlag struct {
+	MemberID         int
+	MemberName       string
+	Attacks          int
+	DeviationPercent float64 // Signed deviation from the mean, e.g. -100 for no attacks
+	Flag             string  // ParticipationHigh or ParticipationLow
+}
+
+// Counterattack represents one of our outgoing attacks followed shortly by a successful
+// attack back on the same member by the opponent they hit
+type Counterattack struct {
+	MemberID            int
+	MemberName          string
+	OpponentID          int
+	OpponentName        string
+	AttackedAt          time.Time
+	AttackResult        string
+	CounterattackedAt   time.Time
+	CounterattackResult string
+	RetaliationModifier float64 // Retaliation modifier of the counterattack; above 1 means a retaliation bonus
+}
+
+// RespectTrendWindow represents the average respect per outgoing hit within one time window of a war
+type RespectTrendWindow struct {
+	WindowStart time.Time
+	AvgRespect  float64
+	AttackCount int
+}
+
+// WarStateTransition represents one accepted change of our war state for the state transition log
+type WarStateTransition struct {
+	Timestamp time.Time
+	FromState string // Empty for the state observed at startup
+	ToState   string
+	WarID     int // Zero when no war is selected
 }
 
 // FactionInfoResponse represents response from /faction/?selections=basic (own faction)
@@ -239,21 +433,96 @@ type StateRecord struct {
 	StatusState       string    `json:"status_state"`
 	StatusUntil       time.Time `json:"status_until"`
 	StatusTravelType  string    `json:"status_travel_type"`
+	Event             string    `json:"event,omitempty"` // Notable event behind the change, e.g. "Revived"
 }
 
 // StatusV2Record represents a member's data for Status v2 sheets
 type StatusV2Record struct {
-	Name            string    `json:"name"`
-	MemberID        string    `json:"member_id"`
-	Level           int       `json:"level"`
-	State           string    `json:"state"`            // LastActionStatus from StateRecord
-	Status          string    `json:"status"`           // StatusDescription from StateRecord
-	Location        string    `json:"location"`         // Destination for traveling, otherwise current location
-	Countdown       string    `json:"countdown"`        // Calculated from StatusUntil field
-	Departure       string    `json:"departure"`        // Manual adjustment preserved
-	Arrival         string    `json:"arrival"`          // Manual adjustment preserved
-	BusinessArrival string    `json:"business_arrival"` // Alternative arrival time assuming business class
-	Until           time.Time `json:"until"`            // StatusUntil timestamp from StateRecord
+	Name               string    `json:"name"`
+	MemberID           string    `json:"member_id"`
+	Level              int       `json:"level"`
+	State              string    `json:"state"`                // LastActionStatus from StateRecord
+	Status             string    `json:"status"`               // StatusDescription from StateRecord
+	Location           string    `json:"location"`             // Destination for traveling, otherwise current location
+	Countdown          string    `json:"countdown"`            // Calculated from StatusUntil field
+	Departure          string    `json:"departure"`            // Manual adjustment preserved
+	Arrival            string    `json:"arrival"`              // Manual adjustment preserved
+	BusinessArrival    string    `json:"business_arrival"`     // Alternative arrival time assuming business class
+	Until              time.Time `json:"until"`                // StatusUntil timestamp from StateRecord
+	Online             string    `json:"online"`               // "Online", "Idle" or "Offline" from faction LastAction; empty if unknown
+	LastActionRelative string    `json:"last_action_relative"` // Time since last action, e.g. "5 minutes ago"; empty if unknown
+	StatusCategory     string    `json:"status_category"`      // "available", "hospital", "traveling", "jail" or "unknown" from the status color
+}
+
+// AlertEnemyChainBroken is the alert type for an enemy chain dropping to near zero
+const AlertEnemyChainBroken = "Enemy Chain Broken"
+
+// AlertLowAttackPace is the alert type for our attack rate falling below the configured floor
+const AlertLowAttackPace = "Low Attack Pace"
+
+// AlertWatchedFactionWar is the alert type for a watched rival faction entering a war
+const AlertWatchedFactionWar = "Watched Faction War"
+
+// Alert is a tactical event worth surfacing to the faction, e.g. a broken enemy chain
+type Alert struct {
+	Timestamp time.Time
+	WarID     int
+	Type      string
+	Message   string
+}
+
+// CountdownDrift records how far a traveling member's calculated countdown is from the
+// countdown derived from the Torn API's status until time
+type CountdownDrift struct {
+	Timestamp           time.Time
+	FactionID           int
+	MemberID            string
+	MemberName          string
+	Location            string
+	OriginalCountdown   string
+	CalculatedCountdown string
+	DriftSeconds        int
+}
+
+// EnemyOverview counts a faction's members by activity and by high-level state.
+// Every member is in one activity bucket; only hospitalized, traveling or jailed
+// members are counted in a state bucket.
+type EnemyOverview struct {
+	Online    int
+	Idle      int
+	Offline   int
+	Hospital  int
+	Traveling int
+	Jail      int
+	Updated   time.Time
+}
+
+// EnemyHospitalEntry represents a hospitalized enemy member and when they will be out
+type EnemyHospitalEntry struct {
+	MemberID  int
+	Name      string
+	Level     int
+	OutAt     time.Time
+	Countdown string // H:MM:SS until OutAt
+}
+
+// TargetPriorityWeights are the points an enemy member scores per level and for being
+// online or idle in the target priority list
+type TargetPriorityWeights struct {
+	Level  int
+	Online int
+	Idle   int
+}
+
+// TargetPriorityEntry is an enemy member ranked by how good a target they are right now
+type TargetPriorityEntry struct {
+	MemberID    int
+	Name        string
+	Level       int
+	Online      string // "Online", "Idle" or "Offline"
+	State       string // Status state, e.g. "Okay" or "Hospital"
+	Attackable  bool   // Not in hospital, traveling, abroad or jail
+	TargetScore int
 }
 
 // JSONMember represents a member in the JSON export format
@@ -267,6 +536,10 @@ type JSONMember struct {
 	Until           string `json:"Until,omitempty"`
 	Arrival         string `json:"Arrival,omitempty"`
 	BusinessArrival string `json:"BusinessArrival,omitempty"`
+	Online          string `json:"Online,omitempty"`
+	LastAction      string `json:"LastAction,omitempty"`
+	Note            string `json:"Note,omitempty"` // Spotter note from the member notes sheet
+	StatusCategory  string `json:"StatusCategory,omitempty"`
 }
 
 // LocationData represents the traveling and located members for a location
@@ -275,10 +548,27 @@ type LocationData struct {
 	LocatedIn []JSONMember `json:"Located In"`
 }
 
+// StatusV2SchemaVersion is the version of the StatusV2JSON structure consumed by the
+// external dashboard. Bump it whenever the structure changes.
+const StatusV2SchemaVersion = 2
+
 // StatusV2JSON represents the complete JSON export structure
 type StatusV2JSON struct {
-	Faction   string                  `json:"Faction"`
-	Updated   string                  `json:"Updated"`
-	Interval  int                     `json:"Interval"` // Update interval in seconds
-	Locations map[string]LocationData `json:"Locations"`
+	SchemaVersion int                     `json:"SchemaVersion"`
+	GeneratedBy   string                  `json:"GeneratedBy,omitempty"` // Build identifier of the exporting binary
+	Faction       string                  `json:"Faction"`
+	Updated       string                  `json:"Updated"`
+	Interval      int                     `json:"Interval"` // Update interval in seconds
+	Locations     map[string]LocationData `json:"Locations"`
+}
+
+// StatusV2DeltaJSON represents the members whose exported status changed since the
+// previous export, grouped the same way as StatusV2JSON, plus the IDs of members
+// that are no longer present
+type StatusV2DeltaJSON struct {
+	Faction  string                  `json:"Faction"`
+	Updated  string                  `json:"Updated"`
+	Interval int                     `json:"Interval"` // Update interval in seconds
+	Changed  map[string]LocationData `json:"Changed"`
+	Removed  []string                `json:"Removed"` // Member IDs absent from the current export
 }
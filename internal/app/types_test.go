@@ -0,0 +1,41 @@
+package app
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// jsonFields lists the JSON names of a struct's fields in declaration order
+func jsonFields(t reflect.Type) []string {
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		names = append(names, name)
+	}
+	return names
+}
+
+// TestStatusV2SchemaVersion pins the exported structure to its schema version. When this
+// fails after a change to the Status v2 JSON, bump StatusV2SchemaVersion and update the
+// expected fields here together.
+func TestStatusV2SchemaVersion(t *testing.T) {
+	if StatusV2SchemaVersion != 2 {
+		t.Fatalf("StatusV2SchemaVersion changed to %d; update the pinned fields below to match", StatusV2SchemaVersion)
+	}
+
+	expected := map[reflect.Type][]string{
+		reflect.TypeOf(StatusV2JSON{}): {"SchemaVersion", "GeneratedBy", "Faction", "Updated", "Interval", "Locations"},
+		reflect.TypeOf(LocationData{}): {"Traveling", "Located In"},
+		reflect.TypeOf(JSONMember{}): {
+			"Name", "MemberID", "Level", "State", "Status", "Countdown", "Until", "Arrival",
+			"BusinessArrival", "Online", "LastAction", "Note", "StatusCategory",
+		},
+	}
+
+	for typ, want := range expected {
+		if got := jsonFields(typ); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s fields changed without a schema version bump: got %v, want %v", typ.Name(), got, want)
+		}
+	}
+}
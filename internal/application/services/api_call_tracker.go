@@ -9,20 +9,25 @@ import (
 )
 
 // APICallTracker monitors and optimizes API call usage, tracking session statistics,
-// endpoint-specific call counts, and providing predictions for future API usage.
+// endpoint-specific call counts and cache effectiveness, and providing predictions
+// for future API usage.
 type APICallTracker struct {
-	sessionStart    time.Time
-	sessionCalls    int64
-	totalCalls      int64
-	callsByEndpoint map[string]int64
-	mutex           sync.RWMutex
+	sessionStart          time.Time
+	sessionCalls          int64
+	totalCalls            int64
+	callsByEndpoint       map[string]int64
+	cacheHitsByEndpoint   map[string]int64
+	cacheMissesByEndpoint map[string]int64
+	mutex                 sync.RWMutex
 }
 
 // NewAPICallTracker creates a new API call tracker
 func NewAPICallTracker() *APICallTracker {
 	return &APICallTracker{
-		sessionStart:    time.Now(),
-		callsByEndpoint: make(map[string]int64),
+		sessionStart:          time.Now(),
+		callsByEndpoint:       make(map[string]int64),
+		cacheHitsByEndpoint:   make(map[string]int64),
+		cacheMissesByEndpoint: make(map[string]int64),
 	}
 }
 
@@ -36,6 +41,22 @@ func (t *APICallTracker) RecordCall(endpoint string) {
 	t.callsByEndpoint[endpoint]++
 }
 
+// RecordCacheHit records a call served from cache instead of the API
+func (t *APICallTracker) RecordCacheHit(endpoint string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.cacheHitsByEndpoint[endpoint]++
+}
+
+// RecordCacheMiss records a cacheable call that had to go to the API
+func (t *APICallTracker) RecordCacheMiss(endpoint string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.cacheMissesByEndpoint[endpoint]++
+}
+
 // GetSessionStats returns API call statistics for current session
 func (t *APICallTracker) GetSessionStats() APICallStats {
 	t.mutex.RLock()
@@ -48,17 +69,33 @@ func (t *APICallTracker) GetSessionStats() APICallStats {
 		endpointCopy[k] = v
 	}
 
+	var cacheHits, cacheMisses int64
+	hitsCopy := make(map[string]int64)
+	for k, v := range t.cacheHitsByEndpoint {
+		hitsCopy[k] = v
+		cacheHits += v
+	}
+	missesCopy := make(map[string]int64)
+	for k, v := range t.cacheMissesByEndpoint {
+		missesCopy[k] = v
+		cacheMisses += v
+	}
+
 	callsPerMinute := 0.0
 	if duration > 0 {
 		callsPerMinute = float64(t.sessionCalls) / duration.Minutes()
 	}
 
 	return APICallStats{
-		SessionCalls:    t.sessionCalls,
-		TotalCalls:      t.totalCalls,
-		SessionDuration: duration,
-		CallsByEndpoint: endpointCopy,
-		CallsPerMinute:  callsPerMinute,
+		SessionCalls:     t.sessionCalls,
+		TotalCalls:       t.totalCalls,
+		SessionDuration:  duration,
+		CallsByEndpoint:  endpointCopy,
+		CallsPerMinute:   callsPerMinute,
+		CacheHits:        cacheHits,
+		CacheMisses:      cacheMisses,
+		HitsByEndpoint:   hitsCopy,
+		MissesByEndpoint: missesCopy,
 	}
 }
 
@@ -80,7 +117,9 @@ func (t *APICallTracker) LogSessionSummary(ctx context.Context) {
 		Int64("session_calls", stats.SessionCalls).
 		Int64("total_calls", stats.TotalCalls).
 		Float64("calls_per_minute", stats.CallsPerMinute).
-		Dur("session_duration", stats.SessionDuration)
+		Dur("session_duration", stats.SessionDuration).
+		Int64("cache_hits", stats.CacheHits).
+		Int64("cache_misses", stats.CacheMisses)
 
 	// Add breakdown by endpoint
 	for endpoint, count := range stats.CallsByEndpoint {
@@ -91,13 +130,27 @@ func (t *APICallTracker) LogSessionSummary(ctx context.Context) {
 }
 
 // APICallStats represents API call statistics for a session, including call counts,
-// duration, breakdown by endpoint, and calls per minute rate.
+// duration, breakdown by endpoint, calls per minute rate, and cache hits and misses.
 type APICallStats struct {
-	SessionCalls    int64
-	TotalCalls      int64
-	SessionDuration time.Duration
-	CallsByEndpoint map[string]int64
-	CallsPerMinute  float64
+	SessionCalls     int64
+	TotalCalls       int64
+	SessionDuration  time.Duration
+	CallsByEndpoint  map[string]int64
+	CallsPerMinute   float64
+	CacheHits        int64
+	CacheMisses      int64
+	HitsByEndpoint   map[string]int64
+	MissesByEndpoint map[string]int64
+}
+
+// CacheHitRate returns the fraction of cacheable calls served from cache (0 to 1),
+// or zero when no cacheable calls were made
+func (s APICallStats) CacheHitRate() float64 {
+	total := s.CacheHits + s.CacheMisses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.CacheHits) / float64(total)
 }
 
 // PredictCallsForNextCycle estimates API calls needed for next execution cycle
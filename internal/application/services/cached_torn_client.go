@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"sync"
+
+	"torn_rw_stats/internal/app"
+	"torn_rw_stats/internal/processing"
+)
+
+// Endpoint names used when recording cache hits and misses
+const (
+	EndpointOwnFaction   = "own_faction"
+	EndpointFactionBasic = "faction_basic"
+)
+
+// CachedTornClient wraps a Torn client and serves our own faction's info from memory
+// after the first successful fetch, since it does not change while running. Faction
+// members are kept until ResetCycle, so every step of a processing cycle shares one
+// fetch per faction. Every other call goes straight to the wrapped client. Cache hits
+// and misses are recorded on the tracker.
+type CachedTornClient struct {
+	processing.TornClientInterface
+	tracker *APICallTracker
+
+	mu         sync.Mutex
+	ownFaction *app.FactionInfoResponse
+	factions   map[int]*app.FactionBasicResponse // this cycle's members, by faction ID
+}
+
+// NewCachedTornClient creates a caching wrapper around client
+func NewCachedTornClient(client processing.TornClientInterface, tracker *APICallTracker) *CachedTornClient {
+	return &CachedTornClient{
+		TornClientInterface: client,
+		tracker:             tracker,
+		factions:            make(map[int]*app.FactionBasicResponse),
+	}
+}
+
+// GetOwnFaction returns our faction's info, fetching it only on the first call
+func (c *CachedTornClient) GetOwnFaction(ctx context.Context) (*app.FactionInfoResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.ownFaction != nil {
+		c.tracker.RecordCacheHit(EndpointOwnFaction)
+		return c.ownFaction, nil
+	}
+
+	c.tracker.RecordCacheMiss(EndpointOwnFaction)
+	ownFaction, err := c.TornClientInterface.GetOwnFaction(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.ownFaction = ownFaction
+	return ownFaction, nil
+}
+
+// GetFactionBasic returns a faction's members, fetching them only on the first call
+// for that faction since the last ResetCycle. The lock is not held while fetching, so
+// concurrent lookups of different factions are not serialized.
+func (c *CachedTornClient) GetFactionBasic(ctx context.Context, factionID int) (*app.FactionBasicResponse, error) {
+	c.mu.Lock()
+	faction, ok := c.factions[factionID]
+	c.mu.Unlock()
+
+	if ok {
+		c.tracker.RecordCacheHit(EndpointFactionBasic)
+		return faction, nil
+	}
+
+	c.tracker.RecordCacheMiss(EndpointFactionBasic)
+	faction, err := c.TornClientInterface.GetFactionBasic(ctx, factionID)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.factions[factionID] = faction
+	c.mu.Unlock()
+	return faction, nil
+}
+
+// ResetCycle forgets the faction members fetched during the last processing cycle
+func (c *CachedTornClient) ResetCycle() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.factions = make(map[int]*app.FactionBasicResponse)
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"torn_rw_stats/internal/app"
+	"torn_rw_stats/internal/processing/mocks"
+)
+
+// countingTornClient counts GetOwnFaction calls reaching the underlying client
+type countingTornClient struct {
+	*mocks.MockTornClient
+	ownFactionCalls int
+}
+
+func (c *countingTornClient) GetOwnFaction(ctx context.Context) (*app.FactionInfoResponse, error) {
+	c.ownFactionCalls++
+	return c.MockTornClient.GetOwnFaction(ctx)
+}
+
+func TestCachedTornClient_GetOwnFaction(t *testing.T) {
+	underlying := &countingTornClient{MockTornClient: mocks.NewMockTornClient()}
+	underlying.OwnFactionResponse = &app.FactionInfoResponse{ID: 100, Name: "TestFaction"}
+	tracker := NewAPICallTracker()
+	client := NewCachedTornClient(underlying, tracker)
+
+	for i := 0; i < 2; i++ {
+		faction, err := client.GetOwnFaction(context.Background())
+		if err != nil {
+			t.Fatalf("GetOwnFaction() returned unexpected error: %v", err)
+		}
+		if faction.ID != 100 {
+			t.Errorf("expected faction 100, got %d", faction.ID)
+		}
+	}
+
+	if underlying.ownFactionCalls != 1 {
+		t.Errorf("expected 1 call to the underlying client, got %d", underlying.ownFactionCalls)
+	}
+
+	stats := tracker.GetSessionStats()
+	if stats.CacheHits != 1 || stats.CacheMisses != 1 {
+		t.Errorf("expected 1 hit and 1 miss, got %d hits and %d misses", stats.CacheHits, stats.CacheMisses)
+	}
+	if stats.HitsByEndpoint[EndpointOwnFaction] != 1 || stats.MissesByEndpoint[EndpointOwnFaction] != 1 {
+		t.Errorf("expected own faction hit and miss, got hits=%v misses=%v", stats.HitsByEndpoint, stats.MissesByEndpoint)
+	}
+	if rate := stats.CacheHitRate(); rate != 0.5 {
+		t.Errorf("expected hit rate 0.5, got %f", rate)
+	}
+}
+
+func TestCachedTornClient_ErrorNotCached(t *testing.T) {
+	underlying := &countingTornClient{MockTornClient: mocks.NewMockTornClient()}
+	underlying.OwnFactionError = context.DeadlineExceeded
+	client := NewCachedTornClient(underlying, NewAPICallTracker())
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetOwnFaction(context.Background()); err == nil {
+			t.Fatal("expected error from underlying client, got nil")
+		}
+	}
+
+	if underlying.ownFactionCalls != 2 {
+		t.Errorf("expected failed lookups to be retried, got %d underlying calls", underlying.ownFactionCalls)
+	}
+}
+
+func TestCachedTornClient_GetFactionBasicPerCycle(t *testing.T) {
+	underlying := mocks.NewMockTornClient()
+	underlying.FactionBasicResponse = &app.FactionBasicResponse{ID: 200}
+	tracker := NewAPICallTracker()
+	client := NewCachedTornClient(underlying, tracker)
+
+	for _, factionID := range []int{200, 200, 300} {
+		if _, err := client.GetFactionBasic(context.Background(), factionID); err != nil {
+			t.Fatalf("GetFactionBasic() returned unexpected error: %v", err)
+		}
+	}
+	if len(underlying.GetFactionBasicCalls) != 2 {
+		t.Errorf("expected one fetch per faction within a cycle, got %v", underlying.GetFactionBasicCalls)
+	}
+
+	client.ResetCycle()
+	if _, err := client.GetFactionBasic(context.Background(), 200); err != nil {
+		t.Fatalf("GetFactionBasic() returned unexpected error: %v", err)
+	}
+	if len(underlying.GetFactionBasicCalls) != 3 {
+		t.Errorf("expected a fresh fetch after the cycle reset, got %v", underlying.GetFactionBasicCalls)
+	}
+
+	stats := tracker.GetSessionStats()
+	if stats.HitsByEndpoint[EndpointFactionBasic] != 1 || stats.MissesByEndpoint[EndpointFactionBasic] != 3 {
+		t.Errorf("expected 1 hit and 3 misses, got hits=%v misses=%v", stats.HitsByEndpoint, stats.MissesByEndpoint)
+	}
+}
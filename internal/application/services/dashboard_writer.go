@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"torn_rw_stats/internal/app"
+	"torn_rw_stats/internal/processing"
+)
+
+// DashboardWriter collects a row for each war processed during a cycle and rewrites
+// the combined dashboard sheet from them once the cycle is done
+type DashboardWriter struct {
+	sheetsClient  processing.SheetsClientInterface
+	spreadsheetID string
+	rows          []app.WarDashboardRow
+	lastRows      map[int]app.WarDashboardRow // rows of the last write, by war ID
+}
+
+// NewDashboardWriter creates a dashboard writer for the given spreadsheet
+func NewDashboardWriter(sheetsClient processing.SheetsClientInterface, spreadsheetID string) *DashboardWriter {
+	return &DashboardWriter{
+		sheetsClient:  sheetsClient,
+		spreadsheetID: spreadsheetID,
+	}
+}
+
+// AddWar records a processed war's summary for the next dashboard write
+func (w *DashboardWriter) AddWar(summary *app.WarSummary) {
+	w.rows = append(w.rows, app.WarDashboardRow{
+		WarID:       summary.WarID,
+		WarType:     summary.WarType,
+		EnemyName:   summary.EnemyFaction.Name,
+		OurScore:    summary.OurFaction.Score,
+		EnemyScore:  summary.EnemyFaction.Score,
+		AttacksWon:  summary.AttacksWon,
+		AttacksLost: summary.AttacksLost,
+		Status:      summary.Status,
+	})
+}
+
+// KeepWar carries a war's row from the last write over to the next one, for wars
+// skipped this cycle. It reports false when the war had no row to keep.
+func (w *DashboardWriter) KeepWar(warID int) bool {
+	row, ok := w.lastRows[warID]
+	if ok {
+		w.rows = append(w.rows, row)
+	}
+	return ok
+}
+
+// Write rewrites the dashboard with the wars added since the last write, then starts
+// collecting afresh for the next cycle
+func (w *DashboardWriter) Write(ctx context.Context) error {
+	rows := w.rows
+	w.rows = nil
+
+	w.lastRows = make(map[int]app.WarDashboardRow, len(rows))
+	for _, row := range rows {
+		w.lastRows[row.WarID] = row
+	}
+
+	if err := w.sheetsClient.UpdateDashboard(ctx, w.spreadsheetID, rows); err != nil {
+		return fmt.Errorf("failed to update dashboard: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"torn_rw_stats/internal/app"
+)
+
+// processFactionsConcurrently runs fn for every faction with at most limit calls in
+// flight, returning the errors keyed by faction ID. A limit below one uses the default.
+// Each faction after the first starts delay after the previous one to smooth API load;
+// if ctx is cancelled during that wait, the factions not yet started are skipped with
+// the context's error. Each faction writes to its own tabs, so fn needs no locking
+// beyond the clients' own.
+func processFactionsConcurrently(ctx context.Context, factionIDs []int, limit int, delay time.Duration, fn func(factionID int) error) map[int]error {
+	if limit < 1 {
+		limit = app.DefaultMaxConcurrentFactions
+	}
+
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		errs   = make(map[int]error)
+		tokens = make(chan struct{}, limit)
+	)
+
+	for i, factionID := range factionIDs {
+		if i > 0 && delay > 0 {
+			if err := waitOrCancel(ctx, delay); err != nil {
+				mu.Lock()
+				for _, skipped := range factionIDs[i:] {
+					errs[skipped] = err
+				}
+				mu.Unlock()
+				break
+			}
+		}
+
+		wg.Add(1)
+		tokens <- struct{}{}
+		go func(factionID int) {
+			defer wg.Done()
+			defer func() { <-tokens }()
+
+			if err := fn(factionID); err != nil {
+				mu.Lock()
+				errs[factionID] = err
+				mu.Unlock()
+			}
+		}(factionID)
+	}
+
+	wg.Wait()
+	return errs
+}
+
+// waitOrCancel sleeps for delay, returning early with the context's error if ctx is
+// cancelled first
+func waitOrCancel(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"context"
+	"sync"
+
+	"torn_rw_stats/internal/app"
+	"torn_rw_stats/internal/processing"
+
+	"github.com/rs/zerolog/log"
+)
+
+// FactionTagCache resolves faction IDs to their tags, fetching each faction's basic
+// info once and serving it from memory afterwards since tags rarely change. Failed
+// lookups are not cached, so they are retried on the next cycle.
+type FactionTagCache struct {
+	client processing.TornClientInterface
+
+	mu   sync.Mutex
+	tags map[int]string
+}
+
+// NewFactionTagCache creates a tag cache backed by client
+func NewFactionTagCache(client processing.TornClientInterface) *FactionTagCache {
+	return &FactionTagCache{
+		client: client,
+		tags:   make(map[int]string),
+	}
+}
+
+// ApplyFactionTags fills in the attacker and defender faction tags of each record.
+// Records without a faction, or whose faction's info could not be fetched, keep an
+// empty tag.
+func (c *FactionTagCache) ApplyFactionTags(ctx context.Context, records []app.AttackRecord) {
+	failed := make(map[int]bool)
+	for i := range records {
+		records[i].AttackerFactionTag = c.tag(ctx, records[i].AttackerFactionID, failed)
+		records[i].DefenderFactionTag = c.tag(ctx, records[i].DefenderFactionID, failed)
+	}
+}
+
+// tag returns a faction's cached tag, fetching it on first use. Factions in failed
+// are skipped so one unreachable faction costs a single request per call.
+func (c *FactionTagCache) tag(ctx context.Context, factionID *int, failed map[int]bool) string {
+	if factionID == nil || *factionID == 0 || failed[*factionID] {
+		return ""
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if tag, ok := c.tags[*factionID]; ok {
+		return tag
+	}
+
+	faction, err := c.client.GetFactionBasic(ctx, *factionID)
+	if err != nil || faction == nil {
+		log.Debug().
+			Err(err).
+			Int("faction_id", *factionID).
+			Msg("Failed to fetch faction info for tag - leaving tag empty")
+		failed[*factionID] = true
+		return ""
+	}
+
+	c.tags[*factionID] = faction.Tag
+	return faction.Tag
+}
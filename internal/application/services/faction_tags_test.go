@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"torn_rw_stats/internal/app"
+	"torn_rw_stats/internal/processing/mocks"
+)
+
+func TestFactionTagCache_ApplyFactionTags(t *testing.T) {
+	ourFactionID, enemyFactionID := 1001, 2002
+	client := mocks.NewMockTornClient()
+	client.FactionBasicResponses = map[int]*app.FactionBasicResponse{
+		ourFactionID:   {ID: ourFactionID, Tag: "OUR"},
+		enemyFactionID: {ID: enemyFactionID, Tag: "ENMY"},
+	}
+
+	records := []app.AttackRecord{
+		{Code: "out", AttackerFactionID: &ourFactionID, DefenderFactionID: &enemyFactionID},
+		{Code: "in", AttackerFactionID: &enemyFactionID, DefenderFactionID: &ourFactionID},
+		{Code: "factionless", AttackerFactionID: &enemyFactionID},
+	}
+
+	cache := NewFactionTagCache(client)
+	cache.ApplyFactionTags(context.Background(), records)
+
+	expected := [][2]string{{"OUR", "ENMY"}, {"ENMY", "OUR"}, {"ENMY", ""}}
+	for i, want := range expected {
+		got := [2]string{records[i].AttackerFactionTag, records[i].DefenderFactionTag}
+		if got != want {
+			t.Errorf("record %s: expected tags %v, got %v", records[i].Code, want, got)
+		}
+	}
+
+	// Each faction is fetched once, then served from the cache
+	cache.ApplyFactionTags(context.Background(), records)
+	if !reflect.DeepEqual(client.GetFactionBasicCalls, []int{ourFactionID, enemyFactionID}) {
+		t.Errorf("expected one fetch per faction, got %v", client.GetFactionBasicCalls)
+	}
+}
+
+func TestFactionTagCache_FetchErrorLeavesTagEmpty(t *testing.T) {
+	factionID := 2002
+	client := mocks.NewMockTornClient()
+	client.FactionBasicError = errors.New("api unavailable")
+
+	records := []app.AttackRecord{
+		{AttackerFactionID: &factionID},
+		{AttackerFactionID: &factionID},
+	}
+
+	cache := NewFactionTagCache(client)
+	cache.ApplyFactionTags(context.Background(), records)
+
+	for i, record := range records {
+		if record.AttackerFactionTag != "" {
+			t.Errorf("record %d: expected empty tag, got %q", i, record.AttackerFactionTag)
+		}
+	}
+	if len(client.GetFactionBasicCalls) != 1 {
+		t.Errorf("expected a single failed fetch per call, got %d", len(client.GetFactionBasicCalls))
+	}
+
+	// Failures are retried once the API recovers
+	client.FactionBasicError = nil
+	client.FactionBasicResponse = &app.FactionBasicResponse{ID: factionID, Tag: "ENMY"}
+	cache.ApplyFactionTags(context.Background(), records)
+	if records[0].AttackerFactionTag != "ENMY" {
+		t.Errorf("expected tag after recovery, got %q", records[0].AttackerFactionTag)
+	}
+}
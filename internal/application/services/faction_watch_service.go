@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"torn_rw_stats/internal/app"
+	"torn_rw_stats/internal/domain/war"
+	"torn_rw_stats/internal/processing"
+
+	"github.com/rs/zerolog/log"
+)
+
+// DefaultWatchInterval is how often each watched faction is polled when not configured
+const DefaultWatchInterval = 15 * time.Minute
+
+// FactionWatchAlert reports a watched faction entering a war against anyone
+type FactionWatchAlert struct {
+	FactionID     int
+	PreviousState war.WarState
+	State         war.WarState
+	War           app.War
+	DetectedAt    time.Time
+}
+
+// SheetAlert converts the watch alert into an alerts sheet row naming both sides of the war
+func (a FactionWatchAlert) SheetAlert() app.Alert {
+	watched, opponent := fmt.Sprintf("Faction %d", a.FactionID), "unknown faction"
+	for _, faction := range a.War.Factions {
+		name := faction.Name
+		if name == "" {
+			name = fmt.Sprintf("Faction %d", faction.ID)
+		}
+		if faction.ID == a.FactionID {
+			watched = name
+		} else {
+			opponent = name
+		}
+	}
+
+	verb := "is at war with"
+	if a.State == war.PreWar {
+		verb = "has a war scheduled against"
+	}
+
+	return app.Alert{
+		Timestamp: a.DetectedAt,
+		WarID:     a.War.ID,
+		Type:      app.AlertWatchedFactionWar,
+		Message:   fmt.Sprintf("%s %s %s", watched, verb, opponent),
+	}
+}
+
+// FactionWatchService polls watched rival factions' wars on its own interval, separate
+// from our own war processing, and reports when one enters PreWar or ActiveWar.
+type FactionWatchService struct {
+	tornClient    processing.TornClientInterface
+	sheetsClient  processing.SheetsClientInterface
+	spreadsheetID string
+	alertsSheet   bool // Also append alerts to the alerts sheet
+	factionIDs    []int
+	interval      time.Duration
+	postWarWindow time.Duration
+
+	// Last observed state and poll time per faction; unpolled factions count as NoWars
+	lastStates  map[int]war.WarState
+	lastChecked map[int]time.Time
+}
+
+// NewFactionWatchService creates a watch over config.WatchFactionIDs
+func NewFactionWatchService(tornClient processing.TornClientInterface, sheetsClient processing.SheetsClientInterface, config *app.Config) *FactionWatchService {
+	interval := config.WatchInterval
+	if interval == 0 {
+		interval = DefaultWatchInterval
+	}
+
+	return &FactionWatchService{
+		tornClient:    tornClient,
+		sheetsClient:  sheetsClient,
+		spreadsheetID: config.SpreadsheetID,
+		alertsSheet:   config.AlertsSheet,
+		factionIDs:    config.WatchFactionIDs,
+		interval:      interval,
+		postWarWindow: config.PostWarWindow,
+		lastStates:    make(map[int]war.WarState),
+		lastChecked:   make(map[int]time.Time),
+	}
+}
+
+// CheckWatchedFactions polls every watched faction whose last poll is older than the
+// watch interval and returns an alert for each that has just entered a war. Alerts are
+// always logged and, when configured, appended to the alerts sheet.
+// Failures for one faction are logged and do not stop the others.
+func (s *FactionWatchService) CheckWatchedFactions(ctx context.Context) []FactionWatchAlert {
+	var alerts []FactionWatchAlert
+
+	for _, factionID := range s.factionIDs {
+		now := time.Now()
+		if lastChecked, ok := s.lastChecked[factionID]; ok && now.Sub(lastChecked) < s.interval {
+			continue
+		}
+
+		warResponse, err := s.tornClient.GetFactionWarsByID(ctx, factionID)
+		if err != nil {
+			log.Error().
+				Err(err).
+				Int("faction_id", factionID).
+				Msg("Failed to fetch wars for watched faction")
+			continue
+		}
+		s.lastChecked[factionID] = now
+
+		previous := s.lastStates[factionID]
+		current, selectedWar := war.DetermineWarState(warResponse, now, s.postWarWindow)
+		s.lastStates[factionID] = current
+
+		if !war.IsWatchAlertTransition(previous, current) {
+			continue
+		}
+
+		alert := FactionWatchAlert{
+			FactionID:     factionID,
+			PreviousState: previous,
+			State:         current,
+			War:           *selectedWar,
+			DetectedAt:    now,
+		}
+		alerts = append(alerts, alert)
+
+		log.Warn().
+			Int("faction_id", factionID).
+			Int("war_id", alert.War.ID).
+			Str("previous_state", previous.String()).
+			Str("war_state", current.String()).
+			Time("war_start", time.Unix(alert.War.Start, 0)).
+			Msg("Watched faction entered a war")
+
+		if s.alertsSheet {
+			if err := s.sheetsClient.AppendAlert(ctx, s.spreadsheetID, alert.SheetAlert()); err != nil {
+				log.Warn().
+					Err(err).
+					Int("faction_id", factionID).
+					Msg("Failed to append alert to alerts sheet")
+			}
+		}
+	}
+
+	return alerts
+}
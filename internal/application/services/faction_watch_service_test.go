@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"torn_rw_stats/internal/app"
+	"torn_rw_stats/internal/domain/war"
+	"torn_rw_stats/internal/processing/mocks"
+)
+
+func TestFactionWatchService_AlertsWhenWatchedFactionEntersWar(t *testing.T) {
+	tornMock := mocks.NewMockTornClient()
+	config := &app.Config{WatchFactionIDs: []int{500, 600}, WatchInterval: time.Nanosecond}
+	service := NewFactionWatchService(tornMock, mocks.NewMockSheetsClient(), config)
+
+	// First poll: neither rival is at war
+	if alerts := service.CheckWatchedFactions(context.Background()); len(alerts) != 0 {
+		t.Fatalf("expected no alerts while no wars exist, got %+v", alerts)
+	}
+
+	// Faction 500 gets matched against a third party
+	scheduled := &app.WarResponse{}
+	scheduled.Wars.Ranked = &app.War{
+		ID:       777,
+		Start:    time.Now().Add(6 * time.Hour).Unix(),
+		Factions: []app.Faction{{ID: 500}, {ID: 900}},
+	}
+	tornMock.FactionWarsByIDResponses = map[int]*app.WarResponse{500: scheduled}
+
+	alerts := service.CheckWatchedFactions(context.Background())
+	if len(alerts) != 1 {
+		t.Fatalf("expected 1 alert, got %d: %+v", len(alerts), alerts)
+	}
+	if alerts[0].FactionID != 500 || alerts[0].War.ID != 777 {
+		t.Errorf("expected alert for faction 500 war 777, got %+v", alerts[0])
+	}
+	if alerts[0].PreviousState != war.NoWars || alerts[0].State != war.PreWar {
+		t.Errorf("expected NoWars -> PreWar, got %s -> %s", alerts[0].PreviousState, alerts[0].State)
+	}
+
+	// Still scheduled: no repeat alert
+	if alerts := service.CheckWatchedFactions(context.Background()); len(alerts) != 0 {
+		t.Errorf("expected no repeat alert for an unchanged war, got %+v", alerts)
+	}
+
+	// War begins
+	scheduled.Wars.Ranked.Start = time.Now().Add(-time.Minute).Unix()
+	alerts = service.CheckWatchedFactions(context.Background())
+	if len(alerts) != 1 || alerts[0].State != war.ActiveWar {
+		t.Errorf("expected an ActiveWar alert once the war starts, got %+v", alerts)
+	}
+}
+
+func TestFactionWatchService_AppendsAlertsToSheet(t *testing.T) {
+	tornMock := mocks.NewMockTornClient()
+	scheduled := &app.WarResponse{}
+	scheduled.Wars.Ranked = &app.War{
+		ID:       777,
+		Start:    time.Now().Add(6 * time.Hour).Unix(),
+		Factions: []app.Faction{{ID: 500, Name: "Rivals"}, {ID: 900, Name: "Others"}},
+	}
+	tornMock.FactionWarsByIDResponses = map[int]*app.WarResponse{500: scheduled}
+
+	sheetsMock := mocks.NewMockSheetsClient()
+	config := &app.Config{SpreadsheetID: "spreadsheet-id", WatchFactionIDs: []int{500}, WatchInterval: time.Nanosecond, AlertsSheet: true}
+	service := NewFactionWatchService(tornMock, sheetsMock, config)
+
+	service.CheckWatchedFactions(context.Background())
+
+	if len(sheetsMock.AppendAlertCalls) != 1 {
+		t.Fatalf("expected 1 alert appended to the sheet, got %+v", sheetsMock.AppendAlertCalls)
+	}
+	alert := sheetsMock.AppendAlertCalls[0]
+	if alert.WarID != 777 || alert.Type != app.AlertWatchedFactionWar || alert.Message != "Rivals has a war scheduled against Others" {
+		t.Errorf("unexpected alert: %+v", alert)
+	}
+
+	// Without the alerts sheet the alert is only logged
+	sheetsMock = mocks.NewMockSheetsClient()
+	config.AlertsSheet = false
+	NewFactionWatchService(tornMock, sheetsMock, config).CheckWatchedFactions(context.Background())
+	if len(sheetsMock.AppendAlertCalls) != 0 {
+		t.Errorf("expected no sheet writes without the alerts sheet, got %+v", sheetsMock.AppendAlertCalls)
+	}
+}
+
+func TestFactionWatchService_CachesWithinInterval(t *testing.T) {
+	tornMock := mocks.NewMockTornClient()
+	config := &app.Config{WatchFactionIDs: []int{500}, WatchInterval: time.Hour}
+	service := NewFactionWatchService(tornMock, mocks.NewMockSheetsClient(), config)
+
+	service.CheckWatchedFactions(context.Background())
+	service.CheckWatchedFactions(context.Background())
+
+	if len(tornMock.GetFactionWarsByIDCalls) != 1 {
+		t.Errorf("expected 1 API call within the watch interval, got %d", len(tornMock.GetFactionWarsByIDCalls))
+	}
+}
+
+func TestFactionWatchService_RetriesAfterError(t *testing.T) {
+	tornMock := mocks.NewMockTornClient()
+	tornMock.FactionWarsByIDError = errors.New("api down")
+	config := &app.Config{WatchFactionIDs: []int{500}, WatchInterval: time.Hour}
+	service := NewFactionWatchService(tornMock, mocks.NewMockSheetsClient(), config)
+
+	service.CheckWatchedFactions(context.Background())
+	tornMock.FactionWarsByIDError = nil
+	service.CheckWatchedFactions(context.Background())
+
+	if len(tornMock.GetFactionWarsByIDCalls) != 2 {
+		t.Errorf("expected a failed poll to be retried, got %d calls", len(tornMock.GetFactionWarsByIDCalls))
+	}
+}
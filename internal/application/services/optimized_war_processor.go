@@ -3,10 +3,14 @@ package services
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"torn_rw_stats/internal/app"
+	"torn_rw_stats/internal/domain/attack"
 	"torn_rw_stats/internal/domain/war"
+	"torn_rw_stats/internal/health"
+	"torn_rw_stats/internal/metrics"
 	"torn_rw_stats/internal/processing"
 
 	"github.com/rs/zerolog/log"
@@ -17,12 +21,34 @@ import (
 type OptimizedWarProcessor struct {
 	processor         *WarProcessor
 	tornClient        processing.TornClientInterface
+	cachedClient      *CachedTornClient // tornClient, kept concrete to reset per cycle
+	sheetsClient      processing.SheetsClientInterface
 	tracker           *APICallTracker
 	stateManager      *war.WarStateManager
 	stateTracker      *StateTrackingService
 	statusV2Processor *StatusV2Processor
 	spreadsheetID     string
 	config            *app.Config
+
+	// stateObserved is set once the first war state has been logged
+	stateObserved bool
+
+	// enemyChains is the enemy chain length seen last cycle, keyed by war ID
+	enemyChains map[int]int
+
+	// lowPaceWars holds the wars whose attack pace is currently below the floor, so the
+	// warning fires once per drop rather than every cycle
+	lowPaceWars map[int]bool
+
+	// runMu prevents overlapping processing cycles from racing on Sheets
+	runMu sync.Mutex
+
+	// lastCycle is read by the health endpoint while cycles run, so it has its own lock
+	lastCycleMu       sync.RWMutex
+	lastCycle         health.Snapshot
+	lastCycleDuration time.Duration
+	lastCycleAttacks  int
+	apiCallsTotal     int64 // API calls summed over completed cycles, for metrics
 }
 
 // NewOptimizedWarProcessor creates a WarProcessor with war state management
@@ -39,13 +65,23 @@ func NewOptimizedWarProcessor(
 
 	// Create war state management
 	tracker := NewAPICallTracker()
-	stateManager := war.NewWarStateManager()
+	stateManager := war.NewWarStateManagerWithConfig(config)
+
+	// Serve stable lookups such as our own faction from cache across all processors
+	cachedClient := NewCachedTornClient(tornClient, tracker)
+	tornClient = cachedClient
 
 	// Create state tracking service with optional BigQuery sink
 	stateTracker := NewStateTrackingServiceWithBigQuery(tornClient, sheetsClient, bqClient)
+	stateTracker.SetReviveDetection(config.DetectRevives)
+	stateTracker.SetMaxStateChanges(config.MaxStateChangesPerCycle)
+	stateTracker.SetMaxStateChangeRows(config.MaxStateChangeRows)
+	stateTracker.SetMaxConcurrentFactions(config.MaxConcurrentFactions)
+	stateTracker.SetInterFactionDelay(config.InterFactionDelay)
+	stateTracker.SetDisplayLocation(app.DisplayLocation(config.DisplayTimezone))
 
 	// Create Status v2 processor
-	statusV2Processor := NewStatusV2Processor(tornClient, sheetsClient, config.DeployURL)
+	statusV2Processor := NewStatusV2Processor(tornClient, sheetsClient, config)
 
 	// Create processor with raw client
 	processor := NewWarProcessor(
@@ -61,17 +97,113 @@ func NewOptimizedWarProcessor(
 	return &OptimizedWarProcessor{
 		processor:         processor,
 		tornClient:        tornClient,
+		cachedClient:      cachedClient,
+		sheetsClient:      sheetsClient,
 		tracker:           tracker,
 		stateManager:      stateManager,
 		stateTracker:      stateTracker,
 		statusV2Processor: statusV2Processor,
 		spreadsheetID:     config.SpreadsheetID,
 		config:            config,
+		enemyChains:       make(map[int]int),
+		lowPaceWars:       make(map[int]bool),
+		lastCycle:         health.Snapshot{State: stateManager.GetCurrentState().String()},
 	}
 }
 
-// ProcessActiveWars processes wars with continuous monitoring
+// ProcessActiveWars processes wars with continuous monitoring.
+// Only one cycle runs at a time; an overlapping call either waits for the
+// in-flight cycle or is skipped, depending on config.WaitOnOverlap.
 func (owp *OptimizedWarProcessor) ProcessActiveWars(ctx context.Context) error {
+	if owp.config.WaitOnOverlap {
+		owp.runMu.Lock()
+	} else if !owp.runMu.TryLock() {
+		log.Warn().
+			Msg("Previous processing cycle still running - skipping overlapping cycle")
+		return nil
+	}
+	defer owp.runMu.Unlock()
+
+	startedAt := time.Now()
+	owp.processor.attacksProcessed = 0
+	// Members fetched this cycle are shared by its steps but never carried into the next
+	defer owp.cachedClient.ResetCycle()
+
+	if err := owp.processActiveWars(ctx); err != nil {
+		return err
+	}
+
+	owp.recordCompletedCycle(startedAt)
+	return nil
+}
+
+// recordCompletedCycle stores the health snapshot and cycle metrics for a successfully
+// completed cycle
+func (owp *OptimizedWarProcessor) recordCompletedCycle(startedAt time.Time) {
+	completedAt := time.Now()
+	nextCheck := owp.stateManager.GetNextCheckTime()
+	apiCalls := owp.tornClient.GetAPICallCount()
+
+	owp.lastCycleMu.Lock()
+	defer owp.lastCycleMu.Unlock()
+
+	owp.lastCycle = health.Snapshot{
+		State:             owp.stateManager.GetCurrentState().String(),
+		LastCycleTime:     &completedAt,
+		LastCycleAPICalls: apiCalls,
+		NextCheckTime:     &nextCheck,
+	}
+	owp.lastCycleDuration = completedAt.Sub(startedAt)
+	owp.lastCycleAttacks = owp.processor.attacksProcessed
+	owp.apiCallsTotal += apiCalls
+}
+
+// HealthSnapshot returns the state of the last completed processing cycle
+func (owp *OptimizedWarProcessor) HealthSnapshot() health.Snapshot {
+	owp.lastCycleMu.RLock()
+	defer owp.lastCycleMu.RUnlock()
+
+	return owp.lastCycle
+}
+
+// MetricsSnapshot returns API usage and the state of the last completed processing cycle
+func (owp *OptimizedWarProcessor) MetricsSnapshot() metrics.Snapshot {
+	summary := owp.GetProcessingSummary()
+
+	owp.lastCycleMu.RLock()
+	defer owp.lastCycleMu.RUnlock()
+
+	return metrics.Snapshot{
+		APICallsTotal:             owp.apiCallsTotal,
+		CacheHitsTotal:            summary.CacheHits,
+		CacheMissesTotal:          summary.CacheMisses,
+		WarState:                  owp.lastCycle.State,
+		WarStates:                 []string{war.NoWars.String(), war.PreWar.String(), war.ActiveWar.String(), war.PostWar.String()},
+		AttacksProcessedLastCycle: owp.lastCycleAttacks,
+		LastCycleDuration:         owp.lastCycleDuration,
+	}
+}
+
+// ValidateSheets ensures the current or upcoming war's sheets exist and reports any
+// header mismatches, bypassing war state management
+func (owp *OptimizedWarProcessor) ValidateSheets(ctx context.Context) ([]string, error) {
+	owp.runMu.Lock()
+	defer owp.runMu.Unlock()
+
+	return owp.processor.ValidateSheets(ctx)
+}
+
+// ProcessAttacksInRange runs a one-shot fetch of attacks for the current war between
+// from and to, bypassing war state management
+func (owp *OptimizedWarProcessor) ProcessAttacksInRange(ctx context.Context, from, to time.Time) error {
+	owp.runMu.Lock()
+	defer owp.runMu.Unlock()
+
+	return owp.processor.ProcessAttacksInRange(ctx, from, to)
+}
+
+// processActiveWars runs a single processing cycle
+func (owp *OptimizedWarProcessor) processActiveWars(ctx context.Context) error {
 	// Always fetch war data first to determine actual current state
 	log.Debug().
 		Msg("Fetching war data to determine current state")
@@ -82,8 +214,7 @@ func (owp *OptimizedWarProcessor) ProcessActiveWars(ctx context.Context) error {
 	}
 
 	// Update war state based on fresh data
-	previousState := owp.stateManager.GetCurrentState()
-	currentState := owp.stateManager.UpdateState(warResponse)
+	previousState, currentState := owp.updateWarState(ctx, warResponse)
 
 	// Log current state at start of processing loop
 	stateInfo := owp.stateManager.GetStateInfo()
@@ -119,6 +250,14 @@ func (owp *OptimizedWarProcessor) ProcessActiveWars(ctx context.Context) error {
 	if err := owp.processor.ensureOurFactionID(ctx); err != nil {
 		log.Error().Err(err).Msg("Failed to ensure our faction ID - continuing without state tracking")
 	}
+	owp.processor.resolveOurFactionByName(warResponse)
+	if owp.statusV2Processor.ourFactionID == 0 {
+		// Share a name-matched ID, which Status v2 cannot detect on its own
+		owp.statusV2Processor.ourFactionID = owp.processor.ourFactionID
+	}
+
+	// Warn as soon as an enemy chain breaks
+	owp.checkEnemyChainBreaks(ctx, warResponse, time.Now())
 
 	// Process state changes for all observed factions
 	owp.processStateChanges(ctx, warResponse, stateInfo)
@@ -160,12 +299,178 @@ func (owp *OptimizedWarProcessor) ProcessActiveWars(ctx context.Context) error {
 		}
 	}
 
+	// Warn when our members stop attacking mid-war, from the attacks just fetched
+	if currentState == war.ActiveWar {
+		owp.checkAttackPace(ctx, owp.stateManager.GetCurrentWar(), time.Now())
+	}
+
 	// Log processing results
 	owp.LogProcessingResults(ctx)
 
 	return nil
 }
 
+// updateWarState updates the war state from fresh war data and appends accepted
+// transitions to the state transition sheet. Blocked transitions leave the state
+// unchanged and are not logged. The first update logs the state observed at startup.
+func (owp *OptimizedWarProcessor) updateWarState(ctx context.Context, warResponse *app.WarResponse) (war.WarState, war.WarState) {
+	previousState := owp.stateManager.GetCurrentState()
+	currentState := owp.stateManager.UpdateState(warResponse)
+
+	if owp.stateObserved && currentState == previousState {
+		return previousState, currentState
+	}
+
+	transition := app.WarStateTransition{
+		Timestamp: time.Now(),
+		ToState:   currentState.String(),
+	}
+	if owp.stateObserved {
+		transition.FromState = previousState.String()
+	}
+	if currentWar := owp.stateManager.GetCurrentWar(); currentWar != nil {
+		transition.WarID = currentWar.ID
+	}
+	owp.stateObserved = true
+
+	// The transition log is an audit aid, so a failed write does not stop the cycle
+	if err := owp.sheetsClient.AppendStateTransition(ctx, owp.spreadsheetID, transition); err != nil {
+		log.Warn().
+			Err(err).
+			Str("from_state", transition.FromState).
+			Str("to_state", transition.ToState).
+			Msg("Failed to log war state transition")
+	}
+
+	return previousState, currentState
+}
+
+// checkEnemyChainBreaks compares each war's enemy chain with the previous cycle and
+// raises an alert for every chain that broke, returning the alerts. Alerts are always
+// logged and, when configured, appended to the alerts sheet.
+func (owp *OptimizedWarProcessor) checkEnemyChainBreaks(ctx context.Context, warResponse *app.WarResponse, now time.Time) []app.Alert {
+	ourFactionID := owp.processor.ourFactionID
+	if ourFactionID == 0 {
+		return nil
+	}
+
+	var wars []app.War
+	if warResponse.Wars.Ranked != nil {
+		wars = append(wars, *warResponse.Wars.Ranked)
+	}
+	wars = append(wars, warResponse.Wars.Raids...)
+	wars = append(wars, warResponse.Wars.Territory...)
+
+	var alerts []app.Alert
+	for i := range wars {
+		enemy := war.IdentifyWarFactions(&wars[i], ourFactionID).EnemyFaction
+		previous, seen := owp.enemyChains[wars[i].ID]
+		owp.enemyChains[wars[i].ID] = enemy.Chain
+
+		if !seen || !war.IsChainBreak(previous, enemy.Chain, owp.config.ChainBreakThreshold) {
+			continue
+		}
+
+		alert := app.Alert{
+			Timestamp: now,
+			WarID:     wars[i].ID,
+			Type:      app.AlertEnemyChainBroken,
+			Message:   fmt.Sprintf("%s chain broken: %d -> %d", enemy.Name, previous, enemy.Chain),
+		}
+		alerts = append(alerts, alert)
+
+		log.Warn().
+			Int("war_id", alert.WarID).
+			Int("enemy_faction_id", enemy.ID).
+			Str("enemy_faction", enemy.Name).
+			Int("previous_chain", previous).
+			Int("current_chain", enemy.Chain).
+			Msg("ENEMY CHAIN BROKEN")
+
+		if owp.config.AlertsSheet {
+			if err := owp.sheetsClient.AppendAlert(ctx, owp.spreadsheetID, alert); err != nil {
+				log.Warn().
+					Err(err).
+					Int("war_id", alert.WarID).
+					Msg("Failed to append alert to alerts sheet")
+			}
+		}
+	}
+
+	return alerts
+}
+
+// attacksPerPage is the most attacks the Torn API returns for one request
+const attacksPerPage = 100
+
+// checkAttackPace measures our outgoing attacks over the configured rolling window
+// ending at the war's latest fetch, and raises an alert when they fall below the
+// configured attacks-per-hour floor, returning the alert. The alert fires when the pace
+// drops and again only after it has recovered. Wars that started less than a window
+// ago, or whose latest fetch does not cover the window, are not checked. Alerts are
+// always logged and, when configured, appended to the alerts sheet.
+func (owp *OptimizedWarProcessor) checkAttackPace(ctx context.Context, currentWar *app.War, now time.Time) *app.Alert {
+	if owp.config.AttackPaceFloor <= 0 || currentWar == nil || owp.processor.ourFactionID == 0 {
+		return nil
+	}
+
+	window := owp.config.AttackPaceWindow
+	if window <= 0 {
+		window = attack.DefaultAttackPaceWindow
+	}
+	fetch, fetched := owp.processor.recentFetches[currentWar.ID]
+	from := fetch.to.Add(-window)
+	if !fetched || time.Unix(currentWar.Start, 0).After(from) {
+		return nil
+	}
+	if fetch.from.After(from) {
+		log.Debug().
+			Int("war_id", currentWar.ID).
+			Time("fetched_from", fetch.from).
+			Dur("window", window).
+			Msg("Latest attack fetch does not cover the attack pace window - skipping check")
+		return nil
+	}
+
+	// A page full of our own attacks in the window is a pace no sensible floor is
+	// above, so it is not counted as a drop; incoming hits do not count towards it
+	pace := attack.AttackPace(fetch.records, from, fetch.to)
+	floor := float64(owp.config.AttackPaceFloor)
+	if pace >= floor || attack.OutgoingAttacks(fetch.records, from, fetch.to) >= attacksPerPage {
+		delete(owp.lowPaceWars, currentWar.ID)
+		return nil
+	}
+	if owp.lowPaceWars[currentWar.ID] {
+		return nil
+	}
+	owp.lowPaceWars[currentWar.ID] = true
+
+	alert := &app.Alert{
+		Timestamp: now,
+		WarID:     currentWar.ID,
+		Type:      app.AlertLowAttackPace,
+		Message:   fmt.Sprintf("Attack pace %.1f/h over the last %s is below %d/h", pace, window, owp.config.AttackPaceFloor),
+	}
+
+	log.Warn().
+		Int("war_id", alert.WarID).
+		Float64("attacks_per_hour", pace).
+		Int("floor_per_hour", owp.config.AttackPaceFloor).
+		Dur("window", window).
+		Msg("LOW ATTACK PACE")
+
+	if owp.config.AlertsSheet {
+		if err := owp.sheetsClient.AppendAlert(ctx, owp.spreadsheetID, *alert); err != nil {
+			log.Warn().
+				Err(err).
+				Int("war_id", alert.WarID).
+				Msg("Failed to append alert to alerts sheet")
+		}
+	}
+
+	return alert
+}
+
 // LogProcessingResults logs the processing session results
 func (owp *OptimizedWarProcessor) LogProcessingResults(ctx context.Context) {
 	// Get current session stats
@@ -191,6 +496,9 @@ func (owp *OptimizedWarProcessor) GetProcessingSummary() ProcessingSummary {
 		TotalAPICalls:   stats.TotalCalls,
 		CallsPerMinute:  stats.CallsPerMinute,
 		SessionDuration: stats.SessionDuration,
+		CacheHits:       stats.CacheHits,
+		CacheMisses:     stats.CacheMisses,
+		CacheHitRate:    stats.CacheHitRate(),
 	}
 }
 
@@ -249,6 +557,30 @@ func (owp *OptimizedWarProcessor) processStateChanges(ctx context.Context, warRe
 	// Remove duplicates
 	factionIDs = owp.removeDuplicateFactionIDs(factionIDs)
 
+	// Build faction list scoped to ranked war only for the tactical dashboard.
+	// When a ranked war is active alongside raids/territory wars, we only want
+	// the ranked war opponent in the Status v2 sheets and travel_data.json.
+	var dashboardFactionIDs []int
+	if owp.processor.ourFactionID != 0 {
+		dashboardFactionIDs = append(dashboardFactionIDs, owp.processor.ourFactionID)
+	}
+	if warResponse.Wars.Ranked != nil {
+		for _, faction := range warResponse.Wars.Ranked.Factions {
+			dashboardFactionIDs = append(dashboardFactionIDs, faction.ID)
+		}
+	}
+	// Fall back to all factions if there is no ranked war
+	if len(dashboardFactionIDs) <= 1 {
+		dashboardFactionIDs = factionIDs
+	}
+	dashboardFactionIDs = owp.removeDuplicateFactionIDs(dashboardFactionIDs)
+
+	// Wars list our own faction too, so it is dropped from both lists when only enemies are tracked
+	if !owp.config.TrackOwnFactionStatus {
+		factionIDs = excludeFactionID(factionIDs, owp.processor.ourFactionID)
+		dashboardFactionIDs = excludeFactionID(dashboardFactionIDs, owp.processor.ourFactionID)
+	}
+
 	// If no factions to track, skip
 	if len(factionIDs) == 0 {
 		log.Debug().Msg("No factions to track for state changes")
@@ -271,24 +603,6 @@ func (owp *OptimizedWarProcessor) processStateChanges(ctx context.Context, warRe
 			Msg("Successfully processed state changes")
 	}
 
-	// Build faction list scoped to ranked war only for the tactical dashboard.
-	// When a ranked war is active alongside raids/territory wars, we only want
-	// the ranked war opponent in the Status v2 sheets and travel_data.json.
-	var dashboardFactionIDs []int
-	if owp.processor.ourFactionID != 0 {
-		dashboardFactionIDs = append(dashboardFactionIDs, owp.processor.ourFactionID)
-	}
-	if warResponse.Wars.Ranked != nil {
-		for _, faction := range warResponse.Wars.Ranked.Factions {
-			dashboardFactionIDs = append(dashboardFactionIDs, faction.ID)
-		}
-	}
-	// Fall back to all factions if there is no ranked war
-	if len(dashboardFactionIDs) <= 1 {
-		dashboardFactionIDs = factionIDs
-	}
-	dashboardFactionIDs = owp.removeDuplicateFactionIDs(dashboardFactionIDs)
-
 	// Process Status v2 sheets for ranked war factions only (tactical dashboard)
 	log.Debug().
 		Ints("faction_ids", dashboardFactionIDs).
@@ -306,6 +620,17 @@ func (owp *OptimizedWarProcessor) processStateChanges(ctx context.Context, warRe
 	}
 }
 
+// excludeFactionID returns factionIDs without factionID, preserving order
+func excludeFactionID(factionIDs []int, factionID int) []int {
+	var result []int
+	for _, id := range factionIDs {
+		if id != factionID {
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
 // removeDuplicateFactionIDs removes duplicate faction IDs from a slice
 func (owp *OptimizedWarProcessor) removeDuplicateFactionIDs(factionIDs []int) []int {
 	seen := make(map[int]bool)
@@ -322,10 +647,13 @@ func (owp *OptimizedWarProcessor) removeDuplicateFactionIDs(factionIDs []int) []
 }
 
 // ProcessingSummary provides a summary of processing session including API call
-// statistics, duration, and call rate metrics.
+// statistics, duration, call rate and cache effectiveness metrics.
 type ProcessingSummary struct {
 	SessionAPICalls int64
 	TotalAPICalls   int64
 	CallsPerMinute  float64
 	SessionDuration time.Duration
+	CacheHits       int64
+	CacheMisses     int64
+	CacheHitRate    float64 // Fraction of cacheable calls served from cache, 0 to 1
 }
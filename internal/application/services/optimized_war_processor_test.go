@@ -0,0 +1,282 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"torn_rw_stats/internal/app"
+	"torn_rw_stats/internal/domain/war"
+	"torn_rw_stats/internal/processing/mocks"
+)
+
+// slowTornClient records how many GetFactionWars calls are in flight at once
+type slowTornClient struct {
+	*mocks.MockTornClient
+	delay     time.Duration
+	calls     int32
+	active    int32
+	maxActive int32
+}
+
+func (c *slowTornClient) GetFactionWars(ctx context.Context) (*app.WarResponse, error) {
+	atomic.AddInt32(&c.calls, 1)
+	active := atomic.AddInt32(&c.active, 1)
+	for {
+		maxActive := atomic.LoadInt32(&c.maxActive)
+		if active <= maxActive || atomic.CompareAndSwapInt32(&c.maxActive, maxActive, active) {
+			break
+		}
+	}
+
+	time.Sleep(c.delay)
+	atomic.AddInt32(&c.active, -1)
+
+	// End the cycle early; only the overlap guard is under test
+	return nil, errors.New("wars unavailable")
+}
+
+func runConcurrentCycles(t *testing.T, config *app.Config, cycles int) *slowTornClient {
+	t.Helper()
+
+	client := &slowTornClient{MockTornClient: mocks.NewMockTornClient(), delay: 50 * time.Millisecond}
+	processor := NewOptimizedWarProcessor(client, mocks.NewMockSheetsClient(), nil, nil, nil, nil, config, nil)
+
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	for i := 0; i < cycles; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			_ = processor.ProcessActiveWars(context.Background())
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	return client
+}
+
+func TestOptimizedWarProcessor_SkipsOverlappingCycles(t *testing.T) {
+	client := runConcurrentCycles(t, &app.Config{}, 5)
+
+	if client.maxActive != 1 {
+		t.Errorf("expected no overlapping cycles, got %d running at once", client.maxActive)
+	}
+	if client.calls >= 5 {
+		t.Errorf("expected overlapping cycles to be skipped, got %d cycles run", client.calls)
+	}
+}
+
+func TestOptimizedWarProcessor_WaitsForOverlappingCycles(t *testing.T) {
+	client := runConcurrentCycles(t, &app.Config{WaitOnOverlap: true}, 3)
+
+	if client.maxActive != 1 {
+		t.Errorf("expected no overlapping cycles, got %d running at once", client.maxActive)
+	}
+	if client.calls != 3 {
+		t.Errorf("expected every cycle to run after waiting, got %d cycles run", client.calls)
+	}
+}
+
+func TestOptimizedWarProcessor_LogsStateTransitions(t *testing.T) {
+	sheetsMock := mocks.NewMockSheetsClient()
+	processor := NewOptimizedWarProcessor(mocks.NewMockTornClient(), sheetsMock, nil, nil, nil, nil, &app.Config{SpreadsheetID: "spreadsheet-id"}, nil)
+	ctx := context.Background()
+	now := time.Now()
+
+	noWars := &app.WarResponse{}
+
+	preWar := &app.WarResponse{}
+	preWar.Wars.Ranked = &app.War{ID: 777, Start: now.Add(time.Hour).Unix()}
+
+	activeWar := &app.WarResponse{}
+	activeWar.Wars.Ranked = &app.War{ID: 777, Start: now.Add(-time.Minute).Unix()}
+
+	for _, resp := range []*app.WarResponse{noWars, noWars, preWar, activeWar, activeWar} {
+		processor.updateWarState(ctx, resp)
+	}
+
+	transitions := sheetsMock.AppendStateTransitionCalls
+	if len(transitions) != 3 {
+		t.Fatalf("expected 3 logged transitions, got %d: %+v", len(transitions), transitions)
+	}
+
+	expected := []struct {
+		from, to string
+		warID    int
+	}{
+		{"", "NoWars", 0},
+		{"NoWars", "PreWar", 777},
+		{"PreWar", "ActiveWar", 777},
+	}
+	for i, want := range expected {
+		got := transitions[i]
+		if got.FromState != want.from || got.ToState != want.to || got.WarID != want.warID {
+			t.Errorf("transition %d: expected %s -> %s (war %d), got %s -> %s (war %d)",
+				i, want.from, want.to, want.warID, got.FromState, got.ToState, got.WarID)
+		}
+	}
+}
+
+func TestOptimizedWarProcessor_BlockedTransitionNotLogged(t *testing.T) {
+	sheetsMock := mocks.NewMockSheetsClient()
+	processor := NewOptimizedWarProcessor(mocks.NewMockTornClient(), sheetsMock, nil, nil, nil, nil, &app.Config{SpreadsheetID: "spreadsheet-id"}, nil)
+	ctx := context.Background()
+
+	activeWar := &app.WarResponse{}
+	activeWar.Wars.Ranked = &app.War{ID: 777, Start: time.Now().Add(-time.Minute).Unix()}
+
+	// ActiveWar -> NoWars is blocked; the war must go through PostWar first
+	processor.updateWarState(ctx, activeWar)
+	processor.updateWarState(ctx, &app.WarResponse{})
+
+	if len(sheetsMock.AppendStateTransitionCalls) != 1 {
+		t.Errorf("expected only the startup state to be logged, got %+v", sheetsMock.AppendStateTransitionCalls)
+	}
+}
+
+func TestOptimizedWarProcessor_EnemyChainBreak(t *testing.T) {
+	chainResponse := func(enemyChain int) *app.WarResponse {
+		resp := &app.WarResponse{}
+		resp.Wars.Ranked = &app.War{ID: 777, Factions: []app.Faction{
+			{ID: 100, Name: "Us", Chain: 20},
+			{ID: 200, Name: "Them", Chain: enemyChain},
+		}}
+		return resp
+	}
+
+	tests := []struct {
+		name       string
+		chains     []int
+		wantAlerts int
+	}{
+		{"chain dropped to zero", []int{150, 0}, 1},
+		{"chain still running", []int{150, 148}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sheetsMock := mocks.NewMockSheetsClient()
+			config := &app.Config{SpreadsheetID: "spreadsheet-id", OurFactionID: 100, AlertsSheet: true}
+			processor := NewOptimizedWarProcessor(mocks.NewMockTornClient(), sheetsMock, nil, nil, nil, nil, config, nil)
+
+			var alerts []app.Alert
+			for _, chain := range tt.chains {
+				alerts = append(alerts, processor.checkEnemyChainBreaks(context.Background(), chainResponse(chain), time.Now())...)
+			}
+
+			if len(alerts) != tt.wantAlerts {
+				t.Fatalf("expected %d alerts, got %+v", tt.wantAlerts, alerts)
+			}
+			if len(sheetsMock.AppendAlertCalls) != tt.wantAlerts {
+				t.Errorf("expected %d alerts appended to the alerts sheet, got %d", tt.wantAlerts, len(sheetsMock.AppendAlertCalls))
+			}
+			if tt.wantAlerts > 0 && (alerts[0].WarID != 777 || alerts[0].Type != app.AlertEnemyChainBroken) {
+				t.Errorf("unexpected alert: %+v", alerts[0])
+			}
+		})
+	}
+}
+
+func TestOptimizedWarProcessor_AttackPace(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	currentWar := &app.War{ID: 777, Start: now.Add(-3 * time.Hour).Unix()}
+
+	hits := func(direction string, count int) []app.AttackRecord {
+		records := make([]app.AttackRecord, count)
+		for i := range records {
+			records[i] = app.AttackRecord{
+				AttackID:  int64(i + 1),
+				Started:   now.Add(-time.Duration(i+1) * 30 * time.Second),
+				Direction: direction,
+			}
+		}
+		return records
+	}
+
+	tests := []struct {
+		name        string
+		fetchedFrom time.Time
+		records     []app.AttackRecord
+		wantAlert   bool
+	}{
+		{"too few attacks in window", now.Add(-2 * time.Hour), hits("Outgoing", 3), true},
+		{"healthy window", now.Add(-2 * time.Hour), hits("Outgoing", 25), false},
+		{"page full of incoming hits still alerts", now.Add(-2 * time.Hour), append(hits("Outgoing", 3), hits("Incoming", 100)...), true},
+		{"fetch not covering the window is not checked", now.Add(-30 * time.Minute), hits("Outgoing", 3), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tornMock := mocks.NewMockTornClient()
+			sheetsMock := mocks.NewMockSheetsClient()
+			config := &app.Config{SpreadsheetID: "spreadsheet-id", OurFactionID: 100, AlertsSheet: true, AttackPaceFloor: 20}
+			processor := NewOptimizedWarProcessor(tornMock, sheetsMock, nil, nil, nil, nil, config, nil)
+			processor.processor.recentFetches[777] = recentFetch{from: tt.fetchedFrom, to: now, records: tt.records}
+
+			alert := processor.checkAttackPace(context.Background(), currentWar, now)
+			if (alert != nil) != tt.wantAlert {
+				t.Fatalf("expected alert %v, got %+v", tt.wantAlert, alert)
+			}
+			if tornMock.GetFactionAttacksCalled {
+				t.Error("expected the pace measured from the attacks already fetched, got an extra attacks fetch")
+			}
+			if !tt.wantAlert {
+				return
+			}
+			if alert.Type != app.AlertLowAttackPace || alert.WarID != 777 {
+				t.Errorf("unexpected alert: %+v", alert)
+			}
+			if len(sheetsMock.AppendAlertCalls) != 1 {
+				t.Errorf("expected the alert appended to the alerts sheet, got %d appends", len(sheetsMock.AppendAlertCalls))
+			}
+
+			// The pace staying low does not repeat the warning
+			if again := processor.checkAttackPace(context.Background(), currentWar, now.Add(time.Minute)); again != nil {
+				t.Errorf("expected no repeated alert while the pace stays low, got %+v", again)
+			}
+		})
+	}
+}
+
+func TestOptimizedWarProcessor_TrackOwnFactionStatus(t *testing.T) {
+	tests := []struct {
+		name          string
+		trackOwn      bool
+		wantFactionID []int
+	}{
+		{"own faction tracked", true, []int{100, 200}},
+		{"enemy only", false, []int{200}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tornMock := mocks.NewMockTornClient()
+			tornMock.FactionBasicError = errors.New("faction unavailable") // only the requests matter
+			config := &app.Config{SpreadsheetID: "spreadsheet-id", OurFactionID: 100, TrackOwnFactionStatus: tt.trackOwn}
+			processor := NewOptimizedWarProcessor(tornMock, mocks.NewMockSheetsClient(), nil, nil, nil, nil, config, nil)
+
+			warResponse := &app.WarResponse{}
+			warResponse.Wars.Ranked = &app.War{ID: 777, Factions: []app.Faction{{ID: 100}, {ID: 200}}}
+			processor.processStateChanges(context.Background(), warResponse, war.WarStateInfo{})
+
+			requested := make(map[int]bool)
+			for _, factionID := range tornMock.GetFactionBasicCalls {
+				requested[factionID] = true
+			}
+			if len(requested) != len(tt.wantFactionID) {
+				t.Fatalf("expected factions %v processed, got requests for %v", tt.wantFactionID, tornMock.GetFactionBasicCalls)
+			}
+			for _, factionID := range tt.wantFactionID {
+				if !requested[factionID] {
+					t.Errorf("expected faction %d processed, got requests for %v", factionID, tornMock.GetFactionBasicCalls)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,198 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+
+	"torn_rw_stats/internal/app"
+	"torn_rw_stats/internal/domain/attack"
+	"torn_rw_stats/internal/sheets"
+
+	"github.com/rs/zerolog/log"
+)
+
+// PopulationProgress records, per war, how far an interrupted full population got, so
+// the next run fetches only the attacks still missing. Pagination runs newest first,
+// so the progress is the unix time of the newest attack not yet fetched. The record is
+// optionally persisted to a local JSON file so it survives restarts.
+type PopulationProgress struct {
+	path string // empty = in memory only
+
+	mu           sync.Mutex
+	missingUntil map[int]int64
+}
+
+// NewPopulationProgress creates population progress backed by the file at path,
+// loading any previously saved progress. A missing file starts empty; an empty path
+// keeps the progress in memory only. If the file cannot be read, the returned progress
+// still starts empty and saves to path, alongside the error.
+func NewPopulationProgress(path string) (*PopulationProgress, error) {
+	progress := &PopulationProgress{
+		path:         path,
+		missingUntil: make(map[int]int64),
+	}
+	if path == "" {
+		return progress, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return progress, nil
+	}
+	if err != nil {
+		return progress, fmt.Errorf("failed to read population progress file: %w", err)
+	}
+
+	var saved map[string]int64
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return progress, fmt.Errorf("failed to parse population progress file %s: %w", path, err)
+	}
+	for key, until := range saved {
+		warID, err := strconv.Atoi(key)
+		if err != nil {
+			progress.missingUntil = make(map[int]int64)
+			return progress, fmt.Errorf("invalid war ID %q in population progress file: %w", key, err)
+		}
+		progress.missingUntil[warID] = until
+	}
+
+	return progress, nil
+}
+
+// MissingUntil returns the unix time of the newest attack an interrupted population of
+// the war did not fetch, and whether the war's population is incomplete
+func (p *PopulationProgress) MissingUntil(warID int) (int64, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	until, ok := p.missingUntil[warID]
+	return until, ok
+}
+
+// MarkIncomplete records that the war's attacks up to until are still missing and
+// saves the progress file. The in-memory record is updated even if saving fails.
+func (p *PopulationProgress) MarkIncomplete(warID int, until int64) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.missingUntil[warID] = until
+	return p.save()
+}
+
+// MarkComplete records that the war is fully populated and saves the progress file
+func (p *PopulationProgress) MarkComplete(warID int) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, ok := p.missingUntil[warID]; !ok {
+		return nil
+	}
+	delete(p.missingUntil, warID)
+	return p.save()
+}
+
+// save writes the progress file; callers hold mu
+func (p *PopulationProgress) save() error {
+	if p.path == "" {
+		return nil
+	}
+
+	saved := make(map[string]int64, len(p.missingUntil))
+	for id, until := range p.missingUntil {
+		saved[strconv.Itoa(id)] = until
+	}
+	data, err := json.MarshalIndent(saved, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode population progress: %w", err)
+	}
+
+	if err := writeFileAtomically(p.path, data); err != nil {
+		return fmt.Errorf("failed to save population progress file: %w", err)
+	}
+	return nil
+}
+
+// populationWriter writes the pages of a full population to the records sheets as they
+// are fetched, saving the population progress after each, so a run killed part way
+// resumes from the oldest page written. Pagination runs newest first and each non-empty
+// page is held back until the next arrives, so the oldest page, which holds the first
+// blood, is only written once finish knows whether the fetch reached the war's start.
+type populationWriter struct {
+	wp          *WarProcessor
+	war         *app.War
+	sheetConfig *app.SheetConfig
+
+	earliest, latest    int64          // oldest and newest Started on the sheets, for the hit markers
+	pending             []app.Attack   // the oldest page fetched but not yet written
+	pendingMissingUntil int64          // the newest attack not fetched once pending was
+	written             map[int64]bool // attack IDs of every page written
+}
+
+// newPopulationWriter creates a page writer for a war whose records sheets hold existing
+func (wp *WarProcessor) newPopulationWriter(war *app.War, sheetConfig *app.SheetConfig, existing *sheets.RecordsInfo) *populationWriter {
+	return &populationWriter{
+		wp:          wp,
+		war:         war,
+		sheetConfig: sheetConfig,
+		earliest:    existing.EarliestTimestamp,
+		latest:      existing.LatestTimestamp,
+		written:     make(map[int64]bool),
+	}
+}
+
+// page is the torn.PageHandler writing the previously held back page and holding this one
+func (w *populationWriter) page(ctx context.Context, attacks []app.Attack, missingUntil int64) error {
+	if len(attacks) == 0 {
+		return nil
+	}
+	if err := w.flush(ctx, false); err != nil {
+		return err
+	}
+	w.pending = attacks
+	w.pendingMissingUntil = missingUntil
+	return nil
+}
+
+// finish writes the held back page once the fetch has ended; fromWarStart says the
+// fetch reached the war's start, so the page holds the first blood
+func (w *populationWriter) finish(ctx context.Context, fromWarStart bool) error {
+	return w.flush(ctx, fromWarStart)
+}
+
+// flush writes the held back page and records that only older attacks are missing
+func (w *populationWriter) flush(ctx context.Context, fromWarStart bool) error {
+	if len(w.pending) == 0 {
+		return nil
+	}
+
+	records := w.wp.attackService.ProcessAttacksIntoRecords(w.pending, w.war, w.wp.getOurFactionID(w.war))
+	w.wp.factionTags.ApplyFactionTags(ctx, records)
+	attack.MarkFirstAndLastHits(records, fromWarStart, w.earliest, w.latest)
+
+	// Later pages are older than the rows already written, so each is a backfill
+	if err := w.wp.sheetsClient.BackfillAttackRecords(ctx, w.wp.config.SpreadsheetID, w.sheetConfig, records); err != nil {
+		return fmt.Errorf("failed to write attack page: %w", err)
+	}
+
+	for _, record := range records {
+		w.written[record.AttackID] = true
+		started := record.Started.Unix()
+		if w.earliest == 0 || started < w.earliest {
+			w.earliest = started
+		}
+		w.latest = max(w.latest, started)
+	}
+	if err := w.wp.population.MarkIncomplete(w.war.ID, w.pendingMissingUntil); err != nil {
+		log.Warn().
+			Err(err).
+			Int("war_id", w.war.ID).
+			Msg("Failed to save population progress")
+	}
+
+	w.pending = nil
+	return nil
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ReprocessState records when each war was last processed, optionally persisting the
+// record to a local JSON file so the reprocess cooldown survives restarts.
+type ReprocessState struct {
+	path string // empty = in memory only
+
+	mu   sync.Mutex
+	last map[int]time.Time
+}
+
+// NewReprocessState creates a reprocess state backed by the file at path, loading any
+// previously saved timestamps. A missing file starts empty; an empty path keeps the
+// state in memory only. If the file cannot be read, the returned state still starts
+// empty and saves to path, alongside the error.
+func NewReprocessState(path string) (*ReprocessState, error) {
+	state := &ReprocessState{
+		path: path,
+		last: make(map[int]time.Time),
+	}
+	if path == "" {
+		return state, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return state, fmt.Errorf("failed to read reprocess state file: %w", err)
+	}
+
+	var saved map[string]int64
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return state, fmt.Errorf("failed to parse reprocess state file %s: %w", path, err)
+	}
+	for key, unix := range saved {
+		warID, err := strconv.Atoi(key)
+		if err != nil {
+			state.last = make(map[int]time.Time)
+			return state, fmt.Errorf("invalid war ID %q in reprocess state file: %w", key, err)
+		}
+		state.last[warID] = time.Unix(unix, 0)
+	}
+
+	return state, nil
+}
+
+// LastProcessed returns when a war was last processed, or the zero time if never
+func (s *ReprocessState) LastProcessed(warID int) time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.last[warID]
+}
+
+// MarkProcessed records that a war was processed at t and saves the state file. The
+// in-memory record is updated even if saving fails.
+func (s *ReprocessState) MarkProcessed(warID int, t time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.last[warID] = t
+	if s.path == "" {
+		return nil
+	}
+
+	saved := make(map[string]int64, len(s.last))
+	for id, processed := range s.last {
+		saved[strconv.Itoa(id)] = processed.Unix()
+	}
+	data, err := json.MarshalIndent(saved, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode reprocess state: %w", err)
+	}
+
+	if err := writeFileAtomically(s.path, data); err != nil {
+		return fmt.Errorf("failed to save reprocess state file: %w", err)
+	}
+	return nil
+}
+
+// writeFileAtomically writes data to a temporary file beside path and renames it into
+// place, so a crash never leaves a truncated file
+func writeFileAtomically(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+"_*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
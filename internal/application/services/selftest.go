@@ -0,0 +1,77 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"torn_rw_stats/internal/processing"
+	"torn_rw_stats/internal/torn"
+)
+
+// selfTestAttackWindow is how far back the sample attacks request looks
+const selfTestAttackWindow = time.Hour
+
+// Self-test check outcomes
+const (
+	SelfTestOK               = "OK"
+	SelfTestPermissionDenied = "PERMISSION DENIED"
+	SelfTestFailed           = "FAILED"
+)
+
+// SelfTestCheck is the outcome of one API call made by the self-test
+type SelfTestCheck struct {
+	Name string
+	Err  error
+}
+
+// Status classifies the check as OK, a permission problem with the API key, or any
+// other failure such as a network error
+func (c SelfTestCheck) Status() string {
+	switch {
+	case c.Err == nil:
+		return SelfTestOK
+	case torn.IsPermissionError(c.Err):
+		return SelfTestPermissionDenied
+	default:
+		return SelfTestFailed
+	}
+}
+
+// SelfTestReport collects the self-test checks and the faction the API key belongs to
+type SelfTestReport struct {
+	FactionID   int // Zero when our faction could not be fetched
+	FactionName string
+	Checks      []SelfTestCheck
+}
+
+// Passed reports whether every check succeeded
+func (r SelfTestReport) Passed() bool {
+	for _, check := range r.Checks {
+		if check.Err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// RunSelfTest calls each Torn endpoint the processor depends on once - our faction,
+// its wars and a sample of recent attacks - and reports which succeeded. Every check
+// runs even when an earlier one fails, so all missing key permissions show up at once.
+func RunSelfTest(ctx context.Context, client processing.TornClientInterface, now time.Time) SelfTestReport {
+	var report SelfTestReport
+
+	faction, err := client.GetOwnFaction(ctx)
+	if err == nil && faction != nil {
+		report.FactionID = faction.ID
+		report.FactionName = faction.Name
+	}
+	report.Checks = append(report.Checks, SelfTestCheck{Name: "GetOwnFaction", Err: err})
+
+	_, err = client.GetFactionWars(ctx)
+	report.Checks = append(report.Checks, SelfTestCheck{Name: "GetFactionWars", Err: err})
+
+	_, err = client.GetFactionAttacks(ctx, now.Add(-selfTestAttackWindow).Unix(), now.Unix())
+	report.Checks = append(report.Checks, SelfTestCheck{Name: "GetFactionAttacks", Err: err})
+
+	return report
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"torn_rw_stats/internal/app"
+	"torn_rw_stats/internal/processing/mocks"
+	"torn_rw_stats/internal/torn"
+)
+
+func TestRunSelfTest(t *testing.T) {
+	client := mocks.NewMockTornClient()
+	client.OwnFactionResponse = &app.FactionInfoResponse{ID: 1001, Name: "Our Faction"}
+	client.FactionWarsResponse = &app.WarResponse{}
+	client.FactionAttacksError = &torn.APIError{Code: 16, Message: "Access level of this key is not high enough"}
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	report := RunSelfTest(context.Background(), client, now)
+
+	if report.FactionID != 1001 || report.FactionName != "Our Faction" {
+		t.Errorf("expected faction 1001 Our Faction, got %d %q", report.FactionID, report.FactionName)
+	}
+	if report.Passed() {
+		t.Error("expected the self-test to fail with a permission error")
+	}
+
+	expected := map[string]string{
+		"GetOwnFaction":     SelfTestOK,
+		"GetFactionWars":    SelfTestOK,
+		"GetFactionAttacks": SelfTestPermissionDenied,
+	}
+	if len(report.Checks) != len(expected) {
+		t.Fatalf("expected %d checks, got %+v", len(expected), report.Checks)
+	}
+	for _, check := range report.Checks {
+		if check.Status() != expected[check.Name] {
+			t.Errorf("%s: expected %s, got %s (%v)", check.Name, expected[check.Name], check.Status(), check.Err)
+		}
+	}
+
+	if from := client.GetFactionAttacksCalledWith.From; from != now.Add(-time.Hour).Unix() {
+		t.Errorf("expected sample attacks from an hour ago, got %d", from)
+	}
+}
+
+func TestRunSelfTestNetworkFailure(t *testing.T) {
+	client := mocks.NewMockTornClient()
+	client.OwnFactionError = errors.New("failed to make request: connection refused")
+	client.FactionWarsError = errors.New("failed to make request: connection refused")
+	client.FactionAttacksResponse = &app.AttackResponse{}
+
+	report := RunSelfTest(context.Background(), client, time.Now())
+
+	if report.FactionID != 0 {
+		t.Errorf("expected no faction detected, got %d", report.FactionID)
+	}
+	statuses := []string{SelfTestFailed, SelfTestFailed, SelfTestOK}
+	for i, check := range report.Checks {
+		if check.Status() != statuses[i] {
+			t.Errorf("%s: expected %s, got %s", check.Name, statuses[i], check.Status())
+		}
+	}
+	if report.Passed() {
+		t.Error("expected the self-test to fail")
+	}
+}
@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"torn_rw_stats/internal/app"
@@ -17,11 +18,26 @@ import (
 // and recording member state changes (status, location, travel) to Google Sheets
 // and optionally to BigQuery.
 type StateTrackingService struct {
-	tornClient     processing.TornClientInterface
-	sheetsClient   processing.SheetsClientInterface
-	bigqueryClient processing.BigQueryClientInterface // nil = disabled
-	converter      *processing.StateRecordConverter
-	comparator     *processing.StateRecordComparator
+	tornClient        processing.TornClientInterface
+	sheetsClient      processing.SheetsClientInterface
+	bigqueryClient    processing.BigQueryClientInterface // nil = disabled
+	converter         *processing.StateRecordConverter
+	comparator        *processing.StateRecordComparator
+	detectRevives     bool
+	maxChanges        int                        // Per-cycle cap on state change rows written
+	maxRows           int                        // Data rows kept in the Changed States sheet; zero uses the default
+	concurrency       int                        // Factions fetched in parallel; zero uses the default
+	interFactionDelay time.Duration              // Wait between starting each faction's fetch; zero disables
+	location          *time.Location             // Timezone sheet timestamps are written in; nil is UTC
+	roster            map[string]map[string]bool // Member IDs per faction seen last cycle, for join detection
+	headerChecked     bool                       // An existing Changed States header row has been brought up to date
+}
+
+// changedStatesHeaders is the Changed States sheet's header row, columns A:K
+var changedStatesHeaders = []interface{}{
+	"Timestamp", "Member ID", "Member Name",
+	"Faction ID", "Faction Name", "Last Action Status", "Status Description",
+	"Status State", "Status Until", "Status Travel Type", "Event",
 }
 
 // NewStateTrackingService creates a new state tracking service without BigQuery.
@@ -31,6 +47,7 @@ func NewStateTrackingService(tornClient processing.TornClientInterface, sheetsCl
 		sheetsClient: sheetsClient,
 		converter:    processing.NewStateRecordConverter(),
 		comparator:   processing.NewStateRecordComparator(),
+		maxChanges:   state.DefaultMaxStateChangesPerCycle,
 	}
 }
 
@@ -47,9 +64,56 @@ func NewStateTrackingServiceWithBigQuery(
 		bigqueryClient: bqClient,
 		converter:      processing.NewStateRecordConverter(),
 		comparator:     processing.NewStateRecordComparator(),
+		maxChanges:     state.DefaultMaxStateChangesPerCycle,
 	}
 }
 
+// SetReviveDetection enables or disables marking early hospital exits as revives
+func (s *StateTrackingService) SetReviveDetection(enabled bool) {
+	s.detectRevives = enabled
+}
+
+// SetMaxStateChanges sets the per-cycle cap on state change rows written;
+// zero keeps the default cap
+func (s *StateTrackingService) SetMaxStateChanges(maxChanges int) {
+	if maxChanges == 0 {
+		maxChanges = state.DefaultMaxStateChangesPerCycle
+	}
+	s.maxChanges = maxChanges
+}
+
+// SetMaxStateChangeRows sets how many data rows the Changed States sheet keeps before
+// the oldest are pruned; zero keeps the default
+func (s *StateTrackingService) SetMaxStateChangeRows(maxRows int) {
+	s.maxRows = maxRows
+}
+
+// SetMaxConcurrentFactions sets how many factions are fetched in parallel;
+// zero keeps the default
+func (s *StateTrackingService) SetMaxConcurrentFactions(limit int) {
+	s.concurrency = limit
+}
+
+// SetInterFactionDelay sets how long to wait between starting each faction's fetch;
+// zero starts them back-to-back
+func (s *StateTrackingService) SetInterFactionDelay(delay time.Duration) {
+	s.interFactionDelay = delay
+}
+
+// SetDisplayLocation sets the timezone Timestamp and Status Until are written in; the
+// UTC offset is written alongside so rows read back the same instant. nil keeps UTC
+func (s *StateTrackingService) SetDisplayLocation(location *time.Location) {
+	s.location = location
+}
+
+// displayLocation returns the configured sheet timezone, defaulting to UTC
+func (s *StateTrackingService) displayLocation() *time.Location {
+	if s.location == nil {
+		return time.UTC
+	}
+	return s.location
+}
+
 // ProcessStateChanges executes the complete state tracking workflow
 func (s *StateTrackingService) ProcessStateChanges(ctx context.Context, spreadsheetID string, factionIDs []int) error {
 	currentTime := time.Now().UTC()
@@ -93,8 +157,24 @@ func (s *StateTrackingService) ProcessStateChanges(ctx context.Context, spreadsh
 	// Step 5: Compare states and find changes
 	updatedStateRecords := s.comparator.FindChangedStates(currentStateRecords, s.mapToSlice(previousStateRecords))
 
+	// Step 5b: Annotate early hospital exits as revives (if enabled)
+	if s.detectRevives {
+		updatedStateRecords = state.MarkRevivedStates(updatedStateRecords, previousStateRecords)
+	}
+
+	// Step 5c: Record members who left or joined since the last cycle. These go before
+	// the state changes they share a timestamp with, so a joining member's real state is
+	// the later row and stays their latest record.
+	membershipChanges := state.DetectMembershipChanges(currentStateRecords, s.comparator.GetLatestStateByMember(allPreviousStates), s.roster, currentTime)
+	if len(membershipChanges) > 0 {
+		log.Info().
+			Int("membership_changes", len(membershipChanges)).
+			Msg("Detected faction membership changes")
+		updatedStateRecords = append(membershipChanges, updatedStateRecords...)
+	}
+
 	// Step 6: Use domain function to determine action
-	decision := state.DetermineStateChangeAction(currentStateRecords, s.mapToSlice(previousStateRecords), updatedStateRecords)
+	decision := state.DetermineStateChangeAction(currentStateRecords, s.mapToSlice(previousStateRecords), updatedStateRecords, s.maxChanges)
 
 	log.Info().
 		Int("changed_states", decision.ChangeCount).
@@ -102,6 +182,14 @@ func (s *StateTrackingService) ProcessStateChanges(ctx context.Context, spreadsh
 		Str("reason", decision.Reason).
 		Msg("Determined state change action")
 
+	if decision.DroppedCount > 0 {
+		log.Warn().
+			Int("changed_states", decision.ChangeCount).
+			Int("max_changes", s.maxChanges).
+			Int("dropped", decision.DroppedCount).
+			Msg("State changes exceeded per-cycle cap - dropping excess changes")
+	}
+
 	// Step 7: Execute decision - add updated records to sheet (if decided)
 	if decision.ShouldWriteChanges {
 		if err := s.addStateRecords(ctx, spreadsheetID, decision.RecordsToWrite); err != nil {
@@ -111,37 +199,65 @@ func (s *StateTrackingService) ProcessStateChanges(ctx context.Context, spreadsh
 		log.Info().
 			Int("records_added", len(decision.RecordsToWrite)).
 			Msg("Successfully added state changes to Changed States sheet")
+
+		// Step 8: Drop the oldest rows once the sheet outgrows its limit. The records are
+		// already written, so a failure here only delays pruning to the next cycle.
+		if _, err := sheets.PruneOldestRows(ctx, s.sheetsClient, spreadsheetID, "Changed States", s.maxRows); err != nil {
+			log.Error().Err(err).Msg("Failed to prune Changed States sheet")
+		}
 	} else {
 		log.Info().Msg(decision.Reason)
 	}
 
+	// Step 9: Remember this cycle's members so the next cycle detects joins against them.
+	// A faction that failed to load keeps its previous roster.
+	if s.roster == nil {
+		s.roster = make(map[string]map[string]bool)
+	}
+	for factionID, members := range state.RosterByFaction(currentStateRecords) {
+		s.roster[factionID] = members
+	}
+
 	return nil
 }
 
 // getCurrentStateRecords retrieves current state for all specified factions
 func (s *StateTrackingService) getCurrentStateRecords(ctx context.Context, factionIDs []int, currentTime time.Time) ([]app.StateRecord, error) {
-	var allRecords []app.StateRecord
+	// Collect per faction so the combined records keep the input faction order
+	var mu sync.Mutex
+	recordsByFaction := make(map[int][]app.StateRecord, len(factionIDs))
 
-	for _, factionID := range factionIDs {
+	errs := processFactionsConcurrently(ctx, factionIDs, s.concurrency, s.interFactionDelay, func(factionID int) error {
 		// Get faction data
 		factionData, err := s.tornClient.GetFactionBasic(ctx, factionID)
 		if err != nil {
-			log.Error().
-				Err(err).
-				Int("faction_id", factionID).
-				Msg("Failed to get faction data - skipping")
-			continue
+			return err
 		}
 
 		// Convert faction data to state records
 		records := s.converter.ConvertFromFactionBasic(factionData, currentTime)
 
-		allRecords = append(allRecords, records...)
+		mu.Lock()
+		recordsByFaction[factionID] = records
+		mu.Unlock()
 
 		log.Debug().
 			Int("faction_id", factionID).
 			Int("member_count", len(records)).
 			Msg("Retrieved state records for faction")
+		return nil
+	})
+
+	for factionID, err := range errs {
+		log.Error().
+			Err(err).
+			Int("faction_id", factionID).
+			Msg("Failed to get faction data - skipping")
+	}
+
+	var allRecords []app.StateRecord
+	for _, factionID := range factionIDs {
+		allRecords = append(allRecords, recordsByFaction[factionID]...)
 	}
 
 	return allRecords, nil
@@ -171,25 +287,62 @@ func (s *StateTrackingService) ensureChangedStatesSheet(ctx context.Context, spr
 		}
 
 		// Initialize with headers
-		headers := [][]interface{}{
-			{
-				"Timestamp", "Member ID", "Member Name",
-				"Faction ID", "Faction Name", "Last Action Status", "Status Description",
-				"Status State", "Status Until", "Status Travel Type",
-			},
-		}
-
 		rangeSpec := fmt.Sprintf("%s!A1", sheetName)
-		if err := s.sheetsClient.UpdateRange(ctx, spreadsheetID, rangeSpec, headers); err != nil {
+		if err := s.sheetsClient.UpdateRange(ctx, spreadsheetID, rangeSpec, [][]interface{}{changedStatesHeaders}); err != nil {
 			return fmt.Errorf("failed to write Changed States headers: %w", err)
 		}
 
 		log.Info().Str("sheet_name", sheetName).Msg("Created and initialized Changed States sheet")
+		s.headerChecked = true
+		return nil
+	}
+
+	// Sheets created before a column was added keep their old header until rewritten,
+	// which only needs checking once per run
+	if !s.headerChecked {
+		if err := s.updateChangedStatesHeader(ctx, spreadsheetID, sheetName); err != nil {
+			return err
+		}
+		s.headerChecked = true
+	}
+
+	return nil
+}
+
+// updateChangedStatesHeader rewrites an existing Changed States sheet's header row
+// when it differs from the current columns
+func (s *StateTrackingService) updateChangedStatesHeader(ctx context.Context, spreadsheetID, sheetName string) error {
+	rangeSpec := fmt.Sprintf("%s!A1:K1", sheetName)
+	values, err := s.sheetsClient.ReadSheet(ctx, spreadsheetID, rangeSpec)
+	if err != nil {
+		return fmt.Errorf("failed to read Changed States headers: %w", err)
+	}
+
+	if len(values) > 0 && headerMatches(values[0], changedStatesHeaders) {
+		return nil
+	}
+
+	if err := s.sheetsClient.UpdateRange(ctx, spreadsheetID, rangeSpec, [][]interface{}{changedStatesHeaders}); err != nil {
+		return fmt.Errorf("failed to update Changed States headers: %w", err)
 	}
 
+	log.Info().Str("sheet_name", sheetName).Msg("Updated Changed States headers")
 	return nil
 }
 
+// headerMatches reports whether row holds exactly the expected header cells
+func headerMatches(row, expected []interface{}) bool {
+	if len(row) != len(expected) {
+		return false
+	}
+	for i := range expected {
+		if fmt.Sprint(row[i]) != fmt.Sprint(expected[i]) {
+			return false
+		}
+	}
+	return true
+}
+
 // readChangedStatesSheet reads all records from the Changed States sheet
 func (s *StateTrackingService) readChangedStatesSheet(ctx context.Context, spreadsheetID string) ([]app.StateRecord, error) {
 	sheetName := "Changed States"
@@ -235,7 +388,7 @@ func (s *StateTrackingService) addStateRecords(ctx context.Context, spreadsheetI
 		rows = append(rows, row)
 	}
 
-	rangeSpec := fmt.Sprintf("%s!A:J", sheetName)
+	rangeSpec := fmt.Sprintf("%s!A:K", sheetName)
 	if err := s.sheetsClient.AppendRows(ctx, spreadsheetID, rangeSpec, rows); err != nil {
 		return err
 	}
@@ -255,18 +408,18 @@ func (s *StateTrackingService) addStateRecords(ctx context.Context, spreadsheetI
 // convertStateRecordToRow converts a StateRecord into spreadsheet row format
 func (s *StateTrackingService) convertStateRecordToRow(record app.StateRecord) []interface{} {
 	// Format timestamp as human-readable string
-	timestampStr := record.Timestamp.UTC().Format("2006-01-02 15:04:05")
+	timestampStr := sheets.FormatSheetTime(record.Timestamp, s.displayLocation())
 
 	// Handle StatusUntil - only include if it's a meaningful time (not zero time)
 	var statusUntilStr string
 	if !record.StatusUntil.IsZero() {
-		statusUntilStr = record.StatusUntil.UTC().Format("2006-01-02 15:04:05")
+		statusUntilStr = sheets.FormatSheetTime(record.StatusUntil, s.displayLocation())
 	}
 
 	return []interface{}{
 		timestampStr, record.MemberID, record.MemberName,
 		record.FactionID, record.FactionName, record.LastActionStatus, record.StatusDescription,
-		record.StatusState, statusUntilStr, record.StatusTravelType,
+		record.StatusState, statusUntilStr, record.StatusTravelType, record.Event,
 	}
 }
 
@@ -276,7 +429,7 @@ func (s *StateTrackingService) convertRowToStateRecord(row []interface{}) (app.S
 
 	// Parse timestamp (now a string) using type-safe Cell
 	timestampStr := sheets.NewCell(row[0]).String()
-	timestamp, err := time.Parse("2006-01-02 15:04:05", timestampStr)
+	timestamp, err := sheets.ParseSheetTime(timestampStr)
 	if err != nil {
 		return record, fmt.Errorf("invalid timestamp %q: %w", timestampStr, err)
 	}
@@ -299,7 +452,7 @@ func (s *StateTrackingService) convertRowToStateRecord(row []interface{}) (app.S
 	if len(row) > 8 {
 		statusUntilStr := sheets.NewCell(row[8]).String()
 		if statusUntilStr != "" {
-			if statusUntil, err := time.Parse("2006-01-02 15:04:05", statusUntilStr); err == nil {
+			if statusUntil, err := sheets.ParseSheetTime(statusUntilStr); err == nil {
 				record.StatusUntil = statusUntil.UTC()
 			}
 		}
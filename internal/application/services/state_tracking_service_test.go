@@ -3,7 +3,10 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
+	"slices"
 	"testing"
+	"time"
 
 	"torn_rw_stats/internal/app"
 	"torn_rw_stats/internal/processing/mocks"
@@ -131,3 +134,304 @@ func TestStateTrackingService_BigQueryNotCalledForEmptyFactions(t *testing.T) {
 		t.Error("expected BigQuery InsertStateRecords NOT to be called for empty faction list")
 	}
 }
+
+func TestStateTrackingService_RevivedEventOnEarlyHospitalExit(t *testing.T) {
+	ctx := context.Background()
+
+	tornMock := mocks.NewMockTornClient()
+	tornMock.FactionBasicResponse = factionBasicWithMember(100, "42", "Player1", "Okay", "Okay")
+
+	sheetsMock := mocks.NewMockSheetsClient()
+	sheetsMock.SheetExistsResponse = true
+	// Previously hospitalized with a release time far in the future
+	expectedRelease := time.Now().UTC().Add(2 * time.Hour).Format("2006-01-02 15:04:05")
+	sheetsMock.ReadSheetResponse = [][]interface{}{
+		{"2026-01-01 00:00:00", "42", "Player1", "100", "TestFaction", "Online", "In hospital for 2 hrs", "Hospital", expectedRelease, ""},
+	}
+
+	bqMock := mocks.NewMockBigQueryClient()
+
+	svc := NewStateTrackingServiceWithBigQuery(tornMock, sheetsMock, bqMock)
+	svc.SetReviveDetection(true)
+	if err := svc.ProcessStateChanges(ctx, "spreadsheet-id", []int{100}); err != nil {
+		t.Fatalf("ProcessStateChanges() returned unexpected error: %v", err)
+	}
+
+	if len(bqMock.InsertStateRecordsCalledWith) != 1 {
+		t.Fatalf("expected 1 state change, got %d", len(bqMock.InsertStateRecordsCalledWith))
+	}
+	if event := bqMock.InsertStateRecordsCalledWith[0].Event; event != "Revived" {
+		t.Errorf("expected Revived event, got %q", event)
+	}
+}
+
+func TestStateTrackingService_NoRevivedEventOnNormalRelease(t *testing.T) {
+	ctx := context.Background()
+
+	tornMock := mocks.NewMockTornClient()
+	tornMock.FactionBasicResponse = factionBasicWithMember(100, "42", "Player1", "Okay", "Okay")
+
+	sheetsMock := mocks.NewMockSheetsClient()
+	sheetsMock.SheetExistsResponse = true
+	// Release time has already passed
+	expectedRelease := time.Now().UTC().Add(-time.Minute).Format("2006-01-02 15:04:05")
+	sheetsMock.ReadSheetResponse = [][]interface{}{
+		{"2026-01-01 00:00:00", "42", "Player1", "100", "TestFaction", "Online", "In hospital for 1 min", "Hospital", expectedRelease, ""},
+	}
+
+	bqMock := mocks.NewMockBigQueryClient()
+
+	svc := NewStateTrackingServiceWithBigQuery(tornMock, sheetsMock, bqMock)
+	svc.SetReviveDetection(true)
+	if err := svc.ProcessStateChanges(ctx, "spreadsheet-id", []int{100}); err != nil {
+		t.Fatalf("ProcessStateChanges() returned unexpected error: %v", err)
+	}
+
+	if len(bqMock.InsertStateRecordsCalledWith) != 1 {
+		t.Fatalf("expected 1 state change, got %d", len(bqMock.InsertStateRecordsCalledWith))
+	}
+	if event := bqMock.InsertStateRecordsCalledWith[0].Event; event != "" {
+		t.Errorf("expected no event for a normal release, got %q", event)
+	}
+}
+
+func TestStateTrackingService_CapLimitsWrites(t *testing.T) {
+	ctx := context.Background()
+
+	tornMock := mocks.NewMockTornClient()
+	tornMock.FactionBasicResponse = factionBasicWithMember(100, "1", "Player1", "Okay", "Okay")
+	for _, id := range []string{"2", "3", "4"} {
+		tornMock.FactionBasicResponse.Members[id] = app.FactionMember{
+			Name:   "Player" + id,
+			Status: app.MemberStatus{State: "Okay", Description: "Okay"},
+		}
+	}
+
+	sheetsMock := mocks.NewMockSheetsClient()
+	sheetsMock.SheetExistsResponse = true
+	// No previous records → every member is a change
+
+	bqMock := mocks.NewMockBigQueryClient()
+
+	svc := NewStateTrackingServiceWithBigQuery(tornMock, sheetsMock, bqMock)
+	svc.SetMaxStateChanges(2)
+	if err := svc.ProcessStateChanges(ctx, "spreadsheet-id", []int{100}); err != nil {
+		t.Fatalf("ProcessStateChanges() returned unexpected error: %v", err)
+	}
+
+	if len(bqMock.InsertStateRecordsCalledWith) != 2 {
+		t.Errorf("expected writes capped at 2 records, got %d", len(bqMock.InsertStateRecordsCalledWith))
+	}
+}
+
+func TestStateTrackingService_MemberLeavingRecordsMembershipChange(t *testing.T) {
+	ctx := context.Background()
+
+	tornMock := mocks.NewMockTornClient()
+	tornMock.FactionBasicResponse = factionBasicWithMember(100, "42", "Player1", "Okay", "Okay")
+
+	sheetsMock := mocks.NewMockSheetsClient()
+	sheetsMock.SheetExistsResponse = true
+	// Player2 was stored last cycle but is no longer in the faction; Player1 is unchanged
+	sheetsMock.ReadSheetResponse = [][]interface{}{
+		{"2026-01-01 00:00:00", "42", "Player1", "100", "TestFaction", "Online", "Okay", "Okay", "", ""},
+		{"2026-01-01 00:00:00", "43", "Player2", "100", "TestFaction", "Offline", "Okay", "Okay", "", ""},
+	}
+
+	bqMock := mocks.NewMockBigQueryClient()
+
+	svc := NewStateTrackingServiceWithBigQuery(tornMock, sheetsMock, bqMock)
+	if err := svc.ProcessStateChanges(ctx, "spreadsheet-id", []int{100}); err != nil {
+		t.Fatalf("ProcessStateChanges() returned unexpected error: %v", err)
+	}
+
+	if len(bqMock.InsertStateRecordsCalledWith) != 1 {
+		t.Fatalf("expected 1 membership change, got %d: %+v", len(bqMock.InsertStateRecordsCalledWith), bqMock.InsertStateRecordsCalledWith)
+	}
+	record := bqMock.InsertStateRecordsCalledWith[0]
+	if record.MemberID != "43" || record.StatusState != "Left" || record.Event != "Membership" {
+		t.Errorf("expected Left membership record for member 43, got %+v", record)
+	}
+}
+
+func TestStateTrackingService_JoinsComparedAgainstPreviousCycle(t *testing.T) {
+	ctx := context.Background()
+
+	// Player44's rows were pruned, so only Player42 has a stored record
+	tornMock := mocks.NewMockTornClient()
+	tornMock.FactionBasicResponse = factionBasicWithMember(100, "42", "Player42", "Okay", "Okay")
+	tornMock.FactionBasicResponse.Members["44"] = app.FactionMember{Name: "Player44", Status: app.MemberStatus{State: "Okay", Description: "Okay"}}
+
+	client := &changedStatesSheetsClient{MockSheetsClient: mocks.NewMockSheetsClient(), api: newMemorySheetsAPI()}
+	client.api.rows["Changed States"] = [][]interface{}{
+		changedStatesHeaders,
+		{"2026-01-01 00:00:00", "42", "Player42", "100", "TestFaction", "Online", "Okay", "Okay", "", "", ""},
+	}
+
+	svc := NewStateTrackingService(tornMock, client)
+	if err := svc.ProcessStateChanges(ctx, "spreadsheet-id", []int{100}); err != nil {
+		t.Fatalf("ProcessStateChanges() returned unexpected error: %v", err)
+	}
+
+	// Player43 joins; Player44 was already a member last cycle
+	tornMock.FactionBasicResponse.Members["43"] = app.FactionMember{Name: "Player43", Status: app.MemberStatus{State: "Okay", Description: "Okay"}}
+	if err := svc.ProcessStateChanges(ctx, "spreadsheet-id", []int{100}); err != nil {
+		t.Fatalf("ProcessStateChanges() returned unexpected error: %v", err)
+	}
+	if err := svc.ProcessStateChanges(ctx, "spreadsheet-id", []int{100}); err != nil {
+		t.Fatalf("ProcessStateChanges() returned unexpected error: %v", err)
+	}
+
+	var written []string
+	for _, row := range client.api.rows["Changed States"][2:] {
+		written = append(written, fmt.Sprintf("%v:%v", row[1], row[7]))
+	}
+	// The Joined row precedes Player43's state, which stays their latest record, so the
+	// third cycle writes nothing
+	expected := []string{"44:Okay", "43:Joined", "43:Okay"}
+	if !slices.Equal(written, expected) {
+		t.Errorf("expected rows %v, got %v", expected, written)
+	}
+}
+
+func TestStateTrackingService_DisplayLocation(t *testing.T) {
+	location, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+
+	svc := NewStateTrackingService(mocks.NewMockTornClient(), mocks.NewMockSheetsClient())
+	svc.SetDisplayLocation(location)
+
+	record := app.StateRecord{
+		Timestamp:   time.Date(2026, 1, 15, 17, 0, 0, 0, time.UTC),
+		StatusUntil: time.Date(2026, 7, 15, 17, 0, 0, 0, time.UTC),
+		MemberID:    "42",
+	}
+
+	row := svc.convertStateRecordToRow(record)
+	if row[0] != "2026-01-15 12:00:00 -05:00" || row[8] != "2026-07-15 13:00:00 -04:00" {
+		t.Errorf("expected New York times with standard and daylight offsets, got %v and %v", row[0], row[8])
+	}
+
+	// Rows read back the same instant after the timezone changes, and so does the
+	// status v2 reader
+	svc.SetDisplayLocation(nil)
+	parsed, err := svc.convertRowToStateRecord(row)
+	if err != nil {
+		t.Fatalf("convertRowToStateRecord() returned unexpected error: %v", err)
+	}
+	if !parsed.Timestamp.Equal(record.Timestamp) || !parsed.StatusUntil.Equal(record.StatusUntil) {
+		t.Errorf("expected round trip to %v/%v, got %v/%v", record.Timestamp, record.StatusUntil, parsed.Timestamp, parsed.StatusUntil)
+	}
+
+	statusParsed, err := (&StatusV2Service{}).parseStateRecordFromRow(row)
+	if err != nil {
+		t.Fatalf("parseStateRecordFromRow() returned unexpected error: %v", err)
+	}
+	if !statusParsed.Timestamp.Equal(record.Timestamp) || !statusParsed.StatusUntil.Equal(record.StatusUntil) {
+		t.Errorf("expected status v2 to read %v/%v, got %v/%v", record.Timestamp, record.StatusUntil, statusParsed.Timestamp, statusParsed.StatusUntil)
+	}
+
+	// Rows written before offsets were added are UTC
+	legacy, err := svc.convertRowToStateRecord([]interface{}{"2026-01-15 17:00:00", "42", "", "", "", "", "", "", ""})
+	if err != nil {
+		t.Fatalf("convertRowToStateRecord() returned unexpected error: %v", err)
+	}
+	if !legacy.Timestamp.Equal(record.Timestamp) {
+		t.Errorf("expected legacy row at %v, got %v", record.Timestamp, legacy.Timestamp)
+	}
+}
+
+// changedStatesSheetsClient keeps the Changed States sheet in memory, leaving every
+// other call to the mock
+type changedStatesSheetsClient struct {
+	*mocks.MockSheetsClient
+	api *memorySheetsAPI
+}
+
+func (c *changedStatesSheetsClient) SheetExists(ctx context.Context, spreadsheetID, sheetName string) (bool, error) {
+	return c.api.SheetExists(ctx, spreadsheetID, sheetName)
+}
+
+func (c *changedStatesSheetsClient) ReadSheet(ctx context.Context, spreadsheetID, range_ string) ([][]interface{}, error) {
+	return c.api.ReadSheet(ctx, spreadsheetID, range_)
+}
+
+func (c *changedStatesSheetsClient) UpdateRange(ctx context.Context, spreadsheetID, range_ string, values [][]interface{}) error {
+	return c.api.UpdateRange(ctx, spreadsheetID, range_, values)
+}
+
+func (c *changedStatesSheetsClient) ClearRange(ctx context.Context, spreadsheetID, range_ string) error {
+	return c.api.ClearRange(ctx, spreadsheetID, range_)
+}
+
+func (c *changedStatesSheetsClient) AppendRows(ctx context.Context, spreadsheetID, range_ string, rows [][]interface{}) error {
+	return c.api.AppendRows(ctx, spreadsheetID, range_, rows)
+}
+
+func (c *changedStatesSheetsClient) DeleteRows(ctx context.Context, spreadsheetID, sheetName string, startRow, count int) error {
+	return c.api.DeleteRows(ctx, spreadsheetID, sheetName, startRow, count)
+}
+
+func TestStateTrackingService_PrunesChangedStatesSheet(t *testing.T) {
+	ctx := context.Background()
+
+	tornMock := mocks.NewMockTornClient()
+	tornMock.FactionBasicResponse = factionBasicWithMember(100, "42", "Player1", "Okay", "Okay")
+
+	client := &changedStatesSheetsClient{MockSheetsClient: mocks.NewMockSheetsClient(), api: newMemorySheetsAPI()}
+	client.api.rows["Changed States"] = [][]interface{}{
+		{"Timestamp", "Member ID", "Member Name", "Faction ID", "Faction Name", "Last Action Status",
+			"Status Description", "Status State", "Status Until", "Status Travel Type", "Event"},
+		{"2026-01-01 00:00:00", "42", "Player1", "100", "TestFaction", "Online", "Okay", "Okay", "", ""},
+		{"2026-01-01 01:00:00", "42", "Player1", "100", "TestFaction", "Online", "Traveling", "Traveling", "", ""},
+		{"2026-01-01 02:00:00", "42", "Player1", "100", "TestFaction", "Online", "In hospital", "Hospital", "", ""},
+	}
+
+	svc := NewStateTrackingService(tornMock, client)
+	svc.SetMaxStateChangeRows(2)
+	if err := svc.ProcessStateChanges(ctx, "spreadsheet-id", []int{100}); err != nil {
+		t.Fatalf("ProcessStateChanges() returned unexpected error: %v", err)
+	}
+
+	// The appended Okay row takes the sheet to 4 data rows, so the 2 oldest are dropped
+	rows := client.api.rows["Changed States"]
+	if len(rows) != 3 {
+		t.Fatalf("expected header plus 2 rows after pruning, got %d rows: %v", len(rows), rows)
+	}
+	if rows[0][0] != "Timestamp" || rows[1][7] != "Hospital" || rows[2][7] != "Okay" {
+		t.Errorf("expected header, the Hospital row and the new Okay row, got %v", rows)
+	}
+}
+
+func TestStateTrackingService_UpdatesOutdatedChangedStatesHeader(t *testing.T) {
+	ctx := context.Background()
+
+	tornMock := mocks.NewMockTornClient()
+	tornMock.FactionBasicResponse = factionBasicWithMember(100, "42", "Player1", "Okay", "Okay")
+
+	// A sheet created before the Event column was added
+	client := &changedStatesSheetsClient{MockSheetsClient: mocks.NewMockSheetsClient(), api: newMemorySheetsAPI()}
+	client.api.rows["Changed States"] = [][]interface{}{
+		{"Timestamp", "Member ID", "Member Name", "Faction ID", "Faction Name", "Last Action Status",
+			"Status Description", "Status State", "Status Until", "Status Travel Type"},
+		{"2026-01-01 00:00:00", "42", "Player1", "100", "TestFaction", "Online", "In hospital", "Hospital", "", ""},
+	}
+
+	svc := NewStateTrackingService(tornMock, client)
+	if err := svc.ProcessStateChanges(ctx, "spreadsheet-id", []int{100}); err != nil {
+		t.Fatalf("ProcessStateChanges() returned unexpected error: %v", err)
+	}
+
+	rows := client.api.rows["Changed States"]
+	if len(rows) != 3 {
+		t.Fatalf("expected header, the existing row and the new row, got %d rows: %v", len(rows), rows)
+	}
+	if len(rows[0]) != 11 || rows[0][10] != "Event" {
+		t.Errorf("expected the header to gain the Event column, got %v", rows[0])
+	}
+	if rows[1][7] != "Hospital" || rows[2][7] != "Okay" {
+		t.Errorf("expected existing data rows to be kept, got %v", rows)
+	}
+}
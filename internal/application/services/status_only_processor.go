@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"torn_rw_stats/internal/app"
+	"torn_rw_stats/internal/processing"
+
+	"github.com/rs/zerolog/log"
+)
+
+// StatusOnlyProcessor tracks a single faction's status for lightweight deployments,
+// running state change tracking and Status v2 without any war processing.
+type StatusOnlyProcessor struct {
+	stateTracker      *StateTrackingService
+	statusV2Processor *StatusV2Processor
+	factionID         int
+	spreadsheetID     string
+	updateInterval    time.Duration
+}
+
+// NewStatusOnlyProcessor creates a processor that only tracks the given faction's status.
+// bqClient may be nil to disable BigQuery integration.
+func NewStatusOnlyProcessor(
+	tornClient processing.TornClientInterface,
+	sheetsClient processing.SheetsClientInterface,
+	config *app.Config,
+	bqClient processing.BigQueryClientInterface,
+	factionID int,
+) *StatusOnlyProcessor {
+	stateTracker := NewStateTrackingServiceWithBigQuery(tornClient, sheetsClient, bqClient)
+	stateTracker.SetReviveDetection(config.DetectRevives)
+	stateTracker.SetMaxStateChanges(config.MaxStateChangesPerCycle)
+	stateTracker.SetMaxStateChangeRows(config.MaxStateChangeRows)
+	stateTracker.SetMaxConcurrentFactions(config.MaxConcurrentFactions)
+	stateTracker.SetInterFactionDelay(config.InterFactionDelay)
+	stateTracker.SetDisplayLocation(app.DisplayLocation(config.DisplayTimezone))
+
+	return &StatusOnlyProcessor{
+		stateTracker:      stateTracker,
+		statusV2Processor: NewStatusV2Processor(tornClient, sheetsClient, config),
+		factionID:         factionID,
+		spreadsheetID:     config.SpreadsheetID,
+		updateInterval:    config.UpdateInterval,
+	}
+}
+
+// ProcessStatus runs one status-only cycle: state changes followed by Status v2
+func (p *StatusOnlyProcessor) ProcessStatus(ctx context.Context) error {
+	log.Debug().
+		Int("faction_id", p.factionID).
+		Msg("Processing faction status only")
+
+	factionIDs := []int{p.factionID}
+
+	if err := p.stateTracker.ProcessStateChanges(ctx, p.spreadsheetID, factionIDs); err != nil {
+		return fmt.Errorf("failed to process state changes: %w", err)
+	}
+
+	if err := p.statusV2Processor.ProcessStatusV2ForFaction(ctx, p.spreadsheetID, p.factionID, p.updateInterval); err != nil {
+		return fmt.Errorf("failed to process Status v2: %w", err)
+	}
+
+	return nil
+}
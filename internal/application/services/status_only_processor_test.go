@@ -0,0 +1,41 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"torn_rw_stats/internal/app"
+	"torn_rw_stats/internal/processing/mocks"
+)
+
+func TestStatusOnlyProcessor_SkipsWarProcessing(t *testing.T) {
+	tornMock := mocks.NewMockTornClient()
+	tornMock.OwnFactionResponse = &app.FactionInfoResponse{ID: 100, Name: "TestFaction"}
+	tornMock.FactionBasicResponse = factionBasicWithMember(200, "42", "Player1", "Okay", "Okay")
+
+	sheetsMock := mocks.NewMockSheetsClient()
+	sheetsMock.SheetExistsResponse = true
+	sheetsMock.EnsureStatusV2SheetResponse = "Status v2 - 200"
+
+	config := &app.Config{SpreadsheetID: "spreadsheet-id", UpdateInterval: time.Minute}
+	processor := NewStatusOnlyProcessor(tornMock, sheetsMock, config, nil, 200)
+
+	if err := processor.ProcessStatus(context.Background()); err != nil {
+		t.Fatalf("ProcessStatus() returned unexpected error: %v", err)
+	}
+
+	if !tornMock.GetFactionBasicCalled || tornMock.GetFactionBasicCalledWithID != 200 {
+		t.Errorf("expected faction 200 status to be fetched, got called=%v id=%d",
+			tornMock.GetFactionBasicCalled, tornMock.GetFactionBasicCalledWithID)
+	}
+	if tornMock.GetFactionWarsCalled {
+		t.Error("expected wars not to be fetched in status-only mode")
+	}
+	if tornMock.GetFactionAttacksCalled {
+		t.Error("expected attacks not to be fetched in status-only mode")
+	}
+	if sheetsMock.EnsureWarSheetsCalled || sheetsMock.UpdateWarSummaryCalled || sheetsMock.UpdateAttackRecordsCalled {
+		t.Error("expected no war sheets to be touched in status-only mode")
+	}
+}
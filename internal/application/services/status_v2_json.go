@@ -13,9 +13,25 @@ func (s *StatusV2Service) ConvertToJSON(records []app.StatusV2Record, factionNam
 	locations := status.GroupRecordsByLocation(records)
 
 	return app.StatusV2JSON{
-		Faction:   factionName,
-		Updated:   currentTime.Format(time.RFC3339),
-		Interval:  int(updateInterval.Seconds()),
-		Locations: locations,
+		SchemaVersion: app.StatusV2SchemaVersion,
+		GeneratedBy:   app.BuildIdentifier(),
+		Faction:       factionName,
+		Updated:       currentTime.Format(time.RFC3339),
+		Interval:      int(updateInterval.Seconds()),
+		Locations:     locations,
+	}
+}
+
+// ConvertToDeltaJSON builds the delta export listing only members whose status changed
+// between the previous and current exports, plus members that were removed
+func (s *StatusV2Service) ConvertToDeltaJSON(previous, current app.StatusV2JSON) app.StatusV2DeltaJSON {
+	changed, removed := status.CalculateLocationDelta(previous.Locations, current.Locations)
+
+	return app.StatusV2DeltaJSON{
+		Faction:  current.Faction,
+		Updated:  current.Updated,
+		Interval: current.Interval,
+		Changed:  changed,
+		Removed:  removed,
 	}
 }
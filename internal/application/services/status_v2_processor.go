@@ -3,44 +3,105 @@ package services
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
 	"encoding/json"
 	"fmt"
+	"io"
+	"strconv"
+	"sync"
 	"time"
 
 	"torn_rw_stats/internal/app"
 	"torn_rw_stats/internal/deployment"
+	"torn_rw_stats/internal/domain/status"
+	"torn_rw_stats/internal/export"
 	"torn_rw_stats/internal/processing"
 
 	"github.com/rs/zerolog/log"
 )
 
+// statusDeployer uploads exported JSON to the remote server
+type statusDeployer interface {
+	DeployData(data io.Reader, size int64, filename string) error
+}
+
 // StatusV2Processor handles Status v2 sheet processing, converting faction member
 // states to status sheets and JSON exports for external consumption.
 type StatusV2Processor struct {
-	tornClient   processing.TornClientInterface
-	sheetsClient processing.SheetsClientInterface
-	service      *StatusV2Service
-	ourFactionID int // cached faction ID, fetched via API
-	deployer     *deployment.SSHDeployer
+	tornClient        processing.TornClientInterface
+	sheetsClient      processing.SheetsClientInterface
+	service           *StatusV2Service
+	ourFactionID      int                          // cached faction ID, fetched via API
+	deployer          statusDeployer               // nil = remote deployment disabled
+	deployed          map[string][sha256.Size]byte // content hash last deployed per remote file
+	deployedMu        sync.Mutex                   // guards deployed across concurrent factions
+	deltaExport       bool
+	archiver          *export.JSONArchiver      // nil = archiving disabled
+	lastExports       map[int]app.StatusV2JSON  // last exported snapshot per faction, for delta export
+	notesSheet        string                    // empty = member notes disabled
+	memberNotes       map[string]string         // member ID -> note, reloaded each cycle before factions run
+	exportsMu         sync.Mutex                // guards lastExports across concurrent factions
+	excluded          map[int]bool              // member IDs left out of sheets and JSON exports
+	concurrency       int                       // factions processed in parallel
+	interFactionDelay time.Duration             // wait between starting each faction; zero disables
+	minEnemyLevel     int                       // enemy members below this level are left out; zero keeps all
+	targetWeights     app.TargetPriorityWeights // scoring weights for the target priority sheet
+	trackDrift        bool                      // append countdown drift to the countdown drift sheet
+	driftMu           sync.Mutex                // serializes appends to the shared countdown drift sheet
+	history           bool                      // also append each update to the status history sheet
+	historyMaxRows    int                       // rows kept in status history sheets; zero uses the default
 }
 
 // NewStatusV2Processor creates a new Status v2 processor
-func NewStatusV2Processor(tornClient processing.TornClientInterface, sheetsClient processing.SheetsClientInterface, deployURL string) *StatusV2Processor {
-	var deployer *deployment.SSHDeployer
-	if deployURL != "" {
-		deployer = deployment.NewSSHDeployer(deployURL)
+func NewStatusV2Processor(tornClient processing.TornClientInterface, sheetsClient processing.SheetsClientInterface, config *app.Config) *StatusV2Processor {
+	var deployer statusDeployer
+	if config.DeployURL != "" {
+		sshDeployer := deployment.NewSSHDeployer(config.DeployURL)
+		sshDeployer.SetRetries(config.DeployRetries)
+		deployer = sshDeployer
+	}
+
+	excluded := make(map[int]bool, len(config.ExcludedMemberIDs))
+	for _, memberID := range config.ExcludedMemberIDs {
+		excluded[memberID] = true
+	}
+
+	var archiver *export.JSONArchiver
+	if config.JSONArchiveDir != "" {
+		archiver = export.NewJSONArchiver(config.JSONArchiveDir, config.JSONArchiveRetention)
 	}
 
+	service := NewStatusV2Service(sheetsClient)
+	service.travelTimeService.SetMemberReductions(config.MemberTravelReductions)
+	service.locationService.SetLocationCacheTTL(config.LocationCacheTTL)
+
 	return &StatusV2Processor{
-		tornClient:   tornClient,
-		sheetsClient: sheetsClient,
-		service:      NewStatusV2Service(sheetsClient),
-		ourFactionID: 0, // will be fetched via API when needed
-		deployer:     deployer,
+		tornClient:        tornClient,
+		sheetsClient:      sheetsClient,
+		service:           service,
+		ourFactionID:      config.OurFactionID, // zero is fetched via API when needed
+		deployer:          deployer,
+		deployed:          make(map[string][sha256.Size]byte),
+		deltaExport:       config.StatusDeltaExport,
+		archiver:          archiver,
+		lastExports:       make(map[int]app.StatusV2JSON),
+		notesSheet:        config.MemberNotesSheet,
+		excluded:          excluded,
+		concurrency:       config.MaxConcurrentFactions,
+		interFactionDelay: config.InterFactionDelay,
+		minEnemyLevel:     config.MinEnemyLevel,
+		targetWeights: app.TargetPriorityWeights{
+			Level:  config.TargetLevelWeight,
+			Online: config.TargetOnlineWeight,
+			Idle:   config.TargetIdleWeight,
+		},
+		trackDrift:     config.TrackCountdownDrift,
+		history:        config.StatusV2History,
+		historyMaxRows: config.StatusHistoryMaxRows,
 	}
 }
 
-// ensureOurFactionID fetches and caches our faction ID if not already set
+// ensureOurFactionID fetches and caches our faction ID if not already set or configured
 func (p *StatusV2Processor) ensureOurFactionID(ctx context.Context) error {
 	if p.ourFactionID == 0 {
 		log.Debug().Msg("StatusV2Processor: Fetching our faction ID from API")
@@ -72,23 +133,52 @@ func (p *StatusV2Processor) ProcessStatusV2ForFactions(ctx context.Context, spre
 		Int("our_faction_id", p.ourFactionID).
 		Msg("Processing Status v2 for factions")
 
-	for _, factionID := range factionIDs {
+	p.loadMemberNotes(ctx, spreadsheetID)
+
+	errs := processFactionsConcurrently(ctx, factionIDs, p.concurrency, p.interFactionDelay, func(factionID int) error {
 		if err := p.ProcessStatusV2ForFaction(ctx, spreadsheetID, factionID, updateInterval); err != nil {
-			log.Error().
-				Err(err).
-				Int("faction_id", factionID).
-				Msg("Failed to process Status v2 for faction - continuing with others")
-			continue
+			return err
 		}
 
 		log.Debug().
 			Int("faction_id", factionID).
 			Msg("Successfully processed Status v2 for faction")
+		return nil
+	})
+
+	for factionID, err := range errs {
+		log.Error().
+			Err(err).
+			Int("faction_id", factionID).
+			Msg("Failed to process Status v2 for faction - continuing with others")
 	}
 
 	return nil
 }
 
+// loadMemberNotes reads the configured member notes sheet. Notes are optional, so a
+// failed read is logged and the notes from the last successful read are kept.
+func (p *StatusV2Processor) loadMemberNotes(ctx context.Context, spreadsheetID string) {
+	if p.notesSheet == "" {
+		return
+	}
+
+	rows, err := p.sheetsClient.ReadSheet(ctx, spreadsheetID, fmt.Sprintf("'%s'!A:B", p.notesSheet))
+	if err != nil {
+		log.Warn().
+			Err(err).
+			Str("sheet_name", p.notesSheet).
+			Msg("Failed to read member notes - keeping previous notes")
+		return
+	}
+
+	p.memberNotes = status.ParseMemberNotes(rows)
+	log.Debug().
+		Str("sheet_name", p.notesSheet).
+		Int("notes", len(p.memberNotes)).
+		Msg("Loaded member notes")
+}
+
 // ProcessStatusV2ForFaction processes Status v2 sheet for a single faction
 func (p *StatusV2Processor) ProcessStatusV2ForFaction(ctx context.Context, spreadsheetID string, factionID int, updateInterval time.Duration) error {
 	// Step 1: Ensure Status v2 sheet exists
@@ -103,6 +193,40 @@ func (p *StatusV2Processor) ProcessStatusV2ForFaction(ctx context.Context, sprea
 		return fmt.Errorf("failed to get faction data: %w", err)
 	}
 
+	// Step 2b: Refresh the member state overview (only for opposing factions)
+	if factionID != p.ourFactionID {
+		if err := p.UpdateEnemyOverview(ctx, spreadsheetID, factionData); err != nil {
+			log.Warn().
+				Err(err).
+				Int("faction_id", factionID).
+				Msg("Failed to update enemy overview - continuing with processing")
+		}
+
+		// Step 2c: List hospitalized enemies by when they get out
+		if err := p.UpdateEnemyHospital(ctx, spreadsheetID, factionData); err != nil {
+			log.Warn().
+				Err(err).
+				Int("faction_id", factionID).
+				Msg("Failed to update enemy hospital - continuing with processing")
+		}
+
+		// Step 2d: Rank enemy members by how good a target they are right now
+		if err := p.UpdateTargetPriority(ctx, spreadsheetID, factionData); err != nil {
+			log.Warn().
+				Err(err).
+				Int("faction_id", factionID).
+				Msg("Failed to update target priority - continuing with processing")
+		}
+
+		// Step 2e: Track the enemy roster size over the war
+		if err := p.sheetsClient.AppendEnemyRosterSize(ctx, spreadsheetID, factionID, time.Now(), len(factionData.Members)); err != nil {
+			log.Warn().
+				Err(err).
+				Int("faction_id", factionID).
+				Msg("Failed to append enemy roster size - continuing with processing")
+		}
+	}
+
 	// Step 3: Read all state records from Changed States sheet to get current state
 	allStateRecords, err := p.service.ReadAllStateRecords(ctx, spreadsheetID)
 	if err != nil {
@@ -138,6 +262,12 @@ func (p *StatusV2Processor) ProcessStatusV2ForFaction(ctx context.Context, sprea
 		return fmt.Errorf("failed to convert state records to Status v2: %w", err)
 	}
 
+	// Step 5b: Drop low-level enemy members from both the sheet and the JSON export
+	statusV2Records = p.filterByMinEnemyLevel(statusV2Records, factionID)
+
+	// Step 5c: Report how far calculated travel countdowns are from the API's
+	p.reportCountdownDrift(ctx, spreadsheetID, factionID, statusV2Records, time.Now().UTC())
+
 	log.Info().
 		Int("faction_id", factionID).
 		Int("status_v2_records", len(statusV2Records)).
@@ -170,6 +300,16 @@ func (p *StatusV2Processor) ProcessStatusV2ForFaction(ctx context.Context, sprea
 		Int("faction_members", len(factionData.Members)).
 		Msg("Successfully updated Status v2 sheet")
 
+	// Step 6b: Keep a timestamped snapshot alongside the overwritten sheet
+	if p.history {
+		if err := p.sheetsClient.AppendStatusHistory(ctx, spreadsheetID, factionID, time.Now(), statusV2Records, p.historyMaxRows); err != nil {
+			log.Warn().
+				Err(err).
+				Int("faction_id", factionID).
+				Msg("Failed to append Status v2 history snapshot - continuing with processing")
+		}
+	}
+
 	// Step 7: Export JSON alongside sheet update (only for opposing factions)
 	if factionID != p.ourFactionID {
 		if err := p.exportAndDeployJSON(statusV2Records, factionData.Name, factionID, updateInterval); err != nil {
@@ -187,7 +327,81 @@ func (p *StatusV2Processor) ProcessStatusV2ForFaction(ctx context.Context, sprea
 	return nil
 }
 
-// filterStateRecordsForFaction filters state records to only include current records for the specified faction
+// UpdateEnemyOverview counts a faction's members by activity and state and overwrites
+// the faction's overview row, leaving out excluded members
+func (p *StatusV2Processor) UpdateEnemyOverview(ctx context.Context, spreadsheetID string, factionData *app.FactionBasicResponse) error {
+	members := make(map[string]app.FactionMember, len(factionData.Members))
+	for memberID, member := range factionData.Members {
+		if !p.isExcludedMember(memberID) {
+			members[memberID] = member
+		}
+	}
+
+	overview := status.CountMemberStates(members)
+	overview.Updated = time.Now().UTC()
+
+	if err := p.sheetsClient.UpdateEnemyOverview(ctx, spreadsheetID, factionData.ID, overview); err != nil {
+		return fmt.Errorf("failed to update enemy overview: %w", err)
+	}
+
+	log.Debug().
+		Int("faction_id", factionData.ID).
+		Int("online", overview.Online).
+		Int("hospital", overview.Hospital).
+		Int("traveling", overview.Traveling).
+		Msg("Updated enemy overview")
+
+	return nil
+}
+
+// UpdateEnemyHospital rewrites a faction's hospitalized members sorted by release time,
+// leaving out excluded members and those already out
+func (p *StatusV2Processor) UpdateEnemyHospital(ctx context.Context, spreadsheetID string, factionData *app.FactionBasicResponse) error {
+	members := make(map[string]app.FactionMember, len(factionData.Members))
+	for memberID, member := range factionData.Members {
+		if !p.isExcludedMember(memberID) {
+			members[memberID] = member
+		}
+	}
+
+	entries := status.BuildHospitalList(members, time.Now().UTC())
+	if err := p.sheetsClient.UpdateEnemyHospital(ctx, spreadsheetID, factionData.ID, entries); err != nil {
+		return fmt.Errorf("failed to update enemy hospital: %w", err)
+	}
+
+	log.Debug().
+		Int("faction_id", factionData.ID).
+		Int("hospitalized", len(entries)).
+		Msg("Updated enemy hospital")
+
+	return nil
+}
+
+// UpdateTargetPriority rewrites a faction's members ranked by target score, leaving out
+// excluded members
+func (p *StatusV2Processor) UpdateTargetPriority(ctx context.Context, spreadsheetID string, factionData *app.FactionBasicResponse) error {
+	members := make(map[string]app.FactionMember, len(factionData.Members))
+	for memberID, member := range factionData.Members {
+		if !p.isExcludedMember(memberID) {
+			members[memberID] = member
+		}
+	}
+
+	entries := status.BuildTargetPriorityList(members, p.targetWeights)
+	if err := p.sheetsClient.UpdateTargetPriority(ctx, spreadsheetID, factionData.ID, entries); err != nil {
+		return fmt.Errorf("failed to update target priority: %w", err)
+	}
+
+	log.Debug().
+		Int("faction_id", factionData.ID).
+		Int("members", len(entries)).
+		Msg("Updated target priority")
+
+	return nil
+}
+
+// filterStateRecordsForFaction filters state records to only include current records for the specified
+// faction, leaving out excluded members so they reach neither the sheet nor the JSON export
 func (p *StatusV2Processor) filterStateRecordsForFaction(allStateRecords []app.StateRecord, factionID int) []app.StateRecord {
 	factionIDStr := fmt.Sprintf("%d", factionID)
 
@@ -200,6 +414,7 @@ func (p *StatusV2Processor) filterStateRecordsForFaction(allStateRecords []app.S
 	// Group by member ID and find the most recent record for each member
 	memberLatest := make(map[string]app.StateRecord)
 	matchingRecords := 0
+	excludedRecords := 0
 
 	// Debug: Show what we're actually reading from each column
 	if len(allStateRecords) > 0 {
@@ -220,6 +435,10 @@ func (p *StatusV2Processor) filterStateRecordsForFaction(allStateRecords []app.S
 		if record.FactionID != factionIDStr {
 			continue
 		}
+		if p.isExcludedMember(record.MemberID) {
+			excludedRecords++
+			continue
+		}
 		matchingRecords++
 
 		existing, exists := memberLatest[record.MemberID]
@@ -233,6 +452,7 @@ func (p *StatusV2Processor) filterStateRecordsForFaction(allStateRecords []app.S
 		Str("faction_id_str", factionIDStr).
 		Int("total_records_checked", len(allStateRecords)).
 		Int("matching_faction_records", matchingRecords).
+		Int("excluded_member_records", excludedRecords).
 		Int("unique_members", len(memberLatest)).
 		Msg("Faction filtering progress")
 
@@ -250,12 +470,47 @@ func (p *StatusV2Processor) filterStateRecordsForFaction(allStateRecords []app.S
 	return currentRecords
 }
 
+// filterByMinEnemyLevel leaves out an enemy faction's members below the configured
+// minimum level. Our own faction is always fully tracked.
+func (p *StatusV2Processor) filterByMinEnemyLevel(records []app.StatusV2Record, factionID int) []app.StatusV2Record {
+	if p.minEnemyLevel <= 0 || factionID == p.ourFactionID {
+		return records
+	}
+
+	filtered := make([]app.StatusV2Record, 0, len(records))
+	for _, record := range records {
+		if record.Level >= p.minEnemyLevel {
+			filtered = append(filtered, record)
+		}
+	}
+
+	if dropped := len(records) - len(filtered); dropped > 0 {
+		log.Debug().
+			Int("faction_id", factionID).
+			Int("min_enemy_level", p.minEnemyLevel).
+			Int("members_dropped", dropped).
+			Msg("Left out enemy members below minimum level")
+	}
+
+	return filtered
+}
+
+// isExcludedMember reports whether a state record's member ID is in the excluded list
+func (p *StatusV2Processor) isExcludedMember(memberID string) bool {
+	if len(p.excluded) == 0 {
+		return false
+	}
+	id, err := strconv.Atoi(memberID)
+	return err == nil && p.excluded[id]
+}
+
 // exportAndDeployJSON converts StatusV2Records to JSON format and deploys it
 func (p *StatusV2Processor) exportAndDeployJSON(records []app.StatusV2Record, factionName string, factionID int, updateInterval time.Duration) error {
 	currentTime := time.Now().UTC()
 
 	// Convert to JSON format using the service
 	jsonData := p.service.ConvertToJSON(records, factionName, currentTime, updateInterval)
+	status.ApplyMemberNotes(jsonData.Locations, p.memberNotes)
 
 	// Marshal to JSON bytes
 	jsonBytes, err := json.MarshalIndent(jsonData, "", "    ")
@@ -269,26 +524,161 @@ func (p *StatusV2Processor) exportAndDeployJSON(records []app.StatusV2Record, fa
 		Int("json_size_bytes", len(jsonBytes)).
 		Msg("Successfully generated Status v2 JSON")
 
-	// Deploy to remote server if deployer is configured
-	if p.deployer != nil {
-		// Use a fixed filename for the remote deployment
-		remoteFilename := "travel_data.json"
-
-		// Deploy directly from memory without writing to disk
-		if err := p.deployer.DeployData(bytes.NewReader(jsonBytes), int64(len(jsonBytes)), remoteFilename); err != nil {
-			return fmt.Errorf("failed to deploy JSON data: %w", err)
+	// The archive is a local history of exports, so a failure is logged rather than returned
+	if p.archiver != nil {
+		if _, err := p.archiver.Archive(factionID, currentTime, jsonBytes); err != nil {
+			log.Error().
+				Err(err).
+				Int("faction_id", factionID).
+				Msg("Failed to archive Status v2 JSON")
 		}
+	}
 
+	// The Updated timestamp, countdowns and last action text change every cycle, so they
+	// are left out when comparing content
+	unstamped := jsonData
+	unstamped.Updated = ""
+	unstamped.Locations = status.WithoutVolatileFields(jsonData.Locations)
+	contentBytes, err := json.Marshal(unstamped)
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON for comparison: %w", err)
+	}
+	contentHash := sha256.Sum256(contentBytes)
+
+	const remoteFilename = "travel_data.json"
+	if p.deployer != nil && p.lastDeployedHash(remoteFilename) == contentHash {
 		log.Info().
 			Int("faction_id", factionID).
 			Str("remote_file", remoteFilename).
-			Int("size_bytes", len(jsonBytes)).
-			Msg("Successfully deployed Status v2 JSON")
-	} else {
+			Msg("status unchanged, skipping deploy")
+		return nil
+	}
+
+	if err := p.deployJSON(jsonBytes, remoteFilename, factionID); err != nil {
+		return err
+	}
+	if p.deployer != nil {
+		p.deployedMu.Lock()
+		p.deployed[remoteFilename] = contentHash
+		p.deployedMu.Unlock()
+	}
+
+	if p.deltaExport {
+		if err := p.exportAndDeployDeltaJSON(jsonData, factionID); err != nil {
+			return fmt.Errorf("failed to export delta JSON: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// lastDeployedHash returns the content hash last deployed to a remote file, or the zero
+// hash when nothing has been deployed there yet
+func (p *StatusV2Processor) lastDeployedHash(remoteFilename string) [sha256.Size]byte {
+	p.deployedMu.Lock()
+	defer p.deployedMu.Unlock()
+	return p.deployed[remoteFilename]
+}
+
+// exportAndDeployDeltaJSON deploys only the members that changed since the last
+// exported snapshot for the faction, then records the new snapshot
+func (p *StatusV2Processor) exportAndDeployDeltaJSON(jsonData app.StatusV2JSON, factionID int) error {
+	p.exportsMu.Lock()
+	previous := p.lastExports[factionID]
+	p.exportsMu.Unlock()
+	delta := p.service.ConvertToDeltaJSON(previous, jsonData)
+
+	jsonBytes, err := json.MarshalIndent(delta, "", "    ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal delta JSON: %w", err)
+	}
+
+	log.Info().
+		Int("faction_id", factionID).
+		Int("changed_locations", len(delta.Changed)).
+		Int("removed_members", len(delta.Removed)).
+		Int("json_size_bytes", len(jsonBytes)).
+		Msg("Successfully generated Status v2 delta JSON")
+
+	if err := p.deployJSON(jsonBytes, "travel_data_delta.json", factionID); err != nil {
+		return err
+	}
+
+	// Only advance the snapshot once the delta has been exported
+	p.exportsMu.Lock()
+	p.lastExports[factionID] = jsonData
+	p.exportsMu.Unlock()
+	return nil
+}
+
+// deployJSON deploys JSON bytes to the remote server if a deployer is configured
+func (p *StatusV2Processor) deployJSON(jsonBytes []byte, remoteFilename string, factionID int) error {
+	if p.deployer == nil {
 		log.Debug().
 			Int("faction_id", factionID).
+			Str("remote_file", remoteFilename).
 			Msg("No deployer configured - skipping remote deployment")
+		return nil
 	}
 
+	// Deploy directly from memory without writing to disk
+	if err := p.deployer.DeployData(bytes.NewReader(jsonBytes), int64(len(jsonBytes)), remoteFilename); err != nil {
+		return fmt.Errorf("failed to deploy JSON data: %w", err)
+	}
+
+	log.Info().
+		Int("faction_id", factionID).
+		Str("remote_file", remoteFilename).
+		Int("size_bytes", len(jsonBytes)).
+		Msg("Successfully deployed Status v2 JSON")
+
 	return nil
 }
+
+// reportCountdownDrift logs the drift between the API-derived and calculated countdown of
+// every member with both, returning the measurements. With drift tracking enabled they
+// are also appended to the countdown drift sheet; a failure there is only logged.
+func (p *StatusV2Processor) reportCountdownDrift(ctx context.Context, spreadsheetID string, factionID int, records []app.StatusV2Record, now time.Time) []app.CountdownDrift {
+	var drifts []app.CountdownDrift
+	for _, record := range records {
+		drift, ok := status.CalculateCountdownDrift(record.Until, record.Countdown, now)
+		if !ok {
+			continue
+		}
+
+		measurement := app.CountdownDrift{
+			Timestamp:           now,
+			FactionID:           factionID,
+			MemberID:            record.MemberID,
+			MemberName:          record.Name,
+			Location:            record.Location,
+			OriginalCountdown:   status.CalculateCountdown(record.Until, now),
+			CalculatedCountdown: record.Countdown,
+			DriftSeconds:        int(drift.Seconds()),
+		}
+		drifts = append(drifts, measurement)
+
+		log.Debug().
+			Int("faction_id", factionID).
+			Str("member_id", record.MemberID).
+			Str("location", record.Location).
+			Str("original_countdown", measurement.OriginalCountdown).
+			Str("calculated_countdown", measurement.CalculatedCountdown).
+			Int("drift_seconds", measurement.DriftSeconds).
+			Msg("Countdown drift")
+	}
+
+	if p.trackDrift && len(drifts) > 0 {
+		p.driftMu.Lock()
+		err := p.sheetsClient.AppendCountdownDrift(ctx, spreadsheetID, drifts)
+		p.driftMu.Unlock()
+		if err != nil {
+			log.Warn().
+				Err(err).
+				Int("faction_id", factionID).
+				Msg("Failed to append countdown drift - continuing with processing")
+		}
+	}
+
+	return drifts
+}
@@ -0,0 +1,449 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"torn_rw_stats/internal/app"
+	"torn_rw_stats/internal/processing/mocks"
+)
+
+func TestStatusV2Processor_DeltaExportTracksLastSnapshot(t *testing.T) {
+	processor := NewStatusV2Processor(mocks.NewMockTornClient(), mocks.NewMockSheetsClient(), &app.Config{StatusDeltaExport: true})
+
+	records := []app.StatusV2Record{
+		{Name: "Player1", MemberID: "1", Level: 50, State: "Online", Status: "Okay", Location: "Torn"},
+	}
+
+	if err := processor.exportAndDeployJSON(records, "Enemy", 200, time.Minute); err != nil {
+		t.Fatalf("exportAndDeployJSON() returned unexpected error: %v", err)
+	}
+
+	snapshot, exists := processor.lastExports[200]
+	if !exists {
+		t.Fatal("expected snapshot to be recorded for faction 200")
+	}
+	if len(snapshot.Locations["Torn"].LocatedIn) != 1 {
+		t.Errorf("expected 1 member in snapshot, got %d", len(snapshot.Locations["Torn"].LocatedIn))
+	}
+
+	// An unchanged export against the recorded snapshot yields an empty delta
+	delta := processor.service.ConvertToDeltaJSON(snapshot, processor.service.ConvertToJSON(records, "Enemy", time.Now(), time.Minute))
+	if len(delta.Changed) != 0 || len(delta.Removed) != 0 {
+		t.Errorf("expected empty delta for unchanged export, got changed=%v removed=%v", delta.Changed, delta.Removed)
+	}
+}
+
+// recordingDeployer records the remote files and contents deployed instead of uploading them
+type recordingDeployer struct {
+	files    []string
+	contents [][]byte
+}
+
+func (d *recordingDeployer) DeployData(data io.Reader, size int64, filename string) error {
+	content, err := io.ReadAll(data)
+	if err != nil {
+		return err
+	}
+	d.files = append(d.files, filename)
+	d.contents = append(d.contents, content)
+	return nil
+}
+
+func TestStatusV2Processor_SkipsUnchangedDeploy(t *testing.T) {
+	processor := NewStatusV2Processor(mocks.NewMockTornClient(), mocks.NewMockSheetsClient(), &app.Config{})
+	deployer := &recordingDeployer{}
+	processor.deployer = deployer
+
+	records := []app.StatusV2Record{
+		{Name: "Player1", MemberID: "1", Level: 50, State: "Online", Status: "Okay", Location: "Torn", LastActionRelative: "1 minute ago"},
+	}
+
+	// The second generation differs only in its Updated timestamp and last action text
+	for i := 0; i < 2; i++ {
+		if err := processor.exportAndDeployJSON(records, "Enemy", 200, time.Minute); err != nil {
+			t.Fatalf("exportAndDeployJSON() returned unexpected error: %v", err)
+		}
+		records[0].LastActionRelative = "2 minutes ago"
+	}
+	if len(deployer.files) != 1 {
+		t.Fatalf("expected the identical second export to skip deployment, got %d deploys", len(deployer.files))
+	}
+
+	records[0].Status = "Hospital"
+	if err := processor.exportAndDeployJSON(records, "Enemy", 200, time.Minute); err != nil {
+		t.Fatalf("exportAndDeployJSON() returned unexpected error: %v", err)
+	}
+	if len(deployer.files) != 2 {
+		t.Errorf("expected a changed export to be deployed, got %d deploys", len(deployer.files))
+	}
+}
+
+func TestStatusV2Processor_MemberNotes(t *testing.T) {
+	sheetsMock := mocks.NewMockSheetsClient()
+	sheetsMock.ReadSheetResponse = [][]interface{}{{"Member ID", "Note"}, {"2", "Hits hard"}}
+	processor := NewStatusV2Processor(mocks.NewMockTornClient(), sheetsMock, &app.Config{MemberNotesSheet: "Notes"})
+	deployer := &recordingDeployer{}
+	processor.deployer = deployer
+
+	processor.loadMemberNotes(context.Background(), "spreadsheet-id")
+	if sheetsMock.ReadSheetCalledWith.Range != "'Notes'!A:B" {
+		t.Errorf("expected notes read from 'Notes'!A:B, got %q", sheetsMock.ReadSheetCalledWith.Range)
+	}
+	if processor.memberNotes["2"] != "Hits hard" {
+		t.Fatalf("expected the notes sheet loaded, got %v", processor.memberNotes)
+	}
+
+	// Inject notes directly to check they land on the right member
+	processor.memberNotes = map[string]string{"2": "Avoid - revives"}
+	records := []app.StatusV2Record{
+		{Name: "Plain", MemberID: "1", Level: 50, State: "Online", Status: "Okay", Location: "Torn"},
+		{Name: "Noted", MemberID: "2", Level: 60, State: "Online", Status: "Okay", Location: "Torn"},
+	}
+	if err := processor.exportAndDeployJSON(records, "Enemy", 200, time.Minute); err != nil {
+		t.Fatalf("exportAndDeployJSON() returned unexpected error: %v", err)
+	}
+	if len(deployer.contents) != 1 {
+		t.Fatalf("expected one deploy, got %d", len(deployer.contents))
+	}
+
+	var exported app.StatusV2JSON
+	if err := json.Unmarshal(deployer.contents[0], &exported); err != nil {
+		t.Fatalf("failed to parse exported JSON: %v", err)
+	}
+	notes := make(map[string]string)
+	for _, member := range exported.Locations["Torn"].LocatedIn {
+		notes[member.Name] = member.Note
+	}
+	if notes["Noted"] != "Avoid - revives" || notes["Plain"] != "" {
+		t.Errorf("expected the note on Noted only, got %v", notes)
+	}
+}
+
+func TestStatusV2Processor_DeltaExportDisabledByDefault(t *testing.T) {
+	processor := NewStatusV2Processor(mocks.NewMockTornClient(), mocks.NewMockSheetsClient(), &app.Config{})
+
+	records := []app.StatusV2Record{
+		{Name: "Player1", MemberID: "1", Level: 50, State: "Online", Status: "Okay", Location: "Torn"},
+	}
+
+	if err := processor.exportAndDeployJSON(records, "Enemy", 200, time.Minute); err != nil {
+		t.Fatalf("exportAndDeployJSON() returned unexpected error: %v", err)
+	}
+
+	if len(processor.lastExports) != 0 {
+		t.Errorf("expected no snapshots when delta export is disabled, got %d", len(processor.lastExports))
+	}
+}
+
+func TestStatusV2Processor_ExcludedMembers(t *testing.T) {
+	config := &app.Config{ExcludedMemberIDs: []int{2}}
+	processor := NewStatusV2Processor(mocks.NewMockTornClient(), mocks.NewMockSheetsClient(), config)
+
+	now := time.Now()
+	stateRecords := []app.StateRecord{
+		{Timestamp: now, MemberID: "1", MemberName: "Enemy1", FactionID: "200"},
+		{Timestamp: now, MemberID: "2", MemberName: "OurSpy", FactionID: "200"},
+		{Timestamp: now, MemberID: "3", MemberName: "Enemy2", FactionID: "200"},
+	}
+
+	records := processor.filterStateRecordsForFaction(stateRecords, 200)
+	if len(records) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(records))
+	}
+	for _, record := range records {
+		if record.MemberID == "2" {
+			t.Errorf("expected excluded member 2 to be filtered out, got %+v", record)
+		}
+	}
+}
+
+func TestStatusV2Processor_MinEnemyLevel(t *testing.T) {
+	processor := NewStatusV2Processor(mocks.NewMockTornClient(), mocks.NewMockSheetsClient(), &app.Config{MinEnemyLevel: 40})
+	processor.ourFactionID = 100
+
+	records := []app.StatusV2Record{
+		{Name: "Low", MemberID: "1", Level: 10},
+		{Name: "Mid", MemberID: "2", Level: 50},
+		{Name: "High", MemberID: "3", Level: 90},
+	}
+
+	filtered := processor.filterByMinEnemyLevel(records, 200)
+	if len(filtered) != 2 {
+		t.Fatalf("expected 2 enemy members at or above level 40, got %d: %+v", len(filtered), filtered)
+	}
+	for _, record := range filtered {
+		if record.Level < 40 {
+			t.Errorf("expected members below level 40 to be left out, got %+v", record)
+		}
+	}
+
+	// Our own faction is always fully tracked
+	if own := processor.filterByMinEnemyLevel(records, 100); len(own) != 3 {
+		t.Errorf("expected all 3 of our own members to be kept, got %d", len(own))
+	}
+}
+
+func TestStatusV2Processor_ConfiguredFactionIDSkipsLookup(t *testing.T) {
+	tornClient := mocks.NewMockTornClient()
+	processor := NewStatusV2Processor(tornClient, mocks.NewMockSheetsClient(), &app.Config{OurFactionID: 100})
+
+	if err := processor.ensureOurFactionID(context.Background()); err != nil {
+		t.Fatalf("ensureOurFactionID() returned unexpected error: %v", err)
+	}
+
+	if tornClient.GetOwnFactionCalled {
+		t.Error("expected no GetOwnFaction call when our faction ID is configured")
+	}
+	if processor.ourFactionID != 100 {
+		t.Errorf("expected configured faction ID 100, got %d", processor.ourFactionID)
+	}
+}
+
+// concurrencyTrackingTornClient records which factions were fetched and the peak
+// number of GetFactionBasic calls in flight
+type concurrencyTrackingTornClient struct {
+	*mocks.MockTornClient
+
+	mu       sync.Mutex
+	inFlight int
+	peak     int
+	fetched  []int
+}
+
+func (c *concurrencyTrackingTornClient) GetFactionBasic(ctx context.Context, factionID int) (*app.FactionBasicResponse, error) {
+	c.mu.Lock()
+	c.inFlight++
+	if c.inFlight > c.peak {
+		c.peak = c.inFlight
+	}
+	c.fetched = append(c.fetched, factionID)
+	c.mu.Unlock()
+
+	time.Sleep(10 * time.Millisecond)
+
+	c.mu.Lock()
+	c.inFlight--
+	c.mu.Unlock()
+
+	// Failing every faction checks that errors do not abort the batch
+	return nil, errors.New("faction unavailable")
+}
+
+func TestStatusV2Processor_ProcessesFactionsConcurrently(t *testing.T) {
+	tornClient := &concurrencyTrackingTornClient{MockTornClient: mocks.NewMockTornClient()}
+	tornClient.OwnFactionResponse = &app.FactionInfoResponse{ID: 100}
+
+	sheetsClient := mocks.NewMockSheetsClient()
+	sheetsClient.EnsureStatusV2SheetResponse = "Status v2"
+
+	processor := NewStatusV2Processor(tornClient, sheetsClient, &app.Config{MaxConcurrentFactions: 2})
+
+	factionIDs := []int{201, 202, 203, 204, 205}
+	if err := processor.ProcessStatusV2ForFactions(context.Background(), "sheet", factionIDs, time.Minute); err != nil {
+		t.Fatalf("ProcessStatusV2ForFactions() returned unexpected error: %v", err)
+	}
+
+	sort.Ints(tornClient.fetched)
+	if len(tornClient.fetched) != len(factionIDs) {
+		t.Fatalf("expected all %d factions processed, got %v", len(factionIDs), tornClient.fetched)
+	}
+	for i, factionID := range factionIDs {
+		if tornClient.fetched[i] != factionID {
+			t.Errorf("expected faction %d to be processed, got %v", factionID, tornClient.fetched)
+		}
+	}
+	if tornClient.peak > 2 {
+		t.Errorf("expected at most 2 factions in flight, got %d", tornClient.peak)
+	}
+	if tornClient.peak < 2 {
+		t.Errorf("expected factions to be processed in parallel, peak in flight was %d", tornClient.peak)
+	}
+}
+
+func TestStatusV2Processor_InterFactionDelay(t *testing.T) {
+	const delay = 50 * time.Millisecond
+
+	t.Run("waits between factions", func(t *testing.T) {
+		tornClient := &concurrencyTrackingTornClient{MockTornClient: mocks.NewMockTornClient()}
+		tornClient.OwnFactionResponse = &app.FactionInfoResponse{ID: 100}
+		sheetsClient := mocks.NewMockSheetsClient()
+		sheetsClient.EnsureStatusV2SheetResponse = "Status v2"
+
+		processor := NewStatusV2Processor(tornClient, sheetsClient, &app.Config{MaxConcurrentFactions: 2, InterFactionDelay: delay})
+
+		start := time.Now()
+		if err := processor.ProcessStatusV2ForFactions(context.Background(), "sheet", []int{201, 202}, time.Minute); err != nil {
+			t.Fatalf("ProcessStatusV2ForFactions() returned unexpected error: %v", err)
+		}
+		if elapsed := time.Since(start); elapsed < delay {
+			t.Errorf("expected processing two factions to take at least %v, took %v", delay, elapsed)
+		}
+		if len(tornClient.fetched) != 2 {
+			t.Errorf("expected both factions processed, got %v", tornClient.fetched)
+		}
+	})
+
+	t.Run("cancellation interrupts the wait", func(t *testing.T) {
+		tornClient := &concurrencyTrackingTornClient{MockTornClient: mocks.NewMockTornClient()}
+		tornClient.OwnFactionResponse = &app.FactionInfoResponse{ID: 100}
+		sheetsClient := mocks.NewMockSheetsClient()
+		sheetsClient.EnsureStatusV2SheetResponse = "Status v2"
+
+		processor := NewStatusV2Processor(tornClient, sheetsClient, &app.Config{MaxConcurrentFactions: 2, InterFactionDelay: time.Hour})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(delay, cancel)
+
+		start := time.Now()
+		if err := processor.ProcessStatusV2ForFactions(ctx, "sheet", []int{201, 202}, time.Minute); err != nil {
+			t.Fatalf("ProcessStatusV2ForFactions() returned unexpected error: %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > 5*time.Second {
+			t.Errorf("expected cancellation to interrupt the delay, took %v", elapsed)
+		}
+		if len(tornClient.fetched) != 1 || tornClient.fetched[0] != 201 {
+			t.Errorf("expected only the first faction processed before cancellation, got %v", tornClient.fetched)
+		}
+	})
+}
+
+func TestStatusV2Processor_UpdateEnemyOverview(t *testing.T) {
+	sheetsClient := mocks.NewMockSheetsClient()
+	processor := NewStatusV2Processor(mocks.NewMockTornClient(), sheetsClient, &app.Config{})
+
+	member := func(lastAction, state string) app.FactionMember {
+		return app.FactionMember{
+			LastAction: app.LastAction{Status: lastAction},
+			Status:     app.MemberStatus{State: state},
+		}
+	}
+	factionData := &app.FactionBasicResponse{
+		ID: 200,
+		Members: map[string]app.FactionMember{
+			"1": member("Online", "Okay"),
+			"2": member("Online", "Hospital"),
+			"3": member("Idle", "Traveling"),
+			"4": member("Offline", "Traveling"),
+			"5": member("Offline", "Jail"),
+			"6": member("Offline", "Abroad"),
+		},
+	}
+
+	if err := processor.UpdateEnemyOverview(context.Background(), "sheet", factionData); err != nil {
+		t.Fatalf("UpdateEnemyOverview() returned unexpected error: %v", err)
+	}
+
+	overview, ok := sheetsClient.UpdateEnemyOverviewCalls[200]
+	if !ok {
+		t.Fatal("expected an overview to be written for faction 200")
+	}
+	if overview.Online != 2 || overview.Idle != 1 || overview.Offline != 3 {
+		t.Errorf("expected 2 online, 1 idle, 3 offline, got %+v", overview)
+	}
+	if overview.Hospital != 1 || overview.Traveling != 2 || overview.Jail != 1 {
+		t.Errorf("expected 1 hospital, 2 traveling, 1 jail, got %+v", overview)
+	}
+	if overview.Updated.IsZero() {
+		t.Error("expected the overview to be timestamped")
+	}
+}
+
+func TestStatusV2Processor_AppendsEnemyRosterSize(t *testing.T) {
+	tornClient := mocks.NewMockTornClient()
+	sheetsClient := mocks.NewMockSheetsClient()
+	processor := NewStatusV2Processor(tornClient, sheetsClient, &app.Config{OurFactionID: 100})
+
+	members := func(count int) map[string]app.FactionMember {
+		roster := make(map[string]app.FactionMember, count)
+		for i := 1; i <= count; i++ {
+			roster[strconv.Itoa(i)] = app.FactionMember{Name: "Enemy" + strconv.Itoa(i)}
+		}
+		return roster
+	}
+
+	// Two cycles, with the enemy shedding a member in between
+	for _, count := range []int{5, 4} {
+		tornClient.FactionBasicResponse = &app.FactionBasicResponse{ID: 200, Name: "Them", Members: members(count)}
+		if err := processor.ProcessStatusV2ForFaction(context.Background(), "sheet", 200, time.Minute); err != nil {
+			t.Fatalf("ProcessStatusV2ForFaction() returned unexpected error: %v", err)
+		}
+	}
+
+	// Our own faction's roster is not tracked
+	tornClient.FactionBasicResponse = &app.FactionBasicResponse{ID: 100, Name: "Us", Members: members(3)}
+	if err := processor.ProcessStatusV2ForFaction(context.Background(), "sheet", 100, time.Minute); err != nil {
+		t.Fatalf("ProcessStatusV2ForFaction() returned unexpected error: %v", err)
+	}
+
+	if got := sheetsClient.AppendEnemyRosterSizeCalls[200]; !reflect.DeepEqual(got, []int{5, 4}) {
+		t.Errorf("expected roster sizes [5 4] appended for faction 200, got %v", got)
+	}
+	if got, ok := sheetsClient.AppendEnemyRosterSizeCalls[100]; ok {
+		t.Errorf("expected no roster sizes for our faction, got %v", got)
+	}
+}
+
+func TestStatusV2Processor_UpdateTargetPriority(t *testing.T) {
+	sheetsClient := mocks.NewMockSheetsClient()
+	processor := NewStatusV2Processor(mocks.NewMockTornClient(), sheetsClient, &app.Config{
+		ExcludedMemberIDs:  []int{3},
+		TargetLevelWeight:  1,
+		TargetOnlineWeight: 50,
+	})
+
+	factionData := &app.FactionBasicResponse{ID: 200, Members: map[string]app.FactionMember{
+		"1": {Name: "Hospitalized", Level: 100, LastAction: app.LastAction{Status: "Online"}, Status: app.MemberStatus{State: "Hospital"}},
+		"2": {Name: "Target", Level: 60, LastAction: app.LastAction{Status: "Online"}, Status: app.MemberStatus{State: "Okay"}},
+		"3": {Name: "Excluded", Level: 90, LastAction: app.LastAction{Status: "Online"}, Status: app.MemberStatus{State: "Okay"}},
+	}}
+
+	if err := processor.UpdateTargetPriority(context.Background(), "sheet", factionData); err != nil {
+		t.Fatalf("UpdateTargetPriority() returned unexpected error: %v", err)
+	}
+
+	entries := sheetsClient.UpdateTargetPriorityCalls[200]
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 ranked members without the excluded one, got %+v", entries)
+	}
+	if entries[0].Name != "Target" || entries[0].TargetScore != 110 {
+		t.Errorf("expected online attackable member first scoring 110, got %+v", entries[0])
+	}
+	if entries[1].Name != "Hospitalized" || entries[1].TargetScore != 0 {
+		t.Errorf("expected hospitalized member last scoring 0, got %+v", entries[1])
+	}
+}
+
+func TestStatusV2Processor_ReportCountdownDrift(t *testing.T) {
+	now := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+	records := []app.StatusV2Record{
+		{Name: "Traveler", MemberID: "1", Location: "Mexico", Until: now.Add(20 * time.Minute), Countdown: "'00:21:30"},
+		{Name: "Hospitalized", MemberID: "2", Location: "Torn", Until: now.Add(10 * time.Minute)},
+		{Name: "Okay", MemberID: "3", Location: "Torn"},
+	}
+
+	sheetsClient := mocks.NewMockSheetsClient()
+	processor := NewStatusV2Processor(mocks.NewMockTornClient(), sheetsClient, &app.Config{})
+
+	drifts := processor.reportCountdownDrift(context.Background(), "test-sheet-id", 200, records, now)
+	if len(drifts) != 1 || drifts[0].MemberID != "1" || drifts[0].DriftSeconds != 90 {
+		t.Fatalf("expected a single 90 second drift for the traveler, got %+v", drifts)
+	}
+	if sheetsClient.AppendCountdownDriftCalled {
+		t.Error("expected no countdown drift sheet writes when tracking is disabled")
+	}
+
+	processor = NewStatusV2Processor(mocks.NewMockTornClient(), sheetsClient, &app.Config{TrackCountdownDrift: true})
+	processor.reportCountdownDrift(context.Background(), "test-sheet-id", 200, records, now)
+	if !sheetsClient.AppendCountdownDriftCalled || len(sheetsClient.AppendCountdownDriftCalledWith.Drifts) != 1 {
+		t.Errorf("expected the drift to be appended to the countdown drift sheet, got %+v", sheetsClient.AppendCountdownDriftCalledWith)
+	}
+}
@@ -97,33 +97,48 @@ func (s *StatusV2Service) convertSingleStateRecord(ctx context.Context, stateRec
 	// Use domain functions for pure calculations
 	existing := status.GetExistingRecord(stateRecord.FactionID, stateRecord.MemberID, stateRecord.MemberName, existingData)
 	level := status.ResolveLevel(stateRecord.MemberID, factionMembers, existing)
-	location := s.calculateLocation(stateRecord)
+	online := status.ResolveOnlineStatus(stateRecord.MemberID, factionMembers)
+	lastActionRelative := status.ResolveLastActionRelative(stateRecord.MemberID, factionMembers, currentTime)
+	statusCategory := status.ResolveStatusCategory(stateRecord.MemberID, factionMembers)
+	location := s.calculateLocation(stateRecord, currentTime)
 
 	travelInfo := s.calculateTravelInfo(ctx, stateRecord, existing, departureMap, currentTime, location)
+	if countdown := status.CalculateReleaseCountdown(stateRecord.StatusState, stateRecord.StatusUntil, currentTime); countdown != "" {
+		travelInfo.Countdown = countdown
+	}
 
-	return s.buildStatusV2Record(stateRecord, level, location, travelInfo)
+	return s.buildStatusV2Record(stateRecord, level, online, lastActionRelative, statusCategory, location, travelInfo)
 }
 
 // buildStatusV2Record constructs the final StatusV2Record
-func (s *StatusV2Service) buildStatusV2Record(stateRecord app.StateRecord, level int, location string, travelInfo TravelInfo) app.StatusV2Record {
+func (s *StatusV2Service) buildStatusV2Record(stateRecord app.StateRecord, level int, online string, lastActionRelative string, statusCategory string, location string, travelInfo TravelInfo) app.StatusV2Record {
 	return app.StatusV2Record{
-		Name:            stateRecord.MemberName,
-		MemberID:        stateRecord.MemberID,
-		Level:           level,
-		State:           stateRecord.LastActionStatus,
-		Status:          stateRecord.StatusState,
-		Location:        location,
-		Countdown:       travelInfo.Countdown,
-		Departure:       travelInfo.Departure,
-		Arrival:         travelInfo.Arrival,
-		BusinessArrival: travelInfo.BusinessArrival,
-		Until:           stateRecord.StatusUntil,
+		Name:               stateRecord.MemberName,
+		MemberID:           stateRecord.MemberID,
+		Level:              level,
+		State:              stateRecord.LastActionStatus,
+		Status:             stateRecord.StatusState,
+		Location:           location,
+		Countdown:          travelInfo.Countdown,
+		Departure:          travelInfo.Departure,
+		Arrival:            travelInfo.Arrival,
+		BusinessArrival:    travelInfo.BusinessArrival,
+		Until:              stateRecord.StatusUntil,
+		Online:             online,
+		LastActionRelative: lastActionRelative,
+		StatusCategory:     statusCategory,
 	}
 }
 
 // calculateLocation determines the location based on member state using LocationService
-func (s *StatusV2Service) calculateLocation(stateRecord app.StateRecord) string {
+func (s *StatusV2Service) calculateLocation(stateRecord app.StateRecord, currentTime time.Time) string {
+	// Federal jail descriptions name no location, so they are labeled directly
+	if status.IsFederalJail(stateRecord.StatusState) {
+		return status.FederalJailLocation
+	}
+
 	// Use the LocationService to parse location from status description
-	// This handles all patterns: hospitals, travel, locations, etc.
-	return s.locationService.ParseLocation(stateRecord.StatusDescription)
+	// This handles all patterns: hospitals, travel, locations, etc. An ambiguous
+	// description falls back to the member's recently resolved location.
+	return s.locationService.ResolveMemberLocation(stateRecord.MemberID, stateRecord.StatusDescription, currentTime)
 }
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"torn_rw_stats/internal/app"
+	"torn_rw_stats/internal/domain/status"
+	"torn_rw_stats/internal/processing/mocks"
+)
+
+func TestConvertStateRecordsToStatusV2_Online(t *testing.T) {
+	service := NewStatusV2Service(mocks.NewMockSheetsClient())
+
+	stateRecords := []app.StateRecord{
+		{MemberID: "1", MemberName: "Active", FactionID: "200", StatusState: "Okay", StatusDescription: "Okay"},
+		{MemberID: "2", MemberName: "Away", FactionID: "200", StatusState: "Okay", StatusDescription: "Okay"},
+		{MemberID: "3", MemberName: "Unknown", FactionID: "200", StatusState: "Okay", StatusDescription: "Okay"},
+	}
+	factionMembers := map[string]app.FactionMember{
+		"1": {Name: "Active", Level: 50, LastAction: app.LastAction{Status: "Online"}},
+		"2": {Name: "Away", Level: 40, LastAction: app.LastAction{Status: "Idle"}},
+		"3": {Name: "Unknown", Level: 30}, // No last action data
+	}
+
+	records, err := service.ConvertStateRecordsToStatusV2(context.Background(), "spreadsheet-id", stateRecords, factionMembers, 200)
+	if err != nil {
+		t.Fatalf("ConvertStateRecordsToStatusV2() returned unexpected error: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("expected 3 records, got %d", len(records))
+	}
+
+	expected := map[string]string{"Active": "Online", "Away": "Idle", "Unknown": ""}
+	for _, record := range records {
+		if record.Online != expected[record.Name] {
+			t.Errorf("%s: expected Online %q, got %q", record.Name, expected[record.Name], record.Online)
+		}
+
+		if member := status.ConvertToJSONMember(record); member.Online != record.Online {
+			t.Errorf("%s: expected JSON Online %q, got %q", record.Name, record.Online, member.Online)
+		}
+	}
+}
+
+func TestConvertStateRecordsToStatusV2_FederalJail(t *testing.T) {
+	service := NewStatusV2Service(mocks.NewMockSheetsClient())
+
+	releasedAt := time.Now().UTC().Add(3 * time.Hour)
+	stateRecords := []app.StateRecord{
+		{MemberID: "1", MemberName: "Jailed", FactionID: "200", StatusState: "Federal", StatusDescription: "In federal jail for 3 hours", StatusUntil: releasedAt},
+		{MemberID: "2", MemberName: "NoUntil", FactionID: "200", StatusState: "Federal", StatusDescription: "In federal jail"},
+	}
+	factionMembers := map[string]app.FactionMember{
+		"1": {Name: "Jailed", Level: 50},
+		"2": {Name: "NoUntil", Level: 40},
+	}
+
+	records, err := service.ConvertStateRecordsToStatusV2(context.Background(), "spreadsheet-id", stateRecords, factionMembers, 200)
+	if err != nil {
+		t.Fatalf("ConvertStateRecordsToStatusV2() returned unexpected error: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(records))
+	}
+
+	for _, record := range records {
+		if record.Location != status.FederalJailLocation {
+			t.Errorf("%s: expected location %q, got %q", record.Name, status.FederalJailLocation, record.Location)
+		}
+	}
+
+	countdown, ok := status.ParseCountdown(records[0].Countdown)
+	if !ok || countdown < 3*time.Hour-time.Minute || countdown > 3*time.Hour {
+		t.Errorf("expected a countdown of about 3 hours, got %q", records[0].Countdown)
+	}
+	if records[1].Countdown != "" {
+		t.Errorf("expected no countdown without an until time, got %q", records[1].Countdown)
+	}
+}
+
+func TestConvertToJSON_SchemaVersion(t *testing.T) {
+	service := NewStatusV2Service(mocks.NewMockSheetsClient())
+
+	records := []app.StatusV2Record{
+		{Name: "Player1", MemberID: "1", Level: 50, State: "Okay", Status: "Okay", Location: "Torn"},
+	}
+	data, err := json.Marshal(service.ConvertToJSON(records, "Enemy", time.Now(), time.Minute))
+	if err != nil {
+		t.Fatalf("failed to marshal status JSON: %v", err)
+	}
+
+	var decoded struct {
+		SchemaVersion int `json:"SchemaVersion"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("failed to unmarshal status JSON: %v", err)
+	}
+	if decoded.SchemaVersion != 2 {
+		t.Errorf("expected SchemaVersion 2, got %d in %s", decoded.SchemaVersion, data)
+	}
+}
@@ -135,7 +135,7 @@ func (s *StatusV2Service) parseStateRecordFromRow(row []interface{}) (app.StateR
 
 	// Parse timestamp from column 0 - this is already formatted as "2025-09-15 1:08:57"
 	if timestampStr, ok := row[0].(string); ok {
-		if timestamp, err := time.Parse("2006-01-02 15:04:05", timestampStr); err == nil {
+		if timestamp, err := sheets.ParseSheetTime(timestampStr); err == nil {
 			record.Timestamp = timestamp.UTC()
 		}
 	}
@@ -151,7 +151,7 @@ func (s *StatusV2Service) parseStateRecordFromRow(row []interface{}) (app.StateR
 	// Parse StatusUntil from column 8 (optional - only present for some status types)
 	if len(row) > 8 {
 		if statusUntilStr := getString(row, 8); statusUntilStr != "" {
-			if statusUntil, err := time.Parse("2006-01-02 15:04:05", statusUntilStr); err == nil {
+			if statusUntil, err := sheets.ParseSheetTime(statusUntilStr); err == nil {
 				record.StatusUntil = statusUntil.UTC()
 			}
 		}
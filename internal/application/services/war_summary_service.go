@@ -1,7 +1,10 @@
 package services
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
 	"time"
 
 	"torn_rw_stats/internal/app"
@@ -14,13 +17,21 @@ import (
 // WarSummaryService handles war summary generation and statistics calculation,
 // aggregating attack data into comprehensive war statistics.
 type WarSummaryService struct {
-	attackService *attack.AttackProcessingService
+	attackService     *attack.AttackProcessingService
+	halfCreditWinRate bool
+	summaryJSONDir    string // empty = JSON export disabled
+	outcomes          attack.OutcomeMap
+	includeInternal   bool // keep attacks between our own members in the statistics
 }
 
 // NewWarSummaryService creates a new war summary service
-func NewWarSummaryService(attackService *attack.AttackProcessingService) *WarSummaryService {
+func NewWarSummaryService(attackService *attack.AttackProcessingService, config *app.Config) *WarSummaryService {
 	return &WarSummaryService{
-		attackService: attackService,
+		attackService:     attackService,
+		halfCreditWinRate: config.HalfCreditWinRate,
+		summaryJSONDir:    config.SummaryJSONDir,
+		outcomes:          attack.NewOutcomeMap(config.WinResults, config.LossResults),
+		includeInternal:   config.IncludeInternalAttacks,
 	}
 }
 
@@ -40,18 +51,53 @@ func (wss *WarSummaryService) GenerateWarSummary(war *app.War, attacks []app.Att
 		summary.Status = "Completed"
 	}
 
+	// Spars between our own members would otherwise count as both a win and a loss
+	if !wss.includeInternal {
+		attacks = attack.ExcludeInternalAttacks(attacks, ourFactionID)
+	}
+
 	// Use domain function to identify factions
 	factions := wardomain.IdentifyWarFactions(war, ourFactionID)
 	summary.OurFaction = factions.OurFaction
 	summary.EnemyFaction = factions.EnemyFaction
 
 	// Use domain function to calculate attack statistics
-	stats := attack.CalculateAttackStatistics(attacks, ourFactionID)
+	stats := attack.CalculateAttackStatisticsWithOutcomes(attacks, ourFactionID, wss.outcomes)
+
+	// Unknown results count as neutral; log them so new result types get classified
+	if unknown := attack.FindUnknownAttackResultsWithOutcomes(attacks, wss.outcomes); len(unknown) > 0 {
+		log.Debug().
+			Int("war_id", war.ID).
+			Strs("unknown_results", unknown).
+			Msg("Attack results not recognised by outcome classifier - counted as neutral")
+	}
 	summary.TotalAttacks = stats.TotalAttacks
 	summary.AttacksWon = stats.AttacksWon
 	summary.AttacksLost = stats.AttacksLost
+	summary.AttacksDrawn = stats.AttacksDrawn
 	summary.RespectGained = stats.RespectGained
 	summary.RespectLost = stats.RespectLost
+	summary.NetRespect = stats.RespectGained - stats.RespectLost
+
+	// Expose both win rates; the headline rate follows configuration (strict by default)
+	summary.StrictWinRate = attack.CalculateWinRate(stats, false)
+	summary.HalfCreditWinRate = attack.CalculateWinRate(stats, true)
+	summary.WinRate = summary.StrictWinRate
+	if wss.halfCreditWinRate {
+		summary.WinRate = summary.HalfCreditWinRate
+	}
+
+	summary.DefensiveAttacks = stats.DefensiveAttacks
+	summary.DefensiveSuccessRate = attack.CalculateDefensiveSuccessRate(stats)
+
+	summary.AverageModifiers = attack.CalculateAverageModifiers(attacks, ourFactionID)
+	summary.OverseasAttacks = attack.CountOverseasAttacks(attacks, ourFactionID)
+	summary.InterruptedAttacks = attack.CountInterruptedAttacks(attacks, ourFactionID)
+	summary.OutgoingAttacks, summary.IncomingAttacks, summary.UnknownDirectionAttacks = attack.CountAttacksByDirection(attacks, ourFactionID)
+	summary.OutcomeCounts = attack.CountOutgoingOutcomes(attacks, ourFactionID)
+
+	averageRespect := attack.CalculateAverageRespectPerWin(attacks, ourFactionID, wss.outcomes)
+	summary.AttacksToOvertake = wardomain.CalculateAttacksToOvertake(summary.OurFaction.Score, summary.EnemyFaction.Score, averageRespect)
 
 	// Set war name based on factions
 	summary.WarName = fmt.Sprintf("%s vs %s", summary.OurFaction.Name, summary.EnemyFaction.Name)
@@ -61,9 +107,93 @@ func (wss *WarSummaryService) GenerateWarSummary(war *app.War, attacks []app.Att
 		Int("total_attacks", summary.TotalAttacks).
 		Int("attacks_won", summary.AttacksWon).
 		Int("attacks_lost", summary.AttacksLost).
+		Float64("win_rate", summary.WinRate).
+		Int("defensive_attacks", summary.DefensiveAttacks).
+		Float64("defensive_success_rate", summary.DefensiveSuccessRate).
 		Float64("respect_gained", summary.RespectGained).
 		Float64("respect_lost", summary.RespectLost).
+		Float64("net_respect", summary.NetRespect).
+		Int("attacks_to_overtake", summary.AttacksToOvertake).
 		Msg("Generated war summary")
 
 	return summary
 }
+
+// ConvertSummaryToJSON converts a war summary to its JSON export format
+func (wss *WarSummaryService) ConvertSummaryToJSON(summary *app.WarSummary) app.WarSummaryJSON {
+	var end *string
+	if summary.EndTime != nil {
+		formatted := summary.EndTime.UTC().Format(time.RFC3339)
+		end = &formatted
+	}
+
+	var attacksToOvertake *int
+	if summary.AttacksToOvertake >= 0 {
+		count := summary.AttacksToOvertake
+		attacksToOvertake = &count
+	}
+
+	return app.WarSummaryJSON{
+		WarID:                   summary.WarID,
+		WarType:                 summary.WarType,
+		WarName:                 summary.WarName,
+		Status:                  summary.Status,
+		Start:                   summary.StartTime.UTC().Format(time.RFC3339),
+		End:                     end,
+		LastUpdated:             summary.LastUpdated.UTC().Format(time.RFC3339),
+		OurFaction:              summary.OurFaction,
+		EnemyFaction:            summary.EnemyFaction,
+		TotalAttacks:            summary.TotalAttacks,
+		AttacksWon:              summary.AttacksWon,
+		AttacksLost:             summary.AttacksLost,
+		AttacksDrawn:            summary.AttacksDrawn,
+		RespectGained:           summary.RespectGained,
+		RespectLost:             summary.RespectLost,
+		NetRespect:              summary.NetRespect,
+		WinRate:                 summary.WinRate,
+		StrictWinRate:           summary.StrictWinRate,
+		HalfCreditWinRate:       summary.HalfCreditWinRate,
+		DefensiveAttacks:        summary.DefensiveAttacks,
+		DefensiveSuccessRate:    summary.DefensiveSuccessRate,
+		TargetScore:             summary.TargetScore,
+		ProgressPercent:         summary.ProgressPercent,
+		AverageModifiers:        summary.AverageModifiers,
+		OverseasAttacks:         summary.OverseasAttacks,
+		InterruptedAttacks:      summary.InterruptedAttacks,
+		OutgoingAttacks:         summary.OutgoingAttacks,
+		IncomingAttacks:         summary.IncomingAttacks,
+		UnknownDirectionAttacks: summary.UnknownDirectionAttacks,
+		AttacksToOvertake:       attacksToOvertake,
+		OutcomeCounts:           summary.OutcomeCounts,
+		AvgHospitalSeconds:      summary.AvgHospitalTimeInflicted.Seconds(),
+	}
+}
+
+// ExportSummaryJSON writes the summary to summary_{warID}.json in the configured
+// directory, replacing any previous export. Does nothing when no directory is configured.
+func (wss *WarSummaryService) ExportSummaryJSON(summary *app.WarSummary) error {
+	if wss.summaryJSONDir == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(wss.summaryJSONDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create summary JSON directory: %w", err)
+	}
+
+	jsonBytes, err := json.MarshalIndent(wss.ConvertSummaryToJSON(summary), "", "    ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal war summary JSON: %w", err)
+	}
+
+	path := filepath.Join(wss.summaryJSONDir, fmt.Sprintf("summary_%d.json", summary.WarID))
+	if err := os.WriteFile(path, jsonBytes, 0o644); err != nil {
+		return fmt.Errorf("failed to write war summary JSON: %w", err)
+	}
+
+	log.Debug().
+		Int("war_id", summary.WarID).
+		Str("path", path).
+		Msg("Exported war summary JSON")
+
+	return nil
+}
@@ -0,0 +1,384 @@
+package services
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"torn_rw_stats/internal/app"
+	"torn_rw_stats/internal/domain/attack"
+)
+
+func TestWarSummaryService_DefensiveSuccessRate(t *testing.T) {
+	ourFaction := &app.Faction{ID: 1001, Name: "Our Faction"}
+	enemyFaction := &app.Faction{ID: 2002, Name: "Enemy Faction"}
+
+	war := &app.War{
+		ID:       123,
+		Start:    1700000000,
+		Factions: []app.Faction{*ourFaction, *enemyFaction},
+	}
+
+	incoming := func(result string) app.Attack {
+		a := app.Attack{Result: result}
+		a.Attacker.Faction = enemyFaction
+		a.Defender.Faction = ourFaction
+		return a
+	}
+	outgoing := func(result string) app.Attack {
+		a := app.Attack{Result: result}
+		a.Attacker.Faction = ourFaction
+		a.Defender.Faction = enemyFaction
+		return a
+	}
+
+	attacks := []app.Attack{
+		incoming("Hospitalized"),
+		incoming("Hospitalized"),
+		incoming("Hospitalized"),
+		incoming("Stalemate"),
+		outgoing("Hospitalized"),
+	}
+
+	service := NewWarSummaryService(attack.NewAttackProcessingService(), &app.Config{})
+	summary := service.GenerateWarSummary(war, attacks, ourFaction.ID)
+
+	if summary.DefensiveAttacks != 4 {
+		t.Errorf("expected 4 defensive attacks, got %d", summary.DefensiveAttacks)
+	}
+	if math.Abs(summary.DefensiveSuccessRate-25) > 1e-9 {
+		t.Errorf("expected defensive success rate 25%%, got %f", summary.DefensiveSuccessRate)
+	}
+}
+
+func TestWarSummaryService_DefensiveSuccessRateNoIncomingAttacks(t *testing.T) {
+	ourFaction := &app.Faction{ID: 1001}
+	enemyFaction := &app.Faction{ID: 2002}
+	war := &app.War{ID: 123, Factions: []app.Faction{*ourFaction, *enemyFaction}}
+
+	a := app.Attack{Result: "Hospitalized"}
+	a.Attacker.Faction = ourFaction
+	a.Defender.Faction = enemyFaction
+
+	service := NewWarSummaryService(attack.NewAttackProcessingService(), &app.Config{})
+	summary := service.GenerateWarSummary(war, []app.Attack{a}, ourFaction.ID)
+
+	if summary.DefensiveAttacks != 0 {
+		t.Errorf("expected no defensive attacks, got %d", summary.DefensiveAttacks)
+	}
+	if summary.DefensiveSuccessRate != 0 || math.IsNaN(summary.DefensiveSuccessRate) {
+		t.Errorf("expected defensive success rate 0, got %f", summary.DefensiveSuccessRate)
+	}
+}
+
+func TestWarSummaryService_AverageModifiers(t *testing.T) {
+	ourFaction := &app.Faction{ID: 1001}
+	enemyFaction := &app.Faction{ID: 2002}
+	war := &app.War{ID: 123, Factions: []app.Faction{*ourFaction, *enemyFaction}}
+
+	first := app.Attack{Result: "Hospitalized", Modifiers: app.AttackModifiers{FairFight: 3, War: 2, Chain: 1.5}}
+	first.Attacker.Faction = ourFaction
+	first.Defender.Faction = enemyFaction
+
+	// No modifiers reported, but the attack still counts towards the averages
+	second := app.Attack{Result: "Hospitalized"}
+	second.Attacker.Faction = ourFaction
+	second.Defender.Faction = enemyFaction
+
+	// Incoming attacks do not affect our modifier averages
+	incoming := app.Attack{Result: "Hospitalized", Modifiers: app.AttackModifiers{FairFight: 5, War: 2}}
+	incoming.Attacker.Faction = enemyFaction
+	incoming.Defender.Faction = ourFaction
+
+	service := NewWarSummaryService(attack.NewAttackProcessingService(), &app.Config{})
+	summary := service.GenerateWarSummary(war, []app.Attack{first, second, incoming}, ourFaction.ID)
+
+	expected := app.AttackModifiers{FairFight: 1.5, War: 1, Chain: 0.75}
+	if summary.AverageModifiers != expected {
+		t.Errorf("expected average modifiers %+v, got %+v", expected, summary.AverageModifiers)
+	}
+}
+
+func TestWarSummaryService_OverseasAttacks(t *testing.T) {
+	ourFaction := &app.Faction{ID: 1001}
+	enemyFaction := &app.Faction{ID: 2002}
+	war := &app.War{ID: 123, Factions: []app.Faction{*ourFaction, *enemyFaction}}
+
+	overseas := app.Attack{Result: "Hospitalized", Modifiers: app.AttackModifiers{Overseas: 1.25}}
+	overseas.Attacker.Faction = ourFaction
+	overseas.Defender.Faction = enemyFaction
+
+	domestic := app.Attack{Result: "Hospitalized", Modifiers: app.AttackModifiers{Overseas: 1}}
+	domestic.Attacker.Faction = ourFaction
+	domestic.Defender.Faction = enemyFaction
+
+	service := NewWarSummaryService(attack.NewAttackProcessingService(), &app.Config{})
+	summary := service.GenerateWarSummary(war, []app.Attack{overseas, domestic}, ourFaction.ID)
+
+	if summary.OverseasAttacks != 1 {
+		t.Errorf("expected 1 overseas attack, got %d", summary.OverseasAttacks)
+	}
+}
+
+func TestWarSummaryService_InterruptedAttacks(t *testing.T) {
+	ourFaction := &app.Faction{ID: 1001}
+	enemyFaction := &app.Faction{ID: 2002}
+	war := &app.War{ID: 123, Factions: []app.Faction{*ourFaction, *enemyFaction}}
+
+	interrupted := app.Attack{Result: "Interrupted", IsInterrupted: true}
+	interrupted.Attacker.Faction = ourFaction
+	interrupted.Defender.Faction = enemyFaction
+
+	clean := app.Attack{Result: "Hospitalized"}
+	clean.Attacker.Faction = ourFaction
+	clean.Defender.Faction = enemyFaction
+
+	service := NewWarSummaryService(attack.NewAttackProcessingService(), &app.Config{})
+	summary := service.GenerateWarSummary(war, []app.Attack{interrupted, clean}, ourFaction.ID)
+
+	if summary.InterruptedAttacks != 1 {
+		t.Errorf("expected 1 interrupted attack, got %d", summary.InterruptedAttacks)
+	}
+}
+
+func TestWarSummaryService_AttackDirections(t *testing.T) {
+	ourFaction := &app.Faction{ID: 1001}
+	enemyFaction := &app.Faction{ID: 2002}
+	war := &app.War{ID: 123, Factions: []app.Faction{*ourFaction, *enemyFaction}}
+
+	outgoing := app.Attack{Result: "Hospitalized"}
+	outgoing.Attacker.Faction = ourFaction
+	outgoing.Defender.Faction = enemyFaction
+
+	incoming := app.Attack{Result: "Attacked"}
+	incoming.Attacker.Faction = enemyFaction
+	incoming.Defender.Faction = ourFaction
+
+	unknown := app.Attack{Result: "Attacked"}
+	unknown.Attacker.Faction = enemyFaction
+
+	service := NewWarSummaryService(attack.NewAttackProcessingService(), &app.Config{})
+	summary := service.GenerateWarSummary(war, []app.Attack{outgoing, outgoing, incoming, unknown}, ourFaction.ID)
+
+	if summary.OutgoingAttacks != 2 || summary.IncomingAttacks != 1 || summary.UnknownDirectionAttacks != 1 {
+		t.Errorf("expected 2 outgoing, 1 incoming and 1 unknown, got %d, %d and %d",
+			summary.OutgoingAttacks, summary.IncomingAttacks, summary.UnknownDirectionAttacks)
+	}
+}
+
+func TestWarSummaryService_NetRespect(t *testing.T) {
+	ourFaction := &app.Faction{ID: 1001}
+	enemyFaction := &app.Faction{ID: 2002}
+	war := &app.War{ID: 123, Factions: []app.Faction{*ourFaction, *enemyFaction}}
+
+	outgoing := app.Attack{Result: "Hospitalized", RespectGain: 125.75}
+	outgoing.Attacker.Faction = ourFaction
+	outgoing.Defender.Faction = enemyFaction
+
+	incoming := app.Attack{Result: "Hospitalized", RespectGain: 45.25}
+	incoming.Attacker.Faction = enemyFaction
+	incoming.Defender.Faction = ourFaction
+
+	service := NewWarSummaryService(attack.NewAttackProcessingService(), &app.Config{})
+	summary := service.GenerateWarSummary(war, []app.Attack{outgoing, incoming}, ourFaction.ID)
+
+	if summary.RespectGained != 125.75 || summary.RespectLost != 45.25 {
+		t.Fatalf("expected 125.75 gained and 45.25 lost, got %f and %f", summary.RespectGained, summary.RespectLost)
+	}
+	if math.Abs(summary.NetRespect-80.5) > 1e-9 {
+		t.Errorf("expected net respect 80.5, got %f", summary.NetRespect)
+	}
+}
+
+func TestWarSummaryService_AttacksToOvertake(t *testing.T) {
+	ourFaction := &app.Faction{ID: 1001}
+	enemyFaction := &app.Faction{ID: 2002}
+	war := &app.War{ID: 123, Factions: []app.Faction{
+		{ID: 1001, Score: 1000},
+		{ID: 2002, Score: 1100},
+	}}
+
+	// Two wins averaging 30 respect; the loss does not count towards the average
+	var attacks []app.Attack
+	for _, a := range []app.Attack{
+		{Result: "Hospitalized", RespectGain: 25},
+		{Result: "Attacked", RespectGain: 35},
+		{Result: "Lost", RespectGain: 0},
+	} {
+		a.Attacker.Faction = ourFaction
+		a.Defender.Faction = enemyFaction
+		attacks = append(attacks, a)
+	}
+
+	service := NewWarSummaryService(attack.NewAttackProcessingService(), &app.Config{})
+
+	// Trailing by 100 at 30 per hit needs 4 more hits to take the lead
+	summary := service.GenerateWarSummary(war, attacks, ourFaction.ID)
+	if summary.AttacksToOvertake != 4 {
+		t.Errorf("expected 4 attacks to overtake, got %d", summary.AttacksToOvertake)
+	}
+	if exported := service.ConvertSummaryToJSON(summary).AttacksToOvertake; exported == nil || *exported != 4 {
+		t.Errorf("expected 4 attacks to overtake in JSON, got %v", exported)
+	}
+
+	// Without any attacks the count is unknown and left out of the JSON
+	summary = service.GenerateWarSummary(war, nil, ourFaction.ID)
+	if summary.AttacksToOvertake >= 0 {
+		t.Errorf("expected an unknown count without attacks, got %d", summary.AttacksToOvertake)
+	}
+	if exported := service.ConvertSummaryToJSON(summary).AttacksToOvertake; exported != nil {
+		t.Errorf("expected attacks to overtake omitted from JSON, got %d", *exported)
+	}
+}
+
+func TestWarSummaryService_ExportSummaryJSON(t *testing.T) {
+	dir := t.TempDir()
+	service := NewWarSummaryService(attack.NewAttackProcessingService(), &app.Config{SummaryJSONDir: dir})
+
+	summary := &app.WarSummary{
+		WarID:         123,
+		WarType:       app.WarTypeRanked,
+		WarName:       "Our Faction vs Enemy Faction",
+		StartTime:     time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC),
+		Status:        "Active",
+		OurFaction:    app.Faction{ID: 1001, Name: "Our Faction", Score: 250},
+		EnemyFaction:  app.Faction{ID: 2002, Name: "Enemy Faction", Score: 100},
+		TotalAttacks:  10,
+		AttacksWon:    7,
+		RespectGained: 42.5,
+		WinRate:       70,
+		LastUpdated:   time.Date(2025, 1, 1, 14, 30, 0, 0, time.UTC),
+		AverageModifiers: app.AttackModifiers{
+			FairFight: 2.5,
+		},
+		InterruptedAttacks: 1,
+		OutcomeCounts:      map[string]int{"Hospitalized": 5, "Mugged": 2},
+	}
+
+	if err := service.ExportSummaryJSON(summary); err != nil {
+		t.Fatalf("ExportSummaryJSON() returned unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "summary_123.json"))
+	if err != nil {
+		t.Fatalf("failed to read exported summary: %v", err)
+	}
+
+	// Active wars must emit an explicit null end
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatalf("failed to unmarshal exported summary: %v", err)
+	}
+	if end, ok := raw["end"]; !ok || end != nil {
+		t.Errorf("expected \"end\": null for an active war, got %v (present: %v)", end, ok)
+	}
+
+	var exported app.WarSummaryJSON
+	if err := json.Unmarshal(data, &exported); err != nil {
+		t.Fatalf("failed to unmarshal exported summary: %v", err)
+	}
+	if exported.WarID != 123 || exported.Status != "Active" || exported.WarType != app.WarTypeRanked {
+		t.Errorf("unexpected war identity: %+v", exported)
+	}
+	if exported.Start != "2025-01-01T12:00:00Z" || exported.LastUpdated != "2025-01-01T14:30:00Z" {
+		t.Errorf("unexpected timestamps: start %q, last updated %q", exported.Start, exported.LastUpdated)
+	}
+	if exported.OurFaction.Score != 250 || exported.EnemyFaction.Name != "Enemy Faction" {
+		t.Errorf("unexpected factions: %+v vs %+v", exported.OurFaction, exported.EnemyFaction)
+	}
+	if exported.TotalAttacks != 10 || exported.AttacksWon != 7 || exported.RespectGained != 42.5 || exported.WinRate != 70 {
+		t.Errorf("unexpected attack totals: %+v", exported)
+	}
+	if exported.AverageModifiers.FairFight != 2.5 || exported.InterruptedAttacks != 1 {
+		t.Errorf("unexpected computed fields: %+v", exported)
+	}
+	if exported.OutcomeCounts["Hospitalized"] != 5 || exported.OutcomeCounts["Mugged"] != 2 || len(exported.OutcomeCounts) != 2 {
+		t.Errorf("unexpected outcome counts: %v", exported.OutcomeCounts)
+	}
+
+	// Completed wars carry their end time
+	end := time.Date(2025, 1, 2, 12, 0, 0, 0, time.UTC)
+	summary.EndTime = &end
+	if completed := service.ConvertSummaryToJSON(summary); completed.End == nil || *completed.End != "2025-01-02T12:00:00Z" {
+		t.Errorf("expected end 2025-01-02T12:00:00Z for a completed war, got %v", completed.End)
+	}
+}
+
+func TestWarSummaryService_ExportSummaryJSONDisabled(t *testing.T) {
+	service := NewWarSummaryService(attack.NewAttackProcessingService(), &app.Config{})
+
+	if err := service.ExportSummaryJSON(&app.WarSummary{WarID: 123}); err != nil {
+		t.Errorf("expected no error when the export is disabled, got %v", err)
+	}
+}
+
+func TestWarSummaryService_CustomWinLossResults(t *testing.T) {
+	ourFaction := &app.Faction{ID: 1001}
+	enemyFaction := &app.Faction{ID: 2002}
+	war := &app.War{ID: 123, Factions: []app.Faction{*ourFaction, *enemyFaction}}
+
+	outgoing := func(result string) app.Attack {
+		a := app.Attack{Result: result}
+		a.Attacker.Faction = ourFaction
+		a.Defender.Faction = enemyFaction
+		return a
+	}
+	attacks := []app.Attack{
+		outgoing("Looted"),
+		outgoing("Looted"),
+		outgoing("Mugged"),
+		outgoing("Lost"),
+		outgoing("Timeout"),
+	}
+
+	// Only looting counts as a win; mugging drops to neutral and default losses still apply
+	service := NewWarSummaryService(attack.NewAttackProcessingService(), &app.Config{WinResults: []string{"Looted"}})
+	summary := service.GenerateWarSummary(war, attacks, ourFaction.ID)
+
+	if summary.TotalAttacks != 5 || summary.AttacksWon != 2 || summary.AttacksLost != 2 {
+		t.Errorf("expected 5 attacks with 2 won and 2 lost, got %d with %d won and %d lost",
+			summary.TotalAttacks, summary.AttacksWon, summary.AttacksLost)
+	}
+	if math.Abs(summary.WinRate-40) > 1e-9 {
+		t.Errorf("expected win rate 40%%, got %f", summary.WinRate)
+	}
+
+	// Timeout is no longer a loss once LOSS_RESULTS replaces the defaults
+	service = NewWarSummaryService(attack.NewAttackProcessingService(), &app.Config{
+		WinResults:  []string{"Looted"},
+		LossResults: []string{"Lost"},
+	})
+	summary = service.GenerateWarSummary(war, attacks, ourFaction.ID)
+	if summary.AttacksWon != 2 || summary.AttacksLost != 1 {
+		t.Errorf("expected 2 won and 1 lost, got %d won and %d lost", summary.AttacksWon, summary.AttacksLost)
+	}
+}
+
+func TestWarSummaryService_InternalAttacks(t *testing.T) {
+	ourFaction := &app.Faction{ID: 1001}
+	enemyFaction := &app.Faction{ID: 2002}
+	war := &app.War{ID: 123, Factions: []app.Faction{*ourFaction, *enemyFaction}}
+
+	hit := app.Attack{Result: "Hospitalized", RespectGain: 3}
+	hit.Attacker.Faction = ourFaction
+	hit.Defender.Faction = enemyFaction
+
+	spar := app.Attack{Result: "Hospitalized", RespectGain: 0}
+	spar.Attacker.Faction = ourFaction
+	spar.Defender.Faction = ourFaction
+
+	service := NewWarSummaryService(attack.NewAttackProcessingService(), &app.Config{})
+	summary := service.GenerateWarSummary(war, []app.Attack{hit, spar}, ourFaction.ID)
+	if summary.TotalAttacks != 1 || summary.AttacksWon != 1 {
+		t.Errorf("expected the spar to be dropped, got %d attacks with %d won", summary.TotalAttacks, summary.AttacksWon)
+	}
+
+	service = NewWarSummaryService(attack.NewAttackProcessingService(), &app.Config{IncludeInternalAttacks: true})
+	summary = service.GenerateWarSummary(war, []app.Attack{hit, spar}, ourFaction.ID)
+	if summary.TotalAttacks != 2 {
+		t.Errorf("expected the spar to be kept when internal attacks are included, got %d attacks", summary.TotalAttacks)
+	}
+}
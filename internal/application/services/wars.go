@@ -2,12 +2,17 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"strconv"
+	"time"
 
 	"torn_rw_stats/internal/app"
 	"torn_rw_stats/internal/domain/attack"
 	"torn_rw_stats/internal/domain/travel"
 	wardomain "torn_rw_stats/internal/domain/war"
+	"torn_rw_stats/internal/export"
 	"torn_rw_stats/internal/processing"
 	"torn_rw_stats/internal/sheets"
 	"torn_rw_stats/internal/torn"
@@ -26,6 +31,23 @@ type WarProcessor struct {
 	travelTimeService processing.TravelTimeServiceInterface
 	attackService     processing.AttackProcessingServiceInterface
 	summaryService    processing.WarSummaryServiceInterface
+	csvExporter       *export.CSVExporter // nil = disabled
+	population        *PopulationProgress // wars whose last full fetch only partly succeeded
+	lastFullFetch     map[int]time.Time   // when each war was last fully populated
+	dashboard         *DashboardWriter
+	factionTags       *FactionTagCache
+	reprocess         *ReprocessState
+	now               func() time.Time
+	attacksProcessed  int                     // attacks fetched since the owning cycle reset the count
+	hospitalUntil     map[int]map[int64]int64 // war ID -> attack ID -> unix end of the hospital stay it caused
+	recentFetches     map[int]recentFetch     // war ID -> the records its latest fetch returned
+}
+
+// recentFetch is the records of a war's latest attack fetch and the span it covers:
+// every attack of the war started from from up to to is among records
+type recentFetch struct {
+	from, to time.Time
+	records  []app.AttackRecord
 }
 
 // NewWarProcessor creates a WarProcessor with interface dependencies for testability
@@ -38,15 +60,45 @@ func NewWarProcessor(
 	summaryService processing.WarSummaryServiceInterface,
 	config *app.Config,
 ) *WarProcessor {
+	var csvExporter *export.CSVExporter
+	if config.CSVExportDir != "" {
+		csvExporter = export.NewCSVExporter(config.CSVExportDir, sheetsClient.NewAttackRecordsProcessor())
+	}
+
+	reprocess, err := NewReprocessState(config.ReprocessStateFile)
+	if err != nil {
+		log.Warn().
+			Err(err).
+			Str("path", config.ReprocessStateFile).
+			Msg("Failed to load reprocess state - starting without last-processed times")
+	}
+
+	population, err := NewPopulationProgress(config.PopulationProgressFile)
+	if err != nil {
+		log.Warn().
+			Err(err).
+			Str("path", config.PopulationProgressFile).
+			Msg("Failed to load population progress - interrupted fetches will not resume")
+	}
+
 	return &WarProcessor{
 		tornClient:        tornClient,
 		sheetsClient:      sheetsClient,
 		config:            config,
-		ourFactionID:      0, // Will be initialized on first use
+		ourFactionID:      config.OurFactionID, // Zero is detected from the API on first use
 		locationService:   locationService,
 		travelTimeService: travelTimeService,
 		attackService:     attackService,
 		summaryService:    summaryService,
+		csvExporter:       csvExporter,
+		population:        population,
+		lastFullFetch:     make(map[int]time.Time),
+		dashboard:         NewDashboardWriter(sheetsClient, config.SpreadsheetID),
+		factionTags:       NewFactionTagCache(tornClient),
+		reprocess:         reprocess,
+		now:               time.Now,
+		hospitalUntil:     make(map[int]map[int64]int64),
+		recentFetches:     make(map[int]recentFetch),
 	}
 }
 
@@ -56,13 +108,16 @@ func NewWarProcessor(
 func NewOptimizedProcessor(tornClient *torn.Client, sheetsClient *sheets.Client, config *app.Config, bqClient processing.BigQueryClientInterface) *OptimizedWarProcessor {
 	// Create the attack processing service
 	attackService := attack.NewAttackProcessingService()
-	summaryService := NewWarSummaryService(attackService)
+	attackService.SetIncludeInternalAttacks(config.IncludeInternalAttacks)
+	summaryService := NewWarSummaryService(attackService, config)
+	travelTimeService := travel.NewTravelTimeService()
+	travelTimeService.SetMemberReductions(config.MemberTravelReductions)
 
 	return NewOptimizedWarProcessor(
 		tornClient,
 		sheetsClient,
 		travel.NewLocationService(),
-		travel.NewTravelTimeService(),
+		travelTimeService,
 		attackService,
 		summaryService,
 		config,
@@ -70,13 +125,21 @@ func NewOptimizedProcessor(tornClient *torn.Client, sheetsClient *sheets.Client,
 	)
 }
 
-// ensureOurFactionID fetches and caches our faction ID if not already set
+// ensureOurFactionID fetches and caches our faction ID if not already set or configured
 func (wp *WarProcessor) ensureOurFactionID(ctx context.Context) error {
 	if wp.ourFactionID == 0 {
 		log.Debug().Msg("Fetching our faction ID from API")
 
 		factionInfo, err := wp.tornClient.GetOwnFaction(ctx)
 		if err != nil {
+			if wp.config.OurFactionName != "" {
+				// Matched against war faction names once wars are fetched
+				log.Warn().
+					Err(err).
+					Str("faction_name", wp.config.OurFactionName).
+					Msg("Failed to get own faction info - falling back to matching our faction by name")
+				return nil
+			}
 			return fmt.Errorf("failed to get own faction info: %w", err)
 		}
 
@@ -90,6 +153,28 @@ func (wp *WarProcessor) ensureOurFactionID(ctx context.Context) error {
 	return nil
 }
 
+// resolveOurFactionByName sets our faction ID from the configured faction name when
+// it could not be detected from the API, matching against the factions in warResponse
+func (wp *WarProcessor) resolveOurFactionByName(warResponse *app.WarResponse) {
+	if wp.ourFactionID != 0 || wp.config.OurFactionName == "" {
+		return
+	}
+
+	factionID := wardomain.FindFactionIDByName(warResponse, wp.config.OurFactionName)
+	if factionID == 0 {
+		log.Warn().
+			Str("faction_name", wp.config.OurFactionName).
+			Msg("No war faction matches our faction name")
+		return
+	}
+
+	wp.ourFactionID = factionID
+	log.Info().
+		Int("faction_id", factionID).
+		Str("faction_name", wp.config.OurFactionName).
+		Msg("Detected our faction ID by name")
+}
+
 // ProcessActiveWars fetches current wars and processes each one
 func (wp *WarProcessor) ProcessActiveWars(ctx context.Context) error {
 	log.Info().Msg("Processing active wars")
@@ -103,6 +188,7 @@ func (wp *WarProcessor) ProcessActiveWars(ctx context.Context) error {
 	if err != nil {
 		return fmt.Errorf("failed to fetch faction wars: %w", err)
 	}
+	wp.resolveOurFactionByName(warResponse)
 
 	var processedWars int
 
@@ -112,7 +198,7 @@ func (wp *WarProcessor) ProcessActiveWars(ctx context.Context) error {
 			Int("war_id", warResponse.Wars.Ranked.ID).
 			Msg("Processing ranked war")
 
-		if err := wp.processWar(ctx, warResponse.Wars.Ranked); err != nil {
+		if err := wp.processWar(ctx, warResponse.Wars.Ranked, app.WarTypeRanked); err != nil {
 			log.Error().
 				Err(err).
 				Int("war_id", warResponse.Wars.Ranked.ID).
@@ -128,7 +214,7 @@ func (wp *WarProcessor) ProcessActiveWars(ctx context.Context) error {
 			Int("war_id", war.ID).
 			Msg("Processing raid war")
 
-		if err := wp.processWar(ctx, &war); err != nil {
+		if err := wp.processWar(ctx, &war, app.WarTypeRaid); err != nil {
 			log.Error().
 				Err(err).
 				Int("war_id", war.ID).
@@ -144,7 +230,7 @@ func (wp *WarProcessor) ProcessActiveWars(ctx context.Context) error {
 			Int("war_id", war.ID).
 			Msg("Processing territory war")
 
-		if err := wp.processWar(ctx, &war); err != nil {
+		if err := wp.processWar(ctx, &war, app.WarTypeTerritory); err != nil {
 			log.Error().
 				Err(err).
 				Int("war_id", war.ID).
@@ -154,6 +240,15 @@ func (wp *WarProcessor) ProcessActiveWars(ctx context.Context) error {
 		}
 	}
 
+	wp.forgetHospitalStays(warResponse)
+
+	// The dashboard is a convenience view, so a failure is logged rather than returned
+	if err := wp.dashboard.Write(ctx); err != nil {
+		log.Error().
+			Err(err).
+			Msg("Failed to write war dashboard")
+	}
+
 	log.Info().
 		Int("processed_wars", processedWars).
 		Msg("Completed processing active wars")
@@ -161,8 +256,59 @@ func (wp *WarProcessor) ProcessActiveWars(ctx context.Context) error {
 	return nil
 }
 
-// processWar handles processing a single war
-func (wp *WarProcessor) processWar(ctx context.Context, war *app.War) error {
+// newAttackProcessor creates an attack processor using the configured fetch settings
+func (wp *WarProcessor) newAttackProcessor() *torn.AttackProcessor {
+	processor := torn.NewAttackProcessor(wp.tornClient)
+	processor.SetIncrementalFetchBuffer(wp.config.IncrementalFetchBuffer)
+	pagination := attack.DefaultPaginationConfig()
+	pagination.MaxPages = wp.config.FetchMaxPages
+	processor.SetPagination(pagination)
+	return processor
+}
+
+// processWar handles processing a single war of the given type (app.WarTypeRanked etc.),
+// skipping it when its factions are malformed or it was already processed within the
+// configured reprocess interval
+func (wp *WarProcessor) processWar(ctx context.Context, war *app.War, warType string) error {
+	// Malformed faction lists would attribute our own attacks to the enemy
+	if err := wardomain.ValidateWarFactions(war, wp.ourFactionID); err != nil {
+		return fmt.Errorf("skipping war with invalid factions: %w", err)
+	}
+
+	startedAt := wp.now()
+	lastProcessed := wp.reprocess.LastProcessed(war.ID)
+	if wardomain.ShouldSkipReprocess(lastProcessed, startedAt, wp.config.MinReprocessInterval) {
+		log.Info().
+			Int("war_id", war.ID).
+			Time("last_processed", lastProcessed).
+			Dur("min_reprocess_interval", wp.config.MinReprocessInterval).
+			Msg("War skipped, processed recently")
+
+		// The dashboard is rewritten every cycle, so a skipped war keeps its last row or,
+		// before it has one, a row from the scores the API reports
+		if !wp.dashboard.KeepWar(war.ID) {
+			summary := wp.summaryService.GenerateWarSummary(war, nil, wp.ourFactionID)
+			summary.WarType = warType
+			wp.dashboard.AddWar(summary)
+		}
+		return nil
+	}
+
+	if err := wp.processWarAttacks(ctx, war, warType); err != nil {
+		return err
+	}
+
+	if err := wp.reprocess.MarkProcessed(war.ID, startedAt); err != nil {
+		log.Warn().
+			Err(err).
+			Int("war_id", war.ID).
+			Msg("Failed to save reprocess state")
+	}
+	return nil
+}
+
+// processWarAttacks fetches, records, and summarises a single war's attacks
+func (wp *WarProcessor) processWarAttacks(ctx context.Context, war *app.War, warType string) error {
 	log.Info().
 		Int("war_id", war.ID).
 		Int("factions_count", len(war.Factions)).
@@ -174,9 +320,16 @@ func (wp *WarProcessor) processWar(ctx context.Context, war *app.War) error {
 	if err != nil {
 		return fmt.Errorf("failed to ensure war sheets: %w", err)
 	}
+	sheetConfig.WarType = warType
+
+	if wp.config.SplitRecordsByDirection {
+		if err := wp.sheetsClient.EnsureDirectionSheets(ctx, wp.config.SpreadsheetID, sheetConfig); err != nil {
+			return fmt.Errorf("failed to ensure direction sheets: %w", err)
+		}
+	}
 
 	// Check if we have existing records to determine update mode
-	existingInfo, err := wp.sheetsClient.ReadExistingRecords(ctx, wp.config.SpreadsheetID, sheetConfig.RecordsTabName)
+	existingInfo, err := wp.readExistingRecords(ctx, sheetConfig)
 	if err != nil {
 		return fmt.Errorf("failed to read existing records: %w", err)
 	}
@@ -192,17 +345,97 @@ func (wp *WarProcessor) processWar(ctx context.Context, war *app.War) error {
 
 	// Fetch attacks based on decision
 	var attacks []app.Attack
-	processor := torn.NewAttackProcessor(wp.tornClient)
-	if fetchDecision.UseFullMode {
+	processor := wp.newAttackProcessor()
+	fetchTime := time.Now()
+	useFullMode := fetchDecision.UseFullMode
+	if !useFullMode && wardomain.ShouldForceFullRescan(wp.lastFullFetch[war.ID], fetchTime, wp.config.FullRescanInterval) {
+		log.Info().
+			Int("war_id", war.ID).
+			Time("last_full_fetch", wp.lastFullFetch[war.ID]).
+			Dur("full_rescan_interval", wp.config.FullRescanInterval).
+			Msg("Full re-scan interval elapsed, forcing full population mode")
+		useFullMode = true
+	}
+	// Full populations and resumes write each page as it is fetched and save how far
+	// they got, so a run killed part way resumes from there rather than the war's start
+	pages := wp.newPopulationWriter(war, sheetConfig, existingInfo)
+	if useFullMode {
+		processor.SetPageHandler(pages.page)
 		attacks, err = processor.GetAllAttacksForWar(ctx, war)
 	} else {
 		attacks, err = processor.GetAttacksForTimeRange(ctx, war, war.Start, &fetchDecision.LatestTimestamp)
 	}
 
-	if err != nil {
+	// A war whose last full fetch was cut short resumes with the older attacks it is
+	// still missing, since the incremental pass only covers attacks after the latest
+	// one written
+	var partialErr *torn.PartialFetchError
+	missingUntil, resuming := wp.population.MissingUntil(war.ID)
+	resuming = resuming && !useFullMode && err == nil
+	if resuming {
+		log.Info().
+			Int("war_id", war.ID).
+			Int64("missing_until", missingUntil).
+			Msg("Resuming interrupted full population")
+
+		processor.SetPageHandler(pages.page)
+		older, resumeErr := wp.fetchMissingAttacks(ctx, processor, war, missingUntil)
+		attacks = attack.MergeAttacks(older, attacks)
+		if resumeErr != nil && !errors.As(resumeErr, &partialErr) {
+			// The new attacks are still written; the missing range is retried next cycle
+			log.Warn().
+				Err(resumeErr).
+				Int("war_id", war.ID).
+				Msg("Failed to resume interrupted full population - retrying next cycle")
+			resuming = false
+		} else {
+			err = resumeErr
+		}
+	}
+
+	// Attacks recovered for an interrupted population, and those a full re-scan finds
+	// missing, predate the rows already written, so those batches are deduplicated by
+	// code and attack ID only
+	backfill := resuming || (useFullMode && existingInfo.RecordCount > 0)
+
+	switch {
+	case errors.As(err, &partialErr):
+		// Write what we got; the older pages are fetched next cycle and backfilled
+		log.Warn().
+			Err(err).
+			Int("war_id", war.ID).
+			Int("partial_attacks", len(attacks)).
+			Int64("missing_until", partialErr.MissingUntil).
+			Msg("Writing partial attack fetch, remaining attacks scheduled for next cycle")
+		if err := pages.finish(ctx, false); err != nil {
+			return err
+		}
+		if err := wp.population.MarkIncomplete(war.ID, partialErr.MissingUntil); err != nil {
+			log.Warn().
+				Err(err).
+				Int("war_id", war.ID).
+				Msg("Failed to save population progress")
+		}
+	case err != nil:
 		return fmt.Errorf("failed to fetch attacks for war: %w", err)
+	case useFullMode || resuming:
+		if err := pages.finish(ctx, true); err != nil {
+			return err
+		}
+		if err := wp.population.MarkComplete(war.ID); err != nil {
+			log.Warn().
+				Err(err).
+				Int("war_id", war.ID).
+				Msg("Failed to save population progress")
+		}
+		wp.lastFullFetch[war.ID] = fetchTime
+	case wp.lastFullFetch[war.ID].IsZero():
+		// Wars already populated before startup start the re-scan clock at first sight
+		wp.lastFullFetch[war.ID] = fetchTime
 	}
 
+	wp.attacksProcessed += len(attacks)
+
 	log.Debug().
 		Int("war_id", war.ID).
 		Int("attacks_count", len(attacks)).
@@ -213,13 +446,29 @@ func (wp *WarProcessor) processWar(ctx context.Context, war *app.War) error {
 
 	// Process attack data into records
 	records := wp.attackService.ProcessAttacksIntoRecords(attacks, war, ourFactionID)
+	wp.factionTags.ApplyFactionTags(ctx, records)
+	// Only a fetch that reached back to the war's start can hold its first blood
+	fromWarStart := (useFullMode || resuming) && partialErr == nil
+	attack.MarkFirstAndLastHits(records, fromWarStart, existingInfo.EarliestTimestamp, existingInfo.LatestTimestamp)
+
+	// Kept for the attack pace check, which needs no fetch of its own
+	fetchedFrom := war.Start
+	switch {
+	case !useFullMode:
+		fetchedFrom = attack.CalculateTimeRangeWithBuffer(war, &fetchDecision.LatestTimestamp, fetchTime.Unix(), wp.config.IncrementalFetchBuffer).FromTime
+	case partialErr != nil:
+		fetchedFrom = partialErr.MissingUntil + 1
+	}
+	wp.recentFetches[war.ID] = recentFetch{from: time.Unix(fetchedFrom, 0), to: fetchTime, records: records}
 
-	// Check for duplicates in processed records
+	// Check for duplicates in processed records, by code or by attack ID
 	codeCount := make(map[string]int)
+	idCount := make(map[int64]int)
 	var duplicateRecords []string
 	for _, record := range records {
 		codeCount[record.Code]++
-		if codeCount[record.Code] == 2 {
+		idCount[record.AttackID]++
+		if codeCount[record.Code] == 2 || idCount[record.AttackID] == 2 {
 			duplicateRecords = append(duplicateRecords, fmt.Sprintf("ID:%d Code:%s", record.AttackID, record.Code))
 		}
 	}
@@ -227,33 +476,472 @@ func (wp *WarProcessor) processWar(ctx context.Context, war *app.War) error {
 	if len(duplicateRecords) > 0 {
 		log.Error().
 			Int("total_records", len(records)).
-			Int("duplicate_codes", len(duplicateRecords)).
+			Int("duplicate_records", len(duplicateRecords)).
 			Strs("duplicate_records", duplicateRecords).
 			Msg("=== DUPLICATES DETECTED IN PROCESSED RECORDS ===")
 	}
 
-	// Generate war summary
+	if err := wp.writeWarSummary(ctx, war, warType, sheetConfig, attacks); err != nil {
+		return err
+	}
+	if err := wp.writeWarResults(ctx, war, sheetConfig, records, pages.written, backfill); err != nil {
+		return err
+	}
+
+	log.Info().
+		Int("war_id", war.ID).
+		Int("attacks_processed", len(attacks)).
+		Int("records_created", len(records)).
+		Msg("=== EXITING processWar - Successfully processed war ===")
+
+	return nil
+}
+
+// writeWarSummary generates the war summary from a cycle's attacks and writes it to the
+// Summary sheet, the dashboard and the summary JSON export
+func (wp *WarProcessor) writeWarSummary(ctx context.Context, war *app.War, warType string, sheetConfig *app.SheetConfig, attacks []app.Attack) error {
+	ourFactionID := wp.getOurFactionID(war)
+
+	wp.recordHospitalStays(ctx, war, attacks, ourFactionID)
 	summary := wp.summaryService.GenerateWarSummary(war, attacks, ourFactionID)
+	summary.AvgHospitalTimeInflicted = attack.CalculateAverageHospitalTime(attacks, ourFactionID, wp.hospitalUntil[war.ID])
+	summary.WarType = warType
+	if warType == app.WarTypeRaid {
+		summary.TargetScore = war.Target
+		summary.ProgressPercent = wardomain.CalculateRaidProgress(summary.OurFaction.Score, war.Target)
+	}
 
 	// Update sheets
 	if err := wp.sheetsClient.UpdateWarSummary(ctx, wp.config.SpreadsheetID, sheetConfig, summary); err != nil {
 		return fmt.Errorf("failed to update war summary: %w", err)
 	}
+	wp.dashboard.AddWar(summary)
 
-	if err := wp.sheetsClient.UpdateAttackRecords(ctx, wp.config.SpreadsheetID, sheetConfig, records); err != nil {
+	// The summary JSON export is secondary to Sheets, so a failure is logged rather than returned
+	if err := wp.summaryService.ExportSummaryJSON(summary); err != nil {
+		log.Error().
+			Err(err).
+			Int("war_id", war.ID).
+			Msg("Failed to export war summary JSON")
+	}
+
+	return nil
+}
+
+// writeWarResults writes the attack records and every sheet and export derived from
+// them, skipping records whose attack IDs are in written, which are already on the
+// records sheets. With backfill, records are deduplicated by code and attack ID only,
+// since they may predate the newest row.
+func (wp *WarProcessor) writeWarResults(ctx context.Context, war *app.War, sheetConfig *app.SheetConfig, records []app.AttackRecord, written map[int64]bool, backfill bool) error {
+	ourFactionID := wp.getOurFactionID(war)
+
+	unwritten := records
+	if len(written) > 0 {
+		unwritten = slices.DeleteFunc(slices.Clone(records), func(record app.AttackRecord) bool {
+			return written[record.AttackID]
+		})
+	}
+
+	// Batches that may predate the newest row already written are deduplicated by code
+	// and attack ID only
+	writeRecords := wp.sheetsClient.UpdateAttackRecords
+	if backfill {
+		writeRecords = wp.sheetsClient.BackfillAttackRecords
+	}
+	if err := writeRecords(ctx, wp.config.SpreadsheetID, sheetConfig, unwritten); err != nil {
 		return fmt.Errorf("failed to update attack records: %w", err)
 	}
 
+	// During an active war a long stretch without attacks usually means a missed page
+	if war.End == nil {
+		wp.warnOnAttackGaps(war, records)
+	}
+
+	// CSV export is secondary to Sheets, so a failure is logged rather than returned
+	if wp.csvExporter != nil {
+		if err := wp.csvExporter.ExportRecords(war.ID, records); err != nil {
+			log.Error().
+				Err(err).
+				Int("war_id", war.ID).
+				Msg("Failed to export attack records to CSV")
+		}
+	}
+
+	// Sheets rebuilt each cycle cover the whole war, so they are built from every record
+	// on the records sheets rather than this cycle's batch
+	warRecords, err := wp.readWarRecords(ctx, sheetConfig)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Int("war_id", war.ID).
+			Msg("Failed to read war records - derived sheets only cover this cycle's attacks")
+		warRecords = records
+	}
+
+	// The records are already written, so a failed leaderboard is logged rather than returned
+	leaderboard := attack.BuildMemberLeaderboard(warRecords, ourFactionID)
+	if err := wp.sheetsClient.UpdateLeaderboard(ctx, wp.config.SpreadsheetID, war.ID, leaderboard); err != nil {
+		log.Error().
+			Err(err).
+			Int("war_id", war.ID).
+			Msg("Failed to update leaderboard")
+	}
+
+	threats := attack.BuildIncomingThreats(warRecords, ourFactionID)
+	if err := wp.sheetsClient.UpdateIncomingThreats(ctx, wp.config.SpreadsheetID, war.ID, threats); err != nil {
+		log.Error().
+			Err(err).
+			Int("war_id", war.ID).
+			Msg("Failed to update incoming threats")
+	}
+
+	// Members who have not attacked at all only count as slacking while the war is on
+	activeWar := war.End == nil
+	var roster map[int]string
+	if activeWar {
+		roster = wp.ourRoster(ctx)
+	}
+	flags := attack.FlagParticipation(warRecords, ourFactionID, roster, float64(wp.config.ParticipationDeviation), activeWar)
+	if err := wp.sheetsClient.UpdateParticipationFlags(ctx, wp.config.SpreadsheetID, war.ID, flags); err != nil {
+		log.Error().
+			Err(err).
+			Int("war_id", war.ID).
+			Msg("Failed to update participation flags")
+	}
+
+	counterattacks := attack.FindCounterattacks(warRecords, attack.DefaultCounterattackWindow)
+	if err := wp.sheetsClient.UpdateCounterattacks(ctx, wp.config.SpreadsheetID, war.ID, counterattacks); err != nil {
+		log.Error().
+			Err(err).
+			Int("war_id", war.ID).
+			Msg("Failed to update counterattacks")
+	}
+
+	trendEnd := time.Now()
+	if war.End != nil {
+		trendEnd = time.Unix(*war.End, 0)
+	}
+	trend := attack.BuildRespectTrend(warRecords, ourFactionID, time.Unix(war.Start, 0), trendEnd, wp.config.RespectTrendWindow)
+	if err := wp.sheetsClient.UpdateRespectTrend(ctx, wp.config.SpreadsheetID, war.ID, trend); err != nil {
+		log.Error().
+			Err(err).
+			Int("war_id", war.ID).
+			Msg("Failed to update respect trend")
+	}
+
+	return nil
+}
+
+// ProcessAttacksInRange re-fetches attacks for the current war between from and to and
+// writes them to its records sheets, then refreshes the derived sheets from every record
+// as a regular cycle does. The summary, dashboard and summary JSON are left to the next
+// regular cycle, since the range's attacks alone would understate them. The range usually
+// predates the newest row, so records are only deduplicated by code and attack ID, and
+// re-running the same range does not duplicate rows.
+func (wp *WarProcessor) ProcessAttacksInRange(ctx context.Context, from, to time.Time) error {
+	if err := wp.ensureOurFactionID(ctx); err != nil {
+		return fmt.Errorf("failed to initialize faction ID: %w", err)
+	}
+
+	warResponse, err := wp.tornClient.GetFactionWars(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch faction wars: %w", err)
+	}
+	wp.resolveOurFactionByName(warResponse)
+
+	war, warType := currentWar(warResponse, wp.config.PinnedWarID)
+	if war == nil {
+		return fmt.Errorf("no active war to fetch attacks for")
+	}
+
 	log.Info().
 		Int("war_id", war.ID).
-		Int("attacks_processed", len(attacks)).
+		Str("war_type", warType).
+		Time("from", from).
+		Time("to", to).
+		Msg("Fetching attacks for time range")
+
+	sheetConfig, err := wp.sheetsClient.EnsureWarSheets(ctx, wp.config.SpreadsheetID, war)
+	if err != nil {
+		return fmt.Errorf("failed to ensure war sheets: %w", err)
+	}
+	sheetConfig.WarType = warType
+
+	if wp.config.SplitRecordsByDirection {
+		if err := wp.sheetsClient.EnsureDirectionSheets(ctx, wp.config.SpreadsheetID, sheetConfig); err != nil {
+			return fmt.Errorf("failed to ensure direction sheets: %w", err)
+		}
+	}
+
+	existingInfo, err := wp.readExistingRecords(ctx, sheetConfig)
+	if err != nil {
+		return fmt.Errorf("failed to read existing records: %w", err)
+	}
+
+	attacks, err := wp.newAttackProcessor().GetAttacksBetween(ctx, war, from, to)
+	if err != nil {
+		return fmt.Errorf("failed to fetch attacks for time range: %w", err)
+	}
+
+	records := wp.attackService.ProcessAttacksIntoRecords(attacks, war, wp.getOurFactionID(war))
+	wp.factionTags.ApplyFactionTags(ctx, records)
+	fromWarStart := !from.After(time.Unix(war.Start, 0))
+	attack.MarkFirstAndLastHits(records, fromWarStart, existingInfo.EarliestTimestamp, existingInfo.LatestTimestamp)
+	if err := wp.writeWarResults(ctx, war, sheetConfig, records, nil, true); err != nil {
+		return err
+	}
+
+	log.Info().
+		Int("war_id", war.ID).
+		Int("attacks_fetched", len(attacks)).
 		Int("records_created", len(records)).
-		Msg("=== EXITING processWar - Successfully processed war ===")
+		Msg("Completed fetching attacks for time range")
 
 	return nil
 }
 
+// ValidateSheets ensures the current or upcoming war's sheets exist, then compares their
+// header rows and summary labels against the expected layout, returning every mismatch
+func (wp *WarProcessor) ValidateSheets(ctx context.Context) ([]string, error) {
+	warResponse, err := wp.tornClient.GetFactionWars(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch faction wars: %w", err)
+	}
+
+	war, warType := currentWar(warResponse, wp.config.PinnedWarID)
+	if war == nil {
+		return nil, fmt.Errorf("no current or upcoming war to validate sheets for")
+	}
+
+	sheetConfig, err := wp.sheetsClient.EnsureWarSheets(ctx, wp.config.SpreadsheetID, war)
+	if err != nil {
+		return nil, fmt.Errorf("failed to ensure war sheets: %w", err)
+	}
+	sheetConfig.WarType = warType
+
+	if wp.config.SplitRecordsByDirection {
+		if err := wp.sheetsClient.EnsureDirectionSheets(ctx, wp.config.SpreadsheetID, sheetConfig); err != nil {
+			return nil, fmt.Errorf("failed to ensure direction sheets: %w", err)
+		}
+	}
+
+	mismatches, err := wp.sheetsClient.ValidateWarSheets(ctx, wp.config.SpreadsheetID, sheetConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to validate war sheets: %w", err)
+	}
+
+	log.Info().
+		Int("war_id", war.ID).
+		Str("war_type", warType).
+		Int("mismatches", len(mismatches)).
+		Msg("Validated war sheets")
+
+	return mismatches, nil
+}
+
+// currentWar picks the war to use for one-shot operations: the pinned war if it is in
+// the response, otherwise the ranked war if there is one, otherwise the first raid or
+// territory war
+func currentWar(warResponse *app.WarResponse, pinnedWarID int) (*app.War, string) {
+	if pinnedWarID != 0 {
+		if ranked := warResponse.Wars.Ranked; ranked != nil && ranked.ID == pinnedWarID {
+			return ranked, app.WarTypeRanked
+		}
+		for i := range warResponse.Wars.Raids {
+			if warResponse.Wars.Raids[i].ID == pinnedWarID {
+				return &warResponse.Wars.Raids[i], app.WarTypeRaid
+			}
+		}
+		for i := range warResponse.Wars.Territory {
+			if warResponse.Wars.Territory[i].ID == pinnedWarID {
+				return &warResponse.Wars.Territory[i], app.WarTypeTerritory
+			}
+		}
+	}
+	if warResponse.Wars.Ranked != nil {
+		return warResponse.Wars.Ranked, app.WarTypeRanked
+	}
+	if len(warResponse.Wars.Raids) > 0 {
+		return &warResponse.Wars.Raids[0], app.WarTypeRaid
+	}
+	if len(warResponse.Wars.Territory) > 0 {
+		return &warResponse.Wars.Territory[0], app.WarTypeTerritory
+	}
+	return nil, ""
+}
+
+// warnOnAttackGaps logs a warning for every suspiciously long gap between consecutive
+// attack records, returning the gaps it reported
+func (wp *WarProcessor) warnOnAttackGaps(war *app.War, records []app.AttackRecord) []attack.AttackGap {
+	gaps := attack.FindAttackGaps(records, wp.config.SuspiciousGapThreshold)
+	if len(gaps) == 0 {
+		return nil
+	}
+
+	boundaries := make([]string, len(gaps))
+	for i, gap := range gaps {
+		boundaries[i] = fmt.Sprintf("%s - %s (%s)",
+			gap.From.UTC().Format("2006-01-02 15:04:05"),
+			gap.To.UTC().Format("2006-01-02 15:04:05"),
+			gap.Duration())
+	}
+
+	log.Warn().
+		Int("war_id", war.ID).
+		Int("gap_count", len(gaps)).
+		Strs("gaps", boundaries).
+		Msg("Suspicious gaps between attack timestamps - a page may have been missed")
+
+	return gaps
+}
+
+// fetchMissingAttacks fetches the attacks an interrupted full population did not reach,
+// from the war start up to missingUntil
+func (wp *WarProcessor) fetchMissingAttacks(ctx context.Context, processor *torn.AttackProcessor, war *app.War, missingUntil int64) ([]app.Attack, error) {
+	if missingUntil <= war.Start {
+		return nil, nil
+	}
+	return processor.GetAttacksBetween(ctx, war, time.Unix(war.Start, 0), time.Unix(missingUntil, 0))
+}
+
+// hospitalAttributionWindow is how recently a hospitalizing hit must have ended for the
+// enemy's current hospital stays to be fetched and credited to it
+const hospitalAttributionWindow = time.Hour
+
+// recordHospitalStays credits the enemy's current hospital stays to our recent
+// hospitalizing attacks, keeping each attack's first observed stay end. The enemy
+// roster is only fetched when a recent hospitalizing hit has no stay recorded yet;
+// within a cycle it is usually served from the members state tracking already fetched.
+func (wp *WarProcessor) recordHospitalStays(ctx context.Context, war *app.War, attacks []app.Attack, ourFactionID int) {
+	stays := wp.hospitalUntil[war.ID]
+	if stays == nil {
+		stays = make(map[int64]int64)
+		wp.hospitalUntil[war.ID] = stays
+	}
+
+	cutoff := wp.now().Add(-hospitalAttributionWindow).Unix()
+	pending := false
+	for _, a := range attacks {
+		if _, recorded := stays[a.ID]; !recorded && a.Ended >= cutoff &&
+			a.Result == attack.HospitalizedResult && attack.IsOurAttack(a, ourFactionID) {
+			pending = true
+			break
+		}
+	}
+	if !pending {
+		return
+	}
+
+	enemyFactionID := wardomain.IdentifyWarFactions(war, ourFactionID).EnemyFaction.ID
+	faction, err := wp.tornClient.GetFactionBasic(ctx, enemyFactionID)
+	if err != nil || faction == nil {
+		log.Debug().
+			Err(err).
+			Int("war_id", war.ID).
+			Int("faction_id", enemyFactionID).
+			Msg("Failed to fetch enemy members for hospital time - retrying next cycle")
+		return
+	}
+
+	for attackID, until := range attack.AttributeHospitalStays(attacks, ourFactionID, faction.Members) {
+		if _, recorded := stays[attackID]; !recorded {
+			stays[attackID] = until
+		}
+	}
+}
+
+// forgetHospitalStays drops the recorded hospital stays of wars no longer in warResponse
+func (wp *WarProcessor) forgetHospitalStays(warResponse *app.WarResponse) {
+	current := make(map[int]bool)
+	if warResponse.Wars.Ranked != nil {
+		current[warResponse.Wars.Ranked.ID] = true
+	}
+	for _, war := range warResponse.Wars.Raids {
+		current[war.ID] = true
+	}
+	for _, war := range warResponse.Wars.Territory {
+		current[war.ID] = true
+	}
+
+	for warID := range wp.hospitalUntil {
+		if !current[warID] {
+			delete(wp.hospitalUntil, warID)
+		}
+	}
+}
+
+// ourRoster returns our faction's members by ID, or nil when they cannot be fetched
+// so participation is judged on attackers alone
+func (wp *WarProcessor) ourRoster(ctx context.Context) map[int]string {
+	faction, err := wp.tornClient.GetFactionBasic(ctx, wp.ourFactionID)
+	if err != nil || faction == nil {
+		log.Warn().
+			Err(err).
+			Int("faction_id", wp.ourFactionID).
+			Msg("Failed to fetch our members for participation flags - members without attacks are not flagged")
+		return nil
+	}
+
+	roster := make(map[int]string, len(faction.Members))
+	for id, member := range faction.Members {
+		memberID, err := strconv.Atoi(id)
+		if err != nil {
+			continue
+		}
+		roster[memberID] = member.Name
+	}
+	return roster
+}
+
 // getOurFactionID determines which faction is "ours" in the war
 func (wp *WarProcessor) getOurFactionID(war *app.War) int {
 	return wp.ourFactionID
 }
+
+// readWarRecords reads every attack record of a war back from all of its records sheets
+func (wp *WarProcessor) readWarRecords(ctx context.Context, sheetConfig *app.SheetConfig) ([]app.AttackRecord, error) {
+	var records []app.AttackRecord
+	for _, tabName := range sheetConfig.AllRecordsTabNames() {
+		tabRecords, err := wp.sheetsClient.ReadAttackRecords(ctx, wp.config.SpreadsheetID, tabName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read records from %s: %w", tabName, err)
+		}
+		records = append(records, tabRecords...)
+	}
+	return records, nil
+}
+
+// readExistingRecords combines existing record information across every records
+// sheet of a war, so split sheets are treated as one set when deciding fetch mode
+func (wp *WarProcessor) readExistingRecords(ctx context.Context, sheetConfig *app.SheetConfig) (*sheets.RecordsInfo, error) {
+	combined := &sheets.RecordsInfo{
+		AttackCodes: make(map[string]bool),
+		AttackIDs:   make(map[int64]bool),
+	}
+
+	for _, tabName := range sheetConfig.AllRecordsTabNames() {
+		info, err := wp.sheetsClient.ReadExistingRecords(ctx, wp.config.SpreadsheetID, tabName)
+		if err != nil {
+			return nil, err
+		}
+		if info == nil {
+			continue
+		}
+
+		for code := range info.AttackCodes {
+			combined.AttackCodes[code] = true
+		}
+		for attackID := range info.AttackIDs {
+			combined.AttackIDs[attackID] = true
+		}
+		combined.RecordCount += info.RecordCount
+		if info.LatestTimestamp > combined.LatestTimestamp {
+			combined.LatestTimestamp = info.LatestTimestamp
+		}
+		if info.EarliestTimestamp != 0 && (combined.EarliestTimestamp == 0 || info.EarliestTimestamp < combined.EarliestTimestamp) {
+			combined.EarliestTimestamp = info.EarliestTimestamp
+		}
+	}
+
+	return combined, nil
+}
@@ -0,0 +1,1203 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"torn_rw_stats/internal/app"
+	"torn_rw_stats/internal/domain/attack"
+	"torn_rw_stats/internal/processing/mocks"
+	"torn_rw_stats/internal/sheets"
+)
+
+func TestWarProcessor_TerritoryWarType(t *testing.T) {
+	tornMock := mocks.NewMockTornClient()
+	tornMock.OwnFactionResponse = &app.FactionInfoResponse{ID: 100, Name: "Us"}
+	tornMock.FactionAttacksResponse = &app.AttackResponse{}
+
+	warResponse := &app.WarResponse{}
+	warResponse.Wars.Territory = []app.War{{
+		ID:       4242,
+		Start:    time.Now().Add(-time.Hour).Unix(),
+		Factions: []app.Faction{{ID: 100, Name: "Us"}, {ID: 200, Name: "Them"}},
+	}}
+	tornMock.FactionWarsResponse = warResponse
+
+	sheetsMock := mocks.NewMockSheetsClient()
+	sheetsMock.EnsureWarSheetsResponse = &app.SheetConfig{
+		WarID:          4242,
+		SummaryTabName: "Summary - 4242",
+		RecordsTabName: "Records - 4242",
+	}
+	sheetsMock.ReadExistingRecordsResponse = &sheets.RecordsInfo{AttackCodes: map[string]bool{}}
+
+	config := &app.Config{SpreadsheetID: "spreadsheet-id"}
+	attackService := attack.NewAttackProcessingService()
+	processor := NewWarProcessor(tornMock, sheetsMock, nil, nil, attackService, NewWarSummaryService(attackService, config), config)
+
+	if err := processor.ProcessActiveWars(context.Background()); err != nil {
+		t.Fatalf("ProcessActiveWars() returned unexpected error: %v", err)
+	}
+
+	summary := sheetsMock.UpdateWarSummaryCalledWith.Summary
+	if summary == nil {
+		t.Fatal("expected war summary to be written")
+	}
+	if summary.WarType != app.WarTypeTerritory {
+		t.Errorf("expected summary WarType %q, got %q", app.WarTypeTerritory, summary.WarType)
+	}
+	if summary.TargetScore != 0 || summary.ProgressPercent != 0 {
+		t.Errorf("expected no raid metrics for a territory war, got target %d progress %f", summary.TargetScore, summary.ProgressPercent)
+	}
+	if got := sheetsMock.UpdateWarSummaryCalledWith.Config.WarType; got != app.WarTypeTerritory {
+		t.Errorf("expected sheet config WarType %q, got %q", app.WarTypeTerritory, got)
+	}
+	if got := sheetsMock.UpdateWarSummaryCalledWith.Config.SummaryTabName; got != "Summary - 4242" {
+		t.Errorf("expected tab name to stay %q, got %q", "Summary - 4242", got)
+	}
+}
+
+func TestWarProcessor_RaidProgress(t *testing.T) {
+	tornMock := mocks.NewMockTornClient()
+	tornMock.OwnFactionResponse = &app.FactionInfoResponse{ID: 100, Name: "Us"}
+	tornMock.FactionAttacksResponse = &app.AttackResponse{}
+
+	warResponse := &app.WarResponse{}
+	warResponse.Wars.Raids = []app.War{{
+		ID:       5151,
+		Start:    time.Now().Add(-time.Hour).Unix(),
+		Target:   5000,
+		Factions: []app.Faction{{ID: 100, Name: "Us", Score: 3000}, {ID: 200, Name: "Them", Score: 1200}},
+	}}
+	tornMock.FactionWarsResponse = warResponse
+
+	sheetsMock := mocks.NewMockSheetsClient()
+	sheetsMock.EnsureWarSheetsResponse = &app.SheetConfig{WarID: 5151, SummaryTabName: "Summary - 5151", RecordsTabName: "Records - 5151"}
+	sheetsMock.ReadExistingRecordsResponse = &sheets.RecordsInfo{AttackCodes: map[string]bool{}}
+
+	config := &app.Config{SpreadsheetID: "spreadsheet-id"}
+	attackService := attack.NewAttackProcessingService()
+	processor := NewWarProcessor(tornMock, sheetsMock, nil, nil, attackService, NewWarSummaryService(attackService, config), config)
+
+	if err := processor.ProcessActiveWars(context.Background()); err != nil {
+		t.Fatalf("ProcessActiveWars() returned unexpected error: %v", err)
+	}
+
+	summary := sheetsMock.UpdateWarSummaryCalledWith.Summary
+	if summary == nil {
+		t.Fatal("expected war summary to be written")
+	}
+	if summary.TargetScore != 5000 {
+		t.Errorf("expected TargetScore 5000, got %d", summary.TargetScore)
+	}
+	if summary.ProgressPercent != 60 {
+		t.Errorf("expected ProgressPercent 60, got %f", summary.ProgressPercent)
+	}
+}
+
+func TestWarProcessor_WarnsOnSuspiciousAttackGaps(t *testing.T) {
+	config := &app.Config{SpreadsheetID: "spreadsheet-id", SuspiciousGapThreshold: 2 * time.Hour}
+	attackService := attack.NewAttackProcessingService()
+	processor := NewWarProcessor(mocks.NewMockTornClient(), mocks.NewMockSheetsClient(), nil, nil, attackService, NewWarSummaryService(attackService, config), config)
+
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	records := []app.AttackRecord{
+		{AttackID: 1, Started: start},
+		{AttackID: 2, Started: start.Add(30 * time.Minute)},
+		{AttackID: 3, Started: start.Add(3*time.Hour + 30*time.Minute)},
+	}
+
+	gaps := processor.warnOnAttackGaps(&app.War{ID: 4242}, records)
+	if len(gaps) != 1 {
+		t.Fatalf("expected 1 suspicious gap, got %d: %+v", len(gaps), gaps)
+	}
+	if !gaps[0].From.Equal(records[1].Started) || !gaps[0].To.Equal(records[2].Started) {
+		t.Errorf("expected gap between attacks 2 and 3, got %+v", gaps[0])
+	}
+
+	if gaps := processor.warnOnAttackGaps(&app.War{ID: 4242}, records[:2]); gaps != nil {
+		t.Errorf("expected no warning without a gap, got %+v", gaps)
+	}
+}
+
+func TestWarProcessor_WritesDashboardRowPerWar(t *testing.T) {
+	tornMock := mocks.NewMockTornClient()
+	tornMock.OwnFactionResponse = &app.FactionInfoResponse{ID: 100, Name: "Us"}
+	tornMock.FactionAttacksResponse = &app.AttackResponse{}
+
+	start := time.Now().Add(-time.Hour).Unix()
+	warResponse := &app.WarResponse{}
+	warResponse.Wars.Territory = []app.War{
+		{ID: 4242, Start: start, Factions: []app.Faction{{ID: 100, Name: "Us", Score: 10}, {ID: 200, Name: "Them", Score: 4}}},
+		{ID: 4343, Start: start, Factions: []app.Faction{{ID: 100, Name: "Us", Score: 2}, {ID: 300, Name: "Others", Score: 7}}},
+	}
+	tornMock.FactionWarsResponse = warResponse
+
+	sheetsMock := mocks.NewMockSheetsClient()
+	sheetsMock.EnsureWarSheetsResponse = &app.SheetConfig{SummaryTabName: "Summary", RecordsTabName: "Records"}
+	sheetsMock.ReadExistingRecordsResponse = &sheets.RecordsInfo{AttackCodes: map[string]bool{}}
+
+	config := &app.Config{SpreadsheetID: "spreadsheet-id"}
+	attackService := attack.NewAttackProcessingService()
+	processor := NewWarProcessor(tornMock, sheetsMock, nil, nil, attackService, NewWarSummaryService(attackService, config), config)
+
+	if err := processor.ProcessActiveWars(context.Background()); err != nil {
+		t.Fatalf("ProcessActiveWars() returned unexpected error: %v", err)
+	}
+
+	if !sheetsMock.UpdateDashboardCalled {
+		t.Fatal("expected the dashboard to be written")
+	}
+	rows := sheetsMock.UpdateDashboardCalledWith.Rows
+	if len(rows) != 2 {
+		t.Fatalf("expected 2 dashboard rows, got %d: %+v", len(rows), rows)
+	}
+	if rows[0].WarID != 4242 || rows[0].EnemyName != "Them" || rows[0].OurScore != 10 || rows[0].EnemyScore != 4 {
+		t.Errorf("unexpected first dashboard row: %+v", rows[0])
+	}
+	if rows[1].WarID != 4343 || rows[1].EnemyName != "Others" || rows[1].WarType != app.WarTypeTerritory {
+		t.Errorf("unexpected second dashboard row: %+v", rows[1])
+	}
+
+	// The next cycle starts from an empty dashboard rather than accumulating rows
+	warResponse.Wars.Territory = warResponse.Wars.Territory[:1]
+	if err := processor.ProcessActiveWars(context.Background()); err != nil {
+		t.Fatalf("ProcessActiveWars() returned unexpected error: %v", err)
+	}
+	if rows := sheetsMock.UpdateDashboardCalledWith.Rows; len(rows) != 1 {
+		t.Errorf("expected 1 dashboard row on the next cycle, got %d", len(rows))
+	}
+}
+
+func TestWarProcessor_ConfiguredFactionIDSkipsLookup(t *testing.T) {
+	tornMock := mocks.NewMockTornClient()
+	tornMock.FactionWarsResponse = &app.WarResponse{}
+
+	config := &app.Config{SpreadsheetID: "spreadsheet-id", OurFactionID: 100}
+	attackService := attack.NewAttackProcessingService()
+	processor := NewWarProcessor(tornMock, mocks.NewMockSheetsClient(), nil, nil, attackService, NewWarSummaryService(attackService, config), config)
+
+	if err := processor.ProcessActiveWars(context.Background()); err != nil {
+		t.Fatalf("ProcessActiveWars() returned unexpected error: %v", err)
+	}
+
+	if tornMock.GetOwnFactionCalled {
+		t.Error("expected no GetOwnFaction call when our faction ID is configured")
+	}
+	if processor.ourFactionID != 100 {
+		t.Errorf("expected configured faction ID 100, got %d", processor.ourFactionID)
+	}
+}
+
+func TestWarProcessor_FullRescanAfterInterval(t *testing.T) {
+	now := time.Now()
+	warStart := now.Add(-3 * time.Hour).Unix()
+
+	tornMock := mocks.NewMockTornClient()
+	tornMock.OwnFactionResponse = &app.FactionInfoResponse{ID: 100, Name: "Us"}
+	tornMock.FactionAttacksResponse = &app.AttackResponse{}
+
+	warResponse := &app.WarResponse{}
+	warResponse.Wars.Territory = []app.War{{
+		ID:       4242,
+		Start:    warStart,
+		Factions: []app.Faction{{ID: 100, Name: "Us"}, {ID: 200, Name: "Them"}},
+	}}
+	tornMock.FactionWarsResponse = warResponse
+
+	sheetsMock := mocks.NewMockSheetsClient()
+	sheetsMock.EnsureWarSheetsResponse = &app.SheetConfig{WarID: 4242, SummaryTabName: "Summary - 4242", RecordsTabName: "Records - 4242"}
+	sheetsMock.ReadExistingRecordsResponse = &sheets.RecordsInfo{
+		AttackCodes:     map[string]bool{"abc": true},
+		RecordCount:     1,
+		LatestTimestamp: now.Add(-10 * time.Minute).Unix(),
+	}
+
+	config := &app.Config{SpreadsheetID: "spreadsheet-id", FullRescanInterval: time.Hour}
+	attackService := attack.NewAttackProcessingService()
+	processor := NewWarProcessor(tornMock, sheetsMock, nil, nil, attackService, NewWarSummaryService(attackService, config), config)
+
+	// Within the interval the existing records keep incremental mode
+	processor.lastFullFetch[4242] = now.Add(-30 * time.Minute)
+	if err := processor.ProcessActiveWars(context.Background()); err != nil {
+		t.Fatalf("ProcessActiveWars() returned unexpected error: %v", err)
+	}
+	if tornMock.GetFactionAttacksCalledWith.From == warStart {
+		t.Fatal("expected an incremental fetch before the re-scan interval elapsed")
+	}
+
+	// Past the interval the whole war is fetched again from its start
+	processor.lastFullFetch[4242] = now.Add(-2 * time.Hour)
+	if err := processor.ProcessActiveWars(context.Background()); err != nil {
+		t.Fatalf("ProcessActiveWars() returned unexpected error: %v", err)
+	}
+	if tornMock.GetFactionAttacksCalledWith.From != warStart {
+		t.Errorf("expected a full fetch from war start %d, got from %d", warStart, tornMock.GetFactionAttacksCalledWith.From)
+	}
+	if !processor.lastFullFetch[4242].After(now.Add(-time.Minute)) {
+		t.Errorf("expected the full fetch time to be recorded, got %v", processor.lastFullFetch[4242])
+	}
+}
+
+func TestWarProcessor_MinReprocessInterval(t *testing.T) {
+	tornMock := mocks.NewMockTornClient()
+	tornMock.OwnFactionResponse = &app.FactionInfoResponse{ID: 100, Name: "Us"}
+	tornMock.FactionAttacksResponse = &app.AttackResponse{}
+
+	war := &app.War{
+		ID:       6262,
+		Start:    time.Now().Add(-time.Hour).Unix(),
+		Factions: []app.Faction{{ID: 100, Name: "Us"}, {ID: 200, Name: "Them"}},
+	}
+
+	sheetsMock := mocks.NewMockSheetsClient()
+	sheetsMock.EnsureWarSheetsResponse = &app.SheetConfig{WarID: 6262, SummaryTabName: "Summary - 6262", RecordsTabName: "Records - 6262"}
+	sheetsMock.ReadExistingRecordsResponse = &sheets.RecordsInfo{AttackCodes: map[string]bool{}}
+
+	stateFile := filepath.Join(t.TempDir(), "reprocess_state.json")
+	config := &app.Config{SpreadsheetID: "spreadsheet-id", MinReprocessInterval: 5 * time.Minute, ReprocessStateFile: stateFile}
+	attackService := attack.NewAttackProcessingService()
+	processor := NewWarProcessor(tornMock, sheetsMock, nil, nil, attackService, NewWarSummaryService(attackService, config), config)
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	processor.now = func() time.Time { return now }
+
+	steps := []struct {
+		name          string
+		advance       time.Duration
+		wantProcessed bool
+	}{
+		{name: "first run processes", advance: 0, wantProcessed: true},
+		{name: "within interval skips", advance: 4 * time.Minute, wantProcessed: false},
+		{name: "after interval processes", advance: 2 * time.Minute, wantProcessed: true},
+	}
+
+	for _, step := range steps {
+		now = now.Add(step.advance)
+		sheetsMock.EnsureWarSheetsCalled = false
+
+		if err := processor.processWar(context.Background(), war, app.WarTypeRanked); err != nil {
+			t.Fatalf("%s: processWar() returned unexpected error: %v", step.name, err)
+		}
+		if sheetsMock.EnsureWarSheetsCalled != step.wantProcessed {
+			t.Errorf("%s: expected processed %v, got %v", step.name, step.wantProcessed, sheetsMock.EnsureWarSheetsCalled)
+		}
+	}
+
+	// A new processor picks the last-processed time up from the state file
+	restarted := NewWarProcessor(tornMock, sheetsMock, nil, nil, attackService, NewWarSummaryService(attackService, config), config)
+	restarted.now = func() time.Time { return now.Add(time.Minute) }
+	sheetsMock.EnsureWarSheetsCalled = false
+	if err := restarted.processWar(context.Background(), war, app.WarTypeRanked); err != nil {
+		t.Fatalf("processWar() after restart returned unexpected error: %v", err)
+	}
+	if sheetsMock.EnsureWarSheetsCalled {
+		t.Error("expected war processed before the restart to be skipped")
+	}
+}
+
+func TestWarProcessor_OurFactionNameFallback(t *testing.T) {
+	tornMock := mocks.NewMockTornClient()
+	tornMock.OwnFactionError = errors.New("key belongs to a proxy faction")
+	tornMock.FactionAttacksResponse = &app.AttackResponse{}
+
+	warResponse := &app.WarResponse{}
+	warResponse.Wars.Ranked = &app.War{
+		ID:       7373,
+		Start:    time.Now().Add(-time.Hour).Unix(),
+		Factions: []app.Faction{{ID: 200, Name: "Them", Score: 40}, {ID: 100, Name: "Us", Score: 90}},
+	}
+	tornMock.FactionWarsResponse = warResponse
+
+	sheetsMock := mocks.NewMockSheetsClient()
+	sheetsMock.EnsureWarSheetsResponse = &app.SheetConfig{WarID: 7373, SummaryTabName: "Summary - 7373", RecordsTabName: "Records - 7373"}
+	sheetsMock.ReadExistingRecordsResponse = &sheets.RecordsInfo{AttackCodes: map[string]bool{}}
+
+	config := &app.Config{SpreadsheetID: "spreadsheet-id", OurFactionName: "us"}
+	attackService := attack.NewAttackProcessingService()
+	processor := NewWarProcessor(tornMock, sheetsMock, nil, nil, attackService, NewWarSummaryService(attackService, config), config)
+
+	if err := processor.ProcessActiveWars(context.Background()); err != nil {
+		t.Fatalf("ProcessActiveWars() returned unexpected error: %v", err)
+	}
+
+	if processor.ourFactionID != 100 {
+		t.Errorf("expected our faction ID 100 matched by name, got %d", processor.ourFactionID)
+	}
+	summary := sheetsMock.UpdateWarSummaryCalledWith.Summary
+	if summary == nil {
+		t.Fatal("expected war summary to be written")
+	}
+	if summary.OurFaction.ID != 100 || summary.EnemyFaction.ID != 200 {
+		t.Errorf("expected us (100) vs them (200), got %d vs %d", summary.OurFaction.ID, summary.EnemyFaction.ID)
+	}
+
+	// Without a name to fall back on, the failed lookup still stops processing
+	strict := NewWarProcessor(tornMock, sheetsMock, nil, nil, attackService, NewWarSummaryService(attackService, config), &app.Config{SpreadsheetID: "spreadsheet-id"})
+	if err := strict.ProcessActiveWars(context.Background()); err == nil {
+		t.Error("expected an error when our faction cannot be detected and no name is configured")
+	}
+}
+
+func TestWarProcessor_SkipsWarWithDuplicateFactions(t *testing.T) {
+	tornMock := mocks.NewMockTornClient()
+	tornMock.FactionAttacksResponse = &app.AttackResponse{}
+
+	sheetsMock := mocks.NewMockSheetsClient()
+	sheetsMock.EnsureWarSheetsResponse = &app.SheetConfig{WarID: 8484, SummaryTabName: "Summary - 8484", RecordsTabName: "Records - 8484"}
+	sheetsMock.ReadExistingRecordsResponse = &sheets.RecordsInfo{AttackCodes: map[string]bool{}}
+
+	config := &app.Config{SpreadsheetID: "spreadsheet-id", OurFactionID: 100}
+	attackService := attack.NewAttackProcessingService()
+	processor := NewWarProcessor(tornMock, sheetsMock, nil, nil, attackService, NewWarSummaryService(attackService, config), config)
+
+	war := &app.War{
+		ID:       8484,
+		Start:    time.Now().Add(-time.Hour).Unix(),
+		Factions: []app.Faction{{ID: 100, Name: "Us"}, {ID: 100, Name: "Us"}},
+	}
+
+	err := processor.processWar(context.Background(), war, app.WarTypeRanked)
+	if err == nil {
+		t.Fatal("expected an error for a war listing the same faction twice")
+	}
+	if !strings.Contains(err.Error(), "faction 100 more than once") {
+		t.Errorf("expected the error to name the duplicate faction, got %v", err)
+	}
+	if sheetsMock.EnsureWarSheetsCalled || tornMock.GetFactionAttacksCalled {
+		t.Error("expected the war to be skipped before any sheets or attacks were touched")
+	}
+}
+
+func TestWarProcessor_AvgHospitalTimeInflicted(t *testing.T) {
+	now := time.Now()
+	ours := &app.Faction{ID: 100}
+	enemy := &app.Faction{ID: 200}
+	hospitalize := func(id int64, defenderID int, ended time.Time) app.Attack {
+		return app.Attack{
+			ID:       id,
+			Code:     "code" + strconv.FormatInt(id, 10),
+			Started:  ended.Add(-time.Minute).Unix(),
+			Ended:    ended.Unix(),
+			Result:   "Hospitalized",
+			Attacker: app.User{ID: 1, Faction: ours},
+			Defender: app.User{ID: defenderID, Faction: enemy},
+		}
+	}
+	firstEnded := now.Add(-10 * time.Minute).Truncate(time.Second)
+	secondEnded := now.Add(-5 * time.Minute).Truncate(time.Second)
+	until := func(t time.Time) *int64 {
+		ts := t.Unix()
+		return &ts
+	}
+
+	tornMock := mocks.NewMockTornClient()
+	tornMock.OwnFactionResponse = &app.FactionInfoResponse{ID: 100, Name: "Us"}
+	tornMock.FactionAttacksResponse = &app.AttackResponse{Attacks: []app.Attack{
+		hospitalize(1, 501, firstEnded),
+		hospitalize(2, 502, secondEnded),
+	}}
+	tornMock.FactionBasicResponses = map[int]*app.FactionBasicResponse{200: {
+		ID: 200,
+		Members: map[string]app.FactionMember{
+			"501": {Status: app.MemberStatus{State: "Hospital", Until: until(firstEnded.Add(30 * time.Minute))}},
+			"502": {Status: app.MemberStatus{State: "Hospital", Until: until(secondEnded.Add(90 * time.Minute))}},
+		},
+	}}
+
+	war := &app.War{
+		ID:       8484,
+		Start:    now.Add(-time.Hour).Unix(),
+		Factions: []app.Faction{{ID: 100, Name: "Us"}, {ID: 200, Name: "Them"}},
+	}
+
+	sheetsMock := mocks.NewMockSheetsClient()
+	sheetsMock.EnsureWarSheetsResponse = &app.SheetConfig{WarID: 8484, SummaryTabName: "Summary - 8484", RecordsTabName: "Records - 8484"}
+	sheetsMock.ReadExistingRecordsResponse = &sheets.RecordsInfo{AttackCodes: map[string]bool{}}
+
+	config := &app.Config{SpreadsheetID: "spreadsheet-id", OurFactionID: 100}
+	attackService := attack.NewAttackProcessingService()
+	processor := NewWarProcessor(tornMock, sheetsMock, nil, nil, attackService, NewWarSummaryService(attackService, config), config)
+
+	if err := processor.processWar(context.Background(), war, app.WarTypeRanked); err != nil {
+		t.Fatalf("processWar() returned unexpected error: %v", err)
+	}
+
+	summary := sheetsMock.UpdateWarSummaryCalledWith.Summary
+	if summary == nil {
+		t.Fatal("expected war summary to be updated")
+	}
+	if summary.AvgHospitalTimeInflicted != time.Hour {
+		t.Errorf("expected average hospital time of 1h, got %v", summary.AvgHospitalTimeInflicted)
+	}
+	if len(processor.hospitalUntil[8484]) != 2 {
+		t.Fatalf("expected both stays recorded against war 8484, got %v", processor.hospitalUntil)
+	}
+
+	// Once the war is no longer returned its recorded stays are dropped
+	tornMock.FactionWarsResponse = &app.WarResponse{}
+	if err := processor.ProcessActiveWars(context.Background()); err != nil {
+		t.Fatalf("ProcessActiveWars() returned unexpected error: %v", err)
+	}
+	if len(processor.hospitalUntil) != 0 {
+		t.Errorf("expected stays of ended wars to be dropped, got %v", processor.hospitalUntil)
+	}
+}
+
+// pagedAttacksTornClient serves scripted GetFactionAttacks responses in order and
+// records each requested range; calls past the script return an empty page
+type pagedAttacksTornClient struct {
+	*mocks.MockTornClient
+
+	pages  []*app.AttackResponse
+	errs   []error
+	killAt int // call that panics, as if the process were killed mid-fetch; zero never does
+	ranges [][2]int64
+}
+
+func (c *pagedAttacksTornClient) GetFactionAttacks(ctx context.Context, from, to int64) (*app.AttackResponse, error) {
+	call := len(c.ranges)
+	c.ranges = append(c.ranges, [2]int64{from, to})
+	if c.killAt > 0 && call == c.killAt {
+		panic("killed")
+	}
+	if call < len(c.errs) && c.errs[call] != nil {
+		return nil, c.errs[call]
+	}
+	if call < len(c.pages) {
+		return c.pages[call], nil
+	}
+	return &app.AttackResponse{}, nil
+}
+
+// warHitPage returns a full page of count hits, one a minute going back from newest
+func warHitPage(firstID int64, newest time.Time, count int) *app.AttackResponse {
+	page := &app.AttackResponse{}
+	for i := 0; i < count; i++ {
+		page.Attacks = append(page.Attacks, warHit(firstID+int64(i), newest.Add(-time.Duration(i)*time.Minute)))
+	}
+	return page
+}
+
+// firstBloodIDs returns the attack IDs marked first blood on a war's records sheets
+func firstBloodIDs(t *testing.T, sheetsClient *recordsSheetsClient, sheetName string) []int64 {
+	t.Helper()
+	records, err := sheetsClient.ReadAttackRecords(context.Background(), "spreadsheet-id", sheetName)
+	if err != nil {
+		t.Fatalf("ReadAttackRecords() returned unexpected error: %v", err)
+	}
+	var ids []int64
+	for _, record := range records {
+		if record.IsFirstBlood {
+			ids = append(ids, record.AttackID)
+		}
+	}
+	return ids
+}
+
+func TestWarProcessor_ResumesInterruptedFullPopulation(t *testing.T) {
+	now := time.Now()
+	war := &app.War{
+		ID:       9595,
+		Start:    now.Add(-72 * time.Hour).Unix(),
+		Factions: []app.Faction{{ID: 100, Name: "Us"}, {ID: 200, Name: "Them"}},
+	}
+	sheetConfig := &app.SheetConfig{WarID: 9595, SummaryTabName: "Summary - 9595", RecordsTabName: "Records - 9595"}
+	sheetsClient := newRecordsSheetsClient(sheetConfig)
+	sheetsClient.api.rows["Records - 9595"] = sheets.NewWarSheetsManager(nil).GenerateRecordsSheetHeaders()
+
+	// The first page is full, so pagination continues and the second page fails
+	newestPage := warHitPage(1000, now.Add(-time.Minute), 100)
+	oldestFetched := newestPage.Attacks[99].Started
+
+	progressFile := filepath.Join(t.TempDir(), "population_progress.json")
+	config := &app.Config{SpreadsheetID: "spreadsheet-id", OurFactionID: 100, PopulationProgressFile: progressFile}
+	attackService := attack.NewAttackProcessingService()
+
+	interrupted := &pagedAttacksTornClient{
+		MockTornClient: mocks.NewMockTornClient(),
+		pages:          []*app.AttackResponse{newestPage},
+		errs:           []error{nil, errors.New("connection reset")},
+	}
+	processor := NewWarProcessor(interrupted, sheetsClient, nil, nil, attackService, NewWarSummaryService(attackService, config), config)
+	if err := processor.processWar(context.Background(), war, app.WarTypeRanked); err != nil {
+		t.Fatalf("processWar() returned unexpected error: %v", err)
+	}
+	if codes := sheetsClient.writtenCodes("Records - 9595"); len(codes) != 100 {
+		t.Fatalf("expected the 100 fetched attacks to be written, got %d", len(codes))
+	}
+	if until, incomplete := processor.population.MissingUntil(war.ID); !incomplete || until != oldestFetched-1 {
+		t.Fatalf("expected attacks up to %d recorded as missing, got %d (incomplete %v)", oldestFetched-1, until, incomplete)
+	}
+	if ids := firstBloodIDs(t, sheetsClient, "Records - 9595"); len(ids) != 0 {
+		t.Errorf("expected the partial fetch to leave first blood unmarked, got %v", ids)
+	}
+
+	// Restart after the partial write: the newest attacks are already in the sheet
+	resumed := &pagedAttacksTornClient{
+		MockTornClient: mocks.NewMockTornClient(),
+		pages: []*app.AttackResponse{
+			{Attacks: []app.Attack{warHit(2000, now)}},
+			{Attacks: []app.Attack{warHit(3000, time.Unix(war.Start, 0).Add(time.Hour))}},
+		},
+	}
+	restarted := NewWarProcessor(resumed, sheetsClient, nil, nil, attackService, NewWarSummaryService(attackService, config), config)
+	if err := restarted.processWar(context.Background(), war, app.WarTypeRanked); err != nil {
+		t.Fatalf("processWar() after restart returned unexpected error: %v", err)
+	}
+
+	// One incremental fetch of new attacks, then only the range the first run missed
+	if len(resumed.ranges) != 2 {
+		t.Fatalf("expected an incremental and a resume fetch, got ranges %v", resumed.ranges)
+	}
+	if from := resumed.ranges[0][0]; from <= oldestFetched {
+		t.Errorf("expected the incremental fetch to start after the partial write, got from %d", from)
+	}
+	resume := resumed.ranges[1]
+	if resume[0] != war.Start || resume[1] >= oldestFetched {
+		t.Errorf("expected the resume fetch to cover %d up to before %d, got %v", war.Start, oldestFetched, resume)
+	}
+	if codes := sheetsClient.writtenCodes("Records - 9595"); len(codes) != 102 || !codes["code2000"] || !codes["code3000"] {
+		t.Errorf("expected the new and the resumed attack to be added, got %d codes", len(codes))
+	}
+	if ids := firstBloodIDs(t, sheetsClient, "Records - 9595"); !slices.Equal(ids, []int64{3000}) {
+		t.Errorf("expected only the resumed oldest attack 3000 as first blood, got %v", ids)
+	}
+
+	reloaded, err := NewPopulationProgress(progressFile)
+	if err != nil {
+		t.Fatalf("NewPopulationProgress() returned unexpected error: %v", err)
+	}
+	if until, incomplete := reloaded.MissingUntil(war.ID); incomplete {
+		t.Errorf("expected the population to be complete after resuming, still missing until %d", until)
+	}
+}
+
+func TestWarProcessor_KilledPopulationResumesFromLastWrittenPage(t *testing.T) {
+	now := time.Now()
+	war := &app.War{
+		ID:       9597,
+		Start:    now.Add(-72 * time.Hour).Unix(),
+		Factions: []app.Faction{{ID: 100, Name: "Us"}, {ID: 200, Name: "Them"}},
+	}
+	sheetConfig := &app.SheetConfig{WarID: 9597, SummaryTabName: "Summary - 9597", RecordsTabName: "Records - 9597"}
+	sheetsClient := newRecordsSheetsClient(sheetConfig)
+	sheetsClient.api.rows["Records - 9597"] = sheets.NewWarSheetsManager(nil).GenerateRecordsSheetHeaders()
+
+	// Two full pages arrive, then the process is killed fetching the third
+	firstPage := warHitPage(1000, now.Add(-time.Minute), 100)
+	secondPage := warHitPage(2000, time.Unix(firstPage.Attacks[99].Started, 0).Add(-time.Minute), 100)
+	firstWritten := firstPage.Attacks[99].Started
+
+	progressFile := filepath.Join(t.TempDir(), "population_progress.json")
+	config := &app.Config{SpreadsheetID: "spreadsheet-id", OurFactionID: 100, PopulationProgressFile: progressFile}
+	attackService := attack.NewAttackProcessingService()
+
+	killed := &pagedAttacksTornClient{
+		MockTornClient: mocks.NewMockTornClient(),
+		pages:          []*app.AttackResponse{firstPage, secondPage},
+		killAt:         2,
+	}
+	processor := NewWarProcessor(killed, sheetsClient, nil, nil, attackService, NewWarSummaryService(attackService, config), config)
+	func() {
+		defer func() { _ = recover() }()
+		_ = processor.processWar(context.Background(), war, app.WarTypeRanked)
+		t.Fatal("expected the run to be killed mid-fetch")
+	}()
+
+	// The first page was written once the second arrived; the second was still held back
+	if codes := sheetsClient.writtenCodes("Records - 9597"); len(codes) != 100 || !codes["code1000"] {
+		t.Fatalf("expected the first page written before the kill, got %d codes", len(codes))
+	}
+	saved, err := NewPopulationProgress(progressFile)
+	if err != nil {
+		t.Fatalf("NewPopulationProgress() returned unexpected error: %v", err)
+	}
+	if until, incomplete := saved.MissingUntil(war.ID); !incomplete || until != firstWritten-1 {
+		t.Fatalf("expected attacks up to %d saved as missing, got %d (incomplete %v)", firstWritten-1, until, incomplete)
+	}
+
+	// The restarted run fetches new attacks, then resumes below the first page
+	resumed := &pagedAttacksTornClient{
+		MockTornClient: mocks.NewMockTornClient(),
+		pages: []*app.AttackResponse{
+			{},
+			secondPage,
+			{Attacks: []app.Attack{warHit(3000, time.Unix(war.Start, 0).Add(time.Hour))}},
+		},
+	}
+	restarted := NewWarProcessor(resumed, sheetsClient, nil, nil, attackService, NewWarSummaryService(attackService, config), config)
+	if err := restarted.processWar(context.Background(), war, app.WarTypeRanked); err != nil {
+		t.Fatalf("processWar() after restart returned unexpected error: %v", err)
+	}
+
+	if len(resumed.ranges) < 2 || resumed.ranges[1] != [2]int64{war.Start, firstWritten - 1} {
+		t.Fatalf("expected the resume to start below the first page, got ranges %v", resumed.ranges)
+	}
+	rows := sheetsClient.api.rows["Records - 9597"]
+	if codes := sheetsClient.writtenCodes("Records - 9597"); len(codes) != 201 || len(rows) != 202 {
+		t.Errorf("expected every attack written once, got %d codes in %d rows", len(codes), len(rows)-1)
+	}
+	if ids := firstBloodIDs(t, sheetsClient, "Records - 9597"); !slices.Equal(ids, []int64{3000}) {
+		t.Errorf("expected the oldest attack 3000 as first blood, got %v", ids)
+	}
+	if until, incomplete := restarted.population.MissingUntil(war.ID); incomplete {
+		t.Errorf("expected the population to be complete after resuming, still missing until %d", until)
+	}
+}
+
+// memorySheetsAPI is an in-memory sheets.SheetsAPI that honours the start and end rows
+// of A1 ranges, so a real records processor reads back the rows it wrote
+type memorySheetsAPI struct {
+	rows map[string][][]interface{}
+}
+
+func newMemorySheetsAPI() *memorySheetsAPI {
+	return &memorySheetsAPI{rows: make(map[string][][]interface{})}
+}
+
+// parseRange splits "'Sheet'!A2:F10" into the sheet name and its 1-based start and end
+// rows; an end row of 0 means open-ended
+func (m *memorySheetsAPI) parseRange(range_ string) (string, int, int) {
+	sheetName, cells, _ := strings.Cut(range_, "!")
+	rowOf := func(cell string) int {
+		row, _ := strconv.Atoi(strings.TrimLeft(cell, "ABCDEFGHIJKLMNOPQRSTUVWXYZ"))
+		return row
+	}
+	start, end, _ := strings.Cut(cells, ":")
+	return strings.Trim(sheetName, "'"), max(rowOf(start), 1), rowOf(end)
+}
+
+func (m *memorySheetsAPI) ReadSheet(ctx context.Context, spreadsheetID, range_ string) ([][]interface{}, error) {
+	sheetName, start, end := m.parseRange(range_)
+	rows := m.rows[sheetName]
+	if end > 0 && end < len(rows) {
+		rows = rows[:end]
+	}
+	if start > len(rows) {
+		return nil, nil
+	}
+	return rows[start-1:], nil
+}
+
+func (m *memorySheetsAPI) UpdateRange(ctx context.Context, spreadsheetID, range_ string, values [][]interface{}) error {
+	sheetName, start, _ := m.parseRange(range_)
+	rows := m.rows[sheetName]
+	for len(rows) < start-1+len(values) {
+		rows = append(rows, nil)
+	}
+	for i, row := range values {
+		current := rows[start-1+i]
+		for len(current) < len(row) {
+			current = append(current, nil)
+		}
+		copy(current, row)
+		rows[start-1+i] = current
+	}
+	m.rows[sheetName] = rows
+	return nil
+}
+
+func (m *memorySheetsAPI) ClearRange(ctx context.Context, spreadsheetID, range_ string) error {
+	sheetName, _, _ := m.parseRange(range_)
+	delete(m.rows, sheetName)
+	return nil
+}
+
+func (m *memorySheetsAPI) AppendRows(ctx context.Context, spreadsheetID, range_ string, rows [][]interface{}) error {
+	sheetName, _, _ := m.parseRange(range_)
+	m.rows[sheetName] = append(m.rows[sheetName], rows...)
+	return nil
+}
+
+func (m *memorySheetsAPI) DeleteRows(ctx context.Context, spreadsheetID, sheetName string, startRow, count int) error {
+	rows := m.rows[sheetName]
+	if startRow-1 >= len(rows) {
+		return nil
+	}
+	end := min(startRow-1+count, len(rows))
+	m.rows[sheetName] = append(rows[:startRow-1:startRow-1], rows[end:]...)
+	return nil
+}
+
+func (m *memorySheetsAPI) CreateSheet(ctx context.Context, spreadsheetID, sheetName string) error {
+	return nil
+}
+
+func (m *memorySheetsAPI) SheetExists(ctx context.Context, spreadsheetID, sheetName string) (bool, error) {
+	_, exists := m.rows[sheetName]
+	return exists, nil
+}
+
+func (m *memorySheetsAPI) EnsureSheetCapacity(ctx context.Context, spreadsheetID, sheetName string, requiredRows, requiredCols int) error {
+	return nil
+}
+
+func (m *memorySheetsAPI) FormatStatusSheet(ctx context.Context, spreadsheetID, sheetName string) error {
+	return nil
+}
+
+// recordsSheetsClient routes attack record reads and writes through a real records
+// processor over an in-memory sheet, leaving every other call to the mock
+type recordsSheetsClient struct {
+	*mocks.MockSheetsClient
+
+	api       *memorySheetsAPI
+	processor *sheets.AttackRecordsProcessor
+}
+
+func newRecordsSheetsClient(config *app.SheetConfig) *recordsSheetsClient {
+	api := newMemorySheetsAPI()
+	client := &recordsSheetsClient{
+		MockSheetsClient: mocks.NewMockSheetsClient(),
+		api:              api,
+		processor:        sheets.NewAttackRecordsProcessor(api),
+	}
+	client.EnsureWarSheetsResponse = config
+	return client
+}
+
+func (c *recordsSheetsClient) ReadExistingRecords(ctx context.Context, spreadsheetID, sheetName string) (*sheets.RecordsInfo, error) {
+	return c.processor.ReadExistingRecords(ctx, spreadsheetID, sheetName)
+}
+
+func (c *recordsSheetsClient) ReadAttackRecords(ctx context.Context, spreadsheetID, sheetName string) ([]app.AttackRecord, error) {
+	return c.processor.ReadAttackRecords(ctx, spreadsheetID, sheetName)
+}
+
+func (c *recordsSheetsClient) UpdateAttackRecords(ctx context.Context, spreadsheetID string, config *app.SheetConfig, records []app.AttackRecord) error {
+	return c.processor.UpdateAttackRecords(ctx, spreadsheetID, config, records)
+}
+
+func (c *recordsSheetsClient) BackfillAttackRecords(ctx context.Context, spreadsheetID string, config *app.SheetConfig, records []app.AttackRecord) error {
+	return c.processor.BackfillAttackRecords(ctx, spreadsheetID, config, records)
+}
+
+// writtenCodes returns the attack codes on a records sheet, skipping the header row
+func (c *recordsSheetsClient) writtenCodes(sheetName string) map[string]bool {
+	codes := make(map[string]bool)
+	rows := c.api.rows[sheetName]
+	for _, row := range rows[min(1, len(rows)):] {
+		if len(row) > 1 {
+			codes[fmt.Sprint(row[1])] = true
+		}
+	}
+	return codes
+}
+
+// warHit builds a mugging by our member 1 on their member 2
+func warHit(id int64, started time.Time) app.Attack {
+	return app.Attack{
+		ID:       id,
+		Code:     "code" + strconv.FormatInt(id, 10),
+		Started:  started.Unix(),
+		Ended:    started.Add(time.Minute).Unix(),
+		Result:   "Mugged",
+		Attacker: app.User{ID: 1, Faction: &app.Faction{ID: 100}},
+		Defender: app.User{ID: 2, Faction: &app.Faction{ID: 200}},
+	}
+}
+
+func TestWarProcessor_PageCapLeavesPopulationIncomplete(t *testing.T) {
+	now := time.Now()
+	war := &app.War{
+		ID:       9596,
+		Start:    now.Add(-72 * time.Hour).Unix(),
+		Factions: []app.Faction{{ID: 100, Name: "Us"}, {ID: 200, Name: "Them"}},
+	}
+
+	// A full page means older attacks remain when the one-page cap is reached
+	page := &app.AttackResponse{}
+	for i := 0; i < 100; i++ {
+		started := now.Add(-time.Duration(i+1) * time.Minute)
+		page.Attacks = append(page.Attacks, app.Attack{
+			ID:       int64(1000 + i),
+			Code:     "code" + strconv.Itoa(1000+i),
+			Started:  started.Unix(),
+			Ended:    started.Add(time.Minute).Unix(),
+			Result:   "Mugged",
+			Attacker: app.User{ID: 1, Faction: &app.Faction{ID: 100}},
+			Defender: app.User{ID: 2, Faction: &app.Faction{ID: 200}},
+		})
+	}
+
+	config := &app.Config{SpreadsheetID: "spreadsheet-id", OurFactionID: 100, FetchMaxPages: 1}
+	attackService := attack.NewAttackProcessingService()
+	sheetsMock := mocks.NewMockSheetsClient()
+	sheetsMock.EnsureWarSheetsResponse = &app.SheetConfig{WarID: 9596, SummaryTabName: "Summary - 9596", RecordsTabName: "Records - 9596"}
+	sheetsMock.ReadExistingRecordsResponse = &sheets.RecordsInfo{AttackCodes: map[string]bool{}}
+	client := &pagedAttacksTornClient{MockTornClient: mocks.NewMockTornClient(), pages: []*app.AttackResponse{page}}
+
+	processor := NewWarProcessor(client, sheetsMock, nil, nil, attackService, NewWarSummaryService(attackService, config), config)
+	if err := processor.processWar(context.Background(), war, app.WarTypeRanked); err != nil {
+		t.Fatalf("processWar() returned unexpected error: %v", err)
+	}
+
+	if len(client.ranges) != 1 {
+		t.Errorf("expected a single page request, got %v", client.ranges)
+	}
+	if len(sheetsMock.BackfillAttackRecordsCalledWith.Records) != 100 {
+		t.Errorf("expected the fetched page to be written, got %d records", len(sheetsMock.BackfillAttackRecordsCalledWith.Records))
+	}
+	oldestFetched := page.Attacks[99].Started
+	if until, incomplete := processor.population.MissingUntil(war.ID); !incomplete || until != oldestFetched-1 {
+		t.Errorf("expected attacks up to %d recorded as missing, got %d (incomplete %v)", oldestFetched-1, until, incomplete)
+	}
+}
+
+func TestWarProcessor_ResumeWritesOlderAttacksToSheet(t *testing.T) {
+	now := time.Now()
+	war := &app.War{
+		ID:       9696,
+		Start:    now.Add(-72 * time.Hour).Unix(),
+		Factions: []app.Faction{{ID: 100, Name: "Us"}, {ID: 200, Name: "Them"}},
+	}
+	sheetConfig := &app.SheetConfig{WarID: 9696, SummaryTabName: "Summary - 9696", RecordsTabName: "Records - 9696"}
+	sheetsClient := newRecordsSheetsClient(sheetConfig)
+	sheetsClient.api.rows["Records - 9696"] = [][]interface{}{{"Attack ID", "Code", "Started"}}
+
+	config := &app.Config{SpreadsheetID: "spreadsheet-id", OurFactionID: 100, PopulationProgressFile: filepath.Join(t.TempDir(), "population_progress.json")}
+	attackService := attack.NewAttackProcessingService()
+
+	// An earlier run wrote the newest attack before its fetch was cut short
+	recent := attackService.ProcessAttacksIntoRecords([]app.Attack{warHit(2000, now.Add(-time.Hour))}, war, 100)
+	if err := sheetsClient.processor.UpdateAttackRecords(context.Background(), "spreadsheet-id", sheetConfig, recent); err != nil {
+		t.Fatalf("UpdateAttackRecords() returned unexpected error: %v", err)
+	}
+	progress, err := NewPopulationProgress(config.PopulationProgressFile)
+	if err != nil {
+		t.Fatalf("NewPopulationProgress() returned unexpected error: %v", err)
+	}
+	if err := progress.MarkIncomplete(war.ID, now.Add(-2*time.Hour).Unix()); err != nil {
+		t.Fatalf("MarkIncomplete() returned unexpected error: %v", err)
+	}
+
+	// The incremental fetch finds nothing new; the resume fetch recovers a 70h old attack
+	tornClient := &pagedAttacksTornClient{
+		MockTornClient: mocks.NewMockTornClient(),
+		pages: []*app.AttackResponse{
+			{},
+			{Attacks: []app.Attack{warHit(3000, now.Add(-70*time.Hour))}},
+		},
+	}
+	processor := NewWarProcessor(tornClient, sheetsClient, nil, nil, attackService, NewWarSummaryService(attackService, config), config)
+	if err := processor.processWar(context.Background(), war, app.WarTypeRanked); err != nil {
+		t.Fatalf("processWar() returned unexpected error: %v", err)
+	}
+
+	codes := sheetsClient.writtenCodes("Records - 9696")
+	if !codes["code2000"] || !codes["code3000"] || len(codes) != 2 {
+		t.Errorf("expected the recent and the recovered attack on the sheet, got %v", codes)
+	}
+	if _, incomplete := processor.population.MissingUntil(war.ID); incomplete {
+		t.Error("expected the population to be complete once the older attack was written")
+	}
+}
+
+func TestWarProcessor_PartialFetchFollowUpWritesOlderPages(t *testing.T) {
+	now := time.Now()
+	war := &app.War{
+		ID:       9797,
+		Start:    now.Add(-72 * time.Hour).Unix(),
+		Factions: []app.Faction{{ID: 100, Name: "Us"}, {ID: 200, Name: "Them"}},
+	}
+	sheetConfig := &app.SheetConfig{WarID: 9797, SummaryTabName: "Summary - 9797", RecordsTabName: "Records - 9797"}
+	sheetsClient := newRecordsSheetsClient(sheetConfig)
+	sheetsClient.api.rows["Records - 9797"] = [][]interface{}{{"Attack ID", "Code", "Started"}}
+
+	config := &app.Config{SpreadsheetID: "spreadsheet-id", OurFactionID: 100}
+	attackService := attack.NewAttackProcessingService()
+
+	// A full page keeps pagination going and the next, older page fails
+	newestPage := &app.AttackResponse{}
+	for i := 0; i < 100; i++ {
+		newestPage.Attacks = append(newestPage.Attacks, warHit(int64(1000+i), now.Add(-time.Duration(i+1)*time.Minute)))
+	}
+	tornClient := &pagedAttacksTornClient{
+		MockTornClient: mocks.NewMockTornClient(),
+		pages: []*app.AttackResponse{
+			newestPage,
+			nil,
+			// Next cycle: nothing new, then the older pages the failure lost
+			{},
+			{Attacks: []app.Attack{warHit(3000, now.Add(-70*time.Hour))}},
+		},
+		errs: []error{nil, errors.New("connection reset")},
+	}
+	processor := NewWarProcessor(tornClient, sheetsClient, nil, nil, attackService, NewWarSummaryService(attackService, config), config)
+
+	for cycle := 0; cycle < 2; cycle++ {
+		if err := processor.processWar(context.Background(), war, app.WarTypeRanked); err != nil {
+			t.Fatalf("processWar() cycle %d returned unexpected error: %v", cycle, err)
+		}
+	}
+
+	codes := sheetsClient.writtenCodes("Records - 9797")
+	if len(codes) != 101 || !codes["code1000"] || !codes["code3000"] {
+		t.Errorf("expected the first page and the older recovered attack on the sheet, got %d codes", len(codes))
+	}
+}
+
+func TestWarProcessor_FullRescanWritesMissedAttacks(t *testing.T) {
+	now := time.Now()
+	war := &app.War{
+		ID:       9898,
+		Start:    now.Add(-72 * time.Hour).Unix(),
+		Factions: []app.Faction{{ID: 100, Name: "Us"}, {ID: 200, Name: "Them"}},
+	}
+	sheetConfig := &app.SheetConfig{WarID: 9898, SummaryTabName: "Summary - 9898", RecordsTabName: "Records - 9898"}
+	sheetsClient := newRecordsSheetsClient(sheetConfig)
+	sheetsClient.api.rows["Records - 9898"] = [][]interface{}{{"Attack ID", "Code", "Started"}}
+
+	config := &app.Config{SpreadsheetID: "spreadsheet-id", OurFactionID: 100, FullRescanInterval: time.Hour}
+	attackService := attack.NewAttackProcessingService()
+	written := attackService.ProcessAttacksIntoRecords([]app.Attack{warHit(2000, now.Add(-time.Hour))}, war, 100)
+	if err := sheetsClient.processor.UpdateAttackRecords(context.Background(), "spreadsheet-id", sheetConfig, written); err != nil {
+		t.Fatalf("UpdateAttackRecords() returned unexpected error: %v", err)
+	}
+
+	// The re-scan finds an older attack an earlier incremental pass missed
+	tornClient := &pagedAttacksTornClient{
+		MockTornClient: mocks.NewMockTornClient(),
+		pages: []*app.AttackResponse{{Attacks: []app.Attack{
+			warHit(2000, now.Add(-time.Hour)),
+			warHit(3000, now.Add(-70*time.Hour)),
+		}}},
+	}
+	processor := NewWarProcessor(tornClient, sheetsClient, nil, nil, attackService, NewWarSummaryService(attackService, config), config)
+	processor.lastFullFetch[war.ID] = now.Add(-2 * time.Hour)
+	if err := processor.processWar(context.Background(), war, app.WarTypeRanked); err != nil {
+		t.Fatalf("processWar() returned unexpected error: %v", err)
+	}
+
+	if tornClient.ranges[0][0] != war.Start {
+		t.Fatalf("expected a full re-scan from war start, got ranges %v", tornClient.ranges)
+	}
+	rows := sheetsClient.api.rows["Records - 9898"]
+	codes := sheetsClient.writtenCodes("Records - 9898")
+	if len(rows) != 3 || !codes["code3000"] {
+		t.Errorf("expected the missed attack appended once alongside the existing row, got %d rows and codes %v", len(rows)-1, codes)
+	}
+}
+
+func TestWarProcessor_ProcessAttacksInRangeWritesPastWindow(t *testing.T) {
+	now := time.Now()
+	war := app.War{
+		ID:       9999,
+		Start:    now.Add(-72 * time.Hour).Unix(),
+		Factions: []app.Faction{{ID: 100, Name: "Us"}, {ID: 200, Name: "Them"}},
+	}
+	sheetConfig := &app.SheetConfig{WarID: 9999, SummaryTabName: "Summary - 9999", RecordsTabName: "Records - 9999"}
+	sheetsClient := newRecordsSheetsClient(sheetConfig)
+	sheetsClient.api.rows["Records - 9999"] = [][]interface{}{{"Attack ID", "Code", "Started"}}
+
+	config := &app.Config{SpreadsheetID: "spreadsheet-id", OurFactionID: 100}
+	attackService := attack.NewAttackProcessingService()
+	written := attackService.ProcessAttacksIntoRecords([]app.Attack{warHit(2000, now.Add(-time.Hour))}, &war, 100)
+	if err := sheetsClient.processor.UpdateAttackRecords(context.Background(), "spreadsheet-id", sheetConfig, written); err != nil {
+		t.Fatalf("UpdateAttackRecords() returned unexpected error: %v", err)
+	}
+
+	tornClient := &pagedAttacksTornClient{
+		MockTornClient: mocks.NewMockTornClient(),
+		pages:          []*app.AttackResponse{{Attacks: []app.Attack{warHit(3000, now.Add(-70*time.Hour))}}},
+	}
+	tornClient.FactionWarsResponse = &app.WarResponse{}
+	tornClient.FactionWarsResponse.Wars.Ranked = &war
+
+	processor := NewWarProcessor(tornClient, sheetsClient, nil, nil, attackService, NewWarSummaryService(attackService, config), config)
+	if err := processor.ProcessAttacksInRange(context.Background(), now.Add(-71*time.Hour), now.Add(-69*time.Hour)); err != nil {
+		t.Fatalf("ProcessAttacksInRange() returned unexpected error: %v", err)
+	}
+
+	if codes := sheetsClient.writtenCodes("Records - 9999"); len(codes) != 2 || !codes["code3000"] {
+		t.Errorf("expected the past attack written behind the newer row, got %v", codes)
+	}
+	if !sheetsClient.UpdateLeaderboardCalled || !sheetsClient.UpdateRespectTrendCalled {
+		t.Error("expected the derived sheets to be refreshed after the range pull")
+	}
+	// A summary built from the range's attacks alone would overwrite the whole war's
+	if sheetsClient.UpdateWarSummaryCalled {
+		t.Error("expected the range pull to leave the war summary alone")
+	}
+}
+
+func TestCurrentWar(t *testing.T) {
+	warResponse := &app.WarResponse{}
+	warResponse.Wars.Ranked = &app.War{ID: 1}
+	warResponse.Wars.Raids = []app.War{{ID: 2}, {ID: 3}}
+	warResponse.Wars.Territory = []app.War{{ID: 4}}
+
+	tests := []struct {
+		name     string
+		pinned   int
+		wantID   int
+		wantType string
+	}{
+		{"ranked war by default", 0, 1, app.WarTypeRanked},
+		{"pinned raid", 3, 3, app.WarTypeRaid},
+		{"pinned territory war", 4, 4, app.WarTypeTerritory},
+		{"pinned war missing falls back to priority", 99, 1, app.WarTypeRanked},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			war, warType := currentWar(warResponse, tt.pinned)
+			if war == nil || war.ID != tt.wantID || warType != tt.wantType {
+				t.Errorf("expected war %d (%s), got %+v (%s)", tt.wantID, tt.wantType, war, warType)
+			}
+		})
+	}
+}
+
+func TestWarProcessor_ValidateSheetsUsesPinnedWar(t *testing.T) {
+	tornMock := mocks.NewMockTornClient()
+	tornMock.FactionWarsResponse = &app.WarResponse{}
+	tornMock.FactionWarsResponse.Wars.Ranked = &app.War{ID: 1}
+	tornMock.FactionWarsResponse.Wars.Raids = []app.War{{ID: 2}}
+	sheetsMock := mocks.NewMockSheetsClient()
+	sheetsMock.EnsureWarSheetsResponse = &app.SheetConfig{WarID: 2}
+
+	config := &app.Config{SpreadsheetID: "spreadsheet-id", OurFactionID: 100, PinnedWarID: 2}
+	attackService := attack.NewAttackProcessingService()
+	processor := NewWarProcessor(tornMock, sheetsMock, nil, nil, attackService, NewWarSummaryService(attackService, config), config)
+	if _, err := processor.ValidateSheets(context.Background()); err != nil {
+		t.Fatalf("ValidateSheets() returned unexpected error: %v", err)
+	}
+
+	if war := sheetsMock.EnsureWarSheetsCalledWith.War; war == nil || war.ID != 2 {
+		t.Errorf("expected the pinned raid's sheets validated, got war %+v", war)
+	}
+	if sheetsMock.EnsureWarSheetsResponse.WarType != app.WarTypeRaid {
+		t.Errorf("expected the sheets validated as a raid, got %q", sheetsMock.EnsureWarSheetsResponse.WarType)
+	}
+}
+
+// runIncrementalCycleOverWrittenRecords writes earlier attacks to a records sheet, then
+// processes a cycle that fetches only newer, so tests can check which records the
+// derived sheets were built from
+func runIncrementalCycleOverWrittenRecords(t *testing.T, written, fetched []app.Attack) *recordsSheetsClient {
+	t.Helper()
+	now := time.Now()
+	war := &app.War{
+		ID:       9191,
+		Start:    now.Add(-3 * time.Hour).Unix(),
+		Factions: []app.Faction{{ID: 100, Name: "Us"}, {ID: 200, Name: "Them"}},
+	}
+	sheetConfig := &app.SheetConfig{WarID: 9191, SummaryTabName: "Summary - 9191", RecordsTabName: "Records - 9191"}
+	sheetsClient := newRecordsSheetsClient(sheetConfig)
+	sheetsClient.api.rows["Records - 9191"] = sheets.NewWarSheetsManager(nil).GenerateRecordsSheetHeaders()
+
+	config := &app.Config{SpreadsheetID: "spreadsheet-id", OurFactionID: 100, RespectTrendWindow: time.Hour}
+	attackService := attack.NewAttackProcessingService()
+	records := attackService.ProcessAttacksIntoRecords(written, war, 100)
+	if err := sheetsClient.processor.UpdateAttackRecords(context.Background(), "spreadsheet-id", sheetConfig, records); err != nil {
+		t.Fatalf("UpdateAttackRecords() returned unexpected error: %v", err)
+	}
+
+	tornClient := &pagedAttacksTornClient{
+		MockTornClient: mocks.NewMockTornClient(),
+		pages:          []*app.AttackResponse{{Attacks: fetched}},
+	}
+	tornClient.FactionBasicResponse = &app.FactionBasicResponse{ID: 100, Members: map[string]app.FactionMember{
+		"1": {Name: "First"},
+		"3": {Name: "Third"},
+	}}
+	processor := NewWarProcessor(tornClient, sheetsClient, nil, nil, attackService, NewWarSummaryService(attackService, config), config)
+	if err := processor.processWar(context.Background(), war, app.WarTypeRanked); err != nil {
+		t.Fatalf("processWar() returned unexpected error: %v", err)
+	}
+	return sheetsClient
+}
+
+func TestWarProcessor_DerivedSheetsCoverWholeWar(t *testing.T) {
+	now := time.Now()
+	incoming := warHit(2002, now.Add(-90*time.Minute))
+	incoming.Attacker, incoming.Defender = incoming.Defender, incoming.Attacker
+	byThird := warHit(2003, now.Add(-80*time.Minute))
+	byThird.Attacker.ID = 3
+	byThirdAgain := warHit(2004, now.Add(-70*time.Minute))
+	byThirdAgain.Attacker.ID = 3
+	// Member 3's last hit is answered in the next cycle's batch
+	counter := warHit(2005, now.Add(-65*time.Minute))
+	counter.Attacker, counter.Defender = byThirdAgain.Defender, byThirdAgain.Attacker
+	sheetsClient := runIncrementalCycleOverWrittenRecords(t,
+		[]app.Attack{warHit(2000, now.Add(-2*time.Hour)), incoming, byThird, byThirdAgain},
+		[]app.Attack{counter, warHit(2001, now.Add(-5*time.Minute))},
+	)
+
+	leaderboard := sheetsClient.UpdateLeaderboardCalledWith.Entries
+	if len(leaderboard) != 2 || leaderboard[0].AttacksMade != 2 || leaderboard[1].AttacksMade != 2 {
+		t.Errorf("expected the leaderboard to count both attacks of members 1 and 3, got %+v", leaderboard)
+	}
+	threats := sheetsClient.UpdateIncomingThreatsCalledWith.Entries
+	if len(threats) != 1 || threats[0].AttackerID != 2 || threats[0].AttacksAgainstUs != 2 {
+		t.Errorf("expected the earlier and new incoming attacks among the threats, got %+v", threats)
+	}
+	// Both members made two attacks over the war, so neither is flagged for slacking
+	if flags := sheetsClient.UpdateParticipationFlagsCalledWith.Flags; len(flags) != 0 {
+		t.Errorf("expected no participation flags across the whole war, got %+v", flags)
+	}
+	counterattacks := sheetsClient.UpdateCounterattacksCalledWith.Counterattacks
+	if len(counterattacks) != 1 || counterattacks[0].MemberID != 3 {
+		t.Errorf("expected the counterattack across the cycle boundary, got %+v", counterattacks)
+	}
+	var trendHits []int
+	for _, window := range sheetsClient.UpdateRespectTrendCalledWith.Trend {
+		trendHits = append(trendHits, window.AttackCount)
+	}
+	if !reflect.DeepEqual(trendHits, []int{0, 3, 1, 0}) {
+		t.Errorf("expected every hourly window to count its outgoing attacks, got %v", trendHits)
+	}
+}
+
+func TestWarProcessor_DashboardKeepsSkippedWar(t *testing.T) {
+	tornMock := mocks.NewMockTornClient()
+	tornMock.FactionAttacksResponse = &app.AttackResponse{Attacks: []app.Attack{warHit(5000, time.Now().Add(-time.Minute))}}
+	warResponse := &app.WarResponse{}
+	warResponse.Wars.Ranked = &app.War{
+		ID:       6363,
+		Start:    time.Now().Add(-time.Hour).Unix(),
+		Factions: []app.Faction{{ID: 100, Name: "Us", Score: 12}, {ID: 200, Name: "Them", Score: 5}},
+	}
+	tornMock.FactionWarsResponse = warResponse
+
+	sheetsMock := mocks.NewMockSheetsClient()
+	sheetsMock.EnsureWarSheetsResponse = &app.SheetConfig{WarID: 6363, SummaryTabName: "Summary - 6363", RecordsTabName: "Records - 6363"}
+	sheetsMock.ReadExistingRecordsResponse = &sheets.RecordsInfo{AttackCodes: map[string]bool{}}
+
+	config := &app.Config{SpreadsheetID: "spreadsheet-id", OurFactionID: 100, MinReprocessInterval: 5 * time.Minute,
+		ReprocessStateFile: filepath.Join(t.TempDir(), "reprocess_state.json")}
+	attackService := attack.NewAttackProcessingService()
+	processor := NewWarProcessor(tornMock, sheetsMock, nil, nil, attackService, NewWarSummaryService(attackService, config), config)
+
+	for cycle := 0; cycle < 2; cycle++ {
+		if err := processor.ProcessActiveWars(context.Background()); err != nil {
+			t.Fatalf("ProcessActiveWars() returned unexpected error: %v", err)
+		}
+	}
+
+	// The second cycle skips the war but the dashboard keeps its processed row
+	rows := sheetsMock.UpdateDashboardCalledWith.Rows
+	if len(rows) != 1 || rows[0].WarID != 6363 || rows[0].AttacksWon != 1 {
+		t.Fatalf("expected the skipped war's last row kept, got %+v", rows)
+	}
+
+	// After a restart there is no last row, so the war is shown with the API's scores
+	restarted := NewWarProcessor(tornMock, sheetsMock, nil, nil, attackService, NewWarSummaryService(attackService, config), config)
+	if err := restarted.ProcessActiveWars(context.Background()); err != nil {
+		t.Fatalf("ProcessActiveWars() after restart returned unexpected error: %v", err)
+	}
+	rows = sheetsMock.UpdateDashboardCalledWith.Rows
+	if len(rows) != 1 || rows[0].EnemyName != "Them" || rows[0].OurScore != 12 || rows[0].WarType != app.WarTypeRanked {
+		t.Errorf("expected the skipped war shown from its API scores after a restart, got %+v", rows)
+	}
+}
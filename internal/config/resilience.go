@@ -24,6 +24,13 @@ const (
 	SheetWriteMaxWait           = 10 * time.Second
 	SheetWriteBackoffMultiplier = 2.0
 	SheetWriteTimeout           = 30 * time.Second
+
+	// Sheet quota retry configuration; the Sheets API quota resets every minute, so
+	// the waits add up to just over a minute
+	SheetQuotaMaxAttempts       = 6
+	SheetQuotaInitialWait       = 2 * time.Second
+	SheetQuotaMaxWait           = 32 * time.Second
+	SheetQuotaBackoffMultiplier = 2.0
 )
 
 // RetryConfig defines retry behavior for operations
@@ -40,6 +47,7 @@ type ResilienceConfig struct {
 	APIRequest RetryConfig
 	SheetRead  RetryConfig
 	SheetWrite RetryConfig
+	SheetQuota RetryConfig
 }
 
 // DefaultResilienceConfig provides sensible defaults
@@ -65,4 +73,10 @@ var DefaultResilienceConfig = ResilienceConfig{
 		Multiplier:  SheetWriteBackoffMultiplier,
 		Timeout:     SheetWriteTimeout,
 	},
+	SheetQuota: RetryConfig{
+		MaxAttempts: SheetQuotaMaxAttempts,
+		InitialWait: SheetQuotaInitialWait,
+		MaxWait:     SheetQuotaMaxWait,
+		Multiplier:  SheetQuotaBackoffMultiplier,
+	},
 }
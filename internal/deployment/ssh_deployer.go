@@ -1,14 +1,19 @@
 package deployment
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"net"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"torn_rw_stats/internal/app"
+
 	"github.com/rs/zerolog/log"
 	"golang.org/x/crypto/ssh"
 )
@@ -16,23 +21,50 @@ import (
 const (
 	// SSHConnectionTimeout is the timeout for establishing SSH connections
 	SSHConnectionTimeout = 30 * time.Second
+
+	// DeployRetryDelay is the pause between upload attempts
+	DeployRetryDelay = 2 * time.Second
 )
 
+// Transport performs the individual steps of a deployment. SSHDeployer is its own
+// transport; tests inject one to exercise the retry and verification logic.
+type Transport interface {
+	Connect() error
+	Disconnect() error
+	Upload(data []byte, filename string) error
+	RemoteSize(filename string) (int64, error)
+}
+
 // SSHDeployer handles deployment via SSH/SCP, managing secure file transfers
-// and remote command execution for deploying JSON files to web servers.
+// and remote command execution for deploying JSON files to web servers. Deployments
+// share one connection, so concurrent DeployData calls run one at a time.
 type SSHDeployer struct {
-	keyPath   string
-	deployURL string
-	client    *ssh.Client
-	connected bool
+	mu         sync.Mutex // serializes deployments, which share client
+	keyPath    string
+	deployURL  string
+	client     *ssh.Client
+	connected  bool
+	transport  Transport
+	retries    int
+	retryDelay time.Duration
 }
 
 // NewSSHDeployer creates a new SSH deployer
 func NewSSHDeployer(deployURL string) *SSHDeployer {
-	return &SSHDeployer{
-		keyPath:   "deploy.pem",
-		deployURL: deployURL,
+	d := &SSHDeployer{
+		keyPath:    "deploy.pem",
+		deployURL:  deployURL,
+		retries:    app.DefaultDeployRetries,
+		retryDelay: DeployRetryDelay,
 	}
+	d.transport = d
+	return d
+}
+
+// SetRetries sets how many times a failed or truncated upload is retried; zero
+// disables retries and negative values are treated as zero
+func (d *SSHDeployer) SetRetries(retries int) {
+	d.retries = max(retries, 0)
 }
 
 // parseDeployURL parses a deploy URL in format: user@host:path
@@ -124,21 +156,80 @@ func (d *SSHDeployer) Disconnect() error {
 	return nil
 }
 
-// DeployData uploads data from an io.Reader via SCP.
-// Each call establishes a fresh SSH connection to avoid stale connection issues
+// DeployData uploads data from an io.Reader via SCP, then reads back the remote file
+// size to catch silently truncated uploads. Failed or truncated uploads are retried up
+// to the configured retry count. Concurrent calls wait for the deployment in progress.
+// Each attempt establishes a fresh SSH connection to avoid stale connection issues
 // that occur when TCP idle timeouts close the underlying socket between deployments.
 func (d *SSHDeployer) DeployData(data io.Reader, size int64, filename string) error {
+	content, err := io.ReadAll(data)
+	if err != nil {
+		return fmt.Errorf("failed to read data to deploy: %w", err)
+	}
+	if int64(len(content)) != size {
+		return fmt.Errorf("data to deploy is %d bytes, expected %d", len(content), size)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	attempts := d.retries + 1
+	var lastErr error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if attempt > 1 {
+			log.Warn().
+				Err(lastErr).
+				Str("filename", filename).
+				Int("attempt", attempt).
+				Int("max_attempts", attempts).
+				Msg("Retrying deployment")
+			time.Sleep(d.retryDelay)
+		}
+
+		lastErr = d.deployAttempt(content, filename)
+		if lastErr == nil {
+			log.Info().
+				Str("filename", filename).
+				Int64("size", size).
+				Int("attempt", attempt).
+				Msg("Successfully deployed and verified data via SCP")
+			return nil
+		}
+	}
+
+	return fmt.Errorf("failed to deploy %s after %d attempts: %w", filename, attempts, lastErr)
+}
+
+// deployAttempt uploads the content over a fresh connection and verifies the remote size
+func (d *SSHDeployer) deployAttempt(content []byte, filename string) error {
 	// Always connect fresh to avoid stale connection errors ("connection reset by peer")
 	// that occur when the remote server or intermediate devices close idle TCP connections.
-	if err := d.Connect(); err != nil {
+	if err := d.transport.Connect(); err != nil {
 		return fmt.Errorf("failed to connect: %w", err)
 	}
 	defer func() {
-		if err := d.Disconnect(); err != nil {
+		if err := d.transport.Disconnect(); err != nil {
 			log.Warn().Err(err).Msg("Failed to disconnect SSH after deployment")
 		}
 	}()
 
+	if err := d.transport.Upload(content, filename); err != nil {
+		return err
+	}
+
+	remoteSize, err := d.transport.RemoteSize(filename)
+	if err != nil {
+		return fmt.Errorf("failed to verify remote file size: %w", err)
+	}
+	if remoteSize != int64(len(content)) {
+		return fmt.Errorf("remote file size mismatch for %s: uploaded %d bytes, remote has %d", filename, len(content), remoteSize)
+	}
+
+	return nil
+}
+
+// Upload copies data to the remote deploy path via SCP over the current connection
+func (d *SSHDeployer) Upload(data []byte, filename string) error {
 	_, _, remotePath, err := d.parseDeployURL()
 	if err != nil {
 		return fmt.Errorf("failed to parse deploy URL: %w", err)
@@ -170,14 +261,14 @@ func (d *SSHDeployer) DeployData(data io.Reader, size int64, filename string) er
 	}
 
 	// Send file header
-	header := fmt.Sprintf("C0644 %d %s\n", size, filename)
+	header := fmt.Sprintf("C0644 %d %s\n", len(data), filename)
 	_, err = stdin.Write([]byte(header))
 	if err != nil {
 		return fmt.Errorf("failed to write SCP header: %w", err)
 	}
 
 	// Copy data content
-	_, err = io.Copy(stdin, data)
+	_, err = io.Copy(stdin, bytes.NewReader(data))
 	if err != nil {
 		return fmt.Errorf("failed to copy data content: %w", err)
 	}
@@ -195,10 +286,37 @@ func (d *SSHDeployer) DeployData(data io.Reader, size int64, filename string) er
 		return fmt.Errorf("SCP session failed: %w", err)
 	}
 
-	log.Info().
+	log.Debug().
 		Str("remote_path", remoteFilePath).
-		Int64("size", size).
-		Msg("Successfully deployed data via SCP")
+		Int("size", len(data)).
+		Msg("Uploaded data via SCP")
 
 	return nil
 }
+
+// RemoteSize returns the size in bytes of a file in the remote deploy path
+func (d *SSHDeployer) RemoteSize(filename string) (int64, error) {
+	_, _, remotePath, err := d.parseDeployURL()
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse deploy URL: %w", err)
+	}
+
+	session, err := d.client.NewSession()
+	if err != nil {
+		return 0, fmt.Errorf("failed to create SSH session: %w", err)
+	}
+	defer session.Close()
+
+	remoteFilePath := filepath.Join(remotePath, filename)
+	output, err := session.Output(fmt.Sprintf("wc -c < '%s'", strings.ReplaceAll(remoteFilePath, "'", `'\''`)))
+	if err != nil {
+		return 0, fmt.Errorf("failed to read remote file size: %w", err)
+	}
+
+	remoteSize, err := strconv.ParseInt(strings.TrimSpace(string(output)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("unexpected remote file size output %q: %w", strings.TrimSpace(string(output)), err)
+	}
+
+	return remoteSize, nil
+}
@@ -0,0 +1,178 @@
+package deployment
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeTransport records uploads and reports a remote size per attempt
+type fakeTransport struct {
+	uploads     int
+	connects    int
+	disconnects int
+	uploadErrs  []error // error returned by each upload attempt, nil when exhausted
+	remoteSizes []int64 // remote size reported after each upload attempt
+}
+
+func (f *fakeTransport) Connect() error {
+	f.connects++
+	return nil
+}
+
+func (f *fakeTransport) Disconnect() error {
+	f.disconnects++
+	return nil
+}
+
+func (f *fakeTransport) Upload(data []byte, filename string) error {
+	f.uploads++
+	if f.uploads <= len(f.uploadErrs) {
+		return f.uploadErrs[f.uploads-1]
+	}
+	return nil
+}
+
+func (f *fakeTransport) RemoteSize(filename string) (int64, error) {
+	return f.remoteSizes[f.uploads-1], nil
+}
+
+func TestSSHDeployerDeployData(t *testing.T) {
+	data := []byte(`{"Faction":"Enemy"}`)
+	size := int64(len(data))
+
+	tests := []struct {
+		name        string
+		retries     int
+		uploadErrs  []error
+		remoteSizes []int64
+		wantUploads int
+		wantErr     string
+	}{
+		{
+			name:        "verified on first attempt",
+			retries:     2,
+			remoteSizes: []int64{size},
+			wantUploads: 1,
+		},
+		{
+			name:        "truncated upload is retried",
+			retries:     2,
+			remoteSizes: []int64{size - 5, size},
+			wantUploads: 2,
+		},
+		{
+			name:        "failed upload is retried",
+			retries:     2,
+			uploadErrs:  []error{errors.New("connection reset by peer")},
+			remoteSizes: []int64{0, size},
+			wantUploads: 2,
+		},
+		{
+			name:        "gives up after the configured retries",
+			retries:     1,
+			remoteSizes: []int64{size - 5, size - 5},
+			wantUploads: 2,
+			wantErr:     "after 2 attempts: remote file size mismatch",
+		},
+		{
+			name:        "no retries",
+			retries:     0,
+			remoteSizes: []int64{0},
+			wantUploads: 1,
+			wantErr:     "after 1 attempts",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := &fakeTransport{uploadErrs: tt.uploadErrs, remoteSizes: tt.remoteSizes}
+			deployer := NewSSHDeployer("user@host:/var/www")
+			deployer.transport = transport
+			deployer.retryDelay = 0
+			deployer.SetRetries(tt.retries)
+
+			err := deployer.DeployData(bytes.NewReader(data), size, "travel_data.json")
+
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("DeployData() returned unexpected error: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("DeployData() error = %v, want containing %q", err, tt.wantErr)
+			}
+			if transport.uploads != tt.wantUploads {
+				t.Errorf("expected %d upload attempts, got %d", tt.wantUploads, transport.uploads)
+			}
+			if transport.connects != transport.disconnects {
+				t.Errorf("expected every connection to be closed, got %d connects and %d disconnects",
+					transport.connects, transport.disconnects)
+			}
+		})
+	}
+}
+
+// exclusiveTransport fails any step taken while another deployment holds the connection
+type exclusiveTransport struct {
+	active  atomic.Int32
+	overlap atomic.Bool
+	uploads atomic.Int32
+	size    int64
+}
+
+func (e *exclusiveTransport) Connect() error {
+	if e.active.Add(1) > 1 {
+		e.overlap.Store(true)
+	}
+	return nil
+}
+
+func (e *exclusiveTransport) Disconnect() error {
+	e.active.Add(-1)
+	return nil
+}
+
+func (e *exclusiveTransport) Upload(data []byte, filename string) error {
+	e.uploads.Add(1)
+	time.Sleep(time.Millisecond) // widen the window in which an overlap would be seen
+	return nil
+}
+
+func (e *exclusiveTransport) RemoteSize(filename string) (int64, error) {
+	return e.size, nil
+}
+
+func TestSSHDeployerSerializesConcurrentDeploys(t *testing.T) {
+	data := []byte(`{"Faction":"Enemy"}`)
+	transport := &exclusiveTransport{size: int64(len(data))}
+	deployer := NewSSHDeployer("user@host:/var/www")
+	deployer.transport = transport
+
+	const deploys = 8
+	var wg sync.WaitGroup
+	errs := make(chan error, deploys)
+	for i := 0; i < deploys; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- deployer.DeployData(bytes.NewReader(data), int64(len(data)), "travel_data.json")
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("DeployData() returned unexpected error: %v", err)
+		}
+	}
+	if transport.overlap.Load() {
+		t.Error("expected deployments to hold the connection one at a time")
+	}
+	if got := transport.uploads.Load(); got != deploys {
+		t.Errorf("expected %d uploads, got %d", deploys, got)
+	}
+}
@@ -11,6 +11,7 @@ import (
 // AttackProcessingService handles attack data processing and analysis, converting
 // raw attack data into detailed records and determining attack direction.
 type AttackProcessingService struct {
+	includeInternalAttacks bool
 }
 
 // NewAttackProcessingService creates a new attack processing service
@@ -18,11 +19,24 @@ func NewAttackProcessingService() *AttackProcessingService {
 	return &AttackProcessingService{}
 }
 
-// ProcessAttacksIntoRecords converts attack data into detailed attack records
+// SetIncludeInternalAttacks sets whether attacks between two of our own members are
+// kept as records; by default they are dropped
+func (aps *AttackProcessingService) SetIncludeInternalAttacks(include bool) {
+	aps.includeInternalAttacks = include
+}
+
+// ProcessAttacksIntoRecords converts attack data into detailed attack records.
+// Attacks between our own members are skipped unless internal attacks are included.
 func (aps *AttackProcessingService) ProcessAttacksIntoRecords(attacks []app.Attack, war *app.War, ourFactionID int) []app.AttackRecord {
 	var records []app.AttackRecord
 
+	skipped := 0
 	for _, attack := range attacks {
+		if !aps.includeInternalAttacks && IsInternalAttack(attack, ourFactionID) {
+			skipped++
+			continue
+		}
+
 		record := app.AttackRecord{
 			AttackID:            attack.ID,
 			Code:                attack.Code,
@@ -49,6 +63,7 @@ func (aps *AttackProcessingService) ProcessAttacksIntoRecords(attacks []app.Atta
 			ModifierOverseas:    attack.Modifiers.Overseas,
 			ModifierChain:       attack.Modifiers.Chain,
 			ModifierWarlord:     attack.Modifiers.Warlord,
+			LevelDifference:     attack.Attacker.Level - attack.Defender.Level,
 		}
 
 		// Handle attacker faction
@@ -63,14 +78,16 @@ func (aps *AttackProcessingService) ProcessAttacksIntoRecords(attacks []app.Atta
 			record.DefenderFactionName = attack.Defender.Faction.Name
 		}
 
-		// Handle finishing hit effects (take the first one if it exists)
+		// Handle finishing hit effects (keep all, and the first one in the single fields)
 		if len(attack.FinishingHitEffects) > 0 {
+			record.FinishingHitEffects = attack.FinishingHitEffects
 			record.FinishingHitName = attack.FinishingHitEffects[0].Name
 			record.FinishingHitValue = attack.FinishingHitEffects[0].Value
 		}
 
 		// Determine attack direction
 		record.Direction = aps.determineAttackDirection(attack, ourFactionID)
+		record.ThirdPartyFactionName = DetermineThirdPartyFaction(attack, war, ourFactionID)
 
 		records = append(records, record)
 	}
@@ -78,14 +95,77 @@ func (aps *AttackProcessingService) ProcessAttacksIntoRecords(attacks []app.Atta
 	log.Debug().
 		Int("total_attacks", len(attacks)).
 		Int("records_created", len(records)).
+		Int("internal_attacks_skipped", skipped).
 		Int("our_faction_id", ourFactionID).
 		Msg("Processed attacks into records")
 
 	return records
 }
 
+// IsInternalAttack reports whether both sides of an attack are in our faction, e.g. a
+// training spar.
+//
+// Pure function: No I/O operations, fully testable with direct inputs.
+func IsInternalAttack(attack app.Attack, ourFactionID int) bool {
+	return IsOurAttack(attack, ourFactionID) && IsAttackAgainstUs(attack, ourFactionID)
+}
+
+// ExcludeInternalAttacks returns the attacks that are not between two of our own members.
+//
+// Pure function: No I/O operations, fully testable with direct inputs.
+func ExcludeInternalAttacks(attacks []app.Attack, ourFactionID int) []app.Attack {
+	filtered := make([]app.Attack, 0, len(attacks))
+	for _, attack := range attacks {
+		if !IsInternalAttack(attack, ourFactionID) {
+			filtered = append(filtered, attack)
+		}
+	}
+	return filtered
+}
+
+// DetermineThirdPartyFaction returns the name of the faction outside the war when an
+// attack is between the enemy and someone else, neither side being us. Returns an empty
+// string for any other attack, including ones against factionless players.
+//
+// Pure function: No I/O operations, fully testable with direct inputs.
+func DetermineThirdPartyFaction(attack app.Attack, war *app.War, ourFactionID int) string {
+	attacker, defender := attack.Attacker.Faction, attack.Defender.Faction
+	if attacker == nil || defender == nil || war == nil {
+		return ""
+	}
+	if attacker.ID == ourFactionID || defender.ID == ourFactionID {
+		return ""
+	}
+
+	isEnemy := func(factionID int) bool {
+		for _, faction := range war.Factions {
+			if faction.ID == factionID && faction.ID != ourFactionID {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch {
+	case isEnemy(attacker.ID) && !isEnemy(defender.ID):
+		return defender.Name
+	case isEnemy(defender.ID) && !isEnemy(attacker.ID):
+		return attacker.Name
+	default:
+		return ""
+	}
+}
+
 // determineAttackDirection determines if an attack is outgoing, incoming, or unknown
 func (aps *AttackProcessingService) determineAttackDirection(attack app.Attack, ourFactionID int) string {
+	return AttackDirection(attack, ourFactionID)
+}
+
+// AttackDirection determines if an attack is "Outgoing", "Incoming" or "Unknown"
+// relative to our faction.
+//
+// Pure function: No I/O operations, fully testable with direct inputs.
+func AttackDirection(attack app.Attack, ourFactionID int) string {
 	if attack.Attacker.Faction != nil && attack.Attacker.Faction.ID == ourFactionID {
 		return "Outgoing"
 	} else if attack.Defender.Faction != nil && attack.Defender.Faction.ID == ourFactionID {
@@ -1,6 +1,7 @@
 package attack
 
 import (
+	"reflect"
 	"testing"
 
 	"torn_rw_stats/internal/app"
@@ -88,6 +89,107 @@ func TestAttackProcessingServiceProcessAttacksIntoRecords(t *testing.T) {
 	if record.FinishingHitName != "Critical Hit" {
 		t.Errorf("Expected FinishingHitName 'Critical Hit', got %q", record.FinishingHitName)
 	}
+	if len(record.FinishingHitEffects) != 1 || record.FinishingHitEffects[0].Name != "Critical Hit" {
+		t.Errorf("Expected FinishingHitEffects to hold 'Critical Hit', got %v", record.FinishingHitEffects)
+	}
+}
+
+func TestAttackProcessingServiceLevelDifference(t *testing.T) {
+	service := NewAttackProcessingService()
+	war := &app.War{ID: 1001}
+
+	tests := []struct {
+		name          string
+		attackerLevel int
+		defenderLevel int
+		expected      int
+	}{
+		{"hitting down", 55, 40, 15},
+		{"hitting up", 40, 55, -15},
+		{"same level", 30, 30, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attacks := []app.Attack{{
+				ID:       1,
+				Attacker: app.User{ID: 123, Level: tt.attackerLevel},
+				Defender: app.User{ID: 456, Level: tt.defenderLevel},
+			}}
+
+			records := service.ProcessAttacksIntoRecords(attacks, war, 12345)
+			if records[0].LevelDifference != tt.expected {
+				t.Errorf("Expected LevelDifference %d, got %d", tt.expected, records[0].LevelDifference)
+			}
+		})
+	}
+}
+
+func TestAttackProcessingServiceThirdPartyFaction(t *testing.T) {
+	service := NewAttackProcessingService()
+	ours := &app.Faction{ID: 100, Name: "Us"}
+	enemy := &app.Faction{ID: 200, Name: "Them"}
+	ally := &app.Faction{ID: 300, Name: "Allies"}
+	war := &app.War{ID: 1001, Factions: []app.Faction{*ours, *enemy}}
+
+	attacks := []app.Attack{
+		// Ally assisting by hitting the enemy
+		{ID: 1, Attacker: app.User{ID: 31, Faction: ally}, Defender: app.User{ID: 21, Faction: enemy}},
+		// Enemy hitting the ally back
+		{ID: 2, Attacker: app.User{ID: 22, Faction: enemy}, Defender: app.User{ID: 32, Faction: ally}},
+		// Our own hit has no third party
+		{ID: 3, Attacker: app.User{ID: 11, Faction: ours}, Defender: app.User{ID: 23, Faction: enemy}},
+		// Enemy hitting a factionless player
+		{ID: 4, Attacker: app.User{ID: 24, Faction: enemy}, Defender: app.User{ID: 41}},
+	}
+
+	records := service.ProcessAttacksIntoRecords(attacks, war, ours.ID)
+
+	expected := []string{"Allies", "Allies", "", ""}
+	for i, want := range expected {
+		if records[i].ThirdPartyFactionName != want {
+			t.Errorf("attack %d: expected third party %q, got %q", attacks[i].ID, want, records[i].ThirdPartyFactionName)
+		}
+	}
+}
+
+func TestAttackProcessingServiceInternalAttacks(t *testing.T) {
+	ours := &app.Faction{ID: 100, Name: "Us"}
+	enemy := &app.Faction{ID: 200, Name: "Them"}
+	war := &app.War{ID: 1001, Factions: []app.Faction{*ours, *enemy}}
+
+	attacks := []app.Attack{
+		{ID: 1, Attacker: app.User{ID: 11, Faction: ours}, Defender: app.User{ID: 21, Faction: enemy}},
+		// Training spar between two of our members
+		{ID: 2, Attacker: app.User{ID: 11, Faction: ours}, Defender: app.User{ID: 12, Faction: ours}},
+		{ID: 3, Attacker: app.User{ID: 22, Faction: enemy}, Defender: app.User{ID: 12, Faction: ours}},
+	}
+
+	tests := []struct {
+		name     string
+		include  bool
+		expected []int64
+	}{
+		{"dropped by default", false, []int64{1, 3}},
+		{"kept when included", true, []int64{1, 2, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewAttackProcessingService()
+			service.SetIncludeInternalAttacks(tt.include)
+
+			records := service.ProcessAttacksIntoRecords(attacks, war, ours.ID)
+
+			var ids []int64
+			for _, record := range records {
+				ids = append(ids, record.AttackID)
+			}
+			if !reflect.DeepEqual(ids, tt.expected) {
+				t.Errorf("expected records for attacks %v, got %v", tt.expected, ids)
+			}
+		})
+	}
 }
 
 func TestAttackProcessingServiceDetermineAttackDirection(t *testing.T) {
@@ -0,0 +1,66 @@
+package attack
+
+import (
+	"sort"
+	"time"
+
+	"torn_rw_stats/internal/app"
+)
+
+// DefaultCounterattackWindow is how soon after our attack a hit back from the same
+// opponent counts as a counterattack
+const DefaultCounterattackWindow = 10 * time.Minute
+
+// FindCounterattacks matches each of our outgoing attacks with the first successful
+// incoming attack by the same opponent on the same member that started within window
+// afterwards. An incoming attack answers at most one of ours. A non-positive window
+// uses DefaultCounterattackWindow. Results are in order of our attacks.
+//
+// Pure function: No I/O operations, fully testable with direct inputs.
+func FindCounterattacks(records []app.AttackRecord, window time.Duration) []app.Counterattack {
+	if window <= 0 {
+		window = DefaultCounterattackWindow
+	}
+
+	sorted := make([]app.AttackRecord, len(records))
+	copy(sorted, records)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Started.Before(sorted[j].Started)
+	})
+
+	used := make(map[int]bool)
+	var counterattacks []app.Counterattack
+	for i, ours := range sorted {
+		if ours.Direction != "Outgoing" {
+			continue
+		}
+
+		for j := i + 1; j < len(sorted); j++ {
+			theirs := sorted[j]
+			if theirs.Started.Sub(ours.Started) > window {
+				break
+			}
+			if used[j] || theirs.Direction != "Incoming" ||
+				theirs.AttackerID != ours.DefenderID || theirs.DefenderID != ours.AttackerID ||
+				!IsSuccessfulAttack(theirs.Result) {
+				continue
+			}
+
+			used[j] = true
+			counterattacks = append(counterattacks, app.Counterattack{
+				MemberID:            ours.AttackerID,
+				MemberName:          ours.AttackerName,
+				OpponentID:          ours.DefenderID,
+				OpponentName:        ours.DefenderName,
+				AttackedAt:          ours.Started,
+				AttackResult:        ours.Result,
+				CounterattackedAt:   theirs.Started,
+				CounterattackResult: theirs.Result,
+				RetaliationModifier: theirs.ModifierRetaliation,
+			})
+			break
+		}
+	}
+
+	return counterattacks
+}
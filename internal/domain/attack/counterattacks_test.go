@@ -0,0 +1,81 @@
+package attack
+
+import (
+	"testing"
+	"time"
+
+	"torn_rw_stats/internal/app"
+)
+
+func TestFindCounterattacks(t *testing.T) {
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	outgoing := func(memberID, opponentID int, at time.Duration) app.AttackRecord {
+		return app.AttackRecord{
+			Direction: "Outgoing", Started: start.Add(at), Result: "Hospitalized",
+			AttackerID: memberID, AttackerName: "Member", DefenderID: opponentID, DefenderName: "Opponent",
+		}
+	}
+	incoming := func(opponentID, memberID int, at time.Duration, result string) app.AttackRecord {
+		return app.AttackRecord{
+			Direction: "Incoming", Started: start.Add(at), Result: result, ModifierRetaliation: 1.5,
+			AttackerID: opponentID, DefenderID: memberID,
+		}
+	}
+
+	tests := []struct {
+		name     string
+		records  []app.AttackRecord
+		expected int
+	}{
+		{
+			name:     "matched attack and retaliation",
+			records:  []app.AttackRecord{outgoing(1, 9, 0), incoming(9, 1, 3*time.Minute, "Hospitalized")},
+			expected: 1,
+		},
+		{
+			name:     "unmatched attack",
+			records:  []app.AttackRecord{outgoing(1, 9, 0)},
+			expected: 0,
+		},
+		{
+			name:     "hit back after the window",
+			records:  []app.AttackRecord{outgoing(1, 9, 0), incoming(9, 1, 20*time.Minute, "Hospitalized")},
+			expected: 0,
+		},
+		{
+			name:     "hit back on a different member",
+			records:  []app.AttackRecord{outgoing(1, 9, 0), incoming(9, 2, 3*time.Minute, "Hospitalized")},
+			expected: 0,
+		},
+		{
+			name:     "failed hit back",
+			records:  []app.AttackRecord{outgoing(1, 9, 0), incoming(9, 1, 3*time.Minute, "Lost")},
+			expected: 0,
+		},
+		{
+			name:     "one hit back answers only one attack",
+			records:  []app.AttackRecord{outgoing(1, 9, 0), outgoing(1, 9, time.Minute), incoming(9, 1, 3*time.Minute, "Hospitalized")},
+			expected: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counterattacks := FindCounterattacks(tt.records, DefaultCounterattackWindow)
+			if len(counterattacks) != tt.expected {
+				t.Fatalf("expected %d counterattacks, got %d: %+v", tt.expected, len(counterattacks), counterattacks)
+			}
+		})
+	}
+
+	// Records arrive newest first from the API, so order must not matter
+	counterattacks := FindCounterattacks([]app.AttackRecord{incoming(9, 1, 3*time.Minute, "Hospitalized"), outgoing(1, 9, 0)}, 0)
+	if len(counterattacks) != 1 {
+		t.Fatalf("expected 1 counterattack, got %d", len(counterattacks))
+	}
+	got := counterattacks[0]
+	if got.MemberID != 1 || got.OpponentID != 9 || !got.CounterattackedAt.Equal(start.Add(3*time.Minute)) || got.RetaliationModifier != 1.5 {
+		t.Errorf("unexpected counterattack: %+v", got)
+	}
+}
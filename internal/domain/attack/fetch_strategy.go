@@ -35,8 +35,26 @@ type PaginationConfig struct {
 	GapThreshold time.Duration
 }
 
-// DetermineFetchStrategy decides how to fetch attacks based on time range
-func DetermineFetchStrategy(startTime, endTime time.Time) FetchStrategy {
+// Default pagination limits for large time ranges
+const (
+	DefaultMaxPages     = 100
+	DefaultGapThreshold = 5 * time.Minute
+)
+
+// DefaultPaginationConfig returns the pagination settings used when none are configured
+func DefaultPaginationConfig() PaginationConfig {
+	return PaginationConfig{
+		Enabled:      true,
+		MaxPages:     DefaultMaxPages,
+		StopOnGap:    true,
+		GapThreshold: DefaultGapThreshold,
+	}
+}
+
+// DetermineFetchStrategy decides how to fetch attacks based on time range. Large ranges
+// are paginated with the given settings; a non-positive MaxPages or GapThreshold uses
+// DefaultMaxPages or DefaultGapThreshold.
+func DetermineFetchStrategy(startTime, endTime time.Time, pagination PaginationConfig) FetchStrategy {
 	strategy := FetchStrategy{
 		TimeRange: TimeRange{Start: startTime, End: endTime},
 	}
@@ -47,12 +65,14 @@ func DetermineFetchStrategy(startTime, endTime time.Time) FetchStrategy {
 		strategy.Pagination = PaginationConfig{Enabled: false}
 	} else {
 		strategy.Method = FetchMethodPaginated
-		strategy.Pagination = PaginationConfig{
-			Enabled:      true,
-			MaxPages:     100,
-			StopOnGap:    true,
-			GapThreshold: 5 * time.Minute,
+		if pagination.MaxPages <= 0 {
+			pagination.MaxPages = DefaultMaxPages
+		}
+		if pagination.GapThreshold <= 0 {
+			pagination.GapThreshold = DefaultGapThreshold
 		}
+		pagination.Enabled = true
+		strategy.Pagination = pagination
 	}
 
 	return strategy
@@ -60,7 +60,7 @@ func TestDetermineFetchStrategy(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			strategy := DetermineFetchStrategy(tt.startTime, tt.endTime)
+			strategy := DetermineFetchStrategy(tt.startTime, tt.endTime, DefaultPaginationConfig())
 
 			if strategy.Method != tt.expectedMethod {
 				t.Errorf("expected method %s, got %s", tt.expectedMethod, strategy.Method)
@@ -310,7 +310,7 @@ func TestDetermineFetchStrategyEdgeCases(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			strategy := DetermineFetchStrategy(tt.startTime, tt.endTime)
+			strategy := DetermineFetchStrategy(tt.startTime, tt.endTime, DefaultPaginationConfig())
 
 			if strategy.Method != tt.expectedMethod {
 				t.Errorf("%s: expected method %s, got %s", tt.description, tt.expectedMethod, strategy.Method)
@@ -363,7 +363,7 @@ func TestPaginationConfigDefaults(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			strategy := DetermineFetchStrategy(tt.startTime, tt.endTime)
+			strategy := DetermineFetchStrategy(tt.startTime, tt.endTime, DefaultPaginationConfig())
 
 			if strategy.Pagination.Enabled != tt.expectedEnabled {
 				t.Errorf("expected Enabled=%v, got %v", tt.expectedEnabled, strategy.Pagination.Enabled)
@@ -385,3 +385,21 @@ func TestPaginationConfigDefaults(t *testing.T) {
 		})
 	}
 }
+
+func TestDetermineFetchStrategyCustomPagination(t *testing.T) {
+	now := time.Now()
+
+	custom := PaginationConfig{MaxPages: 10, StopOnGap: false, GapThreshold: 15 * time.Minute}
+	strategy := DetermineFetchStrategy(now.Add(-48*time.Hour), now, custom)
+
+	expected := PaginationConfig{Enabled: true, MaxPages: 10, StopOnGap: false, GapThreshold: 15 * time.Minute}
+	if strategy.Pagination != expected {
+		t.Errorf("expected pagination %+v, got %+v", expected, strategy.Pagination)
+	}
+
+	// Unset limits fall back to the defaults
+	strategy = DetermineFetchStrategy(now.Add(-48*time.Hour), now, PaginationConfig{})
+	if strategy.Pagination.MaxPages != DefaultMaxPages || strategy.Pagination.GapThreshold != DefaultGapThreshold {
+		t.Errorf("expected default limits, got %+v", strategy.Pagination)
+	}
+}
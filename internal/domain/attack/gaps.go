@@ -0,0 +1,51 @@
+package attack
+
+import (
+	"sort"
+	"time"
+
+	"torn_rw_stats/internal/app"
+)
+
+// DefaultSuspiciousGapThreshold is the gap between consecutive attacks that is reported
+// as a likely missed page when none is configured
+const DefaultSuspiciousGapThreshold = 2 * time.Hour
+
+// AttackGap is a stretch with no recorded attacks between two consecutive attacks
+type AttackGap struct {
+	From time.Time // Start of the attack before the gap
+	To   time.Time // Start of the attack after the gap
+}
+
+// Duration returns the length of the gap
+func (g AttackGap) Duration() time.Duration {
+	return g.To.Sub(g.From)
+}
+
+// FindAttackGaps returns every gap between consecutive attack start times that exceeds
+// the threshold, oldest first. A threshold of zero or less falls back to
+// DefaultSuspiciousGapThreshold.
+//
+// Pure function: No I/O operations, fully testable with direct inputs.
+func FindAttackGaps(records []app.AttackRecord, threshold time.Duration) []AttackGap {
+	if threshold <= 0 {
+		threshold = DefaultSuspiciousGapThreshold
+	}
+
+	starts := make([]time.Time, len(records))
+	for i, record := range records {
+		starts[i] = record.Started
+	}
+	sort.Slice(starts, func(i, j int) bool {
+		return starts[i].Before(starts[j])
+	})
+
+	var gaps []AttackGap
+	for i := 1; i < len(starts); i++ {
+		if starts[i].Sub(starts[i-1]) > threshold {
+			gaps = append(gaps, AttackGap{From: starts[i-1], To: starts[i]})
+		}
+	}
+
+	return gaps
+}
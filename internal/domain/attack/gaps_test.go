@@ -0,0 +1,58 @@
+package attack
+
+import (
+	"testing"
+	"time"
+
+	"torn_rw_stats/internal/app"
+)
+
+func TestFindAttackGaps(t *testing.T) {
+	base := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	records := []app.AttackRecord{
+		{Started: base.Add(4 * time.Hour)},
+		{Started: base},
+		{Started: base.Add(30 * time.Minute)},
+		{Started: base.Add(time.Hour)},
+	}
+
+	tests := []struct {
+		name      string
+		threshold time.Duration
+		expected  []AttackGap
+	}{
+		{
+			name:      "default threshold reports the 3 hour gap",
+			threshold: 0,
+			expected:  []AttackGap{{From: base.Add(time.Hour), To: base.Add(4 * time.Hour)}},
+		},
+		{
+			name:      "shorter threshold reports every longer gap",
+			threshold: 20 * time.Minute,
+			expected: []AttackGap{
+				{From: base, To: base.Add(30 * time.Minute)},
+				{From: base.Add(30 * time.Minute), To: base.Add(time.Hour)},
+				{From: base.Add(time.Hour), To: base.Add(4 * time.Hour)},
+			},
+		},
+		{
+			name:      "threshold above the largest gap reports nothing",
+			threshold: 4 * time.Hour,
+			expected:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gaps := FindAttackGaps(records, tt.threshold)
+			if len(gaps) != len(tt.expected) {
+				t.Fatalf("expected %d gaps, got %d: %+v", len(tt.expected), len(gaps), gaps)
+			}
+			for i := range gaps {
+				if !gaps[i].From.Equal(tt.expected[i].From) || !gaps[i].To.Equal(tt.expected[i].To) {
+					t.Errorf("gap %d = %+v, want %+v", i, gaps[i], tt.expected[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package attack
+
+import (
+	"torn_rw_stats/internal/app"
+)
+
+// MarkFirstAndLastHits flags the war's first blood and last hit among records, ordering
+// them by start time with ties broken by attack ID. Records are only compared against
+// what is already in the sheets, whose oldest and newest start times are earliestExisting
+// and latestExisting (zero when the sheets are empty). The first blood is only marked
+// when fromWarStart says the records were fetched back to the war's start, so a partial
+// fetch of the newest pages cannot claim it, and then only when it started before every
+// existing row. The last hit is the latest record started after latestExisting. Every
+// other record has both markers cleared.
+//
+// Pure function: No I/O operations, fully testable with direct inputs.
+func MarkFirstAndLastHits(records []app.AttackRecord, fromWarStart bool, earliestExisting, latestExisting int64) {
+	first, last := -1, -1
+	for i := range records {
+		records[i].IsFirstBlood = false
+		records[i].IsLastHit = false
+
+		if first < 0 || hitBefore(records[i], records[first]) {
+			first = i
+		}
+		if records[i].Started.Unix() > latestExisting && (last < 0 || hitBefore(records[last], records[i])) {
+			last = i
+		}
+	}
+
+	if first >= 0 && fromWarStart && (earliestExisting == 0 || records[first].Started.Unix() < earliestExisting) {
+		records[first].IsFirstBlood = true
+	}
+	if last >= 0 {
+		records[last].IsLastHit = true
+	}
+}
+
+// hitBefore reports whether a started before b, breaking ties by attack ID
+func hitBefore(a, b app.AttackRecord) bool {
+	if !a.Started.Equal(b.Started) {
+		return a.Started.Before(b.Started)
+	}
+	return a.AttackID < b.AttackID
+}
@@ -0,0 +1,87 @@
+package attack
+
+import (
+	"testing"
+	"time"
+
+	"torn_rw_stats/internal/app"
+)
+
+func TestMarkFirstAndLastHits(t *testing.T) {
+	base := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	sortedRecords := func() []app.AttackRecord {
+		return []app.AttackRecord{
+			{AttackID: 10, Started: base},
+			{AttackID: 11, Started: base}, // same second as the first; the lower ID wins
+			{AttackID: 12, Started: base.Add(time.Minute)},
+			{AttackID: 14, Started: base.Add(2 * time.Minute)},
+			{AttackID: 13, Started: base.Add(2 * time.Minute)}, // tie for last; the higher ID wins
+		}
+	}
+
+	tests := []struct {
+		name             string
+		fromWarStart     bool
+		earliestExisting int64
+		latestExisting   int64
+		expectFirst      int64 // 0 when no record should be first blood
+		expectLast       int64 // 0 when no record should be the last hit
+	}{
+		{name: "fresh sheet", fromWarStart: true, expectFirst: 10, expectLast: 14},
+		{name: "partial fetch leaves first blood unmarked", expectLast: 14},
+		{name: "incremental keeps existing first blood", earliestExisting: base.Unix(), latestExisting: base.Unix(), expectLast: 14},
+		{name: "backfill older than the sheet moves first blood", fromWarStart: true,
+			earliestExisting: base.Add(time.Minute).Unix(), latestExisting: base.Add(time.Hour).Unix(), expectFirst: 10},
+		{name: "backfill no older than the sheet keeps first blood", fromWarStart: true,
+			earliestExisting: base.Unix(), latestExisting: base.Add(time.Hour).Unix()},
+		{name: "nothing newer than the sheet", earliestExisting: base.Unix(), latestExisting: base.Add(time.Hour).Unix()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records := sortedRecords()
+			MarkFirstAndLastHits(records, tt.fromWarStart, tt.earliestExisting, tt.latestExisting)
+
+			var firstBloods, lastHits []int64
+			for _, record := range records {
+				if record.IsFirstBlood {
+					firstBloods = append(firstBloods, record.AttackID)
+				}
+				if record.IsLastHit {
+					lastHits = append(lastHits, record.AttackID)
+				}
+			}
+
+			checkMarked(t, "first blood", firstBloods, tt.expectFirst)
+			checkMarked(t, "last hit", lastHits, tt.expectLast)
+		})
+	}
+}
+
+func TestMarkFirstAndLastHitsClearsStaleMarkers(t *testing.T) {
+	base := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	records := []app.AttackRecord{
+		{AttackID: 1, Started: base, IsLastHit: true},
+		{AttackID: 2, Started: base.Add(time.Minute), IsFirstBlood: true},
+	}
+
+	MarkFirstAndLastHits(records, true, 0, 0)
+
+	if !records[0].IsFirstBlood || records[0].IsLastHit || records[1].IsFirstBlood || !records[1].IsLastHit {
+		t.Errorf("unexpected markers: %+v", records)
+	}
+}
+
+// checkMarked fails unless exactly the expected attack ID (or none, for 0) was marked
+func checkMarked(t *testing.T, marker string, marked []int64, expected int64) {
+	t.Helper()
+	if expected == 0 {
+		if len(marked) != 0 {
+			t.Errorf("expected no %s, got %v", marker, marked)
+		}
+		return
+	}
+	if len(marked) != 1 || marked[0] != expected {
+		t.Errorf("expected exactly one %s on attack %d, got %v", marker, expected, marked)
+	}
+}
@@ -0,0 +1,69 @@
+package attack
+
+import (
+	"strconv"
+	"time"
+
+	"torn_rw_stats/internal/app"
+)
+
+// HospitalizedResult is the attack result for a hit that put the defender in hospital
+const HospitalizedResult = "Hospitalized"
+
+// AttributeHospitalStays matches enemy members currently in hospital to the outgoing
+// attack that put them there, returning each attack's ID mapped to the unix time the
+// stay ends. Only a member's latest hospitalizing hit is credited, and only when it
+// ended before their stay ends. The attack log does not report hospital time, so the
+// members' current status is the only source for it.
+//
+// Pure function: No I/O operations, fully testable with direct inputs.
+func AttributeHospitalStays(attacks []app.Attack, ourFactionID int, members map[string]app.FactionMember) map[int64]int64 {
+	latest := make(map[int]app.Attack)
+	for _, attack := range attacks {
+		if !IsOurAttack(attack, ourFactionID) || attack.Result != HospitalizedResult {
+			continue
+		}
+		if previous, ok := latest[attack.Defender.ID]; !ok || attack.Ended > previous.Ended {
+			latest[attack.Defender.ID] = attack
+		}
+	}
+
+	stays := make(map[int64]int64)
+	for defenderID, attack := range latest {
+		member, ok := members[strconv.Itoa(defenderID)]
+		if !ok || member.Status.State != "Hospital" || member.Status.Until == nil {
+			continue
+		}
+		if until := *member.Status.Until; until > attack.Ended {
+			stays[attack.ID] = until
+		}
+	}
+	return stays
+}
+
+// CalculateAverageHospitalTime averages how long our hospitalizing outgoing attacks
+// kept their targets in hospital, from the hospital end times keyed by attack ID.
+// Attacks that did not hospitalize, or whose stay is unknown, are excluded; returns
+// 0 when none remain.
+//
+// Pure function: No I/O operations, fully testable with direct inputs.
+func CalculateAverageHospitalTime(attacks []app.Attack, ourFactionID int, hospitalUntil map[int64]int64) time.Duration {
+	var total time.Duration
+	count := 0
+	for _, attack := range attacks {
+		if !IsOurAttack(attack, ourFactionID) || attack.Result != HospitalizedResult {
+			continue
+		}
+		until, ok := hospitalUntil[attack.ID]
+		if !ok || until <= attack.Ended {
+			continue
+		}
+		total += time.Duration(until-attack.Ended) * time.Second
+		count++
+	}
+
+	if count == 0 {
+		return 0
+	}
+	return total / time.Duration(count)
+}
@@ -0,0 +1,79 @@
+package attack
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"torn_rw_stats/internal/app"
+)
+
+func TestCalculateAverageHospitalTime(t *testing.T) {
+	ours := &app.Faction{ID: 1001}
+	enemy := &app.Faction{ID: 2002}
+	hit := func(id int64, result string, ended int64) app.Attack {
+		return app.Attack{
+			ID:       id,
+			Result:   result,
+			Ended:    ended,
+			Attacker: app.User{ID: 1, Faction: ours},
+			Defender: app.User{ID: int(id) + 100, Faction: enemy},
+		}
+	}
+
+	attacks := []app.Attack{
+		hit(1, "Hospitalized", 1000),
+		hit(2, "Hospitalized", 2000),
+		hit(3, "Mugged", 3000),
+	}
+	hospitalUntil := map[int64]int64{
+		1: 1000 + 30*60, // 30 minutes
+		2: 2000 + 90*60, // 90 minutes
+		3: 3000 + 60*60, // Not hospitalized, excluded
+	}
+
+	tests := []struct {
+		name          string
+		attacks       []app.Attack
+		hospitalUntil map[int64]int64
+		expected      time.Duration
+	}{
+		{"averages hospitalizing attacks", attacks, hospitalUntil, time.Hour},
+		{"unknown stays are excluded", attacks, map[int64]int64{2: 2000 + 90*60}, 90 * time.Minute},
+		{"no known stays", attacks, nil, 0},
+		{"incoming attacks are excluded", []app.Attack{{ID: 1, Result: "Hospitalized", Ended: 1000, Attacker: app.User{Faction: enemy}}}, hospitalUntil, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CalculateAverageHospitalTime(tt.attacks, ours.ID, tt.hospitalUntil); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestAttributeHospitalStays(t *testing.T) {
+	ours := &app.Faction{ID: 1001}
+	until := func(ts int64) *int64 { return &ts }
+
+	attacks := []app.Attack{
+		{ID: 1, Result: "Hospitalized", Ended: 1000, Attacker: app.User{Faction: ours}, Defender: app.User{ID: 501}},
+		{ID: 2, Result: "Hospitalized", Ended: 1500, Attacker: app.User{Faction: ours}, Defender: app.User{ID: 501}},
+		{ID: 3, Result: "Hospitalized", Ended: 1200, Attacker: app.User{Faction: ours}, Defender: app.User{ID: 502}},
+		{ID: 4, Result: "Mugged", Ended: 1300, Attacker: app.User{Faction: ours}, Defender: app.User{ID: 503}},
+		{ID: 5, Result: "Hospitalized", Ended: 1400, Attacker: app.User{Faction: ours}, Defender: app.User{ID: 504}},
+	}
+	members := map[string]app.FactionMember{
+		"501": {Status: app.MemberStatus{State: "Hospital", Until: until(4000)}},
+		"502": {Status: app.MemberStatus{State: "Okay"}},
+		"503": {Status: app.MemberStatus{State: "Hospital", Until: until(5000)}},
+		"504": {Status: app.MemberStatus{State: "Hospital", Until: until(1000)}}, // Stay ended before the hit
+	}
+
+	// Only the latest hit on 501 is credited; 502 is out, 503 was not hospitalized by us
+	expected := map[int64]int64{2: 4000}
+	if got := AttributeHospitalStays(attacks, ours.ID, members); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
@@ -0,0 +1,54 @@
+package attack
+
+import (
+	"sort"
+
+	"torn_rw_stats/internal/app"
+)
+
+// BuildIncomingThreats aggregates incoming attacks by attacker into entries sorted by
+// attacks against us (highest first). Ties are broken by respect they gained and then
+// by name. Attackers from our own faction are never included.
+//
+// Pure function: No I/O operations, fully testable with direct inputs.
+func BuildIncomingThreats(records []app.AttackRecord, ourFactionID int) []app.IncomingThreatEntry {
+	entries := make(map[int]*app.IncomingThreatEntry)
+
+	for _, record := range records {
+		if record.Direction != "Incoming" {
+			continue
+		}
+		if record.AttackerFactionID != nil && *record.AttackerFactionID == ourFactionID {
+			continue
+		}
+
+		entry, exists := entries[record.AttackerID]
+		if !exists {
+			entry = &app.IncomingThreatEntry{
+				AttackerID:   record.AttackerID,
+				AttackerName: record.AttackerName,
+			}
+			entries[record.AttackerID] = entry
+		}
+
+		entry.AttacksAgainstUs++
+		entry.RespectTheyGained += record.RespectGain
+	}
+
+	threats := make([]app.IncomingThreatEntry, 0, len(entries))
+	for _, entry := range entries {
+		threats = append(threats, *entry)
+	}
+
+	sort.Slice(threats, func(i, j int) bool {
+		if threats[i].AttacksAgainstUs != threats[j].AttacksAgainstUs {
+			return threats[i].AttacksAgainstUs > threats[j].AttacksAgainstUs
+		}
+		if threats[i].RespectTheyGained != threats[j].RespectTheyGained {
+			return threats[i].RespectTheyGained > threats[j].RespectTheyGained
+		}
+		return threats[i].AttackerName < threats[j].AttackerName
+	})
+
+	return threats
+}
@@ -0,0 +1,37 @@
+package attack
+
+import (
+	"math"
+	"testing"
+
+	"torn_rw_stats/internal/app"
+)
+
+func TestBuildIncomingThreats(t *testing.T) {
+	ourFactionID := 1001
+	enemyFactionID := 2002
+
+	records := []app.AttackRecord{
+		{Direction: "Incoming", AttackerID: 9, AttackerName: "Mallory", AttackerFactionID: &enemyFactionID, RespectGain: 2.5},
+		{Direction: "Incoming", AttackerID: 8, AttackerName: "Eve", AttackerFactionID: &enemyFactionID, RespectGain: 4.0},
+		{Direction: "Incoming", AttackerID: 9, AttackerName: "Mallory", AttackerFactionID: &enemyFactionID, RespectGain: 1.5},
+		// Outgoing attacks and our own members are never threats
+		{Direction: "Outgoing", AttackerID: 1, AttackerName: "Alice", AttackerFactionID: &ourFactionID, RespectGain: 3.0},
+		{Direction: "Incoming", AttackerID: 2, AttackerName: "Bob", AttackerFactionID: &ourFactionID, RespectGain: 1.0},
+	}
+
+	threats := BuildIncomingThreats(records, ourFactionID)
+
+	if len(threats) != 2 {
+		t.Fatalf("expected 2 attackers, got %d: %+v", len(threats), threats)
+	}
+	if threats[0].AttackerName != "Mallory" || threats[0].AttacksAgainstUs != 2 {
+		t.Errorf("expected Mallory first with 2 attacks, got %+v", threats[0])
+	}
+	if math.Abs(threats[0].RespectTheyGained-4.0) > 1e-9 {
+		t.Errorf("expected Mallory to have gained 4.0 respect, got %f", threats[0].RespectTheyGained)
+	}
+	if threats[1].AttackerName != "Eve" || threats[1].AttacksAgainstUs != 1 {
+		t.Errorf("expected Eve second with 1 attack, got %+v", threats[1])
+	}
+}
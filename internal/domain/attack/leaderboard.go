@@ -0,0 +1,57 @@
+package attack
+
+import (
+	"sort"
+
+	"torn_rw_stats/internal/app"
+)
+
+// BuildMemberLeaderboard aggregates the attacks made by each of our members into
+// leaderboard entries sorted by respect gained (highest first). Ties are broken by
+// attacks made and then by name. Members with no attacks made are not included.
+//
+// Pure function: No I/O operations, fully testable with direct inputs.
+func BuildMemberLeaderboard(records []app.AttackRecord, ourFactionID int) []app.MemberLeaderboardEntry {
+	entries := make(map[int]*app.MemberLeaderboardEntry)
+	fairFightTotals := make(map[int]float64)
+
+	for _, record := range records {
+		if record.AttackerFactionID == nil || *record.AttackerFactionID != ourFactionID {
+			continue
+		}
+
+		entry, exists := entries[record.AttackerID]
+		if !exists {
+			entry = &app.MemberLeaderboardEntry{
+				MemberID:   record.AttackerID,
+				MemberName: record.AttackerName,
+			}
+			entries[record.AttackerID] = entry
+		}
+
+		entry.AttacksMade++
+		entry.RespectGained += record.RespectGain
+		fairFightTotals[record.AttackerID] += record.ModifierFairFight
+		if IsSuccessfulAttack(record.Result) {
+			entry.AttacksWon++
+		}
+	}
+
+	leaderboard := make([]app.MemberLeaderboardEntry, 0, len(entries))
+	for memberID, entry := range entries {
+		entry.AvgFairFight = fairFightTotals[memberID] / float64(entry.AttacksMade)
+		leaderboard = append(leaderboard, *entry)
+	}
+
+	sort.Slice(leaderboard, func(i, j int) bool {
+		if leaderboard[i].RespectGained != leaderboard[j].RespectGained {
+			return leaderboard[i].RespectGained > leaderboard[j].RespectGained
+		}
+		if leaderboard[i].AttacksMade != leaderboard[j].AttacksMade {
+			return leaderboard[i].AttacksMade > leaderboard[j].AttacksMade
+		}
+		return leaderboard[i].MemberName < leaderboard[j].MemberName
+	})
+
+	return leaderboard
+}
@@ -0,0 +1,78 @@
+package attack
+
+import (
+	"math"
+	"testing"
+
+	"torn_rw_stats/internal/app"
+)
+
+func TestBuildMemberLeaderboard(t *testing.T) {
+	ourFactionID := 1001
+	enemyFactionID := 2002
+
+	records := []app.AttackRecord{
+		{AttackerID: 1, AttackerName: "Alice", AttackerFactionID: &ourFactionID, Result: "Hospitalized", RespectGain: 3.5, ModifierFairFight: 2.0},
+		{AttackerID: 1, AttackerName: "Alice", AttackerFactionID: &ourFactionID, Result: "Lost", RespectGain: 0, ModifierFairFight: 1.0},
+		{AttackerID: 2, AttackerName: "Bob", AttackerFactionID: &ourFactionID, Result: "Mugged", RespectGain: 5.0, ModifierFairFight: 3.0},
+		{AttackerID: 3, AttackerName: "Carol", AttackerFactionID: &ourFactionID, Result: "Left", RespectGain: 1.25, ModifierFairFight: 1.5},
+		// Incoming attack must not count towards any of our members
+		{AttackerID: 9, AttackerName: "Enemy", AttackerFactionID: &enemyFactionID, DefenderID: 1, Result: "Hospitalized", RespectGain: 10.0, ModifierFairFight: 3.0},
+		// Stealthed attacker without a faction
+		{AttackerID: 10, AttackerName: "", AttackerFactionID: nil, Result: "Hospitalized", RespectGain: 2.0},
+	}
+
+	leaderboard := BuildMemberLeaderboard(records, ourFactionID)
+
+	expectedOrder := []string{"Bob", "Alice", "Carol"}
+	if len(leaderboard) != len(expectedOrder) {
+		t.Fatalf("expected %d entries, got %d: %+v", len(expectedOrder), len(leaderboard), leaderboard)
+	}
+	for i, name := range expectedOrder {
+		if leaderboard[i].MemberName != name {
+			t.Errorf("position %d: expected %s, got %s", i, name, leaderboard[i].MemberName)
+		}
+	}
+
+	alice := leaderboard[1]
+	if alice.AttacksMade != 2 {
+		t.Errorf("expected Alice to have 2 attacks made, got %d", alice.AttacksMade)
+	}
+	if alice.AttacksWon != 1 {
+		t.Errorf("expected Alice to have 1 attack won, got %d", alice.AttacksWon)
+	}
+	if math.Abs(alice.RespectGained-3.5) > 1e-9 {
+		t.Errorf("expected Alice respect 3.5, got %f", alice.RespectGained)
+	}
+	if math.Abs(alice.AvgFairFight-1.5) > 1e-9 {
+		t.Errorf("expected Alice average fair fight 1.5, got %f", alice.AvgFairFight)
+	}
+}
+
+func TestBuildMemberLeaderboard_TieBreaks(t *testing.T) {
+	ourFactionID := 1001
+
+	records := []app.AttackRecord{
+		{AttackerID: 1, AttackerName: "Zed", AttackerFactionID: &ourFactionID, Result: "Lost"},
+		{AttackerID: 2, AttackerName: "Amy", AttackerFactionID: &ourFactionID, Result: "Lost"},
+		{AttackerID: 3, AttackerName: "Max", AttackerFactionID: &ourFactionID, Result: "Lost"},
+		{AttackerID: 3, AttackerName: "Max", AttackerFactionID: &ourFactionID, Result: "Lost"},
+	}
+
+	leaderboard := BuildMemberLeaderboard(records, ourFactionID)
+
+	expectedOrder := []string{"Max", "Amy", "Zed"}
+	for i, name := range expectedOrder {
+		if leaderboard[i].MemberName != name {
+			t.Errorf("position %d: expected %s, got %s", i, name, leaderboard[i].MemberName)
+		}
+	}
+}
+
+func TestBuildMemberLeaderboard_NoAttacks(t *testing.T) {
+	leaderboard := BuildMemberLeaderboard(nil, 1001)
+
+	if len(leaderboard) != 0 {
+		t.Errorf("expected empty leaderboard, got %+v", leaderboard)
+	}
+}
@@ -1,17 +1,148 @@
 package attack
 
-// IsSuccessfulAttack determines if an attack result represents a successful attack.
-// Successful attacks include hospitalization, mugging, or the attacker leaving.
+import (
+	"sort"
+
+	"torn_rw_stats/internal/app"
+)
+
+// AttackOutcome classifies an attack result from the attacker's side
+type AttackOutcome int
+
+const (
+	// OutcomeNeutral is neither a win nor a loss, e.g. an assist; unknown results are also neutral
+	OutcomeNeutral AttackOutcome = iota
+
+	// OutcomeWin means the attacker beat the defender
+	OutcomeWin
+
+	// OutcomeLoss means the attacker failed, including stalemates and escapes
+	OutcomeLoss
+)
+
+// String returns the string representation of an attack outcome
+func (o AttackOutcome) String() string {
+	switch o {
+	case OutcomeWin:
+		return "Win"
+	case OutcomeLoss:
+		return "Loss"
+	default:
+		return "Neutral"
+	}
+}
+
+// attackOutcomes maps every known Torn attack result to its outcome for the attacker
+var attackOutcomes = OutcomeMap{
+	"Attacked":     OutcomeWin,
+	"Hospitalized": OutcomeWin,
+	"Mugged":       OutcomeWin,
+	"Left":         OutcomeWin,
+	"Arrested":     OutcomeWin,
+	"Looted":       OutcomeWin,
+	"Special":      OutcomeWin,
+	"Bounty":       OutcomeWin,
+	"Lost":         OutcomeLoss,
+	"Stalemate":    OutcomeLoss,
+	"Escape":       OutcomeLoss,
+	"Timeout":      OutcomeLoss,
+	"Assist":       OutcomeNeutral,
+	"Interrupted":  OutcomeNeutral,
+	"None":         OutcomeNeutral,
+}
+
+// ClassifyAttackOutcome maps an attack result to a win, loss or neutral outcome for
+// the attacker. Unknown results are neutral; use IsKnownAttackResult to detect them.
 //
 // Pure function: No I/O operations, fully testable with direct inputs.
-func IsSuccessfulAttack(result string) bool {
-	successfulResults := []string{"Hospitalized", "Mugged", "Left"}
-	for _, successResult := range successfulResults {
-		if result == successResult {
-			return true
+func ClassifyAttackOutcome(result string) AttackOutcome {
+	return attackOutcomes[result]
+}
+
+// OutcomeMap maps attack results to their outcome for the attacker; results missing
+// from the map are neutral
+type OutcomeMap map[string]AttackOutcome
+
+// NewOutcomeMap returns the default classification with the win and/or loss results
+// overridden. A non-empty winResults replaces the default wins, which become neutral
+// unless listed in lossResults, and likewise for lossResults.
+//
+// Pure function: No I/O operations, fully testable with direct inputs.
+func NewOutcomeMap(winResults, lossResults []string) OutcomeMap {
+	outcomes := make(OutcomeMap, len(attackOutcomes))
+	for result, outcome := range attackOutcomes {
+		if (outcome == OutcomeWin && len(winResults) > 0) || (outcome == OutcomeLoss && len(lossResults) > 0) {
+			outcome = OutcomeNeutral
 		}
+		outcomes[result] = outcome
 	}
-	return false
+
+	for _, result := range winResults {
+		outcomes[result] = OutcomeWin
+	}
+	for _, result := range lossResults {
+		outcomes[result] = OutcomeLoss
+	}
+
+	return outcomes
+}
+
+// Classify maps an attack result to its outcome for the attacker.
+//
+// Pure function: No I/O operations, fully testable with direct inputs.
+func (m OutcomeMap) Classify(result string) AttackOutcome {
+	return m[result]
+}
+
+// IsKnownAttackResult reports whether result is a Torn attack result we classify.
+//
+// Pure function: No I/O operations, fully testable with direct inputs.
+func IsKnownAttackResult(result string) bool {
+	_, known := attackOutcomes[result]
+	return known
+}
+
+// FindUnknownAttackResults returns the distinct unclassified results among attacks, sorted.
+//
+// Pure function: No I/O operations, fully testable with direct inputs.
+func FindUnknownAttackResults(attacks []app.Attack) []string {
+	return FindUnknownAttackResultsWithOutcomes(attacks, attackOutcomes)
+}
+
+// FindUnknownAttackResultsWithOutcomes returns the distinct results among attacks
+// missing from outcomes, sorted.
+//
+// Pure function: No I/O operations, fully testable with direct inputs.
+func FindUnknownAttackResultsWithOutcomes(attacks []app.Attack, outcomes OutcomeMap) []string {
+	seen := make(map[string]bool)
+	var unknown []string
+
+	for _, attack := range attacks {
+		if _, known := outcomes[attack.Result]; known || seen[attack.Result] {
+			continue
+		}
+		seen[attack.Result] = true
+		unknown = append(unknown, attack.Result)
+	}
+
+	sort.Strings(unknown)
+	return unknown
+}
+
+// IsSuccessfulAttack determines if an attack result represents a successful attack,
+// i.e. one classified as a win for the attacker.
+//
+// Pure function: No I/O operations, fully testable with direct inputs.
+func IsSuccessfulAttack(result string) bool {
+	return ClassifyAttackOutcome(result) == OutcomeWin
+}
+
+// IsDrawnAttack determines if an attack result represents a draw from the attacker's side.
+// Draws are stalemates and escapes, which count as losses for a strict win rate.
+//
+// Pure function: No I/O operations, fully testable with direct inputs.
+func IsDrawnAttack(result string) bool {
+	return result == "Stalemate" || result == "Escape"
 }
 
 // IsSuccessfulDefense determines if an attack result represents a successful defense.
@@ -0,0 +1,116 @@
+package attack
+
+import (
+	"reflect"
+	"testing"
+
+	"torn_rw_stats/internal/app"
+)
+
+func TestClassifyAttackOutcome(t *testing.T) {
+	tests := []struct {
+		result   string
+		expected AttackOutcome
+	}{
+		{"Attacked", OutcomeWin},
+		{"Hospitalized", OutcomeWin},
+		{"Mugged", OutcomeWin},
+		{"Left", OutcomeWin},
+		{"Arrested", OutcomeWin},
+		{"Looted", OutcomeWin},
+		{"Special", OutcomeWin},
+		{"Bounty", OutcomeWin},
+		{"Lost", OutcomeLoss},
+		{"Stalemate", OutcomeLoss},
+		{"Escape", OutcomeLoss},
+		{"Timeout", OutcomeLoss},
+		{"Assist", OutcomeNeutral},
+		{"Interrupted", OutcomeNeutral},
+		{"None", OutcomeNeutral},
+		{"SomethingNew", OutcomeNeutral},
+		{"", OutcomeNeutral},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.result, func(t *testing.T) {
+			if got := ClassifyAttackOutcome(tt.result); got != tt.expected {
+				t.Errorf("ClassifyAttackOutcome(%q) = %s, expected %s", tt.result, got, tt.expected)
+			}
+			if got := IsSuccessfulAttack(tt.result); got != (tt.expected == OutcomeWin) {
+				t.Errorf("IsSuccessfulAttack(%q) = %v, expected %v", tt.result, got, tt.expected == OutcomeWin)
+			}
+		})
+	}
+
+	// Every known result must be covered above
+	if len(attackOutcomes) != len(tests)-2 {
+		t.Errorf("expected %d known results to be tested, classifier knows %d", len(tests)-2, len(attackOutcomes))
+	}
+}
+
+func TestFindUnknownAttackResults(t *testing.T) {
+	attacks := []app.Attack{
+		{Result: "Hospitalized"},
+		{Result: "Zapped"},
+		{Result: "Assist"},
+		{Result: "Banished"},
+		{Result: "Zapped"},
+	}
+
+	unknown := FindUnknownAttackResults(attacks)
+	expected := []string{"Banished", "Zapped"}
+	if !reflect.DeepEqual(unknown, expected) {
+		t.Errorf("expected %v, got %v", expected, unknown)
+	}
+
+	if unknown := FindUnknownAttackResults([]app.Attack{{Result: "Lost"}}); len(unknown) != 0 {
+		t.Errorf("expected no unknown results, got %v", unknown)
+	}
+}
+
+func TestNewOutcomeMap(t *testing.T) {
+	tests := []struct {
+		name        string
+		winResults  []string
+		lossResults []string
+		expected    map[string]AttackOutcome
+	}{
+		{
+			name: "defaults when unset",
+			expected: map[string]AttackOutcome{
+				"Mugged": OutcomeWin, "Lost": OutcomeLoss, "Assist": OutcomeNeutral, "SomethingNew": OutcomeNeutral,
+			},
+		},
+		{
+			name:       "win override replaces default wins only",
+			winResults: []string{"Hospitalized", "Looted"},
+			expected: map[string]AttackOutcome{
+				"Hospitalized": OutcomeWin, "Looted": OutcomeWin, "Mugged": OutcomeNeutral, "Lost": OutcomeLoss,
+			},
+		},
+		{
+			name:        "loss override can claim a default win",
+			lossResults: []string{"Lost", "Mugged"},
+			expected: map[string]AttackOutcome{
+				"Mugged": OutcomeLoss, "Lost": OutcomeLoss, "Timeout": OutcomeNeutral, "Hospitalized": OutcomeWin,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outcomes := NewOutcomeMap(tt.winResults, tt.lossResults)
+			for result, expected := range tt.expected {
+				if got := outcomes.Classify(result); got != expected {
+					t.Errorf("Classify(%q) = %s, expected %s", result, got, expected)
+				}
+			}
+		})
+	}
+
+	// Overrides never change the package defaults
+	NewOutcomeMap([]string{"Looted"}, nil)
+	if got := ClassifyAttackOutcome("Mugged"); got != OutcomeWin {
+		t.Errorf("expected default classification to stay unchanged, Mugged = %s", got)
+	}
+}
@@ -0,0 +1,42 @@
+package attack
+
+import (
+	"time"
+
+	"torn_rw_stats/internal/app"
+)
+
+// DefaultAttackPaceWindow is the rolling window our attack pace is measured over when
+// none is configured
+const DefaultAttackPaceWindow = time.Hour
+
+// AttackPace returns our outgoing attacks per hour among records started in the
+// window [from, to). Incoming records are ignored. An empty or inverted window has no
+// pace and returns zero.
+//
+// Pure function: No I/O operations, fully testable with direct inputs.
+func AttackPace(records []app.AttackRecord, from, to time.Time) float64 {
+	window := to.Sub(from)
+	if window <= 0 {
+		return 0
+	}
+
+	return float64(OutgoingAttacks(records, from, to)) / window.Hours()
+}
+
+// OutgoingAttacks counts our outgoing records started in the window [from, to)
+//
+// Pure function: No I/O operations, fully testable with direct inputs.
+func OutgoingAttacks(records []app.AttackRecord, from, to time.Time) int {
+	count := 0
+	for _, record := range records {
+		if record.Direction != "Outgoing" {
+			continue
+		}
+		if record.Started.Before(from) || !record.Started.Before(to) {
+			continue
+		}
+		count++
+	}
+	return count
+}
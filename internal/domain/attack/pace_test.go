@@ -0,0 +1,41 @@
+package attack
+
+import (
+	"testing"
+	"time"
+
+	"torn_rw_stats/internal/app"
+)
+
+func TestAttackPace(t *testing.T) {
+	to := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	from := to.Add(-30 * time.Minute)
+
+	records := []app.AttackRecord{
+		{AttackID: 1, Started: to.Add(-10 * time.Minute), Direction: "Outgoing"},
+		{AttackID: 2, Started: to.Add(-20 * time.Minute), Direction: "Outgoing"},
+		{AttackID: 3, Started: to.Add(-5 * time.Minute), Direction: "Incoming"},
+		{AttackID: 4, Started: to.Add(-5 * time.Minute), Direction: "Unknown"},
+		{AttackID: 5, Started: to.Add(-45 * time.Minute), Direction: "Outgoing"},
+		{AttackID: 6, Started: to, Direction: "Outgoing"},
+	}
+
+	tests := []struct {
+		name     string
+		from     time.Time
+		to       time.Time
+		expected float64
+	}{
+		{"two outgoing attacks in half an hour", from, to, 4},
+		{"wider window includes the older attack", to.Add(-time.Hour), to, 3},
+		{"empty window has no pace", to, to, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if pace := AttackPace(records, tt.from, tt.to); pace != tt.expected {
+				t.Errorf("expected pace %v, got %v", tt.expected, pace)
+			}
+		})
+	}
+}
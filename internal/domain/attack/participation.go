@@ -0,0 +1,91 @@
+package attack
+
+import (
+	"sort"
+
+	"torn_rw_stats/internal/app"
+)
+
+// DefaultParticipationDeviation is how far in percent a member's outgoing attack count
+// may stray from the faction mean before they are flagged
+const DefaultParticipationDeviation = 50
+
+// FlagParticipation counts the outgoing attacks made by each of our members and flags
+// those more than thresholdPercent above (High) or below (Low) the faction mean.
+// Members in roster (member ID to name) who made no attacks count towards the mean,
+// and during an active war they are always flagged Low. A non-positive threshold uses
+// DefaultParticipationDeviation. Flags are sorted by deviation, highest first.
+//
+// Pure function: No I/O operations, fully testable with direct inputs.
+func FlagParticipation(records []app.AttackRecord, ourFactionID int, roster map[int]string, thresholdPercent float64, activeWar bool) []app.ParticipationFlag {
+	if thresholdPercent <= 0 {
+		thresholdPercent = DefaultParticipationDeviation
+	}
+
+	counts := make(map[int]int, len(roster))
+	names := make(map[int]string, len(roster))
+	for memberID, name := range roster {
+		counts[memberID] = 0
+		names[memberID] = name
+	}
+
+	for _, record := range records {
+		if record.AttackerFactionID == nil || *record.AttackerFactionID != ourFactionID {
+			continue
+		}
+		counts[record.AttackerID]++
+		if names[record.AttackerID] == "" {
+			names[record.AttackerID] = record.AttackerName
+		}
+	}
+
+	if len(counts) == 0 {
+		return nil
+	}
+
+	total := 0
+	for _, count := range counts {
+		total += count
+	}
+	mean := float64(total) / float64(len(counts))
+
+	var flags []app.ParticipationFlag
+	for memberID, count := range counts {
+		deviation := -100.0
+		if mean > 0 {
+			deviation = (float64(count) - mean) / mean * 100
+		}
+
+		flag := ""
+		switch {
+		case count == 0 && activeWar:
+			flag = app.ParticipationLow
+		case mean == 0:
+			// Nobody has attacked yet, so there is nothing to compare against
+		case deviation > thresholdPercent:
+			flag = app.ParticipationHigh
+		case deviation < -thresholdPercent:
+			flag = app.ParticipationLow
+		}
+		if flag == "" {
+			continue
+		}
+
+		flags = append(flags, app.ParticipationFlag{
+			MemberID:         memberID,
+			MemberName:       names[memberID],
+			Attacks:          count,
+			DeviationPercent: deviation,
+			Flag:             flag,
+		})
+	}
+
+	sort.Slice(flags, func(i, j int) bool {
+		if flags[i].DeviationPercent != flags[j].DeviationPercent {
+			return flags[i].DeviationPercent > flags[j].DeviationPercent
+		}
+		return flags[i].MemberName < flags[j].MemberName
+	})
+
+	return flags
+}
@@ -0,0 +1,94 @@
+package attack
+
+import (
+	"math"
+	"testing"
+
+	"torn_rw_stats/internal/app"
+)
+
+func TestFlagParticipation(t *testing.T) {
+	ourFactionID := 1001
+	enemyFactionID := 2002
+
+	outgoing := func(memberID int, name string, count int) []app.AttackRecord {
+		records := make([]app.AttackRecord, count)
+		for i := range records {
+			records[i] = app.AttackRecord{AttackerID: memberID, AttackerName: name, AttackerFactionID: &ourFactionID}
+		}
+		return records
+	}
+
+	// Alice hoards hits, Frank barely attacks and Eve has not attacked at all
+	var records []app.AttackRecord
+	records = append(records, outgoing(1, "Alice", 10)...)
+	records = append(records, outgoing(2, "Bob", 4)...)
+	records = append(records, outgoing(3, "Carol", 3)...)
+	records = append(records, outgoing(4, "Dave", 3)...)
+	records = append(records, outgoing(6, "Frank", 1)...)
+	// Incoming attacks must not count towards any of our members
+	records = append(records, app.AttackRecord{AttackerID: 9, AttackerName: "Enemy", AttackerFactionID: &enemyFactionID, DefenderID: 2})
+
+	roster := map[int]string{1: "Alice", 2: "Bob", 3: "Carol", 4: "Dave", 5: "Eve", 6: "Frank"}
+
+	tests := []struct {
+		name      string
+		threshold float64
+		activeWar bool
+		expected  map[string]string
+	}{
+		{
+			name:      "skewed distribution at 50 percent",
+			threshold: 50,
+			activeWar: true,
+			expected:  map[string]string{"Alice": app.ParticipationHigh, "Frank": app.ParticipationLow, "Eve": app.ParticipationLow},
+		},
+		{
+			name:      "members without attacks are always low during an active war",
+			threshold: 200,
+			activeWar: true,
+			expected:  map[string]string{"Eve": app.ParticipationLow},
+		},
+		{
+			name:      "members without attacks follow the threshold after the war",
+			threshold: 200,
+			activeWar: false,
+			expected:  map[string]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flags := FlagParticipation(records, ourFactionID, roster, tt.threshold, tt.activeWar)
+
+			if len(flags) != len(tt.expected) {
+				t.Fatalf("expected %d flags, got %d: %+v", len(tt.expected), len(flags), flags)
+			}
+			for _, flag := range flags {
+				if tt.expected[flag.MemberName] != flag.Flag {
+					t.Errorf("expected %s flagged %q, got %q", flag.MemberName, tt.expected[flag.MemberName], flag.Flag)
+				}
+			}
+		})
+	}
+
+	flags := FlagParticipation(records, ourFactionID, roster, 50, true)
+	// Mean is 21 attacks over 6 members = 3.5
+	if flags[0].MemberName != "Alice" || flags[0].Attacks != 10 || math.Abs(flags[0].DeviationPercent-185.71) > 0.01 {
+		t.Errorf("expected Alice first with 10 attacks at +185.71%%, got %+v", flags[0])
+	}
+	if last := flags[len(flags)-1]; last.MemberName != "Eve" || last.DeviationPercent != -100 {
+		t.Errorf("expected Eve last at -100%%, got %+v", last)
+	}
+}
+
+func TestFlagParticipation_NoAttacks(t *testing.T) {
+	roster := map[int]string{1: "Alice", 2: "Bob"}
+
+	if flags := FlagParticipation(nil, 1001, roster, 50, false); len(flags) != 0 {
+		t.Errorf("expected no flags before anyone attacks outside a war, got %+v", flags)
+	}
+	if flags := FlagParticipation(nil, 1001, roster, 50, true); len(flags) != 2 {
+		t.Errorf("expected everyone flagged low during an active war, got %+v", flags)
+	}
+}
@@ -0,0 +1,54 @@
+package attack
+
+import (
+	"time"
+
+	"torn_rw_stats/internal/app"
+)
+
+// DefaultRespectTrendWindow is the respect trend bucket size used when none is configured
+const DefaultRespectTrendWindow = time.Hour
+
+// BuildRespectTrend buckets our outgoing attacks into consecutive windows starting at
+// warStart and reports the average respect gained per hit in each. Every window up to
+// and including the one containing end is returned, so windows without attacks appear
+// with zero values. Attacks started outside [warStart, end] are ignored. A non-positive
+// window falls back to DefaultRespectTrendWindow.
+//
+// Pure function: No I/O operations, fully testable with direct inputs.
+func BuildRespectTrend(records []app.AttackRecord, ourFactionID int, warStart, end time.Time, window time.Duration) []app.RespectTrendWindow {
+	if window <= 0 {
+		window = DefaultRespectTrendWindow
+	}
+	if end.Before(warStart) {
+		return []app.RespectTrendWindow{}
+	}
+
+	windowCount := int(end.Sub(warStart)/window) + 1
+	trend := make([]app.RespectTrendWindow, windowCount)
+	respectTotals := make([]float64, windowCount)
+	for i := range trend {
+		trend[i].WindowStart = warStart.Add(time.Duration(i) * window)
+	}
+
+	for _, record := range records {
+		if record.AttackerFactionID == nil || *record.AttackerFactionID != ourFactionID {
+			continue
+		}
+		if record.Started.Before(warStart) || record.Started.After(end) {
+			continue
+		}
+
+		index := int(record.Started.Sub(warStart) / window)
+		trend[index].AttackCount++
+		respectTotals[index] += record.RespectGain
+	}
+
+	for i := range trend {
+		if trend[i].AttackCount > 0 {
+			trend[i].AvgRespect = respectTotals[i] / float64(trend[i].AttackCount)
+		}
+	}
+
+	return trend
+}
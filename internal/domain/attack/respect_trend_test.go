@@ -0,0 +1,78 @@
+package attack
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"torn_rw_stats/internal/app"
+)
+
+func TestBuildRespectTrend_UnevenTwoHourWar(t *testing.T) {
+	ourFactionID := 1001
+	enemyFactionID := 2002
+	warStart := time.Date(2025, 1, 7, 12, 0, 0, 0, time.UTC)
+	warEnd := warStart.Add(2 * time.Hour)
+
+	records := []app.AttackRecord{
+		// First hour: three hits
+		{Started: warStart.Add(5 * time.Minute), AttackerFactionID: &ourFactionID, RespectGain: 2.0},
+		{Started: warStart.Add(20 * time.Minute), AttackerFactionID: &ourFactionID, RespectGain: 4.0},
+		{Started: warStart.Add(59 * time.Minute), AttackerFactionID: &ourFactionID, RespectGain: 6.0},
+		// Incoming hit in the second hour must not count
+		{Started: warStart.Add(70 * time.Minute), AttackerFactionID: &enemyFactionID, RespectGain: 50.0},
+		// Exactly at war end lands in a trailing window
+		{Started: warEnd, AttackerFactionID: &ourFactionID, RespectGain: 1.5},
+		// Outside the war window
+		{Started: warStart.Add(-time.Minute), AttackerFactionID: &ourFactionID, RespectGain: 99.0},
+	}
+
+	trend := BuildRespectTrend(records, ourFactionID, warStart, warEnd, time.Hour)
+
+	expected := []app.RespectTrendWindow{
+		{WindowStart: warStart, AvgRespect: 4.0, AttackCount: 3},
+		{WindowStart: warStart.Add(time.Hour), AvgRespect: 0, AttackCount: 0},
+		{WindowStart: warStart.Add(2 * time.Hour), AvgRespect: 1.5, AttackCount: 1},
+	}
+
+	if len(trend) != len(expected) {
+		t.Fatalf("expected %d windows, got %d: %+v", len(expected), len(trend), trend)
+	}
+	for i, want := range expected {
+		got := trend[i]
+		if !got.WindowStart.Equal(want.WindowStart) {
+			t.Errorf("window %d: expected start %v, got %v", i, want.WindowStart, got.WindowStart)
+		}
+		if got.AttackCount != want.AttackCount {
+			t.Errorf("window %d: expected %d attacks, got %d", i, want.AttackCount, got.AttackCount)
+		}
+		if math.Abs(got.AvgRespect-want.AvgRespect) > 1e-9 {
+			t.Errorf("window %d: expected avg respect %f, got %f", i, want.AvgRespect, got.AvgRespect)
+		}
+	}
+}
+
+func TestBuildRespectTrend_WindowSizes(t *testing.T) {
+	warStart := time.Date(2025, 1, 7, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		end      time.Time
+		window   time.Duration
+		expected int
+	}{
+		{"thirty minute windows", warStart.Add(90 * time.Minute), 30 * time.Minute, 4},
+		{"zero window uses default", warStart.Add(90 * time.Minute), 0, 2},
+		{"end at start", warStart, time.Hour, 1},
+		{"end before start", warStart.Add(-time.Hour), time.Hour, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trend := BuildRespectTrend(nil, 1001, warStart, tt.end, tt.window)
+			if len(trend) != tt.expected {
+				t.Errorf("expected %d windows, got %d", tt.expected, len(trend))
+			}
+		})
+	}
+}
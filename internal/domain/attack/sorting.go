@@ -17,3 +17,21 @@ func SortAttacksChronologically(attacks []app.Attack) []app.Attack {
 
 	return sorted
 }
+
+// MergeAttacks combines attack batches whose time ranges may overlap, keeping the first
+// copy of each attack ID, and returns them sorted by timestamp (oldest first)
+// Pure function: Does not modify input slices, returns new sorted slice
+func MergeAttacks(batches ...[]app.Attack) []app.Attack {
+	seen := make(map[int64]bool)
+	var merged []app.Attack
+	for _, batch := range batches {
+		for _, attack := range batch {
+			if seen[attack.ID] {
+				continue
+			}
+			seen[attack.ID] = true
+			merged = append(merged, attack)
+		}
+	}
+	return SortAttacksChronologically(merged)
+}
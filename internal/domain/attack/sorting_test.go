@@ -42,3 +42,19 @@ func TestSortAttacksChronologically_SingleItem(t *testing.T) {
 		t.Errorf("Single item not handled correctly")
 	}
 }
+
+func TestMergeAttacks(t *testing.T) {
+	older := []app.Attack{{ID: 1, Started: 100}, {ID: 2, Started: 200}}
+	newer := []app.Attack{{ID: 3, Started: 300}, {ID: 2, Started: 200}}
+
+	merged := MergeAttacks(newer, older)
+
+	if len(merged) != 3 {
+		t.Fatalf("Expected 3 attacks with the overlap removed, got %d", len(merged))
+	}
+	for i, expectedID := range []int64{1, 2, 3} {
+		if merged[i].ID != expectedID {
+			t.Errorf("Expected attack %d at position %d, got %d", expectedID, i, merged[i].ID)
+		}
+	}
+}
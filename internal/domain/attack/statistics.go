@@ -7,9 +7,13 @@ import "torn_rw_stats/internal/app"
 type AttackStatistics struct {
 	TotalAttacks  int
 	AttacksWon    int
-	AttacksLost   int
+	AttacksLost   int // Neutral results (e.g. assists) count as neither won nor lost
+	AttacksDrawn  int // Our attacks ending in a stalemate or escape (also counted as lost)
 	RespectGained float64
 	RespectLost   float64
+
+	DefensiveAttacks   int // Attacks made against us
+	SuccessfulDefenses int // Attacks against us we defended (also counted as won)
 }
 
 // CalculateAttackStatistics computes comprehensive attack statistics for a faction.
@@ -18,11 +22,19 @@ type AttackStatistics struct {
 //
 // Pure function: No I/O operations, fully testable with direct inputs.
 func CalculateAttackStatistics(attacks []app.Attack, ourFactionID int) AttackStatistics {
+	return CalculateAttackStatisticsWithOutcomes(attacks, ourFactionID, attackOutcomes)
+}
+
+// CalculateAttackStatisticsWithOutcomes computes attack statistics like
+// CalculateAttackStatistics, classifying our attacks' results with the given outcomes.
+//
+// Pure function: No I/O operations, fully testable with direct inputs.
+func CalculateAttackStatisticsWithOutcomes(attacks []app.Attack, ourFactionID int, outcomes OutcomeMap) AttackStatistics {
 	var stats AttackStatistics
 
 	for _, attack := range attacks {
 		if IsOurAttack(attack, ourFactionID) {
-			stats = processOffensiveAttack(stats, attack)
+			stats = processOffensiveAttack(stats, attack, outcomes)
 		} else if IsAttackAgainstUs(attack, ourFactionID) {
 			stats = processDefensiveAttack(stats, attack)
 		}
@@ -31,6 +43,125 @@ func CalculateAttackStatistics(attacks []app.Attack, ourFactionID int) AttackSta
 	return stats
 }
 
+// CalculateAverageRespectPerWin averages the respect gained by our outgoing attacks
+// that the outcome map classifies as wins. Returns 0 when we have no successful attacks.
+//
+// Pure function: No I/O operations, fully testable with direct inputs.
+func CalculateAverageRespectPerWin(attacks []app.Attack, ourFactionID int, outcomes OutcomeMap) float64 {
+	total := 0.0
+	count := 0
+	for _, attack := range attacks {
+		if !IsOurAttack(attack, ourFactionID) || outcomes.Classify(attack.Result) != OutcomeWin {
+			continue
+		}
+		total += attack.RespectGain
+		count++
+	}
+
+	if count == 0 {
+		return 0
+	}
+	return total / float64(count)
+}
+
+// CalculateAverageModifiers averages each attack modifier across our outgoing attacks.
+// Attacks reporting no modifiers count as zero, so every outgoing attack is in the denominator.
+//
+// Pure function: No I/O operations, fully testable with direct inputs.
+func CalculateAverageModifiers(attacks []app.Attack, ourFactionID int) app.AttackModifiers {
+	var totals app.AttackModifiers
+	count := 0
+
+	for _, attack := range attacks {
+		if !IsOurAttack(attack, ourFactionID) {
+			continue
+		}
+		count++
+		totals.FairFight += attack.Modifiers.FairFight
+		totals.War += attack.Modifiers.War
+		totals.Retaliation += attack.Modifiers.Retaliation
+		totals.Group += attack.Modifiers.Group
+		totals.Overseas += attack.Modifiers.Overseas
+		totals.Chain += attack.Modifiers.Chain
+		totals.Warlord += attack.Modifiers.Warlord
+	}
+
+	if count == 0 {
+		return app.AttackModifiers{}
+	}
+
+	n := float64(count)
+	return app.AttackModifiers{
+		FairFight:   totals.FairFight / n,
+		War:         totals.War / n,
+		Retaliation: totals.Retaliation / n,
+		Group:       totals.Group / n,
+		Overseas:    totals.Overseas / n,
+		Chain:       totals.Chain / n,
+		Warlord:     totals.Warlord / n,
+	}
+}
+
+// CountOverseasAttacks counts our outgoing attacks that received an overseas bonus,
+// i.e. whose overseas modifier is neither 1 (no bonus) nor 0 (modifiers not reported).
+//
+// Pure function: No I/O operations, fully testable with direct inputs.
+func CountOverseasAttacks(attacks []app.Attack, ourFactionID int) int {
+	count := 0
+	for _, attack := range attacks {
+		overseas := attack.Modifiers.Overseas
+		if IsOurAttack(attack, ourFactionID) && overseas != 0 && overseas != 1.0 {
+			count++
+		}
+	}
+	return count
+}
+
+// CountInterruptedAttacks counts our outgoing attacks that were interrupted.
+//
+// Pure function: No I/O operations, fully testable with direct inputs.
+func CountInterruptedAttacks(attacks []app.Attack, ourFactionID int) int {
+	count := 0
+	for _, attack := range attacks {
+		if IsOurAttack(attack, ourFactionID) && attack.IsInterrupted {
+			count++
+		}
+	}
+	return count
+}
+
+// CountOutgoingOutcomes counts our outgoing attacks by their raw result, e.g.
+// "Hospitalized" or "Stalemate".
+//
+// Pure function: No I/O operations, fully testable with direct inputs.
+func CountOutgoingOutcomes(attacks []app.Attack, ourFactionID int) map[string]int {
+	counts := make(map[string]int)
+	for _, attack := range attacks {
+		if IsOurAttack(attack, ourFactionID) {
+			counts[attack.Result]++
+		}
+	}
+	return counts
+}
+
+// CountAttacksByDirection counts outgoing, incoming and unknown-direction attacks
+// relative to our faction, matching the Direction column of the records sheet.
+//
+// Pure function: No I/O operations, fully testable with direct inputs.
+func CountAttacksByDirection(attacks []app.Attack, ourFactionID int) (outgoing, incoming, unknown int) {
+	for _, attack := range attacks {
+		switch AttackDirection(attack, ourFactionID) {
+		case "Outgoing":
+			outgoing++
+		case "Incoming":
+			incoming++
+		default:
+			unknown++
+		}
+	}
+	return outgoing, incoming, unknown
+}
+
 // IsOurAttack determines if an attack was performed by our faction
 func IsOurAttack(attack app.Attack, ourFactionID int) bool {
 	return attack.Attacker.Faction != nil && attack.Attacker.Faction.ID == ourFactionID
@@ -42,15 +173,19 @@ func IsAttackAgainstUs(attack app.Attack, ourFactionID int) bool {
 }
 
 // processOffensiveAttack processes statistics for an attack we performed
-func processOffensiveAttack(stats AttackStatistics, attack app.Attack) AttackStatistics {
+func processOffensiveAttack(stats AttackStatistics, attack app.Attack, outcomes OutcomeMap) AttackStatistics {
 	stats.TotalAttacks++
 	stats.RespectGained += attack.RespectGain
 	stats.RespectLost += attack.RespectLoss
 
-	if IsSuccessfulAttack(attack.Result) {
+	switch outcomes.Classify(attack.Result) {
+	case OutcomeWin:
 		stats.AttacksWon++
-	} else {
+	case OutcomeLoss:
 		stats.AttacksLost++
+		if IsDrawnAttack(attack.Result) {
+			stats.AttacksDrawn++
+		}
 	}
 
 	return stats
@@ -59,6 +194,7 @@ func processOffensiveAttack(stats AttackStatistics, attack app.Attack) AttackSta
 // processDefensiveAttack processes statistics for an attack against us
 func processDefensiveAttack(stats AttackStatistics, attack app.Attack) AttackStatistics {
 	stats.TotalAttacks++
+	stats.DefensiveAttacks++
 
 	// For defensive stats, respect gain/loss is inverted from attacker's perspective
 	stats.RespectLost += attack.RespectGain
@@ -67,9 +203,39 @@ func processDefensiveAttack(stats AttackStatistics, attack app.Attack) AttackSta
 	// We "won" if we defended successfully
 	if IsSuccessfulDefense(attack.Result) {
 		stats.AttacksWon++
+		stats.SuccessfulDefenses++
 	} else {
 		stats.AttacksLost++
 	}
 
 	return stats
 }
+
+// CalculateWinRate returns the win rate as a percentage of total attacks.
+// The strict rate counts draws as losses; with halfCreditDraws each draw counts as half a win.
+//
+// Pure function: No I/O operations, fully testable with direct inputs.
+func CalculateWinRate(stats AttackStatistics, halfCreditDraws bool) float64 {
+	if stats.TotalAttacks == 0 {
+		return 0
+	}
+
+	wins := float64(stats.AttacksWon)
+	if halfCreditDraws {
+		wins += 0.5 * float64(stats.AttacksDrawn)
+	}
+
+	return wins / float64(stats.TotalAttacks) * 100
+}
+
+// CalculateDefensiveSuccessRate returns the percentage of attacks against us that we
+// defended successfully, or 0 when there were no attacks against us.
+//
+// Pure function: No I/O operations, fully testable with direct inputs.
+func CalculateDefensiveSuccessRate(stats AttackStatistics) float64 {
+	if stats.DefensiveAttacks == 0 {
+		return 0
+	}
+
+	return float64(stats.SuccessfulDefenses) / float64(stats.DefensiveAttacks) * 100
+}
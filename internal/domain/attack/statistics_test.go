@@ -0,0 +1,133 @@
+package attack
+
+import (
+	"math"
+	"testing"
+
+	"torn_rw_stats/internal/app"
+)
+
+func TestCalculateAttackStatistics_CountsDraws(t *testing.T) {
+	ourFactionID := 1001
+	enemyFactionID := 2002
+	ours := &app.Faction{ID: ourFactionID}
+	enemy := &app.Faction{ID: enemyFactionID}
+
+	attacks := []app.Attack{
+		{Result: "Hospitalized"},
+		{Result: "Stalemate"},
+		{Result: "Escape"},
+		{Result: "Lost"},
+		// Defensive stalemate is a successful defense, not a draw
+		{Result: "Stalemate"},
+	}
+	for i := 0; i < 4; i++ {
+		attacks[i].Attacker.Faction = ours
+		attacks[i].Defender.Faction = enemy
+	}
+	attacks[4].Attacker.Faction = enemy
+	attacks[4].Defender.Faction = ours
+
+	stats := CalculateAttackStatistics(attacks, ourFactionID)
+
+	if stats.TotalAttacks != 5 || stats.AttacksWon != 2 || stats.AttacksLost != 3 {
+		t.Errorf("unexpected totals: %+v", stats)
+	}
+	if stats.AttacksDrawn != 2 {
+		t.Errorf("expected 2 drawn attacks, got %d", stats.AttacksDrawn)
+	}
+}
+
+func TestCalculateAttackStatistics_ClassifiesAllResults(t *testing.T) {
+	ourFactionID := 1001
+	ours := &app.Faction{ID: ourFactionID}
+	enemy := &app.Faction{ID: 2002}
+
+	results := []string{"Arrested", "Looted", "Special", "Timeout", "Assist", "Unheard"}
+	attacks := make([]app.Attack, len(results))
+	for i, result := range results {
+		attacks[i] = app.Attack{Result: result}
+		attacks[i].Attacker.Faction = ours
+		attacks[i].Defender.Faction = enemy
+	}
+
+	stats := CalculateAttackStatistics(attacks, ourFactionID)
+
+	// Three wins, one loss; the assist and the unknown result are neutral
+	if stats.TotalAttacks != 6 || stats.AttacksWon != 3 || stats.AttacksLost != 1 {
+		t.Errorf("unexpected totals: %+v", stats)
+	}
+}
+
+func TestCountOutgoingOutcomes(t *testing.T) {
+	ourFactionID := 1001
+	ours := &app.Faction{ID: ourFactionID}
+	enemy := &app.Faction{ID: 2002}
+
+	results := []string{"Hospitalized", "Mugged", "Hospitalized", "Stalemate", "Escape", "Hospitalized", "Mugged", "Lost"}
+	attacks := make([]app.Attack, len(results))
+	for i, result := range results {
+		attacks[i] = app.Attack{Result: result}
+		attacks[i].Attacker.Faction = ours
+		attacks[i].Defender.Faction = enemy
+	}
+	// Incoming attacks are left out
+	incoming := app.Attack{Result: "Hospitalized"}
+	incoming.Attacker.Faction = enemy
+	incoming.Defender.Faction = ours
+	attacks = append(attacks, incoming)
+
+	counts := CountOutgoingOutcomes(attacks, ourFactionID)
+
+	expected := map[string]int{"Hospitalized": 3, "Mugged": 2, "Stalemate": 1, "Escape": 1, "Lost": 1}
+	if len(counts) != len(expected) {
+		t.Fatalf("expected %d outcomes, got %v", len(expected), counts)
+	}
+	for result, count := range expected {
+		if counts[result] != count {
+			t.Errorf("expected %d %s attacks, got %d", count, result, counts[result])
+		}
+	}
+}
+
+func TestCalculateWinRate(t *testing.T) {
+	tests := []struct {
+		name            string
+		stats           AttackStatistics
+		halfCreditDraws bool
+		expected        float64
+	}{
+		{
+			name:     "strict counts draws as losses",
+			stats:    AttackStatistics{TotalAttacks: 10, AttacksWon: 6, AttacksLost: 4, AttacksDrawn: 2},
+			expected: 60,
+		},
+		{
+			name:            "half credit counts draws as half wins",
+			stats:           AttackStatistics{TotalAttacks: 10, AttacksWon: 6, AttacksLost: 4, AttacksDrawn: 2},
+			halfCreditDraws: true,
+			expected:        70,
+		},
+		{
+			name:            "half credit with only draws",
+			stats:           AttackStatistics{TotalAttacks: 3, AttacksLost: 3, AttacksDrawn: 3},
+			halfCreditDraws: true,
+			expected:        50,
+		},
+		{
+			name:            "no attacks",
+			stats:           AttackStatistics{},
+			halfCreditDraws: true,
+			expected:        0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := CalculateWinRate(tt.stats, tt.halfCreditDraws)
+			if math.Abs(result-tt.expected) > 1e-9 {
+				t.Errorf("CalculateWinRate() = %f, want %f", result, tt.expected)
+			}
+		})
+	}
+}
@@ -1,6 +1,14 @@
 package attack
 
-import "torn_rw_stats/internal/app"
+import (
+	"time"
+
+	"torn_rw_stats/internal/app"
+)
+
+// DefaultIncrementalFetchBuffer is how far before the latest stored attack an
+// incremental fetch starts, catching attacks reported late or out of order
+const DefaultIncrementalFetchBuffer = time.Hour
 
 // TimeRangeResult holds the calculated time range and update mode for fetching attacks
 type TimeRangeResult struct {
@@ -16,12 +24,30 @@ const (
 )
 
 // CalculateTimeRange determines the time range and update mode for fetching attacks
+// using the default incremental fetch buffer
 // Pure function: Takes currentTime as parameter to enable deterministic testing
 func CalculateTimeRange(
 	war *app.War,
 	latestExistingTimestamp *int64,
 	currentTime int64,
 ) TimeRangeResult {
+	return CalculateTimeRangeWithBuffer(war, latestExistingTimestamp, currentTime, DefaultIncrementalFetchBuffer)
+}
+
+// CalculateTimeRangeWithBuffer determines the time range and update mode for fetching
+// attacks. Incremental fetches start buffer before the latest existing attack, but
+// never before war start; a zero buffer uses DefaultIncrementalFetchBuffer.
+// Pure function: Takes currentTime as parameter to enable deterministic testing
+func CalculateTimeRangeWithBuffer(
+	war *app.War,
+	latestExistingTimestamp *int64,
+	currentTime int64,
+	buffer time.Duration,
+) TimeRangeResult {
+	if buffer <= 0 {
+		buffer = DefaultIncrementalFetchBuffer
+	}
+
 	var fromTime, toTime int64
 	updateMode := UpdateModeFull
 
@@ -29,9 +55,8 @@ func CalculateTimeRange(
 		// Incremental update mode - only fetch new attacks
 		updateMode = UpdateModeIncremental
 
-		// Add 1-hour buffer to handle timing discrepancies
-		const bufferTime = 3600 // 1 hour in seconds
-		fromTime = *latestExistingTimestamp - bufferTime
+		// Step back by the buffer to catch attacks reported late or out of order
+		fromTime = *latestExistingTimestamp - int64(buffer/time.Second)
 
 		// Ensure we don't go before war start
 		if fromTime < war.Start {
@@ -2,6 +2,8 @@ package attack
 
 import (
 	"testing"
+	"time"
+
 	"torn_rw_stats/internal/app"
 )
 
@@ -116,3 +118,47 @@ func TestCalculateTimeRange(t *testing.T) {
 func ptr(i int64) *int64 {
 	return &i
 }
+
+func TestCalculateTimeRangeWithBuffer(t *testing.T) {
+	warStart := int64(1700000000)
+	currentTime := warStart + 10*3600
+
+	tests := []struct {
+		name             string
+		latest           int64
+		buffer           time.Duration
+		expectedFromTime int64
+	}{
+		{
+			name:             "three hour buffer",
+			latest:           warStart + 5*3600,
+			buffer:           3 * time.Hour,
+			expectedFromTime: warStart + 2*3600,
+		},
+		{
+			name:             "three hour buffer clamped to war start",
+			latest:           warStart + 2*3600,
+			buffer:           3 * time.Hour,
+			expectedFromTime: warStart,
+		},
+		{
+			name:             "zero buffer uses the default",
+			latest:           warStart + 5*3600,
+			expectedFromTime: warStart + 4*3600,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			war := &app.War{Start: warStart}
+			result := CalculateTimeRangeWithBuffer(war, &tt.latest, currentTime, tt.buffer)
+
+			if result.FromTime != tt.expectedFromTime {
+				t.Errorf("FromTime: expected %d, got %d", tt.expectedFromTime, result.FromTime)
+			}
+			if result.ToTime != currentTime || result.UpdateMode != UpdateModeIncremental {
+				t.Errorf("Expected incremental fetch up to %d, got %+v", currentTime, result)
+			}
+		})
+	}
+}
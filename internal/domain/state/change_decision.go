@@ -6,20 +6,27 @@ import (
 	"torn_rw_stats/internal/app"
 )
 
+// DefaultMaxStateChangesPerCycle caps the state change rows written in one cycle,
+// protecting the sheet and API quota from a buggy comparison or a mass event
+const DefaultMaxStateChangesPerCycle = 500
+
 // ChangeDecision describes what actions should be taken based on state changes
 type ChangeDecision struct {
 	ShouldWriteChanges bool
 	RecordsToWrite     []app.StateRecord
 	ChangeCount        int
+	DroppedCount       int // Changes not written because the per-cycle cap was exceeded
 	Reason             string
 }
 
 // DetermineStateChangeAction decides what to do with detected state changes
-// Returns a decision object describing whether to write and what to write
+// Returns a decision object describing whether to write and what to write.
+// At most maxChanges records are written; a maxChanges of 0 or less means no cap.
 func DetermineStateChangeAction(
 	currentStates []app.StateRecord,
 	previousStates []app.StateRecord,
 	changedStates []app.StateRecord,
+	maxChanges int,
 ) ChangeDecision {
 	changeCount := len(changedStates)
 
@@ -32,6 +39,16 @@ func DetermineStateChangeAction(
 		}
 	}
 
+	if maxChanges > 0 && changeCount > maxChanges {
+		return ChangeDecision{
+			ShouldWriteChanges: true,
+			RecordsToWrite:     changedStates[:maxChanges],
+			ChangeCount:        changeCount,
+			DroppedCount:       changeCount - maxChanges,
+			Reason:             fmt.Sprintf("Found %d state changes, writing capped at %d", changeCount, maxChanges),
+		}
+	}
+
 	return ChangeDecision{
 		ShouldWriteChanges: true,
 		RecordsToWrite:     changedStates,
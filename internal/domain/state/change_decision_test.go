@@ -0,0 +1,50 @@
+package state
+
+import (
+	"testing"
+
+	"torn_rw_stats/internal/app"
+)
+
+func TestDetermineStateChangeAction_Cap(t *testing.T) {
+	changed := []app.StateRecord{{MemberID: "1"}, {MemberID: "2"}, {MemberID: "3"}, {MemberID: "4"}}
+
+	tests := []struct {
+		name            string
+		maxChanges      int
+		expectedWritten int
+		expectedDropped int
+	}{
+		{name: "under cap", maxChanges: 10, expectedWritten: 4, expectedDropped: 0},
+		{name: "exactly at cap", maxChanges: 4, expectedWritten: 4, expectedDropped: 0},
+		{name: "over cap", maxChanges: 3, expectedWritten: 3, expectedDropped: 1},
+		{name: "cap disabled", maxChanges: -1, expectedWritten: 4, expectedDropped: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision := DetermineStateChangeAction(nil, nil, changed, tt.maxChanges)
+
+			if !decision.ShouldWriteChanges {
+				t.Fatal("expected changes to be written")
+			}
+			if len(decision.RecordsToWrite) != tt.expectedWritten {
+				t.Errorf("expected %d records written, got %d", tt.expectedWritten, len(decision.RecordsToWrite))
+			}
+			if decision.DroppedCount != tt.expectedDropped {
+				t.Errorf("expected %d dropped, got %d", tt.expectedDropped, decision.DroppedCount)
+			}
+			if decision.ChangeCount != len(changed) {
+				t.Errorf("expected change count %d, got %d", len(changed), decision.ChangeCount)
+			}
+		})
+	}
+}
+
+func TestDetermineStateChangeAction_NoChanges(t *testing.T) {
+	decision := DetermineStateChangeAction(nil, nil, nil, DefaultMaxStateChangesPerCycle)
+
+	if decision.ShouldWriteChanges || decision.DroppedCount != 0 {
+		t.Errorf("expected no write for no changes, got %+v", decision)
+	}
+}
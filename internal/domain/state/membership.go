@@ -0,0 +1,103 @@
+package state
+
+import (
+	"sort"
+	"time"
+
+	"torn_rw_stats/internal/app"
+)
+
+// EventMembership marks a synthetic state change recording a member leaving or joining a faction
+const EventMembership = "Membership"
+
+// Status states of synthetic membership records
+const (
+	MembershipLeft   = "Left"
+	MembershipJoined = "Joined"
+)
+
+// DetectMembershipChanges returns a synthetic record for each member who left or joined
+// a faction. Leaves compare the current members against the latest stored record of
+// every member. Joins compare them against roster, the members seen in the previous
+// cycle, since stored records are pruned and a long-standing member may have none left.
+// Only factions present in currentStates are compared, so a faction that failed to load
+// does not look empty, and joins are only reported for factions in roster, otherwise the
+// first sighting of a faction would mark everyone joined.
+//
+// Pure function: No I/O operations, fully testable with direct inputs.
+func DetectMembershipChanges(currentStates []app.StateRecord, latestByMember map[string]app.StateRecord, roster map[string]map[string]bool, now time.Time) []app.StateRecord {
+	currentByFaction := make(map[string]map[string]app.StateRecord)
+	for _, current := range currentStates {
+		if currentByFaction[current.FactionID] == nil {
+			currentByFaction[current.FactionID] = make(map[string]app.StateRecord)
+		}
+		currentByFaction[current.FactionID][current.MemberID] = current
+	}
+
+	storedByFaction := make(map[string]map[string]app.StateRecord)
+	for memberID, stored := range latestByMember {
+		if stored.StatusState == MembershipLeft {
+			continue
+		}
+		if storedByFaction[stored.FactionID] == nil {
+			storedByFaction[stored.FactionID] = make(map[string]app.StateRecord)
+		}
+		storedByFaction[stored.FactionID][memberID] = stored
+	}
+
+	var changes []app.StateRecord
+	for factionID, currentMembers := range currentByFaction {
+		for memberID, stored := range storedByFaction[factionID] {
+			if _, stillMember := currentMembers[memberID]; !stillMember {
+				changes = append(changes, membershipRecord(stored, MembershipLeft, now))
+			}
+		}
+
+		previousMembers, seen := roster[factionID]
+		if !seen {
+			continue
+		}
+		for memberID, current := range currentMembers {
+			if !previousMembers[memberID] {
+				changes = append(changes, membershipRecord(current, MembershipJoined, now))
+			}
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].FactionID != changes[j].FactionID {
+			return changes[i].FactionID < changes[j].FactionID
+		}
+		return changes[i].MemberID < changes[j].MemberID
+	})
+
+	return changes
+}
+
+// RosterByFaction returns the member IDs of each faction present in states, for the next
+// cycle's DetectMembershipChanges
+func RosterByFaction(states []app.StateRecord) map[string]map[string]bool {
+	roster := make(map[string]map[string]bool)
+	for _, record := range states {
+		if roster[record.FactionID] == nil {
+			roster[record.FactionID] = make(map[string]bool)
+		}
+		roster[record.FactionID][record.MemberID] = true
+	}
+	return roster
+}
+
+// membershipRecord builds a synthetic membership change from a member's state record
+func membershipRecord(record app.StateRecord, membership string, now time.Time) app.StateRecord {
+	return app.StateRecord{
+		Timestamp:         now,
+		MemberName:        record.MemberName,
+		MemberID:          record.MemberID,
+		FactionName:       record.FactionName,
+		FactionID:         record.FactionID,
+		LastActionStatus:  record.LastActionStatus,
+		StatusDescription: membership + " faction",
+		StatusState:       membership,
+		Event:             EventMembership,
+	}
+}
@@ -0,0 +1,92 @@
+package state
+
+import (
+	"testing"
+	"time"
+
+	"torn_rw_stats/internal/app"
+)
+
+func TestDetectMembershipChanges(t *testing.T) {
+	now := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	stored := func(memberID, factionID, statusState string) app.StateRecord {
+		return app.StateRecord{MemberID: memberID, FactionID: factionID, StatusState: statusState, Timestamp: now.Add(-time.Hour)}
+	}
+	current := func(memberID, factionID string) app.StateRecord {
+		return app.StateRecord{MemberID: memberID, FactionID: factionID, StatusState: "Okay", Timestamp: now}
+	}
+
+	tests := []struct {
+		name     string
+		current  []app.StateRecord
+		latest   map[string]app.StateRecord
+		roster   map[string]map[string]bool
+		expected []string // "memberID:status"
+	}{
+		{
+			name:     "unchanged members",
+			current:  []app.StateRecord{current("1", "100"), current("2", "100")},
+			latest:   map[string]app.StateRecord{"1": stored("1", "100", "Okay"), "2": stored("2", "100", "Okay")},
+			roster:   map[string]map[string]bool{"100": {"1": true, "2": true}},
+			expected: nil,
+		},
+		{
+			name:     "member left and another joined",
+			current:  []app.StateRecord{current("1", "100"), current("3", "100")},
+			latest:   map[string]app.StateRecord{"1": stored("1", "100", "Okay"), "2": stored("2", "100", "Hospital")},
+			roster:   map[string]map[string]bool{"100": {"1": true, "2": true}},
+			expected: []string{"2:Left", "3:Joined"},
+		},
+		{
+			name:     "already left is not repeated",
+			current:  []app.StateRecord{current("1", "100")},
+			latest:   map[string]app.StateRecord{"1": stored("1", "100", "Okay"), "2": stored("2", "100", MembershipLeft)},
+			roster:   map[string]map[string]bool{"100": {"1": true}},
+			expected: nil,
+		},
+		{
+			name:     "rejoining after leaving",
+			current:  []app.StateRecord{current("1", "100"), current("2", "100")},
+			latest:   map[string]app.StateRecord{"1": stored("1", "100", "Okay"), "2": stored("2", "100", MembershipLeft)},
+			roster:   map[string]map[string]bool{"100": {"1": true}},
+			expected: []string{"2:Joined"},
+		},
+		{
+			name:     "faction missing from current states is skipped",
+			current:  []app.StateRecord{current("1", "100")},
+			latest:   map[string]app.StateRecord{"1": stored("1", "100", "Okay"), "5": stored("5", "200", "Okay")},
+			roster:   map[string]map[string]bool{"100": {"1": true}, "200": {"5": true}},
+			expected: nil,
+		},
+		{
+			name:     "first sighting of a faction reports no joins",
+			current:  []app.StateRecord{current("1", "100"), current("2", "100")},
+			latest:   map[string]app.StateRecord{},
+			expected: nil,
+		},
+		{
+			name:     "member whose records were pruned is not a join",
+			current:  []app.StateRecord{current("1", "100"), current("2", "100")},
+			latest:   map[string]app.StateRecord{"1": stored("1", "100", "Okay")},
+			roster:   map[string]map[string]bool{"100": {"1": true, "2": true}},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes := DetectMembershipChanges(tt.current, tt.latest, tt.roster, now)
+			if len(changes) != len(tt.expected) {
+				t.Fatalf("expected %d changes, got %d: %+v", len(tt.expected), len(changes), changes)
+			}
+			for i, change := range changes {
+				if got := change.MemberID + ":" + change.StatusState; got != tt.expected[i] {
+					t.Errorf("change %d = %s, expected %s", i, got, tt.expected[i])
+				}
+				if change.Event != EventMembership || !change.Timestamp.Equal(now) {
+					t.Errorf("expected membership event at %v, got %+v", now, change)
+				}
+			}
+		})
+	}
+}
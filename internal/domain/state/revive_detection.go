@@ -0,0 +1,55 @@
+package state
+
+import (
+	"strings"
+	"time"
+
+	"torn_rw_stats/internal/app"
+)
+
+// EventRevived marks a state change where a member left hospital before their release time
+const EventRevived = "Revived"
+
+// ReviveTolerance is how far ahead of the expected release a hospital exit must be
+// observed to count as a revive, absorbing small API timing differences
+const ReviveTolerance = time.Minute
+
+// IsEarlyHospitalExit determines whether a member left hospital before the release
+// time recorded in their previous state, which usually means they were revived.
+// The current record's Timestamp is used as the observed release time.
+//
+// Pure function: No I/O operations, fully testable with direct inputs.
+func IsEarlyHospitalExit(previous, current app.StateRecord) bool {
+	if !isHospitalState(previous.StatusState) || previous.StatusUntil.IsZero() {
+		return false
+	}
+
+	observedAt := current.Timestamp
+	released := !isHospitalState(current.StatusState) ||
+		(!current.StatusUntil.IsZero() && !current.StatusUntil.After(observedAt))
+	if !released {
+		return false
+	}
+
+	return observedAt.Before(previous.StatusUntil.Add(-ReviveTolerance))
+}
+
+// MarkRevivedStates sets EventRevived on changed records whose member exited hospital
+// early compared to their previous state. Records without a previous state are untouched.
+//
+// Pure function: No I/O operations, fully testable with direct inputs.
+func MarkRevivedStates(changedStates []app.StateRecord, previousByMember map[string]app.StateRecord) []app.StateRecord {
+	marked := make([]app.StateRecord, len(changedStates))
+	for i, current := range changedStates {
+		if previous, exists := previousByMember[current.MemberID]; exists && IsEarlyHospitalExit(previous, current) {
+			current.Event = EventRevived
+		}
+		marked[i] = current
+	}
+	return marked
+}
+
+// isHospitalState reports whether a status state represents being in hospital
+func isHospitalState(statusState string) bool {
+	return strings.EqualFold(statusState, "Hospital")
+}
@@ -0,0 +1,95 @@
+package state
+
+import (
+	"testing"
+	"time"
+
+	"torn_rw_stats/internal/app"
+)
+
+func TestIsEarlyHospitalExit(t *testing.T) {
+	observedAt := time.Date(2025, 9, 18, 12, 0, 0, 0, time.UTC)
+	hospitalized := app.StateRecord{
+		MemberID:    "1",
+		StatusState: "Hospital",
+		StatusUntil: observedAt.Add(45 * time.Minute),
+	}
+
+	tests := []struct {
+		name     string
+		previous app.StateRecord
+		current  app.StateRecord
+		expected bool
+	}{
+		{
+			name:     "left hospital well before release",
+			previous: hospitalized,
+			current:  app.StateRecord{MemberID: "1", StatusState: "Okay", Timestamp: observedAt},
+			expected: true,
+		},
+		{
+			name:     "release time dropped to the past while still hospital",
+			previous: hospitalized,
+			current:  app.StateRecord{MemberID: "1", StatusState: "Hospital", StatusUntil: observedAt.Add(-time.Second), Timestamp: observedAt},
+			expected: true,
+		},
+		{
+			name:     "normal release at expected time",
+			previous: hospitalized,
+			current:  app.StateRecord{MemberID: "1", StatusState: "Okay", Timestamp: observedAt.Add(45 * time.Minute)},
+			expected: false,
+		},
+		{
+			name:     "release within tolerance of expected time",
+			previous: hospitalized,
+			current:  app.StateRecord{MemberID: "1", StatusState: "Okay", Timestamp: observedAt.Add(44*time.Minute + 30*time.Second)},
+			expected: false,
+		},
+		{
+			name:     "still in hospital",
+			previous: hospitalized,
+			current:  app.StateRecord{MemberID: "1", StatusState: "Hospital", StatusUntil: observedAt.Add(40 * time.Minute), Timestamp: observedAt},
+			expected: false,
+		},
+		{
+			name:     "previous state was not hospital",
+			previous: app.StateRecord{MemberID: "1", StatusState: "Traveling"},
+			current:  app.StateRecord{MemberID: "1", StatusState: "Okay", Timestamp: observedAt},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := IsEarlyHospitalExit(tt.previous, tt.current); result != tt.expected {
+				t.Errorf("IsEarlyHospitalExit() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMarkRevivedStates(t *testing.T) {
+	observedAt := time.Date(2025, 9, 18, 12, 0, 0, 0, time.UTC)
+
+	previous := map[string]app.StateRecord{
+		"1": {MemberID: "1", StatusState: "Hospital", StatusUntil: observedAt.Add(30 * time.Minute)},
+		"2": {MemberID: "2", StatusState: "Hospital", StatusUntil: observedAt.Add(-time.Minute)},
+	}
+	changed := []app.StateRecord{
+		{MemberID: "1", StatusState: "Okay", Timestamp: observedAt},
+		{MemberID: "2", StatusState: "Okay", Timestamp: observedAt},
+		{MemberID: "3", StatusState: "Okay", Timestamp: observedAt},
+	}
+
+	marked := MarkRevivedStates(changed, previous)
+
+	expected := []string{EventRevived, "", ""}
+	for i, event := range expected {
+		if marked[i].Event != event {
+			t.Errorf("member %s: expected event %q, got %q", marked[i].MemberID, event, marked[i].Event)
+		}
+	}
+	if changed[0].Event != "" {
+		t.Error("expected input records to be left unmodified")
+	}
+}
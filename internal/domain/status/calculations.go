@@ -2,6 +2,7 @@ package status
 
 import (
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -25,6 +26,68 @@ func CalculateCountdown(statusUntil time.Time, currentTime time.Time) string {
 	return fmt.Sprintf("%d:%02d:%02d", hours, minutes, seconds)
 }
 
+// ParseCountdown parses an H:MM:SS countdown, with or without the leading apostrophe
+// written to keep Sheets from converting it. Returns false for empty or malformed input.
+//
+// Pure function: No I/O operations, fully testable with direct inputs.
+func ParseCountdown(countdown string) (time.Duration, bool) {
+	var hours, minutes, seconds int
+	if _, err := fmt.Sscanf(strings.TrimPrefix(countdown, "'"), "%d:%d:%d", &hours, &minutes, &seconds); err != nil {
+		return 0, false
+	}
+	return time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute + time.Duration(seconds)*time.Second, true
+}
+
+// CalculateCountdownDrift returns the absolute difference between the countdown derived
+// from statusUntil and a calculated countdown, or false when either one is missing.
+//
+// Pure function: No I/O operations, fully testable with direct inputs.
+func CalculateCountdownDrift(statusUntil time.Time, calculatedCountdown string, currentTime time.Time) (time.Duration, bool) {
+	original, ok := ParseCountdown(CalculateCountdown(statusUntil, currentTime))
+	if !ok {
+		return 0, false
+	}
+	calculated, ok := ParseCountdown(calculatedCountdown)
+	if !ok {
+		return 0, false
+	}
+
+	drift := original - calculated
+	if drift < 0 {
+		drift = -drift
+	}
+	return drift, true
+}
+
+// FormatTimeSince describes how long ago a timestamp was, e.g. "just now" under a
+// minute, "5 minutes ago", "2 hours ago" or "3 days ago". Returns empty string if
+// timestamp is zero.
+func FormatTimeSince(timestamp time.Time, currentTime time.Time) string {
+	if timestamp.IsZero() {
+		return ""
+	}
+
+	elapsed := currentTime.Sub(timestamp)
+	switch {
+	case elapsed < time.Minute:
+		return "just now"
+	case elapsed < time.Hour:
+		return pluralAgo(int(elapsed.Minutes()), "minute")
+	case elapsed < 24*time.Hour:
+		return pluralAgo(int(elapsed.Hours()), "hour")
+	default:
+		return pluralAgo(int(elapsed.Hours()/24), "day")
+	}
+}
+
+// pluralAgo formats a count of units in the past, e.g. "1 hour ago" or "2 hours ago"
+func pluralAgo(count int, unit string) string {
+	if count == 1 {
+		return fmt.Sprintf("1 %s ago", unit)
+	}
+	return fmt.Sprintf("%d %ss ago", count, unit)
+}
+
 // CalculateTravelTimes calculates departure and arrival times for traveling members
 func CalculateTravelTimes(
 	isTraveling bool,
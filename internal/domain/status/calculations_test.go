@@ -0,0 +1,81 @@
+package status
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatTimeSince(t *testing.T) {
+	now := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		timestamp time.Time
+		expected  string
+	}{
+		{"zero timestamp", time.Time{}, ""},
+		{"30 seconds ago", now.Add(-30 * time.Second), "just now"},
+		{"90 seconds ago", now.Add(-90 * time.Second), "1 minute ago"},
+		{"5 minutes ago", now.Add(-5 * time.Minute), "5 minutes ago"},
+		{"3 hours ago", now.Add(-3 * time.Hour), "3 hours ago"},
+		{"2 days ago", now.Add(-50 * time.Hour), "2 days ago"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatTimeSince(tt.timestamp, now); got != tt.expected {
+				t.Errorf("FormatTimeSince() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseCountdown(t *testing.T) {
+	tests := []struct {
+		countdown string
+		expected  time.Duration
+		ok        bool
+	}{
+		{"1:02:03", time.Hour + 2*time.Minute + 3*time.Second, true},
+		{"'00:15:30", 15*time.Minute + 30*time.Second, true},
+		{"0:00:00", 0, true},
+		{"", 0, false},
+		{"soon", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.countdown, func(t *testing.T) {
+			got, ok := ParseCountdown(tt.countdown)
+			if got != tt.expected || ok != tt.ok {
+				t.Errorf("ParseCountdown(%q) = %v, %v, expected %v, %v", tt.countdown, got, ok, tt.expected, tt.ok)
+			}
+		})
+	}
+}
+
+func TestCalculateCountdownDrift(t *testing.T) {
+	now := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		until      time.Time
+		calculated string
+		expected   time.Duration
+		ok         bool
+	}{
+		{"calculated 90 seconds late", now.Add(20 * time.Minute), "'00:21:30", 90 * time.Second, true},
+		{"calculated 90 seconds early", now.Add(20 * time.Minute), "'00:18:30", 90 * time.Second, true},
+		{"countdowns agree", now.Add(20 * time.Minute), "'00:20:00", 0, true},
+		{"no original countdown", time.Time{}, "'00:20:00", 0, false},
+		{"no calculated countdown", now.Add(20 * time.Minute), "", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			drift, ok := CalculateCountdownDrift(tt.until, tt.calculated, now)
+			if drift != tt.expected || ok != tt.ok {
+				t.Errorf("CalculateCountdownDrift() = %v, %v, expected %v, %v", drift, ok, tt.expected, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package status
+
+import (
+	"strings"
+
+	"torn_rw_stats/internal/app"
+)
+
+// Status categories derived from the Torn API's member status color
+const (
+	CategoryAvailable = "available"
+	CategoryHospital  = "hospital"
+	CategoryTraveling = "traveling"
+	CategoryJail      = "jail"
+	CategoryUnknown   = "unknown"
+)
+
+// CategorizeStatusColor maps a member's status color ("green", "red" or "blue") to a
+// semantic category for the dashboard. Torn colors both hospital and jail red, so the
+// status state tells them apart. Unknown colors map to "unknown".
+//
+// Pure function: No I/O operations, fully testable with direct inputs.
+func CategorizeStatusColor(color, statusState string) string {
+	switch strings.ToLower(strings.TrimSpace(color)) {
+	case "green":
+		return CategoryAvailable
+	case "blue":
+		return CategoryTraveling
+	case "red":
+		if strings.EqualFold(statusState, "Jail") || IsFederalJail(statusState) {
+			return CategoryJail
+		}
+		return CategoryHospital
+	default:
+		return CategoryUnknown
+	}
+}
+
+// ResolveStatusCategory categorizes the member's current status color from faction
+// data. Returns "unknown" if the member is missing
+func ResolveStatusCategory(memberID string, factionMembers map[string]app.FactionMember) string {
+	member, exists := factionMembers[memberID]
+	if !exists {
+		return CategoryUnknown
+	}
+	return CategorizeStatusColor(member.Status.Color, member.Status.State)
+}
@@ -0,0 +1,46 @@
+package status
+
+import (
+	"testing"
+
+	"torn_rw_stats/internal/app"
+)
+
+func TestCategorizeStatusColor(t *testing.T) {
+	tests := []struct {
+		name        string
+		color       string
+		statusState string
+		expected    string
+	}{
+		{"green is available", "green", "Okay", CategoryAvailable},
+		{"red is hospital", "red", "Hospital", CategoryHospital},
+		{"red in jail is jail", "red", "Jail", CategoryJail},
+		{"red in federal jail is jail", "red", "Federal", CategoryJail},
+		{"blue is traveling", "blue", "Traveling", CategoryTraveling},
+		{"case insensitive", "Blue", "Abroad", CategoryTraveling},
+		{"unknown color", "purple", "Okay", CategoryUnknown},
+		{"empty color", "", "Okay", CategoryUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CategorizeStatusColor(tt.color, tt.statusState); got != tt.expected {
+				t.Errorf("CategorizeStatusColor(%q, %q) = %q, expected %q", tt.color, tt.statusState, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestResolveStatusCategory(t *testing.T) {
+	members := map[string]app.FactionMember{
+		"123": {Status: app.MemberStatus{Color: "red", State: "Hospital"}},
+	}
+
+	if got := ResolveStatusCategory("123", members); got != CategoryHospital {
+		t.Errorf("Expected %q for hospitalized member, got %q", CategoryHospital, got)
+	}
+	if got := ResolveStatusCategory("999", members); got != CategoryUnknown {
+		t.Errorf("Expected %q for missing member, got %q", CategoryUnknown, got)
+	}
+}
@@ -0,0 +1,47 @@
+package status
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"torn_rw_stats/internal/app"
+)
+
+// BuildHospitalList collects hospitalized members with a release time still in the
+// future, sorted by release time with the soonest out first. Ties are broken by name.
+//
+// Pure function: No I/O operations, fully testable with direct inputs.
+func BuildHospitalList(members map[string]app.FactionMember, now time.Time) []app.EnemyHospitalEntry {
+	var entries []app.EnemyHospitalEntry
+
+	for id, member := range members {
+		if !strings.EqualFold(member.Status.State, "Hospital") || member.Status.Until == nil {
+			continue
+		}
+
+		outAt := time.Unix(*member.Status.Until, 0).UTC()
+		if !outAt.After(now) {
+			continue
+		}
+
+		memberID, _ := strconv.Atoi(id)
+		entries = append(entries, app.EnemyHospitalEntry{
+			MemberID:  memberID,
+			Name:      member.Name,
+			Level:     member.Level,
+			OutAt:     outAt,
+			Countdown: CalculateCountdown(outAt, now),
+		})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if !entries[i].OutAt.Equal(entries[j].OutAt) {
+			return entries[i].OutAt.Before(entries[j].OutAt)
+		}
+		return entries[i].Name < entries[j].Name
+	})
+
+	return entries
+}
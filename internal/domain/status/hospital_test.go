@@ -0,0 +1,47 @@
+package status
+
+import (
+	"testing"
+	"time"
+
+	"torn_rw_stats/internal/app"
+)
+
+func TestBuildHospitalList(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	until := func(d time.Duration) *int64 {
+		ts := now.Add(d).Unix()
+		return &ts
+	}
+	hospitalized := func(name string, level int, d time.Duration) app.FactionMember {
+		return app.FactionMember{Name: name, Level: level, Status: app.MemberStatus{State: "Hospital", Until: until(d)}}
+	}
+
+	members := map[string]app.FactionMember{
+		"1": hospitalized("Slow", 50, 2*time.Hour),
+		"2": hospitalized("Soon", 40, 5*time.Minute),
+		"3": hospitalized("Middle", 60, 45*time.Minute),
+		// Already out, just not refreshed yet
+		"4": hospitalized("Released", 70, -time.Minute),
+		"5": {Name: "Okay", Level: 80, Status: app.MemberStatus{State: "Okay"}},
+	}
+
+	entries := BuildHospitalList(members, now)
+
+	expected := []string{"Soon", "Middle", "Slow"}
+	if len(entries) != len(expected) {
+		t.Fatalf("expected %d entries, got %d: %+v", len(expected), len(entries), entries)
+	}
+	for i, name := range expected {
+		if entries[i].Name != name {
+			t.Errorf("expected entry %d to be %s, got %s", i, name, entries[i].Name)
+		}
+	}
+
+	if entries[0].MemberID != 2 || entries[0].Level != 40 || entries[0].Countdown != "0:05:00" {
+		t.Errorf("unexpected first entry: %+v", entries[0])
+	}
+	if !entries[2].OutAt.Equal(now.Add(2 * time.Hour)) {
+		t.Errorf("expected last entry out at %v, got %v", now.Add(2*time.Hour), entries[2].OutAt)
+	}
+}
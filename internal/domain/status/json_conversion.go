@@ -32,10 +32,13 @@ func GroupRecordsByLocation(records []app.StatusV2Record) map[string]app.Locatio
 // Pure function: No I/O operations, fully testable with direct inputs.
 func ConvertToJSONMember(record app.StatusV2Record) app.JSONMember {
 	member := app.JSONMember{
-		Name:     record.Name,
-		MemberID: record.MemberID,
-		Level:    record.Level,
-		State:    record.State,
+		Name:           record.Name,
+		MemberID:       record.MemberID,
+		Level:          record.Level,
+		State:          record.State,
+		Online:         record.Online,
+		LastAction:     record.LastActionRelative,
+		StatusCategory: record.StatusCategory,
 	}
 
 	if !record.Until.IsZero() {
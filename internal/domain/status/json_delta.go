@@ -0,0 +1,100 @@
+package status
+
+import (
+	"sort"
+
+	"torn_rw_stats/internal/app"
+)
+
+// exportedMember captures everything about a member's exported status that
+// matters for change detection
+type exportedMember struct {
+	Location  string
+	Traveling bool
+	Member    app.JSONMember
+}
+
+// CalculateLocationDelta compares two grouped exports and returns the members whose
+// exported status changed (grouped by their current location) and the IDs of members
+// present in the previous export but missing from the current one.
+// Countdown and last action text are ignored because they change every cycle without a
+// status change.
+//
+// Pure function: No I/O operations, fully testable with direct inputs.
+func CalculateLocationDelta(previous, current map[string]app.LocationData) (map[string]app.LocationData, []string) {
+	previousMembers := flattenLocations(previous)
+	currentMembers := flattenLocations(current)
+
+	changed := make(map[string]app.LocationData)
+	for memberID, entry := range currentMembers {
+		if prev, exists := previousMembers[memberID]; exists && sameExportedStatus(prev, entry) {
+			continue
+		}
+		AddMemberToLocationData(changed, entry.Location, entry.Member, entry.Traveling)
+	}
+
+	removed := []string{}
+	for memberID := range previousMembers {
+		if _, exists := currentMembers[memberID]; !exists {
+			removed = append(removed, memberID)
+		}
+	}
+	sort.Strings(removed)
+
+	return FilterEmptyLocations(changed), removed
+}
+
+// flattenLocations indexes grouped location data by member ID
+func flattenLocations(locations map[string]app.LocationData) map[string]exportedMember {
+	members := make(map[string]exportedMember)
+
+	for location, data := range locations {
+		for _, member := range data.Traveling {
+			members[member.MemberID] = exportedMember{Location: location, Traveling: true, Member: member}
+		}
+		for _, member := range data.LocatedIn {
+			members[member.MemberID] = exportedMember{Location: location, Traveling: false, Member: member}
+		}
+	}
+
+	return members
+}
+
+// sameExportedStatus compares two exported members ignoring their volatile fields
+func sameExportedStatus(a, b exportedMember) bool {
+	clearVolatileFields(&a.Member)
+	clearVolatileFields(&b.Member)
+	return a == b
+}
+
+// WithoutVolatileFields returns a copy of grouped location data with the member fields
+// that change every cycle without a status change cleared, for comparing exports
+//
+// Pure function: No I/O operations, fully testable with direct inputs.
+func WithoutVolatileFields(locations map[string]app.LocationData) map[string]app.LocationData {
+	stable := make(map[string]app.LocationData, len(locations))
+	for location, data := range locations {
+		stable[location] = app.LocationData{
+			Traveling: withoutVolatileFields(data.Traveling),
+			LocatedIn: withoutVolatileFields(data.LocatedIn),
+		}
+	}
+	return stable
+}
+
+// withoutVolatileFields copies members with their volatile fields cleared
+func withoutVolatileFields(members []app.JSONMember) []app.JSONMember {
+	stable := make([]app.JSONMember, len(members))
+	for i, member := range members {
+		clearVolatileFields(&member)
+		stable[i] = member
+	}
+	return stable
+}
+
+// clearVolatileFields blanks the countdown and relative last action text, which
+// change every cycle even when the member's status does not
+func clearVolatileFields(member *app.JSONMember) {
+	member.Countdown = ""
+	member.LastAction = ""
+}
@@ -0,0 +1,112 @@
+package status
+
+import (
+	"testing"
+
+	"torn_rw_stats/internal/app"
+)
+
+func TestCalculateLocationDelta(t *testing.T) {
+	previous := map[string]app.LocationData{
+		"Torn": {
+			Traveling: []app.JSONMember{},
+			LocatedIn: []app.JSONMember{
+				{Name: "Steady", MemberID: "1", Level: 50, State: "Online", LastAction: "1 minute ago"},
+				{Name: "Hurt", MemberID: "2", Level: 40, State: "Idle", Status: "Hospital", Countdown: "1:10:00"},
+				{Name: "Leaver", MemberID: "3", Level: 30, State: "Offline"},
+			},
+		},
+		"Japan": {
+			Traveling: []app.JSONMember{
+				{Name: "Flyer", MemberID: "4", Level: 60, State: "Online", Countdown: "2:00:00", Arrival: "2025-09-18 03:00:00"},
+			},
+			LocatedIn: []app.JSONMember{},
+		},
+	}
+
+	current := map[string]app.LocationData{
+		"Torn": {
+			Traveling: []app.JSONMember{},
+			LocatedIn: []app.JSONMember{
+				// Last action text moved on but status is unchanged
+				{Name: "Steady", MemberID: "1", Level: 50, State: "Online", LastAction: "6 minutes ago"},
+				// Countdown ticked down but status is unchanged
+				{Name: "Hurt", MemberID: "2", Level: 40, State: "Idle", Status: "Hospital", Countdown: "1:05:00"},
+				// Newly seen member
+				{Name: "Joiner", MemberID: "5", Level: 20, State: "Online"},
+			},
+		},
+		"Japan": {
+			Traveling: []app.JSONMember{},
+			// Landed in Japan
+			LocatedIn: []app.JSONMember{
+				{Name: "Flyer", MemberID: "4", Level: 60, State: "Online"},
+			},
+		},
+	}
+
+	changed, removed := CalculateLocationDelta(previous, current)
+
+	changedIDs := make(map[string]string)
+	for location, data := range changed {
+		for _, member := range data.Traveling {
+			changedIDs[member.MemberID] = location
+		}
+		for _, member := range data.LocatedIn {
+			changedIDs[member.MemberID] = location
+		}
+	}
+
+	expectedChanged := map[string]string{"4": "Japan", "5": "Torn"}
+	if len(changedIDs) != len(expectedChanged) {
+		t.Fatalf("expected %d changed members, got %d: %v", len(expectedChanged), len(changedIDs), changedIDs)
+	}
+	for memberID, location := range expectedChanged {
+		if changedIDs[memberID] != location {
+			t.Errorf("expected member %s changed in %q, got %q", memberID, location, changedIDs[memberID])
+		}
+	}
+
+	if len(removed) != 1 || removed[0] != "3" {
+		t.Errorf("expected removed members [3], got %v", removed)
+	}
+}
+
+func TestCalculateLocationDelta_NoPreviousExport(t *testing.T) {
+	current := map[string]app.LocationData{
+		"Torn": {
+			Traveling: []app.JSONMember{},
+			LocatedIn: []app.JSONMember{
+				{Name: "Player1", MemberID: "1"},
+				{Name: "Player2", MemberID: "2"},
+			},
+		},
+	}
+
+	changed, removed := CalculateLocationDelta(nil, current)
+
+	if len(changed["Torn"].LocatedIn) != 2 {
+		t.Errorf("expected all members to be reported as changed, got %d", len(changed["Torn"].LocatedIn))
+	}
+	if len(removed) != 0 {
+		t.Errorf("expected no removed members, got %v", removed)
+	}
+}
+
+func TestCalculateLocationDelta_NoChanges(t *testing.T) {
+	snapshot := map[string]app.LocationData{
+		"Torn": {
+			Traveling: []app.JSONMember{},
+			LocatedIn: []app.JSONMember{{Name: "Player1", MemberID: "1", State: "Online"}},
+		},
+	}
+
+	changed, removed := CalculateLocationDelta(snapshot, snapshot)
+
+	if len(changed) != 0 {
+		t.Errorf("expected no changed locations, got %v", changed)
+	}
+	if len(removed) != 0 {
+		t.Errorf("expected no removed members, got %v", removed)
+	}
+}
@@ -0,0 +1,57 @@
+package status
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"torn_rw_stats/internal/app"
+)
+
+// ParseMemberNotes reads member notes from sheet rows with the member ID in the first
+// column and the note in the second. Rows without a numeric member ID, such as a
+// header, and rows with a blank note are skipped.
+//
+// Pure function: No I/O operations, fully testable with direct inputs.
+func ParseMemberNotes(rows [][]interface{}) map[string]string {
+	notes := make(map[string]string)
+	for _, row := range rows {
+		if len(row) < 2 || row[0] == nil || row[1] == nil {
+			continue
+		}
+
+		memberID := strings.TrimSpace(fmt.Sprint(row[0]))
+		if _, err := strconv.Atoi(memberID); err != nil {
+			continue
+		}
+
+		if note := strings.TrimSpace(fmt.Sprint(row[1])); note != "" {
+			notes[memberID] = note
+		}
+	}
+	return notes
+}
+
+// ApplyMemberNotes sets the note of every member in locations that has one in notes,
+// keyed by member ID. Members without a note are left unchanged.
+//
+// Pure function: No I/O operations, fully testable with direct inputs.
+func ApplyMemberNotes(locations map[string]app.LocationData, notes map[string]string) {
+	if len(notes) == 0 {
+		return
+	}
+
+	for _, data := range locations {
+		applyNotes(data.Traveling, notes)
+		applyNotes(data.LocatedIn, notes)
+	}
+}
+
+// applyNotes sets the notes of members in place
+func applyNotes(members []app.JSONMember, notes map[string]string) {
+	for i := range members {
+		if note, ok := notes[members[i].MemberID]; ok {
+			members[i].Note = note
+		}
+	}
+}
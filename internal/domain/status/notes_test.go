@@ -0,0 +1,42 @@
+package status
+
+import (
+	"reflect"
+	"testing"
+
+	"torn_rw_stats/internal/app"
+)
+
+func TestParseMemberNotes(t *testing.T) {
+	rows := [][]interface{}{
+		{"Member ID", "Note"},
+		{"123", " Hits hard, avoid "},
+		{float64(456), "Revives"},
+		{"789", ""},
+		{"999"},
+	}
+
+	expected := map[string]string{"123": "Hits hard, avoid", "456": "Revives"}
+	if notes := ParseMemberNotes(rows); !reflect.DeepEqual(notes, expected) {
+		t.Errorf("expected %v, got %v", expected, notes)
+	}
+}
+
+func TestApplyMemberNotes(t *testing.T) {
+	locations := map[string]app.LocationData{
+		"Torn":   {LocatedIn: []app.JSONMember{{Name: "Noted", MemberID: "123"}, {Name: "Plain", MemberID: "456"}}},
+		"Mexico": {Traveling: []app.JSONMember{{Name: "Flyer", MemberID: "789"}}},
+	}
+
+	ApplyMemberNotes(locations, map[string]string{"123": "Hits hard", "789": "Back soon"})
+
+	if note := locations["Torn"].LocatedIn[0].Note; note != "Hits hard" {
+		t.Errorf("expected note on located member, got %q", note)
+	}
+	if note := locations["Torn"].LocatedIn[1].Note; note != "" {
+		t.Errorf("expected no note on member without one, got %q", note)
+	}
+	if note := locations["Mexico"].Traveling[0].Note; note != "Back soon" {
+		t.Errorf("expected note on traveling member, got %q", note)
+	}
+}
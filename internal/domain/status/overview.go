@@ -0,0 +1,38 @@
+package status
+
+import (
+	"strings"
+
+	"torn_rw_stats/internal/app"
+)
+
+// CountMemberStates buckets faction members by last action (online, idle, offline)
+// and by high-level status state (hospital, traveling, jail). Members with an unknown
+// last action count as offline; members who are okay or abroad have no state bucket.
+//
+// Pure function: No I/O operations, fully testable with direct inputs.
+func CountMemberStates(members map[string]app.FactionMember) app.EnemyOverview {
+	var overview app.EnemyOverview
+
+	for _, member := range members {
+		switch strings.ToLower(member.LastAction.Status) {
+		case "online":
+			overview.Online++
+		case "idle":
+			overview.Idle++
+		default:
+			overview.Offline++
+		}
+
+		switch strings.ToLower(member.Status.State) {
+		case "hospital":
+			overview.Hospital++
+		case "traveling":
+			overview.Traveling++
+		case "jail":
+			overview.Jail++
+		}
+	}
+
+	return overview
+}
@@ -2,10 +2,30 @@ package status
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"torn_rw_stats/internal/app"
 )
 
+// FederalJailLocation is the location shown for members in federal jail
+const FederalJailLocation = "Federal Jail"
+
+// IsFederalJail reports whether a status state is federal jail
+func IsFederalJail(statusState string) bool {
+	return strings.EqualFold(statusState, "Federal")
+}
+
+// CalculateReleaseCountdown returns the countdown until a federally jailed member is
+// released, derived from their status until time. Other states and members without an
+// until time have no release countdown.
+func CalculateReleaseCountdown(statusState string, statusUntil time.Time, currentTime time.Time) string {
+	if !IsFederalJail(statusState) {
+		return ""
+	}
+	return CalculateCountdown(statusUntil, currentTime)
+}
+
 // GetExistingRecord finds existing data for a member using both ID and name keys
 // Returns nil if no existing record found
 func GetExistingRecord(
@@ -49,6 +69,25 @@ func ResolveLevel(
 	return 0
 }
 
+// ResolveOnlineStatus returns the member's last action status ("Online", "Idle" or
+// "Offline") from faction data. Returns "" if the member or last action is missing
+func ResolveOnlineStatus(memberID string, factionMembers map[string]app.FactionMember) string {
+	if member, exists := factionMembers[memberID]; exists {
+		return member.LastAction.Status
+	}
+	return ""
+}
+
+// ResolveLastActionRelative describes how long ago the member was last active, from
+// faction data. Returns "" if the member or last action timestamp is missing
+func ResolveLastActionRelative(memberID string, factionMembers map[string]app.FactionMember, currentTime time.Time) string {
+	member, exists := factionMembers[memberID]
+	if !exists || member.LastAction.Timestamp == 0 {
+		return ""
+	}
+	return FormatTimeSince(time.Unix(member.LastAction.Timestamp, 0), currentTime)
+}
+
 // ShouldPreserveTravelData determines if existing travel data should be preserved
 // This happens when the player is still traveling and we have existing departure/arrival times
 func ShouldPreserveTravelData(
@@ -0,0 +1,72 @@
+package status
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"torn_rw_stats/internal/app"
+)
+
+// IsAttackable reports whether a member can be attacked right now, i.e. is not in
+// hospital, traveling, abroad or in jail
+func IsAttackable(member app.FactionMember) bool {
+	return strings.EqualFold(member.Status.State, "Okay")
+}
+
+// CalculateTargetScore scores an enemy member as a target: points per level plus points
+// for being online or idle. Unattackable members score 0 so they rank below everyone
+// who can be hit now.
+//
+// Pure function: No I/O operations, fully testable with direct inputs.
+func CalculateTargetScore(member app.FactionMember, weights app.TargetPriorityWeights) int {
+	if !IsAttackable(member) {
+		return 0
+	}
+
+	score := member.Level * weights.Level
+	switch member.LastAction.Status {
+	case "Online":
+		score += weights.Online
+	case "Idle":
+		score += weights.Idle
+	}
+	return score
+}
+
+// BuildTargetPriorityList scores every member and sorts them best target first.
+// Attackable members rank above unattackable ones with the same score; remaining ties
+// are broken by level, then name.
+//
+// Pure function: No I/O operations, fully testable with direct inputs.
+func BuildTargetPriorityList(members map[string]app.FactionMember, weights app.TargetPriorityWeights) []app.TargetPriorityEntry {
+	entries := make([]app.TargetPriorityEntry, 0, len(members))
+
+	for id, member := range members {
+		memberID, _ := strconv.Atoi(id)
+		entries = append(entries, app.TargetPriorityEntry{
+			MemberID:    memberID,
+			Name:        member.Name,
+			Level:       member.Level,
+			Online:      member.LastAction.Status,
+			State:       member.Status.State,
+			Attackable:  IsAttackable(member),
+			TargetScore: CalculateTargetScore(member, weights),
+		})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].TargetScore != entries[j].TargetScore {
+			return entries[i].TargetScore > entries[j].TargetScore
+		}
+		if entries[i].Attackable != entries[j].Attackable {
+			return entries[i].Attackable
+		}
+		if entries[i].Level != entries[j].Level {
+			return entries[i].Level > entries[j].Level
+		}
+		return entries[i].Name < entries[j].Name
+	})
+
+	return entries
+}
@@ -0,0 +1,77 @@
+package status
+
+import (
+	"testing"
+
+	"torn_rw_stats/internal/app"
+)
+
+func TestBuildTargetPriorityList(t *testing.T) {
+	weights := app.TargetPriorityWeights{Level: 1, Online: 50, Idle: 20}
+	member := func(name string, level int, lastAction, state string) app.FactionMember {
+		return app.FactionMember{
+			Name:       name,
+			Level:      level,
+			LastAction: app.LastAction{Status: lastAction},
+			Status:     app.MemberStatus{State: state},
+		}
+	}
+
+	members := map[string]app.FactionMember{
+		"1": member("Hospitalized", 100, "Online", "Hospital"),
+		"2": member("Target", 80, "Online", "Okay"),
+		"3": member("Sleeper", 90, "Offline", "Okay"),
+		"4": member("Idler", 30, "Idle", "Okay"),
+		"5": member("Flyer", 95, "Online", "Traveling"),
+	}
+
+	entries := BuildTargetPriorityList(members, weights)
+
+	expected := []struct {
+		name  string
+		score int
+	}{
+		{"Target", 130},
+		{"Sleeper", 90},
+		{"Idler", 50},
+		{"Hospitalized", 0},
+		{"Flyer", 0},
+	}
+	if len(entries) != len(expected) {
+		t.Fatalf("expected %d entries, got %d", len(expected), len(entries))
+	}
+	for i, want := range expected {
+		if entries[i].Name != want.name || entries[i].TargetScore != want.score {
+			t.Errorf("entry %d: expected %s scoring %d, got %s scoring %d", i, want.name, want.score, entries[i].Name, entries[i].TargetScore)
+		}
+	}
+	if entries[0].MemberID != 2 || !entries[0].Attackable {
+		t.Errorf("expected attackable member 2 first, got %+v", entries[0])
+	}
+	if entries[3].Attackable {
+		t.Errorf("expected hospitalized member to be unattackable, got %+v", entries[3])
+	}
+}
+
+func TestCalculateTargetScore(t *testing.T) {
+	weights := app.TargetPriorityWeights{Level: 2, Online: 50, Idle: 20}
+
+	tests := []struct {
+		name     string
+		member   app.FactionMember
+		expected int
+	}{
+		{"online", app.FactionMember{Level: 10, LastAction: app.LastAction{Status: "Online"}, Status: app.MemberStatus{State: "Okay"}}, 70},
+		{"idle", app.FactionMember{Level: 10, LastAction: app.LastAction{Status: "Idle"}, Status: app.MemberStatus{State: "Okay"}}, 40},
+		{"offline", app.FactionMember{Level: 10, LastAction: app.LastAction{Status: "Offline"}, Status: app.MemberStatus{State: "Okay"}}, 20},
+		{"jailed", app.FactionMember{Level: 10, LastAction: app.LastAction{Status: "Online"}, Status: app.MemberStatus{State: "Jail"}}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CalculateTargetScore(tt.member, weights); got != tt.expected {
+				t.Errorf("expected %d, got %d", tt.expected, got)
+			}
+		})
+	}
+}
@@ -2,13 +2,30 @@ package travel
 
 import (
 	"strings"
+	"sync"
+	"time"
 )
 
+// DefaultLocationCacheTTL is how long a member's last resolved location is kept as a
+// fallback when none is configured
+const DefaultLocationCacheTTL = 15 * time.Minute
+
 // LocationService handles location parsing and standardization, mapping hospital
 // descriptions and travel status to canonical location names.
 type LocationService struct {
 	hospitalMappings map[string]string
 	locations        []string
+
+	// Last resolved location per member ID, served when a later status is ambiguous
+	cacheMu  sync.Mutex
+	cache    map[string]cachedLocation
+	cacheTTL time.Duration
+}
+
+// cachedLocation is a member's last resolved location and when it was resolved
+type cachedLocation struct {
+	location   string
+	resolvedAt time.Time
 }
 
 // NewLocationService creates a new location service with predefined mappings
@@ -32,7 +49,52 @@ func NewLocationService() *LocationService {
 			"Argentina", "Switzerland", "Japan", "China", "UAE",
 			"South Africa",
 		},
+		cache:    make(map[string]cachedLocation),
+		cacheTTL: DefaultLocationCacheTTL,
+	}
+}
+
+// SetLocationCacheTTL sets how long a member's last resolved location is served for
+// ambiguous statuses; zero or less keeps the default
+func (ls *LocationService) SetLocationCacheTTL(ttl time.Duration) {
+	if ttl <= 0 {
+		ttl = DefaultLocationCacheTTL
+	}
+	ls.cacheTTL = ttl
+}
+
+// ResolveMemberLocation parses a member's location from their status description and
+// remembers it. When the description is empty or matches no known location (e.g. a
+// brief API glitch), the member's last resolved location is returned instead, as long
+// as it is younger than the cache TTL.
+func (ls *LocationService) ResolveMemberLocation(memberID, description string, now time.Time) string {
+	location := ls.ParseLocation(description)
+
+	ls.cacheMu.Lock()
+	defer ls.cacheMu.Unlock()
+
+	if ls.isKnownLocation(location) {
+		ls.cache[memberID] = cachedLocation{location: location, resolvedAt: now}
+		return location
+	}
+
+	if cached, ok := ls.cache[memberID]; ok && now.Sub(cached.resolvedAt) < ls.cacheTTL {
+		return cached.location
+	}
+	return location
+}
+
+// isKnownLocation reports whether a parsed location is Torn or one of the destinations
+func (ls *LocationService) isKnownLocation(location string) bool {
+	if location == "Torn" {
+		return true
+	}
+	for _, known := range ls.locations {
+		if location == known {
+			return true
+		}
 	}
+	return false
 }
 
 // ParseLocation extracts standardized location from status description
@@ -2,6 +2,7 @@ package travel
 
 import (
 	"testing"
+	"time"
 )
 
 func TestLocationServiceParseLocation(t *testing.T) {
@@ -193,3 +194,33 @@ func BenchmarkLocationServiceParseLocation(b *testing.B) {
 		ls.ParseLocation(desc)
 	}
 }
+
+func TestLocationServiceResolveMemberLocation(t *testing.T) {
+	ls := NewLocationService()
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	if location := ls.ResolveMemberLocation("123", "In Mexico", now); location != "Mexico" {
+		t.Fatalf("Expected Mexico, got %q", location)
+	}
+
+	// An empty follow-up within the TTL is served from the cache
+	if location := ls.ResolveMemberLocation("123", "", now.Add(5*time.Minute)); location != "Mexico" {
+		t.Errorf("Expected cached Mexico for empty status, got %q", location)
+	}
+
+	// Other members are not affected by the cache
+	if location := ls.ResolveMemberLocation("456", "", now.Add(5*time.Minute)); location != "" {
+		t.Errorf("Expected no location for an uncached member, got %q", location)
+	}
+
+	// A clear status replaces the cached location
+	if location := ls.ResolveMemberLocation("123", "Okay", now.Add(6*time.Minute)); location != "Torn" {
+		t.Errorf("Expected Torn, got %q", location)
+	}
+
+	// Once the TTL has passed the cached location is no longer used
+	ls.SetLocationCacheTTL(time.Minute)
+	if location := ls.ResolveMemberLocation("123", "", now.Add(10*time.Minute)); location != "" {
+		t.Errorf("Expected expired cache to be ignored, got %q", location)
+	}
+}
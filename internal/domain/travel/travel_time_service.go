@@ -3,6 +3,7 @@ package travel
 import (
 	"context"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/rs/zerolog/log"
@@ -14,11 +15,13 @@ const (
 )
 
 // TravelTimeService handles travel time calculations and formatting, supporting
-// regular, airstrip, and business class travel times for all Torn destinations.
+// regular, airstrip, and business class travel times for all Torn destinations,
+// with optional per-member reductions for members known to travel faster.
 type TravelTimeService struct {
-	regularTimes  map[string]int
-	airstripTimes map[string]int
-	businessTimes map[string]int
+	regularTimes     map[string]int
+	airstripTimes    map[string]int
+	businessTimes    map[string]int
+	memberReductions map[int]float64 // member ID -> travel time reduction in percent
 }
 
 // NewTravelTimeService creates a new travel time service with predefined travel times
@@ -77,6 +80,19 @@ type TravelTimeData struct {
 	Countdown       string
 }
 
+// KnownDestinations returns every destination with predefined travel times,
+// ordered from the shortest to the longest regular flight
+func (tts *TravelTimeService) KnownDestinations() []string {
+	destinations := make([]string, 0, len(tts.regularTimes))
+	for destination := range tts.regularTimes {
+		destinations = append(destinations, destination)
+	}
+	sort.Slice(destinations, func(i, j int) bool {
+		return tts.regularTimes[destinations[i]] < tts.regularTimes[destinations[j]]
+	})
+	return destinations
+}
+
 // GetTravelTime returns travel duration based on destination and travel type
 // Travel types: "regular" (default), "airstrip" (private jet), "business" (business class)
 // Note: Business class detection from API is not currently implemented - this prepares the infrastructure
@@ -102,6 +118,24 @@ func (tts *TravelTimeService) GetTravelTime(destination string, travelType strin
 	return time.Duration(minutes) * time.Minute
 }
 
+// SetMemberReductions sets per-member travel time reductions (member ID -> percent,
+// e.g. 25 for a quarter off the base time). Members not in the map use the base time.
+func (tts *TravelTimeService) SetMemberReductions(reductions map[int]float64) {
+	tts.memberReductions = reductions
+}
+
+// GetMemberTravelTime returns the travel duration for a specific member, applying their
+// configured reduction to the base time for the destination and travel type
+func (tts *TravelTimeService) GetMemberTravelTime(userID int, destination string, travelType string) time.Duration {
+	duration := tts.GetTravelTime(destination, travelType)
+
+	reduction, ok := tts.memberReductions[userID]
+	if !ok {
+		return duration
+	}
+	return time.Duration(float64(duration) * (100 - reduction) / 100)
+}
+
 // FormatTravelTime formats duration as HH:MM:SS
 // Prefixed with apostrophe to force Google Sheets to treat as text (prevents fraction conversion)
 func (tts *TravelTimeService) FormatTravelTime(d time.Duration) string {
@@ -118,8 +152,8 @@ func (tts *TravelTimeService) FormatTravelTime(d time.Duration) string {
 
 // CalculateTravelTimes calculates travel departure, arrival and countdown for a user
 func (tts *TravelTimeService) CalculateTravelTimes(ctx context.Context, userID int, destination string, travelType string, currentTime time.Time, updateInterval time.Duration) *TravelTimeData {
-	// Get travel duration based on destination and travel type
-	travelDuration := tts.GetTravelTime(destination, travelType)
+	// Get travel duration based on destination, travel type and any member reduction
+	travelDuration := tts.GetMemberTravelTime(userID, destination, travelType)
 
 	// Assume they departed 50% through the last cycle interval
 	cycleInterval := updateInterval
@@ -192,7 +226,7 @@ func (tts *TravelTimeService) CalculateTravelTimesFromDeparture(ctx context.Cont
 	// If no existing arrival time or parsing failed, calculate from travel duration
 	if arrivalTime.IsZero() {
 		travelDestination := locationService.GetTravelDestinationForCalculation(statusDescription, destination)
-		travelDuration = tts.GetTravelTime(travelDestination, travelType)
+		travelDuration = tts.GetMemberTravelTime(userID, travelDestination, travelType)
 		arrivalTime = departureTime.Add(travelDuration)
 	}
 
@@ -388,3 +388,59 @@ func BenchmarkTravelTimeServiceFormatTravelTime(b *testing.B) {
 		tts.FormatTravelTime(duration)
 	}
 }
+
+func TestTravelTimeServiceMemberReductions(t *testing.T) {
+	tts := NewTravelTimeService()
+	tts.SetMemberReductions(map[int]float64{123: 25})
+	ls := NewLocationService()
+	ctx := context.Background()
+	currentTime := time.Date(2022, 1, 1, 11, 35, 0, 0, time.UTC)
+
+	// Mexico is 26 minutes regular; a 25% reduction makes it 19m30s
+	reduced := tts.CalculateTravelTimesFromDeparture(ctx, 123, "Mexico", "2022-01-01 11:30:00", "", "regular", currentTime, ls, "")
+	if reduced == nil || reduced.Arrival != "2022-01-01 11:49:30" {
+		t.Errorf("Expected reduced arrival 2022-01-01 11:49:30, got %+v", reduced)
+	}
+
+	base := tts.CalculateTravelTimesFromDeparture(ctx, 456, "Mexico", "2022-01-01 11:30:00", "", "regular", currentTime, ls, "")
+	if base == nil || base.Arrival != "2022-01-01 11:56:00" {
+		t.Errorf("Expected base arrival 2022-01-01 11:56:00 for member without reduction, got %+v", base)
+	}
+
+	if got := tts.GetMemberTravelTime(123, "Mexico", "regular"); got != 19*time.Minute+30*time.Second {
+		t.Errorf("Expected reduced travel time 19m30s, got %v", got)
+	}
+
+	// CalculateTravelTimes estimates departure half an interval ago
+	estimated := tts.CalculateTravelTimes(ctx, 123, "Mexico", "regular", currentTime, 10*time.Minute)
+	if estimated.Arrival != "2022-01-01 11:49:30" {
+		t.Errorf("Expected estimated arrival 2022-01-01 11:49:30, got %s", estimated.Arrival)
+	}
+}
+
+func TestTravelTimeServiceKnownDestinations(t *testing.T) {
+	tts := NewTravelTimeService()
+
+	destinations := tts.KnownDestinations()
+	if len(destinations) != 11 {
+		t.Fatalf("Expected 11 Torn destinations, got %d: %v", len(destinations), destinations)
+	}
+	if destinations[0] != "Mexico" || destinations[len(destinations)-1] != "South Africa" {
+		t.Errorf("Expected destinations ordered from Mexico to South Africa, got %v", destinations)
+	}
+
+	for _, dest := range destinations {
+		for _, travelType := range []string{"regular", "airstrip", "business"} {
+			if duration := tts.GetTravelTime(dest, travelType); duration == DefaultTravelTimeFallback {
+				t.Errorf("Destination %q has no predefined %s travel time", dest, travelType)
+			}
+		}
+	}
+
+	// Every destination the location parser recognises must have travel times
+	for _, dest := range NewLocationService().locations {
+		if tts.regularTimes[dest] == 0 {
+			t.Errorf("Location %q has no travel times", dest)
+		}
+	}
+}
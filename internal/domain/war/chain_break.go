@@ -0,0 +1,21 @@
+package war
+
+// DefaultChainBreakThreshold is the enemy chain length above which a drop to near zero
+// is reported as a broken chain
+const DefaultChainBreakThreshold = 100
+
+// ChainBrokenMax is the longest chain still treated as broken, allowing for a few hits
+// landing on a fresh chain before the next poll
+const ChainBrokenMax = 10
+
+// IsChainBreak reports whether a chain moving from previous to current between two polls
+// means it broke, i.e. it was above threshold and is now near zero. A non-positive
+// threshold uses DefaultChainBreakThreshold.
+//
+// Pure function: No I/O operations, fully testable with direct inputs.
+func IsChainBreak(previous, current, threshold int) bool {
+	if threshold <= 0 {
+		threshold = DefaultChainBreakThreshold
+	}
+	return previous > threshold && current <= ChainBrokenMax
+}
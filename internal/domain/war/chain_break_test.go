@@ -0,0 +1,28 @@
+package war
+
+import "testing"
+
+func TestIsChainBreak(t *testing.T) {
+	tests := []struct {
+		name      string
+		previous  int
+		current   int
+		threshold int
+		expected  bool
+	}{
+		{"long chain dropped to zero", 150, 0, 0, true},
+		{"long chain dropped to a fresh chain", 150, 3, 0, true},
+		{"long chain still running", 150, 148, 0, false},
+		{"short chain dropped to zero", 40, 0, 0, false},
+		{"custom threshold", 40, 0, 25, true},
+		{"chain growing from zero", 0, 150, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsChainBreak(tt.previous, tt.current, tt.threshold); got != tt.expected {
+				t.Errorf("IsChainBreak(%d, %d, %d) = %v, expected %v", tt.previous, tt.current, tt.threshold, got, tt.expected)
+			}
+		})
+	}
+}
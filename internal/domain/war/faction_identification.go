@@ -1,6 +1,10 @@
 package war
 
-import "torn_rw_stats/internal/app"
+import (
+	"fmt"
+
+	"torn_rw_stats/internal/app"
+)
 
 // FactionPair represents our faction and the enemy faction in a war
 type FactionPair struct {
@@ -25,3 +29,29 @@ func IdentifyWarFactions(war *app.War, ourFactionID int) FactionPair {
 
 	return pair
 }
+
+// ValidateWarFactions checks that a war lists two distinct factions and, when our
+// faction ID is known, that one of them is ours. Malformed war data otherwise makes
+// IdentifyWarFactions treat our own faction as the enemy.
+//
+// Pure function: No I/O operations, fully testable with direct inputs.
+func ValidateWarFactions(war *app.War, ourFactionID int) error {
+	seen := make(map[int]bool, len(war.Factions))
+	for _, faction := range war.Factions {
+		if faction.ID == 0 {
+			return fmt.Errorf("war %d lists a faction without an ID", war.ID)
+		}
+		if seen[faction.ID] {
+			return fmt.Errorf("war %d lists faction %d more than once", war.ID, faction.ID)
+		}
+		seen[faction.ID] = true
+	}
+
+	if len(seen) < 2 {
+		return fmt.Errorf("war %d lists %d factions, expected 2", war.ID, len(seen))
+	}
+	if ourFactionID != 0 && !seen[ourFactionID] {
+		return fmt.Errorf("war %d does not list our faction %d", war.ID, ourFactionID)
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package war
+
+import (
+	"testing"
+
+	"torn_rw_stats/internal/app"
+)
+
+func TestValidateWarFactions(t *testing.T) {
+	tests := []struct {
+		name         string
+		factions     []app.Faction
+		ourFactionID int
+		expectError  bool
+	}{
+		{name: "valid war", factions: []app.Faction{{ID: 100}, {ID: 200}}, ourFactionID: 100, expectError: false},
+		{name: "our faction unknown", factions: []app.Faction{{ID: 100}, {ID: 200}}, ourFactionID: 0, expectError: false},
+		{name: "duplicate faction", factions: []app.Faction{{ID: 100}, {ID: 100}}, ourFactionID: 100, expectError: true},
+		{name: "single faction", factions: []app.Faction{{ID: 100}}, ourFactionID: 100, expectError: true},
+		{name: "no factions", factions: nil, ourFactionID: 100, expectError: true},
+		{name: "faction without ID", factions: []app.Faction{{ID: 100}, {ID: 0}}, ourFactionID: 100, expectError: true},
+		{name: "our faction missing", factions: []app.Faction{{ID: 200}, {ID: 300}}, ourFactionID: 100, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateWarFactions(&app.War{ID: 1, Factions: tt.factions}, tt.ourFactionID)
+			if (err != nil) != tt.expectError {
+				t.Errorf("ValidateWarFactions() error = %v, expectError %v", err, tt.expectError)
+			}
+		})
+	}
+}
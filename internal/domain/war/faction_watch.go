@@ -0,0 +1,34 @@
+package war
+
+import (
+	"time"
+
+	"torn_rw_stats/internal/app"
+)
+
+// DetermineWarState classifies a faction's wars into a single war state using the same
+// priority as WarStateManager: active wars first, then scheduled wars, then recently
+// ended ones. It returns the war behind the state, or nil for NoWars. A zero
+// postWarWindow uses RecentlyEndedWarThreshold.
+//
+// Pure function: No I/O operations, fully testable with direct inputs.
+func DetermineWarState(warResponse *app.WarResponse, now time.Time, postWarWindow time.Duration) (WarState, *app.War) {
+	if warResponse == nil {
+		return NoWars, nil
+	}
+
+	wsm := &WarStateManager{postWarWindow: durationOrDefault(postWarWindow, RecentlyEndedWarThreshold)}
+	selectedWar, state := wsm.selectMostRelevantWar(wsm.getAllWars(warResponse), now)
+	return state, selectedWar
+}
+
+// IsWatchAlertTransition reports whether a watched faction moving from previous to
+// current should be reported, i.e. it has just entered PreWar or ActiveWar.
+//
+// Pure function: No I/O operations, fully testable with direct inputs.
+func IsWatchAlertTransition(previous, current WarState) bool {
+	if previous == current {
+		return false
+	}
+	return current == PreWar || current == ActiveWar
+}
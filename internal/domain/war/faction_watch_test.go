@@ -0,0 +1,92 @@
+package war
+
+import (
+	"testing"
+	"time"
+
+	"torn_rw_stats/internal/app"
+)
+
+func TestDetermineWarState(t *testing.T) {
+	now := time.Now()
+	ended := now.Add(-30 * time.Minute).Unix()
+	longAgo := now.Add(-3 * time.Hour).Unix()
+
+	tests := []struct {
+		name       string
+		response   *app.WarResponse
+		expected   WarState
+		expectedID int
+	}{
+		{"nil response", nil, NoWars, 0},
+		{"no wars", &app.WarResponse{}, NoWars, 0},
+		{
+			"scheduled raid",
+			warResponseWith(nil, []app.War{{ID: 1, Start: now.Add(time.Hour).Unix()}}, nil),
+			PreWar, 1,
+		},
+		{
+			"active territory war beats scheduled ranked war",
+			warResponseWith(&app.War{ID: 2, Start: now.Add(time.Hour).Unix()}, nil, []app.War{{ID: 3, Start: now.Add(-time.Hour).Unix()}}),
+			ActiveWar, 3,
+		},
+		{
+			"recently ended",
+			warResponseWith(&app.War{ID: 4, Start: now.Add(-2 * time.Hour).Unix(), End: &ended}, nil, nil),
+			PostWar, 4,
+		},
+		{
+			"ended outside post-war window",
+			warResponseWith(&app.War{ID: 5, Start: now.Add(-5 * time.Hour).Unix(), End: &longAgo}, nil, nil),
+			NoWars, 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state, selected := DetermineWarState(tt.response, now, 0)
+			if state != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, state)
+			}
+
+			selectedID := 0
+			if selected != nil {
+				selectedID = selected.ID
+			}
+			if selectedID != tt.expectedID {
+				t.Errorf("expected war %d, got %d", tt.expectedID, selectedID)
+			}
+		})
+	}
+}
+
+func TestIsWatchAlertTransition(t *testing.T) {
+	tests := []struct {
+		previous WarState
+		current  WarState
+		expected bool
+	}{
+		{NoWars, PreWar, true},
+		{NoWars, ActiveWar, true},
+		{PreWar, ActiveWar, true},
+		{PostWar, PreWar, true},
+		{PreWar, PreWar, false},
+		{ActiveWar, ActiveWar, false},
+		{ActiveWar, PostWar, false},
+		{PostWar, NoWars, false},
+	}
+
+	for _, tt := range tests {
+		if got := IsWatchAlertTransition(tt.previous, tt.current); got != tt.expected {
+			t.Errorf("IsWatchAlertTransition(%s, %s) = %v, expected %v", tt.previous, tt.current, got, tt.expected)
+		}
+	}
+}
+
+func warResponseWith(ranked *app.War, raids, territory []app.War) *app.WarResponse {
+	response := &app.WarResponse{}
+	response.Wars.Ranked = ranked
+	response.Wars.Raids = raids
+	response.Wars.Territory = territory
+	return response
+}
@@ -0,0 +1,20 @@
+package war
+
+import "math"
+
+// CalculateAttacksToOvertake returns how many more successful attacks at the average
+// respect per hit we need for our score to pass the enemy's. Returns 0 when we are
+// already ahead, and -1 when the count is unknown because we have no successful
+// attacks to average yet.
+//
+// Pure function: No I/O operations, fully testable with direct inputs.
+func CalculateAttacksToOvertake(ourScore, enemyScore int, averageRespect float64) int {
+	if ourScore > enemyScore {
+		return 0
+	}
+	if averageRespect <= 0 {
+		return -1
+	}
+	gap := float64(enemyScore - ourScore)
+	return int(math.Floor(gap/averageRespect)) + 1
+}
@@ -0,0 +1,28 @@
+package war
+
+import "testing"
+
+func TestCalculateAttacksToOvertake(t *testing.T) {
+	tests := []struct {
+		name           string
+		ourScore       int
+		enemyScore     int
+		averageRespect float64
+		expected       int
+	}{
+		{"trailing by 100 at 30 per hit", 1000, 1100, 30, 4},
+		{"trailing by an exact multiple needs one more hit", 1000, 1090, 30, 4},
+		{"tied needs one hit", 500, 500, 12.5, 1},
+		{"already ahead", 1200, 1100, 30, 0},
+		{"ahead without attacks", 10, 0, 0, 0},
+		{"trailing without attacks is unknown", 0, 100, 0, -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CalculateAttacksToOvertake(tt.ourScore, tt.enemyScore, tt.averageRespect); got != tt.expected {
+				t.Errorf("expected %d, got %d", tt.expected, got)
+			}
+		})
+	}
+}
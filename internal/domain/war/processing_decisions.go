@@ -1,6 +1,9 @@
 package war
 
 import (
+	"strings"
+	"time"
+
 	"torn_rw_stats/internal/app"
 )
 
@@ -41,6 +44,54 @@ func DetermineAttackFetchMode(existingRecordCount int, latestTimestamp int64) At
 	}
 }
 
+// ShouldForceFullRescan reports whether a war with existing records is due a full
+// re-scan, i.e. the interval has elapsed since its last full population. A zero
+// interval disables re-scans, and a war with no recorded full population is never due.
+func ShouldForceFullRescan(lastFullFetch, now time.Time, interval time.Duration) bool {
+	if interval <= 0 || lastFullFetch.IsZero() {
+		return false
+	}
+	return now.Sub(lastFullFetch) >= interval
+}
+
+// ShouldSkipReprocess reports whether a war processed at lastProcessed is still within
+// its reprocess cooldown at now. A zero interval disables the cooldown, and a war with
+// no recorded processing is never skipped.
+func ShouldSkipReprocess(lastProcessed, now time.Time, minInterval time.Duration) bool {
+	if minInterval <= 0 || lastProcessed.IsZero() {
+		return false
+	}
+	return now.Sub(lastProcessed) < minInterval
+}
+
+// FindFactionIDByName returns the ID of the faction named name in any war of the
+// response, matching case-insensitively and ignoring surrounding whitespace.
+// Returns 0 if name is empty or no faction matches.
+//
+// Pure function: No I/O operations, fully testable with direct inputs.
+func FindFactionIDByName(warResponse *app.WarResponse, name string) int {
+	name = strings.TrimSpace(name)
+	if warResponse == nil || name == "" {
+		return 0
+	}
+
+	var wars []app.War
+	if warResponse.Wars.Ranked != nil {
+		wars = append(wars, *warResponse.Wars.Ranked)
+	}
+	wars = append(wars, warResponse.Wars.Raids...)
+	wars = append(wars, warResponse.Wars.Territory...)
+
+	for _, war := range wars {
+		for _, faction := range war.Factions {
+			if strings.EqualFold(strings.TrimSpace(faction.Name), name) {
+				return faction.ID
+			}
+		}
+	}
+	return 0
+}
+
 // DetermineOurFactionID identifies which faction in the war is ours
 // Returns 0 if our faction is not found in the war
 func DetermineOurFactionID(war *app.War, knownFactionID int) int {
@@ -0,0 +1,85 @@
+package war
+
+import (
+	"testing"
+	"time"
+
+	"torn_rw_stats/internal/app"
+)
+
+func TestShouldForceFullRescan(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		lastFullFetch time.Time
+		interval      time.Duration
+		expected      bool
+	}{
+		{name: "disabled", lastFullFetch: now.Add(-24 * time.Hour), interval: 0, expected: false},
+		{name: "never fully fetched", lastFullFetch: time.Time{}, interval: time.Hour, expected: false},
+		{name: "interval not yet elapsed", lastFullFetch: now.Add(-59 * time.Minute), interval: time.Hour, expected: false},
+		{name: "interval elapsed", lastFullFetch: now.Add(-time.Hour), interval: time.Hour, expected: true},
+		{name: "well past interval", lastFullFetch: now.Add(-6 * time.Hour), interval: time.Hour, expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ShouldForceFullRescan(tt.lastFullFetch, now, tt.interval); got != tt.expected {
+				t.Errorf("ShouldForceFullRescan() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestShouldSkipReprocess(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		lastProcessed time.Time
+		interval      time.Duration
+		expected      bool
+	}{
+		{name: "disabled", lastProcessed: now.Add(-time.Second), interval: 0, expected: false},
+		{name: "never processed", lastProcessed: time.Time{}, interval: time.Hour, expected: false},
+		{name: "within cooldown", lastProcessed: now.Add(-4 * time.Minute), interval: 5 * time.Minute, expected: true},
+		{name: "cooldown elapsed", lastProcessed: now.Add(-5 * time.Minute), interval: 5 * time.Minute, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ShouldSkipReprocess(tt.lastProcessed, now, tt.interval); got != tt.expected {
+				t.Errorf("ShouldSkipReprocess() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFindFactionIDByName(t *testing.T) {
+	warResponse := &app.WarResponse{}
+	warResponse.Wars.Ranked = &app.War{ID: 1, Factions: []app.Faction{{ID: 100, Name: "Iron Wolves"}, {ID: 200, Name: "Red Dawn"}}}
+	warResponse.Wars.Raids = []app.War{{ID: 2, Factions: []app.Faction{{ID: 300, Name: "Night Owls"}, {ID: 100, Name: "Iron Wolves"}}}}
+
+	tests := []struct {
+		name     string
+		response *app.WarResponse
+		faction  string
+		expected int
+	}{
+		{name: "exact match", response: warResponse, faction: "Red Dawn", expected: 200},
+		{name: "case and whitespace insensitive", response: warResponse, faction: "  iron wolves ", expected: 100},
+		{name: "match in raid war", response: warResponse, faction: "Night Owls", expected: 300},
+		{name: "no match", response: warResponse, faction: "Blue Moon", expected: 0},
+		{name: "empty name", response: warResponse, faction: "", expected: 0},
+		{name: "nil response", response: nil, faction: "Red Dawn", expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FindFactionIDByName(tt.response, tt.faction); got != tt.expected {
+				t.Errorf("FindFactionIDByName() = %d, want %d", got, tt.expected)
+			}
+		})
+	}
+}
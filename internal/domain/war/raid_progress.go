@@ -0,0 +1,12 @@
+package war
+
+// CalculateRaidProgress returns our score as a percentage of a raid war's target score.
+// Returns 0 when the war has no target.
+//
+// Pure function: No I/O operations, fully testable with direct inputs.
+func CalculateRaidProgress(ourScore, targetScore int) float64 {
+	if targetScore <= 0 {
+		return 0
+	}
+	return float64(ourScore) / float64(targetScore) * 100
+}
@@ -26,10 +26,11 @@ const (
 	PreWarSchedulingWindow    = 7 * 24 * time.Hour // Wars starting within 7 days are "upcoming"
 	PreWarRealTimeThreshold   = 12 * time.Hour     // Switch to real-time polling this far before ranked war start
 
-	// Tuesday matchmaking configuration
-	MatchmakingHour   = 12 // Matchmaking occurs at 12:05 UTC
-	MatchmakingMinute = 5
-	DaysInWeek        = 7
+	// Default Tuesday matchmaking configuration
+	MatchmakingWeekday = app.DefaultMatchmakingWeekday
+	MatchmakingHour    = app.DefaultMatchmakingHour // Matchmaking occurs at 12:05 UTC
+	MatchmakingMinute  = app.DefaultMatchmakingMinute
+	DaysInWeek         = 7
 )
 
 // WarState represents the different phases a faction can be in regarding wars,
@@ -100,13 +101,47 @@ type WarStateManager struct {
 	currentWar         *app.War
 	currentWarIsRanked bool
 	stateConfigs       map[WarState]WarStateConfig
+	postWarWindow      time.Duration
+	warEndGracePeriod  time.Duration // how long an ended war is still treated as active
+	pinnedWarID        int           // war selected whenever present, regardless of priority; zero disables
+
+	// Weekly matchmaking schedule in UTC
+	matchmakingWeekday time.Weekday
+	matchmakingHour    int
+	matchmakingMinute  int
 }
 
-// NewWarStateManager creates a new war state manager
+// NewWarStateManager creates a new war state manager using the default intervals
+// and Tuesday 12:05 UTC matchmaking
 func NewWarStateManager() *WarStateManager {
+	return NewWarStateManagerWithConfig(&app.Config{})
+}
+
+// NewWarStateManagerWithConfig creates a new war state manager whose intervals, war end
+// grace period and matchmaking schedule come from config, falling back to the default
+// constants for any interval left at zero. A matchmaking schedule not marked as set
+// uses the default Tuesday 12:05 UTC.
+func NewWarStateManagerWithConfig(config *app.Config) *WarStateManager {
+	activeWarInterval := durationOrDefault(config.ActiveWarInterval, ActiveWarUpdateInterval)
+	preWarInterval := durationOrDefault(config.PreWarInterval, PreWarUpdateInterval)
+	postWarWindow := durationOrDefault(config.PostWarWindow, RecentlyEndedWarThreshold)
+
+	matchmakingWeekday, matchmakingHour, matchmakingMinute := MatchmakingWeekday, MatchmakingHour, MatchmakingMinute
+	if config.MatchmakingScheduleSet {
+		matchmakingWeekday = config.MatchmakingWeekday
+		matchmakingHour = config.MatchmakingHour
+		matchmakingMinute = config.MatchmakingMinute
+	}
+
 	return &WarStateManager{
-		currentState:    NoWars,
-		lastStateChange: time.Now(),
+		currentState:       NoWars,
+		lastStateChange:    time.Now(),
+		postWarWindow:      postWarWindow,
+		warEndGracePeriod:  config.WarEndGracePeriod,
+		pinnedWarID:        config.PinnedWarID,
+		matchmakingWeekday: matchmakingWeekday,
+		matchmakingHour:    matchmakingHour,
+		matchmakingMinute:  matchmakingMinute,
 		stateConfigs: map[WarState]WarStateConfig{
 			NoWars: {
 				UpdateInterval:    NoWarsPlaceholderInterval,
@@ -114,12 +149,12 @@ func NewWarStateManager() *WarStateManager {
 				NextCheckStrategy: UntilTuesdayMatchmaking,
 			},
 			PreWar: {
-				UpdateInterval:    PreWarUpdateInterval,
+				UpdateInterval:    preWarInterval,
 				Description:       "War scheduled - reconnaissance phase",
 				NextCheckStrategy: FixedInterval,
 			},
 			ActiveWar: {
-				UpdateInterval:    ActiveWarUpdateInterval,
+				UpdateInterval:    activeWarInterval,
 				Description:       "War in progress - real-time monitoring",
 				NextCheckStrategy: FixedInterval,
 			},
@@ -132,6 +167,14 @@ func NewWarStateManager() *WarStateManager {
 	}
 }
 
+// durationOrDefault returns value unless it is zero, in which case fallback is returned
+func durationOrDefault(value, fallback time.Duration) time.Duration {
+	if value == 0 {
+		return fallback
+	}
+	return value
+}
+
 // UpdateState analyzes current war data and updates the state
 func (wsm *WarStateManager) UpdateState(warResponse *app.WarResponse) WarState {
 	newState := wsm.determineState(warResponse)
@@ -227,32 +270,30 @@ func (wsm *WarStateManager) determineState(warResponse *app.WarResponse) WarStat
 	return NoWars
 }
 
-// selectMostRelevantWar chooses the most important war and its corresponding state
+// selectMostRelevantWar chooses the most important war and its corresponding state.
+// A pinned war present in wars is always chosen, in the state its own timing implies.
 func (wsm *WarStateManager) selectMostRelevantWar(wars []app.War, now time.Time) (*app.War, WarState) {
 	var activeWars, preWars, recentlyEndedWars []app.War
 
 	// Categorize all wars
 	for _, war := range wars {
-		warStart := time.Unix(war.Start, 0)
-
-		if now.After(warStart) {
-			// War has started - check if it's still active
-			if war.End != nil {
-				warEnd := time.Unix(*war.End, 0)
-				if now.Before(warEnd) {
-					// Active war (started but not ended)
-					activeWars = append(activeWars, war)
-				} else if now.Sub(warEnd) <= RecentlyEndedWarThreshold {
-					// Recently ended war
-					recentlyEndedWars = append(recentlyEndedWars, war)
-				}
-			} else {
-				// War started with no end time (still active)
-				activeWars = append(activeWars, war)
-			}
-		} else if warStart.Sub(now) <= PreWarSchedulingWindow {
-			// War scheduled within next 7 days
+		state, relevant := wsm.classifyWar(war, now)
+
+		if wsm.pinnedWarID != 0 && war.ID == wsm.pinnedWarID {
+			pinned := war
+			return &pinned, state
+		}
+		if !relevant {
+			continue
+		}
+
+		switch state {
+		case ActiveWar:
+			activeWars = append(activeWars, war)
+		case PreWar:
 			preWars = append(preWars, war)
+		case PostWar:
+			recentlyEndedWars = append(recentlyEndedWars, war)
 		}
 	}
 
@@ -277,6 +318,32 @@ func (wsm *WarStateManager) selectMostRelevantWar(wars []app.War, now time.Time)
 	return nil, NoWars
 }
 
+// classifyWar returns the state a war's timing implies and whether the war is recent or
+// near enough to be selected without a pin
+func (wsm *WarStateManager) classifyWar(war app.War, now time.Time) (WarState, bool) {
+	warStart := time.Unix(war.Start, 0)
+
+	if !now.After(warStart) {
+		// Scheduled wars are relevant within the next 7 days
+		return PreWar, warStart.Sub(now) <= PreWarSchedulingWindow
+	}
+
+	if war.End == nil {
+		// War started with no end time (still active)
+		return ActiveWar, true
+	}
+
+	warEnd := time.Unix(*war.End, 0)
+	if now.Before(warEnd.Add(wsm.warEndGracePeriod)) {
+		// Active war (ended within the grace period since the API occasionally
+		// reverses a reported end)
+		return ActiveWar, true
+	}
+
+	// Ended wars are relevant while recently ended
+	return PostWar, now.Sub(warEnd) <= wsm.postWarWindow
+}
+
 // selectMostRecentWar finds the war with the latest start time
 func (wsm *WarStateManager) selectMostRecentWar(wars []app.War) app.War {
 	if len(wars) == 0 {
@@ -357,7 +424,7 @@ func (wsm *WarStateManager) GetNextCheckTime() time.Time {
 		return now.Add(config.UpdateInterval)
 
 	case UntilTuesdayMatchmaking:
-		return wsm.getNextTuesdayMatchmaking(now)
+		return wsm.getNextMatchmaking(now)
 
 	case UntilWarStart:
 		if wsm.currentWar != nil {
@@ -369,37 +436,36 @@ func (wsm *WarStateManager) GetNextCheckTime() time.Time {
 		return now.Add(config.UpdateInterval)
 
 	case UntilNextWeekMatchmaking:
-		return wsm.getNextTuesdayMatchmaking(now)
+		return wsm.getNextMatchmaking(now)
 
 	default:
 		return now.Add(config.UpdateInterval)
 	}
 }
 
-// getNextTuesdayMatchmaking calculates the next Tuesday 12:05 UTC
-func (wsm *WarStateManager) getNextTuesdayMatchmaking(now time.Time) time.Time {
+// getNextMatchmaking calculates the next configured matchmaking time (Tuesday 12:05 UTC by default)
+func (wsm *WarStateManager) getNextMatchmaking(now time.Time) time.Time {
 	// Convert to UTC for consistency
 	nowUTC := now.UTC()
 
-	// Find next Tuesday
-	daysUntilTuesday := (int(time.Tuesday) - int(nowUTC.Weekday()) + DaysInWeek) % DaysInWeek
-	if daysUntilTuesday == 0 {
-		// It's Tuesday - check if we're past matchmaking time
-		matchmakingTime := time.Date(nowUTC.Year(), nowUTC.Month(), nowUTC.Day(), MatchmakingHour, MatchmakingMinute, 0, 0, time.UTC)
+	// Find next matchmaking day
+	daysUntilMatchmaking := (int(wsm.matchmakingWeekday) - int(nowUTC.Weekday()) + DaysInWeek) % DaysInWeek
+	if daysUntilMatchmaking == 0 {
+		// It's matchmaking day - check if we're past matchmaking time
+		matchmakingTime := time.Date(nowUTC.Year(), nowUTC.Month(), nowUTC.Day(), wsm.matchmakingHour, wsm.matchmakingMinute, 0, 0, time.UTC)
 		if nowUTC.After(matchmakingTime) {
 			// Past today's matchmaking, wait for next week
-			daysUntilTuesday = DaysInWeek
+			daysUntilMatchmaking = DaysInWeek
 		}
 	}
 
-	nextTuesday := nowUTC.AddDate(0, 0, daysUntilTuesday)
+	nextMatchmakingDay := nowUTC.AddDate(0, 0, daysUntilMatchmaking)
 
-	// Set to 12:05 UTC (5 minutes after matchmaking starts)
 	matchmakingTime := time.Date(
-		nextTuesday.Year(),
-		nextTuesday.Month(),
-		nextTuesday.Day(),
-		MatchmakingHour, MatchmakingMinute, 0, 0,
+		nextMatchmakingDay.Year(),
+		nextMatchmakingDay.Month(),
+		nextMatchmakingDay.Day(),
+		wsm.matchmakingHour, wsm.matchmakingMinute, 0, 0,
 		time.UTC,
 	)
 
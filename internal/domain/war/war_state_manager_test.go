@@ -149,7 +149,7 @@ func TestTuesdayMatchmakingLogic(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			result := wsm.getNextTuesdayMatchmaking(tc.currentTime)
+			result := wsm.getNextMatchmaking(tc.currentTime)
 			if !result.Equal(tc.expected) {
 				t.Errorf("Expected %v, got %v", tc.expected, result)
 			}
@@ -168,6 +168,72 @@ func TestTuesdayMatchmakingLogic(t *testing.T) {
 	}
 }
 
+// TestConfiguredMatchmakingSchedule tests next-check calculation with a Thursday 09:00 UTC schedule
+func TestConfiguredMatchmakingSchedule(t *testing.T) {
+	wsm := NewWarStateManagerWithConfig(&app.Config{
+		MatchmakingWeekday:     time.Thursday,
+		MatchmakingHour:        9,
+		MatchmakingMinute:      0,
+		MatchmakingScheduleSet: true,
+	})
+
+	testCases := []struct {
+		name        string
+		currentTime time.Time
+		expected    time.Time
+	}{
+		{
+			name:        "Tuesday default time is ignored",
+			currentTime: time.Date(2024, 1, 2, 13, 0, 0, 0, time.UTC), // Tuesday 13:00 UTC
+			expected:    time.Date(2024, 1, 4, 9, 0, 0, 0, time.UTC),  // Thursday 09:00 UTC
+		},
+		{
+			name:        "Thursday before matchmaking",
+			currentTime: time.Date(2024, 1, 4, 8, 30, 0, 0, time.UTC), // Thursday 08:30 UTC
+			expected:    time.Date(2024, 1, 4, 9, 0, 0, 0, time.UTC),  // Same Thursday 09:00 UTC
+		},
+		{
+			name:        "Thursday after matchmaking",
+			currentTime: time.Date(2024, 1, 4, 9, 30, 0, 0, time.UTC), // Thursday 09:30 UTC
+			expected:    time.Date(2024, 1, 11, 9, 0, 0, 0, time.UTC), // Next Thursday 09:00 UTC
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := wsm.getNextMatchmaking(tc.currentTime)
+			if !result.Equal(tc.expected) {
+				t.Errorf("Expected %v, got %v", tc.expected, result)
+			}
+		})
+	}
+
+	// With no wars the next check waits for the configured matchmaking
+	next := wsm.GetNextCheckTime()
+	if next.Weekday() != time.Thursday || next.Hour() != 9 || next.Minute() != 0 {
+		t.Errorf("Expected next check on Thursday 09:00 UTC, got %v", next)
+	}
+	if !next.After(time.Now()) || next.Sub(time.Now()) > 7*24*time.Hour {
+		t.Errorf("Expected next check within the coming week, got %v", next)
+	}
+}
+
+// TestMidnightSundayMatchmakingSchedule tests that a set Sunday 00:00 UTC schedule is
+// kept rather than treated as unset
+func TestMidnightSundayMatchmakingSchedule(t *testing.T) {
+	saturday := time.Date(2024, 1, 6, 13, 0, 0, 0, time.UTC)
+
+	wsm := NewWarStateManagerWithConfig(&app.Config{MatchmakingWeekday: time.Sunday, MatchmakingScheduleSet: true})
+	if result, expected := wsm.getNextMatchmaking(saturday), time.Date(2024, 1, 7, 0, 0, 0, 0, time.UTC); !result.Equal(expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+
+	wsm = NewWarStateManagerWithConfig(&app.Config{})
+	if result, expected := wsm.getNextMatchmaking(saturday), time.Date(2024, 1, 9, 12, 5, 0, 0, time.UTC); !result.Equal(expected) {
+		t.Errorf("Expected unset schedule to use Tuesday 12:05 UTC %v, got %v", expected, result)
+	}
+}
+
 // TestStateTransitions tests state transitions and their timing
 func TestStateTransitions(t *testing.T) {
 	wsm := NewWarStateManager()
@@ -290,6 +356,100 @@ func TestUpdateIntervals(t *testing.T) {
 	}
 }
 
+// TestUpdateIntervalsFromConfig tests that configured intervals override the defaults
+func TestUpdateIntervalsFromConfig(t *testing.T) {
+	wsm := NewWarStateManagerWithConfig(&app.Config{ActiveWarInterval: 30 * time.Second})
+
+	wsm.currentState = ActiveWar
+	if interval := wsm.GetStateConfig().UpdateInterval; interval != 30*time.Second {
+		t.Errorf("Expected active war interval 30s, got %v", interval)
+	}
+
+	// Zero values fall back to the default constants
+	wsm.currentState = PreWar
+	if interval := wsm.GetStateConfig().UpdateInterval; interval != PreWarUpdateInterval {
+		t.Errorf("Expected default pre-war interval %v, got %v", PreWarUpdateInterval, interval)
+	}
+	if wsm.postWarWindow != RecentlyEndedWarThreshold {
+		t.Errorf("Expected default post-war window %v, got %v", RecentlyEndedWarThreshold, wsm.postWarWindow)
+	}
+}
+
+// TestPostWarWindowFromConfig tests that the configured post-war window decides recency
+func TestPostWarWindowFromConfig(t *testing.T) {
+	now := time.Now()
+	endedTwoHoursAgo := now.Add(-2 * time.Hour).Unix()
+	wars := []app.War{
+		{ID: 1, Start: now.Add(-26 * time.Hour).Unix(), End: &endedTwoHoursAgo},
+	}
+
+	if _, state := NewWarStateManager().selectMostRelevantWar(wars, now); state != NoWars {
+		t.Errorf("Expected NoWars with default window, got %s", state.String())
+	}
+
+	wsm := NewWarStateManagerWithConfig(&app.Config{PostWarWindow: 3 * time.Hour})
+	if _, state := wsm.selectMostRelevantWar(wars, now); state != PostWar {
+		t.Errorf("Expected PostWar with 3h window, got %s", state.String())
+	}
+}
+
+// TestWarEndGracePeriod tests that a war ended within the grace period stays active
+func TestWarEndGracePeriod(t *testing.T) {
+	now := time.Now()
+	endedThirtySecondsAgo := now.Add(-30 * time.Second).Unix()
+	wars := []app.War{
+		{ID: 1, Start: now.Add(-26 * time.Hour).Unix(), End: &endedThirtySecondsAgo},
+	}
+
+	if _, state := NewWarStateManager().selectMostRelevantWar(wars, now); state != PostWar {
+		t.Errorf("Expected PostWar without a grace period, got %s", state.String())
+	}
+
+	wsm := NewWarStateManagerWithConfig(&app.Config{WarEndGracePeriod: 2 * time.Minute})
+	if _, state := wsm.selectMostRelevantWar(wars, now); state != ActiveWar {
+		t.Errorf("Expected ActiveWar within the 2m grace period, got %s", state.String())
+	}
+	if _, state := wsm.selectMostRelevantWar(wars, now.Add(2*time.Minute)); state != PostWar {
+		t.Errorf("Expected PostWar once the grace period elapsed, got %s", state.String())
+	}
+}
+
+// TestPinnedWarID tests that a pinned war is selected over higher-priority wars
+func TestPinnedWarID(t *testing.T) {
+	now := time.Now()
+	endedLastWeek := now.Add(-7 * 24 * time.Hour).Unix()
+	wars := []app.War{
+		{ID: 1, Start: now.Add(-3 * time.Hour).Unix()},
+		{ID: 2, Start: now.Add(-time.Hour).Unix()},
+		{ID: 3, Start: now.Add(-8 * 24 * time.Hour).Unix(), End: &endedLastWeek},
+	}
+
+	tests := []struct {
+		name          string
+		pinnedWarID   int
+		expectedWarID int
+		expectedState WarState
+	}{
+		{name: "no pin selects most recent active", pinnedWarID: 0, expectedWarID: 2, expectedState: ActiveWar},
+		{name: "pin selects older active war", pinnedWarID: 1, expectedWarID: 1, expectedState: ActiveWar},
+		{name: "pin selects long-ended war", pinnedWarID: 3, expectedWarID: 3, expectedState: PostWar},
+		{name: "pin missing from response is ignored", pinnedWarID: 99, expectedWarID: 2, expectedState: ActiveWar},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wsm := NewWarStateManagerWithConfig(&app.Config{PinnedWarID: tt.pinnedWarID})
+			selected, state := wsm.selectMostRelevantWar(wars, now)
+			if selected == nil || selected.ID != tt.expectedWarID {
+				t.Fatalf("Expected war %d selected, got %+v", tt.expectedWarID, selected)
+			}
+			if state != tt.expectedState {
+				t.Errorf("Expected state %s, got %s", tt.expectedState.String(), state.String())
+			}
+		})
+	}
+}
+
 // TestEdgeCases tests edge cases and special scenarios
 func TestEdgeCases(t *testing.T) {
 	now := time.Now()
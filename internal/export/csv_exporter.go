@@ -0,0 +1,211 @@
+package export
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"torn_rw_stats/internal/app"
+	"torn_rw_stats/internal/sheets"
+
+	"github.com/rs/zerolog/log"
+)
+
+// CSVExporter writes processed attack records to local CSV files using the same
+// column layout as the records sheet, for allies without spreadsheet access.
+type CSVExporter struct {
+	dir       string
+	processor *sheets.AttackRecordsProcessor // formats rows the same way as the records sheet
+}
+
+// NewCSVExporter creates a CSV exporter writing into the given directory. Rows are
+// formatted by the given records processor so the files follow the configured column
+// order, respect precision and timezone; nil uses the default layout.
+func NewCSVExporter(dir string, processor *sheets.AttackRecordsProcessor) *CSVExporter {
+	if processor == nil {
+		processor = sheets.NewAttackRecordsProcessor(nil)
+	}
+	return &CSVExporter{
+		dir:       dir,
+		processor: processor,
+	}
+}
+
+// FilePath returns the CSV file path for a war
+func (e *CSVExporter) FilePath(warID int) string {
+	return filepath.Join(e.dir, fmt.Sprintf("records_%d.csv", warID))
+}
+
+// ExportRecords appends records for a war to its CSV file, creating the file with a
+// header row if needed. Records whose attack code is already in the file are skipped,
+// so incremental fetches can be exported every cycle. A file written with a different
+// column layout is rewritten in the current layout first, so every row matches its header.
+func (e *CSVExporter) ExportRecords(warID int, records []app.AttackRecord) error {
+	if err := os.MkdirAll(e.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create CSV export directory: %w", err)
+	}
+
+	path := e.FilePath(warID)
+	header := e.processor.ColumnNames()
+
+	existingHeader, existingRows, err := readCSVFile(path)
+	if err != nil {
+		return err
+	}
+
+	if !slices.Equal(existingHeader, header) {
+		existingRows = remapColumns(existingRows, existingHeader, header)
+		if err := rewriteCSVFile(path, header, existingRows); err != nil {
+			return err
+		}
+		if existingHeader != nil {
+			log.Info().
+				Int("war_id", warID).
+				Str("path", path).
+				Int("rows", len(existingRows)).
+				Msg("Rewrote CSV export for a changed column layout")
+		}
+	}
+
+	existingCodes := make(map[string]bool, len(existingRows))
+	if codeIndex := slices.Index(header, "Code"); codeIndex >= 0 {
+		for _, row := range existingRows {
+			if codeIndex < len(row) {
+				existingCodes[row[codeIndex]] = true
+			}
+		}
+	}
+
+	var newRecords []app.AttackRecord
+	for _, record := range records {
+		if !existingCodes[record.Code] {
+			newRecords = append(newRecords, record)
+		}
+	}
+
+	if len(newRecords) == 0 {
+		return nil
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open CSV file: %w", err)
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+
+	rows := e.processor.ConvertRecordsToRows(newRecords)
+	for _, row := range rows {
+		if err := writer.Write(toStrings(row)); err != nil {
+			return fmt.Errorf("failed to write CSV row: %w", err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to flush CSV file: %w", err)
+	}
+
+	log.Debug().
+		Int("war_id", warID).
+		Str("path", path).
+		Int("records_written", len(rows)).
+		Msg("Exported attack records to CSV")
+
+	return nil
+}
+
+// readCSVFile returns the header and data rows of a CSV file, or nil for both when the
+// file doesn't exist yet
+func readCSVFile(path string) ([]string, [][]string, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open existing CSV file: %w", err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	var rows [][]string
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read existing CSV rows: %w", err)
+		}
+		rows = append(rows, row)
+	}
+
+	return header, rows, nil
+}
+
+// remapColumns moves each row's values from the columns of one header to the matching
+// columns of another, leaving columns the old header lacks blank
+func remapColumns(rows [][]string, from, to []string) [][]string {
+	remapped := make([][]string, len(rows))
+	for i, row := range rows {
+		remapped[i] = make([]string, len(to))
+		for j, name := range to {
+			if index := slices.Index(from, name); index >= 0 && index < len(row) {
+				remapped[i][j] = row[index]
+			}
+		}
+	}
+	return remapped
+}
+
+// rewriteCSVFile replaces a CSV file with the given header and rows, writing to a
+// temporary file first so an interrupted rewrite leaves the old file intact
+func rewriteCSVFile(path string, header []string, rows [][]string) error {
+	tmpPath := path + ".tmp"
+	file, err := os.Create(tmpPath)
+	if err != nil {
+		return fmt.Errorf("failed to create CSV file: %w", err)
+	}
+
+	writer := csv.NewWriter(file)
+	if err := writer.Write(header); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+	if err := writer.WriteAll(rows); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write CSV rows: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to close CSV file: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to replace CSV file: %w", err)
+	}
+	return nil
+}
+
+// toStrings converts a spreadsheet row into CSV fields
+func toStrings(row []interface{}) []string {
+	fields := make([]string, len(row))
+	for i, value := range row {
+		fields[i] = sheets.NewCell(value).String()
+	}
+	return fields
+}
@@ -0,0 +1,118 @@
+package export
+
+import (
+	"encoding/csv"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"torn_rw_stats/internal/app"
+	"torn_rw_stats/internal/sheets"
+)
+
+func readCSV(t *testing.T, path string) [][]string {
+	t.Helper()
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open CSV: %v", err)
+	}
+	defer file.Close()
+
+	rows, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		t.Fatalf("failed to read CSV: %v", err)
+	}
+	return rows
+}
+
+func TestCSVExporterExportRecords(t *testing.T) {
+	exporter := NewCSVExporter(t.TempDir(), nil)
+
+	attackerFactionID := 1001
+	records := []app.AttackRecord{
+		{
+			AttackID:          100001,
+			Code:              "abc123",
+			Started:           time.Date(2025, 9, 18, 12, 0, 0, 0, time.UTC),
+			Ended:             time.Date(2025, 9, 18, 12, 1, 30, 0, time.UTC),
+			Direction:         "Outgoing",
+			AttackerName:      "Attacker",
+			AttackerFactionID: &attackerFactionID,
+			DefenderName:      "Stealthy",
+			DefenderFactionID: nil,
+			Result:            "Hospitalized",
+			RespectGain:       2.5,
+		},
+	}
+
+	if err := exporter.ExportRecords(12345, records); err != nil {
+		t.Fatalf("ExportRecords() returned unexpected error: %v", err)
+	}
+
+	// Exporting the same records again must not duplicate rows
+	if err := exporter.ExportRecords(12345, records); err != nil {
+		t.Fatalf("ExportRecords() returned unexpected error on re-export: %v", err)
+	}
+
+	rows := readCSV(t, exporter.FilePath(12345))
+	if len(rows) != 2 {
+		t.Fatalf("expected header and 1 row, got %d rows", len(rows))
+	}
+
+	header, row := rows[0], rows[1]
+	if len(header) != 38 || len(row) != 38 {
+		t.Fatalf("expected 38 columns, got header=%d row=%d", len(header), len(row))
+	}
+	if header[0] != "Attack ID" || header[33] != "Third Party Faction" {
+		t.Errorf("unexpected header: %v", header)
+	}
+
+	expected := map[int]string{
+		0:  "100001",
+		1:  "abc123",
+		2:  "2025-09-18 12:00:00",
+		3:  "2025-09-18 12:01:30",
+		8:  "1001",
+		13: "", // Nullable defender faction ID
+		15: "Hospitalized",
+		16: "2.50",
+	}
+	for column, value := range expected {
+		if row[column] != value {
+			t.Errorf("column %d (%s): expected %q, got %q", column, header[column], value, row[column])
+		}
+	}
+}
+
+func TestCSVExporterRewritesChangedLayout(t *testing.T) {
+	processor := sheets.NewAttackRecordsProcessor(nil)
+	processor.SetRecordsColumnOrder([]string{"Code", "Attack ID", "Respect Gain"})
+	processor.SetRespectDecimalPlaces(1)
+	exporter := NewCSVExporter(t.TempDir(), processor)
+
+	// A file from an older release with a different header
+	old := "Attack ID,Code,Started,Respect Gain\n100001,abc123,2025-09-18 12:00:00,2.50\n"
+	if err := os.WriteFile(exporter.FilePath(1), []byte(old), 0o644); err != nil {
+		t.Fatalf("failed to write old CSV: %v", err)
+	}
+
+	records := []app.AttackRecord{
+		{AttackID: 100001, Code: "abc123"},
+		{AttackID: 100002, Code: "def456", RespectGain: 3.26},
+	}
+	if err := exporter.ExportRecords(1, records); err != nil {
+		t.Fatalf("ExportRecords() returned unexpected error: %v", err)
+	}
+
+	rows := readCSV(t, exporter.FilePath(1))
+	expected := [][]string{
+		{"Code", "Attack ID", "Respect Gain"},
+		{"abc123", "100001", "2.50"},
+		{"def456", "100002", "3.3"},
+	}
+	if !reflect.DeepEqual(rows, expected) {
+		t.Errorf("expected the old row moved to the new layout and one new row, got %v", rows)
+	}
+}
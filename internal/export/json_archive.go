@@ -0,0 +1,71 @@
+package export
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"torn_rw_stats/internal/app"
+)
+
+// archiveTimestampFormat sorts lexically in time order, so the oldest archives sort first
+const archiveTimestampFormat = "20060102T150405.000Z"
+
+// JSONArchiver keeps timestamped copies of each faction's Status v2 JSON export,
+// pruning the oldest beyond a per-faction retention count.
+type JSONArchiver struct {
+	dir       string
+	retention int
+}
+
+// NewJSONArchiver creates an archiver writing into dir and keeping retention exports
+// per faction; a retention below one uses the default
+func NewJSONArchiver(dir string, retention int) *JSONArchiver {
+	if retention < 1 {
+		retention = app.DefaultJSONArchiveRetention
+	}
+	return &JSONArchiver{
+		dir:       dir,
+		retention: retention,
+	}
+}
+
+// Archive writes data to status_v2_{factionID}_{timestamp}.json and prunes the faction's
+// oldest archives beyond the retention count, returning the archived file's path
+func (a *JSONArchiver) Archive(factionID int, timestamp time.Time, data []byte) (string, error) {
+	if err := os.MkdirAll(a.dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create JSON archive directory: %w", err)
+	}
+
+	name := fmt.Sprintf("status_v2_%d_%s.json", factionID, timestamp.UTC().Format(archiveTimestampFormat))
+	path := filepath.Join(a.dir, name)
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return "", fmt.Errorf("failed to write JSON archive: %w", err)
+	}
+
+	if err := a.prune(factionID); err != nil {
+		return path, err
+	}
+	return path, nil
+}
+
+// prune removes the faction's oldest archives beyond the retention count
+func (a *JSONArchiver) prune(factionID int) error {
+	archives, err := filepath.Glob(filepath.Join(a.dir, fmt.Sprintf("status_v2_%d_*.json", factionID)))
+	if err != nil {
+		return fmt.Errorf("failed to list JSON archives: %w", err)
+	}
+	if len(archives) <= a.retention {
+		return nil
+	}
+
+	sort.Strings(archives)
+	for _, path := range archives[:len(archives)-a.retention] {
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("failed to prune JSON archive: %w", err)
+		}
+	}
+	return nil
+}
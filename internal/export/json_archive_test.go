@@ -0,0 +1,55 @@
+package export
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestJSONArchiverArchive(t *testing.T) {
+	dir := t.TempDir()
+	archiver := NewJSONArchiver(dir, 3)
+
+	start := time.Date(2025, 9, 18, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 5; i++ {
+		if _, err := archiver.Archive(1001, start.Add(time.Duration(i)*time.Minute), []byte(`{"Faction":"A"}`)); err != nil {
+			t.Fatalf("Archive() returned unexpected error: %v", err)
+		}
+	}
+	// Another faction's archives count against its own retention only
+	if _, err := archiver.Archive(10010, start, []byte(`{"Faction":"B"}`)); err != nil {
+		t.Fatalf("Archive() returned unexpected error: %v", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("failed to read archive directory: %v", err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+
+	expected := []string{
+		"status_v2_10010_20250918T120000.000Z.json",
+		"status_v2_1001_20250918T120200.000Z.json",
+		"status_v2_1001_20250918T120300.000Z.json",
+		"status_v2_1001_20250918T120400.000Z.json",
+	}
+	sort.Strings(expected)
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected archives %v, got %v", expected, names)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "status_v2_1001_20250918T120400.000Z.json"))
+	if err != nil {
+		t.Fatalf("failed to read newest archive: %v", err)
+	}
+	if string(data) != `{"Faction":"A"}` {
+		t.Errorf("unexpected archive content %s", data)
+	}
+}
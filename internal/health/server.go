@@ -0,0 +1,65 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Snapshot is the processing state reported by the health endpoint. LastCycleTime and
+// NextCheckTime are nil until the first cycle completes.
+type Snapshot struct {
+	State             string     `json:"state"`
+	LastCycleTime     *time.Time `json:"lastCycleTime"`
+	LastCycleAPICalls int64      `json:"lastCycleAPICalls"`
+	NextCheckTime     *time.Time `json:"nextCheckTime"`
+}
+
+// SnapshotProvider supplies the current processing state, e.g. the OptimizedWarProcessor.
+// Implementations must be safe to call while a processing cycle is running.
+type SnapshotProvider interface {
+	HealthSnapshot() Snapshot
+}
+
+// NewHandler returns an HTTP handler serving /healthz from the provider's snapshot
+func NewHandler(provider SnapshotProvider) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(provider.HealthSnapshot()); err != nil {
+			log.Warn().Err(err).Msg("Failed to write health response")
+		}
+	})
+	return mux
+}
+
+// Start serves the health endpoint on the given port in a background goroutine,
+// shutting the server down when ctx is cancelled
+func Start(ctx context.Context, port int, provider SnapshotProvider) {
+	server := &http.Server{
+		Addr:              fmt.Sprintf(":%d", port),
+		Handler:           NewHandler(provider),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		log.Info().Int("port", port).Msg("Starting health check server")
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error().Err(err).Int("port", port).Msg("Health check server stopped")
+		}
+	}()
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			log.Warn().Err(err).Msg("Failed to shut down health check server")
+		}
+	}()
+}
@@ -0,0 +1,70 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type stubProvider struct {
+	snapshot Snapshot
+}
+
+func (s stubProvider) HealthSnapshot() Snapshot {
+	return s.snapshot
+}
+
+func TestHealthzHandler(t *testing.T) {
+	lastCycle := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+	nextCheck := lastCycle.Add(time.Minute)
+	provider := stubProvider{snapshot: Snapshot{
+		State:             "ActiveWar",
+		LastCycleTime:     &lastCycle,
+		LastCycleAPICalls: 7,
+		NextCheckTime:     &nextCheck,
+	}}
+
+	recorder := httptest.NewRecorder()
+	NewHandler(provider).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", recorder.Code)
+	}
+	if contentType := recorder.Header().Get("Content-Type"); contentType != "application/json" {
+		t.Errorf("Expected JSON content type, got %q", contentType)
+	}
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if body["state"] != "ActiveWar" {
+		t.Errorf("Expected state ActiveWar, got %v", body["state"])
+	}
+	if body["lastCycleTime"] != "2024-01-02T12:00:00Z" || body["nextCheckTime"] != "2024-01-02T12:01:00Z" {
+		t.Errorf("Unexpected cycle times: %v / %v", body["lastCycleTime"], body["nextCheckTime"])
+	}
+	if body["lastCycleAPICalls"] != float64(7) {
+		t.Errorf("Expected 7 API calls, got %v", body["lastCycleAPICalls"])
+	}
+}
+
+func TestHealthzHandler_BeforeFirstCycle(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	NewHandler(stubProvider{snapshot: Snapshot{State: "NoWars"}}).
+		ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", recorder.Code)
+	}
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if body["lastCycleTime"] != nil || body["nextCheckTime"] != nil {
+		t.Errorf("Expected null cycle times before the first cycle, got %v", body)
+	}
+}
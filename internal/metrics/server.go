@@ -0,0 +1,98 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Snapshot is the processing data exposed on the metrics endpoint
+type Snapshot struct {
+	APICallsTotal             int64
+	CacheHitsTotal            int64
+	CacheMissesTotal          int64
+	WarState                  string   // current war state, reported as 1 on the war state gauge
+	WarStates                 []string // every war state, so inactive states report 0
+	AttacksProcessedLastCycle int
+	LastCycleDuration         time.Duration
+}
+
+// SnapshotProvider supplies the current metrics, e.g. the OptimizedWarProcessor.
+// Implementations must be safe to call while a processing cycle is running.
+type SnapshotProvider interface {
+	MetricsSnapshot() Snapshot
+}
+
+// NewHandler returns an HTTP handler serving /metrics in the Prometheus text format
+func NewHandler(provider SnapshotProvider) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		if _, err := w.Write(Format(provider.MetricsSnapshot())); err != nil {
+			log.Warn().Err(err).Msg("Failed to write metrics response")
+		}
+	})
+	return mux
+}
+
+// Format renders a snapshot in the Prometheus text exposition format
+func Format(snapshot Snapshot) []byte {
+	var buf bytes.Buffer
+
+	writeMetric(&buf, "torn_rw_stats_api_calls_total", "counter", "Torn API calls made since startup.", float64(snapshot.APICallsTotal))
+	writeMetric(&buf, "torn_rw_stats_cache_hits_total", "counter", "Torn API lookups served from cache since startup.", float64(snapshot.CacheHitsTotal))
+	writeMetric(&buf, "torn_rw_stats_cache_misses_total", "counter", "Cacheable Torn API lookups that went to the API since startup.", float64(snapshot.CacheMissesTotal))
+
+	fmt.Fprintln(&buf, "# HELP torn_rw_stats_war_state Current war state, 1 for the active state and 0 for the others.")
+	fmt.Fprintln(&buf, "# TYPE torn_rw_stats_war_state gauge")
+	for _, state := range snapshot.WarStates {
+		value := 0
+		if state == snapshot.WarState {
+			value = 1
+		}
+		fmt.Fprintf(&buf, "torn_rw_stats_war_state{state=%q} %d\n", state, value)
+	}
+
+	writeMetric(&buf, "torn_rw_stats_attacks_processed_last_cycle", "gauge", "Attacks fetched and processed in the last completed cycle.", float64(snapshot.AttacksProcessedLastCycle))
+	writeMetric(&buf, "torn_rw_stats_last_cycle_duration_seconds", "gauge", "Duration of the last completed processing cycle.", snapshot.LastCycleDuration.Seconds())
+
+	return buf.Bytes()
+}
+
+// writeMetric writes a single unlabeled metric with its HELP and TYPE lines
+func writeMetric(buf *bytes.Buffer, name, metricType, help string, value float64) {
+	fmt.Fprintf(buf, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(buf, "# TYPE %s %s\n", name, metricType)
+	fmt.Fprintf(buf, "%s %g\n", name, value)
+}
+
+// Start serves the metrics endpoint on the given port in a background goroutine,
+// shutting the server down when ctx is cancelled
+func Start(ctx context.Context, port int, provider SnapshotProvider) {
+	server := &http.Server{
+		Addr:              fmt.Sprintf(":%d", port),
+		Handler:           NewHandler(provider),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		log.Info().Int("port", port).Msg("Starting metrics server")
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error().Err(err).Int("port", port).Msg("Metrics server stopped")
+		}
+	}()
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			log.Warn().Err(err).Msg("Failed to shut down metrics server")
+		}
+	}()
+}
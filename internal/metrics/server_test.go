@@ -0,0 +1,57 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type stubProvider struct {
+	snapshot Snapshot
+}
+
+func (s stubProvider) MetricsSnapshot() Snapshot {
+	return s.snapshot
+}
+
+func TestMetricsHandler(t *testing.T) {
+	provider := stubProvider{snapshot: Snapshot{
+		APICallsTotal:             42,
+		CacheHitsTotal:            9,
+		CacheMissesTotal:          3,
+		WarState:                  "ActiveWar",
+		WarStates:                 []string{"NoWars", "PreWar", "ActiveWar", "PostWar"},
+		AttacksProcessedLastCycle: 17,
+		LastCycleDuration:         1500 * time.Millisecond,
+	}}
+
+	recorder := httptest.NewRecorder()
+	NewHandler(provider).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", recorder.Code)
+	}
+	if contentType := recorder.Header().Get("Content-Type"); !strings.HasPrefix(contentType, "text/plain") {
+		t.Errorf("Expected text content type, got %q", contentType)
+	}
+
+	body := recorder.Body.String()
+	expected := []string{
+		"# TYPE torn_rw_stats_api_calls_total counter",
+		"torn_rw_stats_api_calls_total 42\n",
+		"torn_rw_stats_cache_hits_total 9\n",
+		"torn_rw_stats_cache_misses_total 3\n",
+		"# TYPE torn_rw_stats_war_state gauge",
+		`torn_rw_stats_war_state{state="ActiveWar"} 1`,
+		`torn_rw_stats_war_state{state="NoWars"} 0`,
+		"torn_rw_stats_attacks_processed_last_cycle 17\n",
+		"torn_rw_stats_last_cycle_duration_seconds 1.5\n",
+	}
+	for _, line := range expected {
+		if !strings.Contains(body, line) {
+			t.Errorf("Expected metrics output to contain %q, got:\n%s", line, body)
+		}
+	}
+}
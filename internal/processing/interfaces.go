@@ -14,6 +14,7 @@ import (
 type TornClientInterface interface {
 	GetOwnFaction(ctx context.Context) (*app.FactionInfoResponse, error)
 	GetFactionWars(ctx context.Context) (*app.WarResponse, error)
+	GetFactionWarsByID(ctx context.Context, factionID int) (*app.WarResponse, error)
 	GetFactionAttacks(ctx context.Context, from, to int64) (*app.AttackResponse, error)
 	GetFactionBasic(ctx context.Context, factionID int) (*app.FactionBasicResponse, error)
 	GetAPICallCount() int64
@@ -24,15 +25,28 @@ type TornClientInterface interface {
 // SheetsClientInterface defines the sheets API client methods used by WarProcessor
 type SheetsClientInterface interface {
 	EnsureWarSheets(ctx context.Context, spreadsheetID string, war *app.War) (*app.SheetConfig, error)
+	EnsureDirectionSheets(ctx context.Context, spreadsheetID string, config *app.SheetConfig) error
+	ValidateWarSheets(ctx context.Context, spreadsheetID string, config *app.SheetConfig) ([]string, error)
 	ReadExistingRecords(ctx context.Context, spreadsheetID, sheetName string) (*sheets.RecordsInfo, error)
+	ReadAttackRecords(ctx context.Context, spreadsheetID, sheetName string) ([]app.AttackRecord, error)
 	UpdateWarSummary(ctx context.Context, spreadsheetID string, config *app.SheetConfig, summary *app.WarSummary) error
 	UpdateAttackRecords(ctx context.Context, spreadsheetID string, config *app.SheetConfig, records []app.AttackRecord) error
+	BackfillAttackRecords(ctx context.Context, spreadsheetID string, config *app.SheetConfig, records []app.AttackRecord) error
+	NewAttackRecordsProcessor() *sheets.AttackRecordsProcessor
+	UpdateLeaderboard(ctx context.Context, spreadsheetID string, warID int, entries []app.MemberLeaderboardEntry) error
+	UpdateRespectTrend(ctx context.Context, spreadsheetID string, warID int, trend []app.RespectTrendWindow) error
+	UpdateIncomingThreats(ctx context.Context, spreadsheetID string, warID int, entries []app.IncomingThreatEntry) error
+	UpdateParticipationFlags(ctx context.Context, spreadsheetID string, warID int, flags []app.ParticipationFlag) error
+	UpdateCounterattacks(ctx context.Context, spreadsheetID string, warID int, counterattacks []app.Counterattack) error
+	UpdateDashboard(ctx context.Context, spreadsheetID string, rows []app.WarDashboardRow) error
+	AppendStateTransition(ctx context.Context, spreadsheetID string, transition app.WarStateTransition) error
 	ReadSheet(ctx context.Context, spreadsheetID, range_ string) ([][]interface{}, error)
 
 	// Additional methods for state tracking
 	UpdateRange(ctx context.Context, spreadsheetID, range_ string, values [][]interface{}) error
 	ClearRange(ctx context.Context, spreadsheetID, range_ string) error
 	AppendRows(ctx context.Context, spreadsheetID, range_ string, rows [][]interface{}) error
+	DeleteRows(ctx context.Context, spreadsheetID, sheetName string, startRow, count int) error
 	CreateSheet(ctx context.Context, spreadsheetID, sheetName string) error
 	SheetExists(ctx context.Context, spreadsheetID, sheetName string) (bool, error)
 	EnsureSheetCapacity(ctx context.Context, spreadsheetID, sheetName string, requiredRows, requiredCols int) error
@@ -40,6 +54,13 @@ type SheetsClientInterface interface {
 	// Status v2 methods
 	EnsureStatusV2Sheet(ctx context.Context, spreadsheetID string, factionID int) (string, error)
 	UpdateStatusV2(ctx context.Context, spreadsheetID, sheetName string, records []app.StatusV2Record) error
+	AppendStatusHistory(ctx context.Context, spreadsheetID string, factionID int, snapshotTime time.Time, records []app.StatusV2Record, maxRows int) error
+	AppendCountdownDrift(ctx context.Context, spreadsheetID string, drifts []app.CountdownDrift) error
+	AppendAlert(ctx context.Context, spreadsheetID string, alert app.Alert) error
+	UpdateEnemyOverview(ctx context.Context, spreadsheetID string, factionID int, overview app.EnemyOverview) error
+	UpdateEnemyHospital(ctx context.Context, spreadsheetID string, factionID int, entries []app.EnemyHospitalEntry) error
+	UpdateTargetPriority(ctx context.Context, spreadsheetID string, factionID int, entries []app.TargetPriorityEntry) error
+	AppendEnemyRosterSize(ctx context.Context, spreadsheetID string, factionID int, timestamp time.Time, memberCount int) error
 }
 
 // LocationServiceInterface defines the location service methods used by WarProcessor
@@ -64,6 +85,7 @@ type AttackProcessingServiceInterface interface {
 // WarSummaryServiceInterface defines the interface for war summary generation
 type WarSummaryServiceInterface interface {
 	GenerateWarSummary(war *app.War, attacks []app.Attack, ourFactionID int) *app.WarSummary
+	ExportSummaryJSON(summary *app.WarSummary) error
 }
 
 // WarStateManagerInterface defines the interface for war state management
@@ -2,6 +2,8 @@ package mocks
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"torn_rw_stats/internal/app"
 	"torn_rw_stats/internal/sheets"
@@ -10,15 +12,27 @@ import (
 // SheetsClient interface defines the methods used by WarProcessor from sheets.Client
 type SheetsClient interface {
 	EnsureWarSheets(ctx context.Context, spreadsheetID string, war *app.War) (*app.SheetConfig, error)
+	EnsureDirectionSheets(ctx context.Context, spreadsheetID string, config *app.SheetConfig) error
+	ValidateWarSheets(ctx context.Context, spreadsheetID string, config *app.SheetConfig) ([]string, error)
 	ReadExistingRecords(ctx context.Context, spreadsheetID, sheetName string) (*sheets.RecordsInfo, error)
+	ReadAttackRecords(ctx context.Context, spreadsheetID, sheetName string) ([]app.AttackRecord, error)
 	UpdateWarSummary(ctx context.Context, spreadsheetID string, config *app.SheetConfig, summary *app.WarSummary) error
 	UpdateAttackRecords(ctx context.Context, spreadsheetID string, config *app.SheetConfig, records []app.AttackRecord) error
+	BackfillAttackRecords(ctx context.Context, spreadsheetID string, config *app.SheetConfig, records []app.AttackRecord) error
+	UpdateLeaderboard(ctx context.Context, spreadsheetID string, warID int, entries []app.MemberLeaderboardEntry) error
+	UpdateRespectTrend(ctx context.Context, spreadsheetID string, warID int, trend []app.RespectTrendWindow) error
+	UpdateIncomingThreats(ctx context.Context, spreadsheetID string, warID int, entries []app.IncomingThreatEntry) error
+	UpdateParticipationFlags(ctx context.Context, spreadsheetID string, warID int, flags []app.ParticipationFlag) error
+	UpdateCounterattacks(ctx context.Context, spreadsheetID string, warID int, counterattacks []app.Counterattack) error
+	UpdateDashboard(ctx context.Context, spreadsheetID string, rows []app.WarDashboardRow) error
+	AppendStateTransition(ctx context.Context, spreadsheetID string, transition app.WarStateTransition) error
 	ReadSheet(ctx context.Context, spreadsheetID, range_ string) ([][]interface{}, error)
 
 	// Additional methods for state tracking
 	UpdateRange(ctx context.Context, spreadsheetID, range_ string, values [][]interface{}) error
 	ClearRange(ctx context.Context, spreadsheetID, range_ string) error
 	AppendRows(ctx context.Context, spreadsheetID, range_ string, rows [][]interface{}) error
+	DeleteRows(ctx context.Context, spreadsheetID, sheetName string, startRow, count int) error
 	CreateSheet(ctx context.Context, spreadsheetID, sheetName string) error
 	SheetExists(ctx context.Context, spreadsheetID, sheetName string) (bool, error)
 	EnsureSheetCapacity(ctx context.Context, spreadsheetID, sheetName string, requiredRows, requiredCols int) error
@@ -26,6 +40,13 @@ type SheetsClient interface {
 	// Status v2 methods
 	EnsureStatusV2Sheet(ctx context.Context, spreadsheetID string, factionID int) (string, error)
 	UpdateStatusV2(ctx context.Context, spreadsheetID, sheetName string, records []app.StatusV2Record) error
+	AppendStatusHistory(ctx context.Context, spreadsheetID string, factionID int, snapshotTime time.Time, records []app.StatusV2Record, maxRows int) error
+	AppendCountdownDrift(ctx context.Context, spreadsheetID string, drifts []app.CountdownDrift) error
+	AppendAlert(ctx context.Context, spreadsheetID string, alert app.Alert) error
+	UpdateEnemyOverview(ctx context.Context, spreadsheetID string, factionID int, overview app.EnemyOverview) error
+	UpdateEnemyHospital(ctx context.Context, spreadsheetID string, factionID int, entries []app.EnemyHospitalEntry) error
+	UpdateTargetPriority(ctx context.Context, spreadsheetID string, factionID int, entries []app.TargetPriorityEntry) error
+	AppendEnemyRosterSize(ctx context.Context, spreadsheetID string, factionID int, timestamp time.Time, memberCount int) error
 }
 
 // MockSheetsClient is a test double for the sheets.Client
@@ -33,31 +54,63 @@ type MockSheetsClient struct {
 	// Responses to return
 	EnsureWarSheetsResponse     *app.SheetConfig
 	ReadExistingRecordsResponse *sheets.RecordsInfo
+	ReadAttackRecordsResponses  map[string][]app.AttackRecord // keyed by sheet name
 	ReadSheetResponse           [][]interface{}
 	SheetExistsResponse         bool
 	EnsureStatusV2SheetResponse string
+	ValidateWarSheetsResponse   []string
 
 	// Errors to return
-	EnsureWarSheetsError     error
-	ReadExistingRecordsError error
-	UpdateWarSummaryError    error
-	UpdateAttackRecordsError error
-	ReadSheetError           error
-	UpdateRangeError         error
-	ClearRangeError          error
-	AppendRowsError          error
-	CreateSheetError         error
-	SheetExistsError         error
-	EnsureSheetCapacityError error
-	EnsureStatusV2SheetError error
-	UpdateStatusV2Error      error
+	EnsureWarSheetsError          error
+	EnsureDirectionSheetsError    error
+	ValidateWarSheetsError        error
+	ReadExistingRecordsError      error
+	ReadAttackRecordsError        error
+	UpdateWarSummaryError         error
+	UpdateAttackRecordsError      error
+	BackfillAttackRecordsError    error
+	UpdateLeaderboardError        error
+	UpdateRespectTrendError       error
+	UpdateIncomingThreatsError    error
+	UpdateParticipationFlagsError error
+	UpdateCounterattacksError     error
+	UpdateDashboardError          error
+	AppendStateTransitionError    error
+	ReadSheetError                error
+	UpdateRangeError              error
+	ClearRangeError               error
+	DeleteRowsError               error
+	AppendRowsError               error
+	CreateSheetError              error
+	SheetExistsError              error
+	EnsureSheetCapacityError      error
+	EnsureStatusV2SheetError      error
+	UpdateStatusV2Error           error
+	AppendStatusHistoryError      error
+	AppendCountdownDriftError     error
+	AppendAlertError              error
+	UpdateEnemyOverviewError      error
+	UpdateEnemyHospitalError      error
+	UpdateTargetPriorityError     error
+	AppendEnemyRosterSizeError    error
 
 	// Call tracking
-	EnsureWarSheetsCalled     bool
-	ReadExistingRecordsCalled bool
-	UpdateWarSummaryCalled    bool
-	UpdateAttackRecordsCalled bool
-	ReadSheetCalled           bool
+	EnsureWarSheetsCalled          bool
+	EnsureDirectionSheetsCalled    bool
+	ValidateWarSheetsCalled        bool
+	ReadExistingRecordsCalled      bool
+	UpdateWarSummaryCalled         bool
+	UpdateAttackRecordsCalled      bool
+	BackfillAttackRecordsCalled    bool
+	UpdateLeaderboardCalled        bool
+	UpdateRespectTrendCalled       bool
+	UpdateIncomingThreatsCalled    bool
+	UpdateParticipationFlagsCalled bool
+	UpdateCounterattacksCalled     bool
+	UpdateDashboardCalled          bool
+	AppendStatusHistoryCalled      bool
+	AppendCountdownDriftCalled     bool
+	ReadSheetCalled                bool
 
 	// Call parameters tracking
 	EnsureWarSheetsCalledWith struct {
@@ -78,10 +131,70 @@ type MockSheetsClient struct {
 		Config        *app.SheetConfig
 		Records       []app.AttackRecord
 	}
+	BackfillAttackRecordsCalledWith struct {
+		SpreadsheetID string
+		Config        *app.SheetConfig
+		Records       []app.AttackRecord
+	}
+	UpdateLeaderboardCalledWith struct {
+		SpreadsheetID string
+		WarID         int
+		Entries       []app.MemberLeaderboardEntry
+	}
+	UpdateRespectTrendCalledWith struct {
+		SpreadsheetID string
+		WarID         int
+		Trend         []app.RespectTrendWindow
+	}
+	UpdateIncomingThreatsCalledWith struct {
+		SpreadsheetID string
+		WarID         int
+		Entries       []app.IncomingThreatEntry
+	}
+	UpdateParticipationFlagsCalledWith struct {
+		SpreadsheetID string
+		WarID         int
+		Flags         []app.ParticipationFlag
+	}
+	UpdateCounterattacksCalledWith struct {
+		SpreadsheetID  string
+		WarID          int
+		Counterattacks []app.Counterattack
+	}
+	UpdateDashboardCalledWith struct {
+		SpreadsheetID string
+		Rows          []app.WarDashboardRow
+	}
+	AppendStatusHistoryCalledWith struct {
+		SpreadsheetID string
+		FactionID     int
+		SnapshotTime  time.Time
+		Records       []app.StatusV2Record
+		MaxRows       int
+	}
+	AppendCountdownDriftCalledWith struct {
+		SpreadsheetID string
+		Drifts        []app.CountdownDrift
+	}
 	ReadSheetCalledWith struct {
 		SpreadsheetID string
 		Range         string
 	}
+
+	// Every transition passed to AppendStateTransition, in order
+	AppendStateTransitionCalls []app.WarStateTransition
+
+	// Every alert passed to AppendAlert, in order
+	AppendAlertCalls []app.Alert
+
+	// Every overview passed to UpdateEnemyOverview, keyed by faction ID
+	UpdateEnemyOverviewCalls map[int]app.EnemyOverview
+	// Every hospital list passed to UpdateEnemyHospital, keyed by faction ID
+	UpdateEnemyHospitalCalls map[int][]app.EnemyHospitalEntry
+	// Every ranked target list passed to UpdateTargetPriority, keyed by faction ID
+	UpdateTargetPriorityCalls map[int][]app.TargetPriorityEntry
+	// Every member count passed to AppendEnemyRosterSize, in order per faction ID
+	AppendEnemyRosterSizeCalls map[int][]int
 }
 
 // NewMockSheetsClient creates a new mock sheets client
@@ -96,6 +209,21 @@ func (m *MockSheetsClient) EnsureWarSheets(ctx context.Context, spreadsheetID st
 	return m.EnsureWarSheetsResponse, m.EnsureWarSheetsError
 }
 
+func (m *MockSheetsClient) EnsureDirectionSheets(ctx context.Context, spreadsheetID string, config *app.SheetConfig) error {
+	m.EnsureDirectionSheetsCalled = true
+	if m.EnsureDirectionSheetsError != nil {
+		return m.EnsureDirectionSheetsError
+	}
+	config.OutgoingTabName = fmt.Sprintf("Outgoing - %d", config.WarID)
+	config.IncomingTabName = fmt.Sprintf("Incoming - %d", config.WarID)
+	return nil
+}
+
+func (m *MockSheetsClient) ValidateWarSheets(ctx context.Context, spreadsheetID string, config *app.SheetConfig) ([]string, error) {
+	m.ValidateWarSheetsCalled = true
+	return m.ValidateWarSheetsResponse, m.ValidateWarSheetsError
+}
+
 func (m *MockSheetsClient) ReadExistingRecords(ctx context.Context, spreadsheetID, sheetName string) (*sheets.RecordsInfo, error) {
 	m.ReadExistingRecordsCalled = true
 	m.ReadExistingRecordsCalledWith.SpreadsheetID = spreadsheetID
@@ -103,6 +231,10 @@ func (m *MockSheetsClient) ReadExistingRecords(ctx context.Context, spreadsheetI
 	return m.ReadExistingRecordsResponse, m.ReadExistingRecordsError
 }
 
+func (m *MockSheetsClient) ReadAttackRecords(ctx context.Context, spreadsheetID, sheetName string) ([]app.AttackRecord, error) {
+	return m.ReadAttackRecordsResponses[sheetName], m.ReadAttackRecordsError
+}
+
 func (m *MockSheetsClient) UpdateWarSummary(ctx context.Context, spreadsheetID string, config *app.SheetConfig, summary *app.WarSummary) error {
 	m.UpdateWarSummaryCalled = true
 	m.UpdateWarSummaryCalledWith.SpreadsheetID = spreadsheetID
@@ -119,6 +251,71 @@ func (m *MockSheetsClient) UpdateAttackRecords(ctx context.Context, spreadsheetI
 	return m.UpdateAttackRecordsError
 }
 
+func (m *MockSheetsClient) BackfillAttackRecords(ctx context.Context, spreadsheetID string, config *app.SheetConfig, records []app.AttackRecord) error {
+	m.BackfillAttackRecordsCalled = true
+	m.BackfillAttackRecordsCalledWith.SpreadsheetID = spreadsheetID
+	m.BackfillAttackRecordsCalledWith.Config = config
+	m.BackfillAttackRecordsCalledWith.Records = records
+	return m.BackfillAttackRecordsError
+}
+
+// NewAttackRecordsProcessor returns a records processor with the default layout and no API
+func (m *MockSheetsClient) NewAttackRecordsProcessor() *sheets.AttackRecordsProcessor {
+	return sheets.NewAttackRecordsProcessor(nil)
+}
+
+func (m *MockSheetsClient) UpdateLeaderboard(ctx context.Context, spreadsheetID string, warID int, entries []app.MemberLeaderboardEntry) error {
+	m.UpdateLeaderboardCalled = true
+	m.UpdateLeaderboardCalledWith.SpreadsheetID = spreadsheetID
+	m.UpdateLeaderboardCalledWith.WarID = warID
+	m.UpdateLeaderboardCalledWith.Entries = entries
+	return m.UpdateLeaderboardError
+}
+
+func (m *MockSheetsClient) UpdateRespectTrend(ctx context.Context, spreadsheetID string, warID int, trend []app.RespectTrendWindow) error {
+	m.UpdateRespectTrendCalled = true
+	m.UpdateRespectTrendCalledWith.SpreadsheetID = spreadsheetID
+	m.UpdateRespectTrendCalledWith.WarID = warID
+	m.UpdateRespectTrendCalledWith.Trend = trend
+	return m.UpdateRespectTrendError
+}
+
+func (m *MockSheetsClient) UpdateIncomingThreats(ctx context.Context, spreadsheetID string, warID int, entries []app.IncomingThreatEntry) error {
+	m.UpdateIncomingThreatsCalled = true
+	m.UpdateIncomingThreatsCalledWith.SpreadsheetID = spreadsheetID
+	m.UpdateIncomingThreatsCalledWith.WarID = warID
+	m.UpdateIncomingThreatsCalledWith.Entries = entries
+	return m.UpdateIncomingThreatsError
+}
+
+func (m *MockSheetsClient) UpdateParticipationFlags(ctx context.Context, spreadsheetID string, warID int, flags []app.ParticipationFlag) error {
+	m.UpdateParticipationFlagsCalled = true
+	m.UpdateParticipationFlagsCalledWith.SpreadsheetID = spreadsheetID
+	m.UpdateParticipationFlagsCalledWith.WarID = warID
+	m.UpdateParticipationFlagsCalledWith.Flags = flags
+	return m.UpdateParticipationFlagsError
+}
+
+func (m *MockSheetsClient) UpdateCounterattacks(ctx context.Context, spreadsheetID string, warID int, counterattacks []app.Counterattack) error {
+	m.UpdateCounterattacksCalled = true
+	m.UpdateCounterattacksCalledWith.SpreadsheetID = spreadsheetID
+	m.UpdateCounterattacksCalledWith.WarID = warID
+	m.UpdateCounterattacksCalledWith.Counterattacks = counterattacks
+	return m.UpdateCounterattacksError
+}
+
+func (m *MockSheetsClient) UpdateDashboard(ctx context.Context, spreadsheetID string, rows []app.WarDashboardRow) error {
+	m.UpdateDashboardCalled = true
+	m.UpdateDashboardCalledWith.SpreadsheetID = spreadsheetID
+	m.UpdateDashboardCalledWith.Rows = rows
+	return m.UpdateDashboardError
+}
+
+func (m *MockSheetsClient) AppendStateTransition(ctx context.Context, spreadsheetID string, transition app.WarStateTransition) error {
+	m.AppendStateTransitionCalls = append(m.AppendStateTransitionCalls, transition)
+	return m.AppendStateTransitionError
+}
+
 func (m *MockSheetsClient) ReadSheet(ctx context.Context, spreadsheetID, range_ string) ([][]interface{}, error) {
 	m.ReadSheetCalled = true
 	m.ReadSheetCalledWith.SpreadsheetID = spreadsheetID
@@ -135,16 +332,42 @@ func (m *MockSheetsClient) Reset() {
 
 	// Clear errors
 	m.EnsureWarSheetsError = nil
+	m.EnsureDirectionSheetsError = nil
+	m.ValidateWarSheetsError = nil
 	m.ReadExistingRecordsError = nil
 	m.UpdateWarSummaryError = nil
 	m.UpdateAttackRecordsError = nil
+	m.UpdateLeaderboardError = nil
+	m.UpdateRespectTrendError = nil
+	m.UpdateIncomingThreatsError = nil
+	m.UpdateParticipationFlagsError = nil
+	m.UpdateCounterattacksError = nil
+	m.UpdateDashboardError = nil
+	m.AppendStatusHistoryError = nil
+	m.AppendCountdownDriftError = nil
+	m.AppendAlertError = nil
+	m.AppendStateTransitionError = nil
+	m.UpdateEnemyOverviewError = nil
+	m.UpdateEnemyHospitalError = nil
+	m.UpdateTargetPriorityError = nil
+	m.AppendEnemyRosterSizeError = nil
 	m.ReadSheetError = nil
 
 	// Clear call tracking
 	m.EnsureWarSheetsCalled = false
+	m.EnsureDirectionSheetsCalled = false
+	m.ValidateWarSheetsCalled = false
 	m.ReadExistingRecordsCalled = false
 	m.UpdateWarSummaryCalled = false
 	m.UpdateAttackRecordsCalled = false
+	m.UpdateLeaderboardCalled = false
+	m.UpdateRespectTrendCalled = false
+	m.UpdateIncomingThreatsCalled = false
+	m.UpdateParticipationFlagsCalled = false
+	m.UpdateCounterattacksCalled = false
+	m.UpdateDashboardCalled = false
+	m.AppendStatusHistoryCalled = false
+	m.AppendCountdownDriftCalled = false
 	m.ReadSheetCalled = false
 
 	// Clear parameter tracking
@@ -166,10 +389,56 @@ func (m *MockSheetsClient) Reset() {
 		Config        *app.SheetConfig
 		Records       []app.AttackRecord
 	}{}
+	m.UpdateLeaderboardCalledWith = struct {
+		SpreadsheetID string
+		WarID         int
+		Entries       []app.MemberLeaderboardEntry
+	}{}
+	m.UpdateRespectTrendCalledWith = struct {
+		SpreadsheetID string
+		WarID         int
+		Trend         []app.RespectTrendWindow
+	}{}
+	m.UpdateIncomingThreatsCalledWith = struct {
+		SpreadsheetID string
+		WarID         int
+		Entries       []app.IncomingThreatEntry
+	}{}
+	m.UpdateParticipationFlagsCalledWith = struct {
+		SpreadsheetID string
+		WarID         int
+		Flags         []app.ParticipationFlag
+	}{}
+	m.UpdateCounterattacksCalledWith = struct {
+		SpreadsheetID  string
+		WarID          int
+		Counterattacks []app.Counterattack
+	}{}
+	m.UpdateDashboardCalledWith = struct {
+		SpreadsheetID string
+		Rows          []app.WarDashboardRow
+	}{}
+	m.AppendStatusHistoryCalledWith = struct {
+		SpreadsheetID string
+		FactionID     int
+		SnapshotTime  time.Time
+		Records       []app.StatusV2Record
+		MaxRows       int
+	}{}
+	m.AppendCountdownDriftCalledWith = struct {
+		SpreadsheetID string
+		Drifts        []app.CountdownDrift
+	}{}
 	m.ReadSheetCalledWith = struct {
 		SpreadsheetID string
 		Range         string
 	}{}
+	m.AppendStateTransitionCalls = nil
+	m.AppendAlertCalls = nil
+	m.UpdateEnemyOverviewCalls = nil
+	m.UpdateEnemyHospitalCalls = nil
+	m.UpdateTargetPriorityCalls = nil
+	m.AppendEnemyRosterSizeCalls = nil
 }
 
 // Additional state tracking methods
@@ -185,6 +454,10 @@ func (m *MockSheetsClient) AppendRows(ctx context.Context, spreadsheetID, range_
 	return m.AppendRowsError
 }
 
+func (m *MockSheetsClient) DeleteRows(ctx context.Context, spreadsheetID, sheetName string, startRow, count int) error {
+	return m.DeleteRowsError
+}
+
 func (m *MockSheetsClient) CreateSheet(ctx context.Context, spreadsheetID, sheetName string) error {
 	return m.CreateSheetError
 }
@@ -205,3 +478,57 @@ func (m *MockSheetsClient) EnsureStatusV2Sheet(ctx context.Context, spreadsheetI
 func (m *MockSheetsClient) UpdateStatusV2(ctx context.Context, spreadsheetID, sheetName string, records []app.StatusV2Record) error {
 	return m.UpdateStatusV2Error
 }
+
+func (m *MockSheetsClient) AppendStatusHistory(ctx context.Context, spreadsheetID string, factionID int, snapshotTime time.Time, records []app.StatusV2Record, maxRows int) error {
+	m.AppendStatusHistoryCalled = true
+	m.AppendStatusHistoryCalledWith.SpreadsheetID = spreadsheetID
+	m.AppendStatusHistoryCalledWith.FactionID = factionID
+	m.AppendStatusHistoryCalledWith.SnapshotTime = snapshotTime
+	m.AppendStatusHistoryCalledWith.Records = records
+	m.AppendStatusHistoryCalledWith.MaxRows = maxRows
+	return m.AppendStatusHistoryError
+}
+
+func (m *MockSheetsClient) AppendCountdownDrift(ctx context.Context, spreadsheetID string, drifts []app.CountdownDrift) error {
+	m.AppendCountdownDriftCalled = true
+	m.AppendCountdownDriftCalledWith.SpreadsheetID = spreadsheetID
+	m.AppendCountdownDriftCalledWith.Drifts = drifts
+	return m.AppendCountdownDriftError
+}
+
+func (m *MockSheetsClient) AppendAlert(ctx context.Context, spreadsheetID string, alert app.Alert) error {
+	m.AppendAlertCalls = append(m.AppendAlertCalls, alert)
+	return m.AppendAlertError
+}
+
+func (m *MockSheetsClient) UpdateEnemyOverview(ctx context.Context, spreadsheetID string, factionID int, overview app.EnemyOverview) error {
+	if m.UpdateEnemyOverviewCalls == nil {
+		m.UpdateEnemyOverviewCalls = make(map[int]app.EnemyOverview)
+	}
+	m.UpdateEnemyOverviewCalls[factionID] = overview
+	return m.UpdateEnemyOverviewError
+}
+
+func (m *MockSheetsClient) UpdateEnemyHospital(ctx context.Context, spreadsheetID string, factionID int, entries []app.EnemyHospitalEntry) error {
+	if m.UpdateEnemyHospitalCalls == nil {
+		m.UpdateEnemyHospitalCalls = make(map[int][]app.EnemyHospitalEntry)
+	}
+	m.UpdateEnemyHospitalCalls[factionID] = entries
+	return m.UpdateEnemyHospitalError
+}
+
+func (m *MockSheetsClient) UpdateTargetPriority(ctx context.Context, spreadsheetID string, factionID int, entries []app.TargetPriorityEntry) error {
+	if m.UpdateTargetPriorityCalls == nil {
+		m.UpdateTargetPriorityCalls = make(map[int][]app.TargetPriorityEntry)
+	}
+	m.UpdateTargetPriorityCalls[factionID] = entries
+	return m.UpdateTargetPriorityError
+}
+
+func (m *MockSheetsClient) AppendEnemyRosterSize(ctx context.Context, spreadsheetID string, factionID int, timestamp time.Time, memberCount int) error {
+	if m.AppendEnemyRosterSizeCalls == nil {
+		m.AppendEnemyRosterSizeCalls = make(map[int][]int)
+	}
+	m.AppendEnemyRosterSizeCalls[factionID] = append(m.AppendEnemyRosterSizeCalls[factionID], memberCount)
+	return m.AppendEnemyRosterSizeError
+}
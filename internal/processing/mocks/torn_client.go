@@ -2,6 +2,7 @@ package mocks
 
 import (
 	"context"
+	"sync"
 
 	"torn_rw_stats/internal/app"
 )
@@ -10,6 +11,7 @@ import (
 type TornClient interface {
 	GetOwnFaction(ctx context.Context) (*app.FactionInfoResponse, error)
 	GetFactionWars(ctx context.Context) (*app.WarResponse, error)
+	GetFactionWarsByID(ctx context.Context, factionID int) (*app.WarResponse, error)
 	GetFactionAttacks(ctx context.Context, from, to int64) (*app.AttackResponse, error)
 	GetFactionBasic(ctx context.Context, factionID int) (*app.FactionBasicResponse, error)
 	GetAPICallCount() int64
@@ -17,8 +19,11 @@ type TornClient interface {
 	ResetAPICallCount()
 }
 
-// MockTornClient is a test double for the torn.Client
+// MockTornClient is a test double for the torn.Client. Its methods are safe to call
+// from concurrent goroutines; tests read the tracked calls once processing is done.
 type MockTornClient struct {
+	mu sync.Mutex
+
 	// Responses to return
 	OwnFactionResponse     *app.FactionInfoResponse
 	FactionWarsResponse    *app.WarResponse
@@ -26,11 +31,18 @@ type MockTornClient struct {
 	FactionBasicResponse   *app.FactionBasicResponse
 	APICallCount           int64
 
+	// Per-faction war responses for GetFactionWarsByID
+	FactionWarsByIDResponses map[int]*app.WarResponse
+
+	// Per-faction responses for GetFactionBasic; FactionBasicResponse is used for others
+	FactionBasicResponses map[int]*app.FactionBasicResponse
+
 	// Errors to return
-	OwnFactionError     error
-	FactionWarsError    error
-	FactionAttacksError error
-	FactionBasicError   error
+	OwnFactionError      error
+	FactionWarsError     error
+	FactionAttacksError  error
+	FactionBasicError    error
+	FactionWarsByIDError error
 
 	// Call tracking
 	GetOwnFactionCalled         bool
@@ -38,6 +50,8 @@ type MockTornClient struct {
 	GetFactionAttacksCalled     bool
 	GetFactionBasicCalled       bool
 	GetFactionBasicCalledWithID int
+	GetFactionBasicCalls        []int
+	GetFactionWarsByIDCalls     []int
 	GetFactionAttacksCalledWith struct {
 		From int64
 		To   int64
@@ -50,16 +64,39 @@ func NewMockTornClient() *MockTornClient {
 }
 
 func (m *MockTornClient) GetOwnFaction(ctx context.Context) (*app.FactionInfoResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	m.GetOwnFactionCalled = true
 	return m.OwnFactionResponse, m.OwnFactionError
 }
 
 func (m *MockTornClient) GetFactionWars(ctx context.Context) (*app.WarResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	m.GetFactionWarsCalled = true
 	return m.FactionWarsResponse, m.FactionWarsError
 }
 
+func (m *MockTornClient) GetFactionWarsByID(ctx context.Context, factionID int) (*app.WarResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.GetFactionWarsByIDCalls = append(m.GetFactionWarsByIDCalls, factionID)
+	if m.FactionWarsByIDError != nil {
+		return nil, m.FactionWarsByIDError
+	}
+	if response, ok := m.FactionWarsByIDResponses[factionID]; ok {
+		return response, nil
+	}
+	return &app.WarResponse{}, nil
+}
+
 func (m *MockTornClient) GetFactionAttacks(ctx context.Context, from, to int64) (*app.AttackResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	m.GetFactionAttacksCalled = true
 	m.GetFactionAttacksCalledWith.From = from
 	m.GetFactionAttacksCalledWith.To = to
@@ -67,41 +104,65 @@ func (m *MockTornClient) GetFactionAttacks(ctx context.Context, from, to int64)
 }
 
 func (m *MockTornClient) GetFactionBasic(ctx context.Context, factionID int) (*app.FactionBasicResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	m.GetFactionBasicCalled = true
 	m.GetFactionBasicCalledWithID = factionID
+	m.GetFactionBasicCalls = append(m.GetFactionBasicCalls, factionID)
+	if response, ok := m.FactionBasicResponses[factionID]; ok && m.FactionBasicError == nil {
+		return response, nil
+	}
 	return m.FactionBasicResponse, m.FactionBasicError
 }
 
 func (m *MockTornClient) GetAPICallCount() int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	return m.APICallCount
 }
 
 func (m *MockTornClient) IncrementAPICall() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	m.APICallCount++
 }
 
 func (m *MockTornClient) ResetAPICallCount() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	m.APICallCount = 0
 }
 
 // Reset clears all call tracking and responses
 func (m *MockTornClient) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	m.OwnFactionResponse = nil
 	m.FactionWarsResponse = nil
 	m.FactionAttacksResponse = nil
 	m.FactionBasicResponse = nil
+	m.FactionWarsByIDResponses = nil
+	m.FactionBasicResponses = nil
 	m.APICallCount = 0
 
 	m.OwnFactionError = nil
 	m.FactionWarsError = nil
 	m.FactionAttacksError = nil
 	m.FactionBasicError = nil
+	m.FactionWarsByIDError = nil
 
 	m.GetOwnFactionCalled = false
 	m.GetFactionWarsCalled = false
 	m.GetFactionAttacksCalled = false
 	m.GetFactionBasicCalled = false
 	m.GetFactionBasicCalledWithID = 0
+	m.GetFactionBasicCalls = nil
+	m.GetFactionWarsByIDCalls = nil
 	m.GetFactionAttacksCalledWith = struct {
 		From int64
 		To   int64
@@ -12,19 +12,25 @@ import (
 type StateRecordComparator struct {
 	hospitalRegex *regexp.Regexp
 	jailRegex     *regexp.Regexp
+	articleRegex  *regexp.Regexp
 }
 
 // NewStateRecordComparator creates a new StateRecord comparator
 func NewStateRecordComparator() *StateRecordComparator {
 	// Compile hospital regex once for reuse (copied from existing logic)
-	hospitalRegex := regexp.MustCompile(`(?i)^in\s+(a\s+[\w\s]+\s+)?hospital(\s+for\s+.*)?$`)
+	// Accepts "a", "an" and "the" before the location qualifier, e.g. "In an Argentinian hospital"
+	hospitalRegex := regexp.MustCompile(`(?i)^in\s+((a|an|the)\s+[\w\s]+\s+)?hospital(\s+for\s+.*)?$`)
 
 	// Compile jail regex to handle jail countdown variations
 	jailRegex := regexp.MustCompile(`(?i)^in\s+jail\s+for\s+.*$`)
 
+	// Compile article regex to strip leading articles from locations, e.g. "In the United Kingdom"
+	articleRegex := regexp.MustCompile(`(?i)^(in|traveling to|returning to torn from)\s+(?:a|an|the)\s+`)
+
 	return &StateRecordComparator{
 		hospitalRegex: hospitalRegex,
 		jailRegex:     jailRegex,
+		articleRegex:  articleRegex,
 	}
 }
 
@@ -84,15 +90,16 @@ func (c *StateRecordComparator) HasStateChanged(previous, current app.StateRecor
 	return false
 }
 
-// GetLatestStateByMember finds the most recent StateRecord for each member from a collection
+// GetLatestStateByMember finds the most recent StateRecord for each member from a collection.
+// Records are appended in order, so of two records with the same timestamp the later wins.
 func (c *StateRecordComparator) GetLatestStateByMember(records []app.StateRecord) map[string]app.StateRecord {
 	latestByMember := make(map[string]app.StateRecord)
 
 	for _, record := range records {
 		existing, exists := latestByMember[record.MemberID]
 
-		// If no existing record or this record is newer, update
-		if !exists || record.Timestamp.After(existing.Timestamp) {
+		// If no existing record or this record is not older, update
+		if !exists || !record.Timestamp.Before(existing.Timestamp) {
 			latestByMember[record.MemberID] = record
 		}
 	}
@@ -116,7 +123,8 @@ func (c *StateRecordComparator) CreatePreviousStateCollection(currentStates []ap
 	return result
 }
 
-// normalizeStatusDescription removes countdown from hospital and jail descriptions for comparison
+// normalizeStatusDescription removes countdown from hospital and jail descriptions and
+// leading articles from locations for comparison
 // Prevents noise from countdown timer changes in Changed States tracking
 func (c *StateRecordComparator) normalizeStatusDescription(description string) string {
 	// Hospital normalization (copied from existing logic in state_change_service.go)
@@ -129,7 +137,8 @@ func (c *StateRecordComparator) normalizeStatusDescription(description string) s
 		return "In jail"
 	}
 
-	return description
+	// Location normalization - "In the United Kingdom" and "In United Kingdom" are the same place
+	return c.articleRegex.ReplaceAllString(description, "$1 ")
 }
 
 // hasStatusUntilChanged determines if StatusUntil represents a meaningful change
@@ -2,6 +2,9 @@ package processing
 
 import (
 	"testing"
+	"time"
+
+	"torn_rw_stats/internal/app"
 )
 
 func TestStateRecordComparator_normalizeStatusDescription(t *testing.T) {
@@ -22,6 +25,21 @@ func TestStateRecordComparator_normalizeStatusDescription(t *testing.T) {
 			description: "In a private hospital for 2 hours 30 minutes",
 			expected:    "In hospital",
 		},
+		{
+			name:        "hospital with an article",
+			description: "In an Argentinian hospital for 12 mins ",
+			expected:    "In hospital",
+		},
+		{
+			name:        "hospital with the article",
+			description: "In the United Kingdom hospital for 1 hr 3 mins ",
+			expected:    "In hospital",
+		},
+		{
+			name:        "hospital with capitalized article",
+			description: "In The United Kingdom hospital for 58 mins ",
+			expected:    "In hospital",
+		},
 		{
 			name:        "jail with hrs format",
 			description: "In jail for 4 hrs 14 mins ",
@@ -47,6 +65,26 @@ func TestStateRecordComparator_normalizeStatusDescription(t *testing.T) {
 			description: "Traveling to Japan",
 			expected:    "Traveling to Japan",
 		},
+		{
+			name:        "location with the article",
+			description: "In the United Kingdom",
+			expected:    "In United Kingdom",
+		},
+		{
+			name:        "traveling with the article",
+			description: "Traveling to the United Kingdom",
+			expected:    "Traveling to United Kingdom",
+		},
+		{
+			name:        "returning with the article",
+			description: "Returning to Torn from the United Kingdom",
+			expected:    "Returning to Torn from United Kingdom",
+		},
+		{
+			name:        "article inside a location name is kept",
+			description: "In Cayman Islands",
+			expected:    "In Cayman Islands",
+		},
 	}
 
 	for _, tt := range tests {
@@ -58,3 +96,32 @@ func TestStateRecordComparator_normalizeStatusDescription(t *testing.T) {
 		})
 	}
 }
+
+func TestStateRecordComparator_FindChangedStates_HospitalArticles(t *testing.T) {
+	comparator := NewStateRecordComparator()
+	until := time.Date(2025, 9, 18, 14, 0, 0, 0, time.UTC)
+
+	previous := []app.StateRecord{
+		{MemberID: "1", MemberName: "Patient", StatusState: "Hospital", StatusDescription: "In the United Kingdom hospital for 1 hr 3 mins ", StatusUntil: until},
+		{MemberID: "2", MemberName: "Visitor", StatusState: "Abroad", StatusDescription: "In the United Kingdom"},
+		{MemberID: "3", MemberName: "Recovering", StatusState: "Hospital", StatusDescription: "In the United Kingdom hospital for 2 mins ", StatusUntil: until},
+	}
+
+	current := []app.StateRecord{
+		// Countdown decremented - not a transition
+		{MemberID: "1", MemberName: "Patient", StatusState: "Hospital", StatusDescription: "In the United Kingdom hospital for 58 mins ", StatusUntil: until},
+		// Same location described without the article - not a transition
+		{MemberID: "2", MemberName: "Visitor", StatusState: "Abroad", StatusDescription: "In United Kingdom"},
+		// Genuine transition out of hospital
+		{MemberID: "3", MemberName: "Recovering", StatusState: "Abroad", StatusDescription: "In the United Kingdom"},
+	}
+
+	changed := comparator.FindChangedStates(current, previous)
+
+	if len(changed) != 1 {
+		t.Fatalf("expected 1 changed state, got %d: %+v", len(changed), changed)
+	}
+	if changed[0].MemberID != "3" {
+		t.Errorf("expected member 3 to be recorded, got %s", changed[0].MemberID)
+	}
+}
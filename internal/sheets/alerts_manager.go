@@ -0,0 +1,74 @@
+package sheets
+
+import (
+	"context"
+	"fmt"
+
+	"torn_rw_stats/internal/app"
+
+	"github.com/rs/zerolog/log"
+)
+
+// AlertsSheetName is the sheet collecting tactical alerts for all wars
+const AlertsSheetName = "Alerts"
+
+// AlertsManager handles the sheet of tactical alerts
+type AlertsManager struct {
+	api SheetsAPI
+}
+
+// NewAlertsManager creates a new alerts manager with the given API client
+func NewAlertsManager(api SheetsAPI) *AlertsManager {
+	return &AlertsManager{
+		api: api,
+	}
+}
+
+// GenerateAlertsHeaders creates the headers for the alerts sheet
+func (m *AlertsManager) GenerateAlertsHeaders() [][]interface{} {
+	return [][]interface{}{
+		{
+			"Timestamp",
+			"War ID",
+			"Alert",
+			"Message",
+		},
+	}
+}
+
+// AppendAlert appends an alert row, creating the sheet if needed
+func (m *AlertsManager) AppendAlert(ctx context.Context, spreadsheetID string, alert app.Alert) error {
+	exists, err := m.api.SheetExists(ctx, spreadsheetID, AlertsSheetName)
+	if err != nil {
+		return fmt.Errorf("failed to check if alerts sheet exists: %w", err)
+	}
+
+	if !exists {
+		log.Info().
+			Str("sheet_name", AlertsSheetName).
+			Msg("Creating alerts sheet")
+
+		if err := m.api.CreateSheet(ctx, spreadsheetID, AlertsSheetName); err != nil {
+			return fmt.Errorf("failed to create alerts sheet: %w", err)
+		}
+
+		rangeSpec := fmt.Sprintf("'%s'!A1", AlertsSheetName)
+		if err := m.api.UpdateRange(ctx, spreadsheetID, rangeSpec, m.GenerateAlertsHeaders()); err != nil {
+			return fmt.Errorf("failed to write alerts headers: %w", err)
+		}
+	}
+
+	row := []interface{}{
+		alert.Timestamp.UTC().Format("2006-01-02 15:04:05"),
+		alert.WarID,
+		alert.Type,
+		alert.Message,
+	}
+
+	rangeSpec := fmt.Sprintf("'%s'!A:D", AlertsSheetName)
+	if err := m.api.AppendRows(ctx, spreadsheetID, rangeSpec, [][]interface{}{row}); err != nil {
+		return fmt.Errorf("failed to append alert: %w", err)
+	}
+
+	return nil
+}
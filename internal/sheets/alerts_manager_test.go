@@ -0,0 +1,38 @@
+package sheets
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"torn_rw_stats/internal/app"
+)
+
+func TestAlertsManagerAppendAlert(t *testing.T) {
+	mockAPI := NewMockSheetsAPI()
+	manager := NewAlertsManager(mockAPI)
+
+	alert := app.Alert{
+		Timestamp: time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC),
+		WarID:     777,
+		Type:      app.AlertEnemyChainBroken,
+		Message:   "Enemy chain broken: 150 -> 0",
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := manager.AppendAlert(context.Background(), "test-sheet-id", alert); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	rows := mockAPI.GetSheetData(AlertsSheetName)
+	if len(rows) != 3 {
+		t.Fatalf("Expected header plus 2 alert rows, got %d rows", len(rows))
+	}
+	if rows[0][2] != "Alert" {
+		t.Errorf("Unexpected header row: %v", rows[0])
+	}
+	if rows[2][0] != "2026-01-02 12:00:00" || rows[2][1] != 777 || rows[2][2] != app.AlertEnemyChainBroken {
+		t.Errorf("Unexpected alert row: %v", rows[2])
+	}
+}
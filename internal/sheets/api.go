@@ -31,6 +31,10 @@ type SheetsAPI interface {
 	// Accepts [][]interface{} as required by Google Sheets API.
 	AppendRows(ctx context.Context, spreadsheetID, range_ string, rows [][]interface{}) error
 
+	// DeleteRows deletes count rows from a sheet starting at the 1-based row startRow,
+	// shifting the rows below up
+	DeleteRows(ctx context.Context, spreadsheetID, sheetName string, startRow, count int) error
+
 	// CreateSheet creates a new sheet in the spreadsheet
 	CreateSheet(ctx context.Context, spreadsheetID, sheetName string) error
 
@@ -43,3 +47,10 @@ type SheetsAPI interface {
 	// FormatStatusSheet applies formatting to a status sheet
 	FormatStatusSheet(ctx context.Context, spreadsheetID, sheetName string) error
 }
+
+// RowsAPI is the subset of SheetsAPI needed to count and delete a sheet's rows,
+// letting callers that only hold a narrower client share helpers such as PruneOldestRows
+type RowsAPI interface {
+	ReadSheet(ctx context.Context, spreadsheetID, range_ string) ([][]interface{}, error)
+	DeleteRows(ctx context.Context, spreadsheetID, sheetName string, startRow, count int) error
+}
@@ -80,6 +80,38 @@ func (c Cell) Int64Ptr() *int64 {
 	return &i
 }
 
+// Float64 returns the cell value as a float64
+func (c Cell) Float64() float64 {
+	if c.raw == nil {
+		return 0
+	}
+	switch v := c.raw.(type) {
+	case float64:
+		return v
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case string:
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return f
+		}
+	}
+	return 0
+}
+
+// Bool returns the cell value as a bool, accepting Sheets' TRUE/FALSE text
+func (c Cell) Bool() bool {
+	switch v := c.raw.(type) {
+	case bool:
+		return v
+	case string:
+		b, _ := strconv.ParseBool(v)
+		return b
+	}
+	return false
+}
+
 // IsEmpty returns true if the cell contains nil or empty string
 func (c Cell) IsEmpty() bool {
 	return c.raw == nil || c.raw == ""
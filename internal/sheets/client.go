@@ -3,6 +3,9 @@ package sheets
 import (
 	"context"
 	"fmt"
+	"time"
+
+	"torn_rw_stats/internal/config"
 
 	"github.com/rs/zerolog/log"
 	"google.golang.org/api/option"
@@ -21,7 +24,12 @@ const (
 // This is the only layer where interface{} should appear. All other code should
 // use the Cell type wrapper for type-safe access to cell values.
 type Client struct {
-	service *sheets.Service
+	service              *sheets.Service
+	respectDecimalPlaces int            // decimal places for respect in attack record cells
+	recordsColumnOrder   []string       // custom records sheet column order, empty for the default
+	displayLocation      *time.Location // timezone attack record dates and times are written in
+	writeBatchSize       int            // attack record rows written per request
+	quotaRetry           config.RetryConfig
 }
 
 // NewClient creates a new Google Sheets client with the provided credentials
@@ -32,15 +40,56 @@ func NewClient(ctx context.Context, credentialsFile string) (*Client, error) {
 	}
 
 	return &Client{
-		service: service,
+		service:              service,
+		respectDecimalPlaces: DefaultRespectDecimalPlaces,
+		displayLocation:      time.UTC,
+		writeBatchSize:       DefaultSheetWriteBatchSize,
+		quotaRetry:           config.DefaultResilienceConfig.SheetQuota,
 	}, nil
 }
 
+// SetRespectDecimalPlaces sets how many decimal places respect is written with in
+// attack records, clamped to 0-4
+func (c *Client) SetRespectDecimalPlaces(places int) {
+	c.respectDecimalPlaces = clampRespectDecimalPlaces(places)
+}
+
+// SetRecordsColumnOrder sets the order and subset of columns written to attack records
+// sheets, returning an error when the order is invalid. An empty order keeps the default.
+func (c *Client) SetRecordsColumnOrder(order []string) error {
+	if err := ValidateRecordsColumnOrder(order); err != nil {
+		return err
+	}
+	c.recordsColumnOrder = order
+	return nil
+}
+
+// SetDisplayLocation sets the timezone attack record dates and times are written in;
+// nil keeps UTC
+func (c *Client) SetDisplayLocation(location *time.Location) {
+	if location != nil {
+		c.displayLocation = location
+	}
+}
+
+// SetSheetWriteBatchSize sets how many attack record rows are written per request;
+// a non-positive size keeps the default
+func (c *Client) SetSheetWriteBatchSize(size int) {
+	if size > 0 {
+		c.writeBatchSize = size
+	}
+}
+
 // ReadSheet reads values from the specified sheet range.
 // Returns [][]interface{} as mandated by Google Sheets API.
 // Wrap returned values with NewCell() for type-safe access.
 func (c *Client) ReadSheet(ctx context.Context, spreadsheetID, range_ string) ([][]interface{}, error) {
-	resp, err := c.service.Spreadsheets.Values.Get(spreadsheetID, range_).Context(ctx).Do()
+	var resp *sheets.ValueRange
+	err := retryOnQuota(ctx, c.quotaRetry, "read sheet", func() error {
+		var err error
+		resp, err = c.service.Spreadsheets.Values.Get(spreadsheetID, range_).Context(ctx).Do()
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to read sheet: %w", err)
 	}
@@ -55,10 +104,13 @@ func (c *Client) UpdateRange(ctx context.Context, spreadsheetID, range_ string,
 		Values: values,
 	}
 
-	_, err := c.service.Spreadsheets.Values.Update(spreadsheetID, range_, valueRange).
-		ValueInputOption("USER_ENTERED").
-		Context(ctx).
-		Do()
+	err := retryOnQuota(ctx, c.quotaRetry, "update range", func() error {
+		_, err := c.service.Spreadsheets.Values.Update(spreadsheetID, range_, valueRange).
+			ValueInputOption("USER_ENTERED").
+			Context(ctx).
+			Do()
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("failed to update range: %w", err)
 	}
@@ -68,9 +120,12 @@ func (c *Client) UpdateRange(ctx context.Context, spreadsheetID, range_ string,
 
 // ClearRange clears all values in the specified sheet range
 func (c *Client) ClearRange(ctx context.Context, spreadsheetID, range_ string) error {
-	_, err := c.service.Spreadsheets.Values.Clear(spreadsheetID, range_, &sheets.ClearValuesRequest{}).
-		Context(ctx).
-		Do()
+	err := retryOnQuota(ctx, c.quotaRetry, "clear range", func() error {
+		_, err := c.service.Spreadsheets.Values.Clear(spreadsheetID, range_, &sheets.ClearValuesRequest{}).
+			Context(ctx).
+			Do()
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("failed to clear range: %w", err)
 	}
@@ -85,11 +140,15 @@ func (c *Client) AppendRows(ctx context.Context, spreadsheetID, range_ string, r
 		Values: rows,
 	}
 
-	_, err := c.service.Spreadsheets.Values.Append(spreadsheetID, range_, valueRange).
-		ValueInputOption("USER_ENTERED").
-		InsertDataOption("INSERT_ROWS").
-		Context(ctx).
-		Do()
+	// A quota error means the append was rejected, so retrying cannot duplicate rows
+	err := retryOnQuota(ctx, c.quotaRetry, "append rows", func() error {
+		_, err := c.service.Spreadsheets.Values.Append(spreadsheetID, range_, valueRange).
+			ValueInputOption("USER_ENTERED").
+			InsertDataOption("INSERT_ROWS").
+			Context(ctx).
+			Do()
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("failed to append rows: %w", err)
 	}
@@ -111,9 +170,12 @@ func (c *Client) CreateSheet(ctx context.Context, spreadsheetID, sheetName strin
 		Requests: []*sheets.Request{req},
 	}
 
-	_, err := c.service.Spreadsheets.BatchUpdate(spreadsheetID, batchUpdate).
-		Context(ctx).
-		Do()
+	err := retryOnQuota(ctx, c.quotaRetry, "create sheet", func() error {
+		_, err := c.service.Spreadsheets.BatchUpdate(spreadsheetID, batchUpdate).
+			Context(ctx).
+			Do()
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("failed to create sheet %s: %w", sheetName, err)
 	}
@@ -121,9 +183,68 @@ func (c *Client) CreateSheet(ctx context.Context, spreadsheetID, sheetName strin
 	return nil
 }
 
+// DeleteRows deletes count rows starting at the 1-based row startRow with a single
+// deleteDimension request, so the rows below shift up without being rewritten
+func (c *Client) DeleteRows(ctx context.Context, spreadsheetID, sheetName string, startRow, count int) error {
+	spreadsheet, err := c.getSpreadsheet(ctx, spreadsheetID)
+	if err != nil {
+		return fmt.Errorf("failed to get spreadsheet: %w", err)
+	}
+
+	var sheetID *int64
+	for _, sheet := range spreadsheet.Sheets {
+		if sheet.Properties.Title == sheetName {
+			sheetID = &sheet.Properties.SheetId
+			break
+		}
+	}
+	if sheetID == nil {
+		return fmt.Errorf("sheet %s not found", sheetName)
+	}
+
+	req := &sheets.Request{
+		DeleteDimension: &sheets.DeleteDimensionRequest{
+			Range: &sheets.DimensionRange{
+				SheetId:         *sheetID,
+				Dimension:       "ROWS",
+				StartIndex:      int64(startRow - 1),
+				EndIndex:        int64(startRow - 1 + count),
+				ForceSendFields: []string{"SheetId", "StartIndex"},
+			},
+		},
+	}
+
+	batchUpdate := &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{req},
+	}
+
+	err = retryOnQuota(ctx, c.quotaRetry, "delete rows", func() error {
+		_, err := c.service.Spreadsheets.BatchUpdate(spreadsheetID, batchUpdate).
+			Context(ctx).
+			Do()
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete rows from sheet %s: %w", sheetName, err)
+	}
+
+	return nil
+}
+
+// getSpreadsheet fetches the spreadsheet's metadata, retrying on quota errors
+func (c *Client) getSpreadsheet(ctx context.Context, spreadsheetID string) (*sheets.Spreadsheet, error) {
+	var spreadsheet *sheets.Spreadsheet
+	err := retryOnQuota(ctx, c.quotaRetry, "get spreadsheet", func() error {
+		var err error
+		spreadsheet, err = c.service.Spreadsheets.Get(spreadsheetID).Context(ctx).Do()
+		return err
+	})
+	return spreadsheet, err
+}
+
 // SheetExists checks if a sheet with the given name exists in the spreadsheet
 func (c *Client) SheetExists(ctx context.Context, spreadsheetID, sheetName string) (bool, error) {
-	spreadsheet, err := c.service.Spreadsheets.Get(spreadsheetID).Context(ctx).Do()
+	spreadsheet, err := c.getSpreadsheet(ctx, spreadsheetID)
 	if err != nil {
 		return false, fmt.Errorf("failed to get spreadsheet: %w", err)
 	}
@@ -140,7 +261,7 @@ func (c *Client) SheetExists(ctx context.Context, spreadsheetID, sheetName strin
 // EnsureSheetCapacity ensures the sheet has at least the required number of rows and columns.
 // Automatically adds a buffer for future growth.
 func (c *Client) EnsureSheetCapacity(ctx context.Context, spreadsheetID, sheetName string, requiredRows, requiredCols int) error {
-	spreadsheet, err := c.service.Spreadsheets.Get(spreadsheetID).Context(ctx).Do()
+	spreadsheet, err := c.getSpreadsheet(ctx, spreadsheetID)
 	if err != nil {
 		return fmt.Errorf("failed to get spreadsheet: %w", err)
 	}
@@ -205,9 +326,12 @@ func (c *Client) EnsureSheetCapacity(ctx context.Context, spreadsheetID, sheetNa
 		Requests: []*sheets.Request{req},
 	}
 
-	_, err = c.service.Spreadsheets.BatchUpdate(spreadsheetID, batchUpdate).
-		Context(ctx).
-		Do()
+	err = retryOnQuota(ctx, c.quotaRetry, "resize sheet", func() error {
+		_, err := c.service.Spreadsheets.BatchUpdate(spreadsheetID, batchUpdate).
+			Context(ctx).
+			Do()
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("failed to resize sheet %s: %w", sheetName, err)
 	}
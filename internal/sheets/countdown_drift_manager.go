@@ -0,0 +1,97 @@
+package sheets
+
+import (
+	"context"
+	"fmt"
+
+	"torn_rw_stats/internal/app"
+
+	"github.com/rs/zerolog/log"
+)
+
+// CountdownDriftSheetName is the sheet collecting travel countdown drift for all factions
+const CountdownDriftSheetName = "Countdown Drift"
+
+// CountdownDriftManager handles the sheet of travel countdown drift measurements
+type CountdownDriftManager struct {
+	api SheetsAPI
+}
+
+// NewCountdownDriftManager creates a new countdown drift manager with the given API client
+func NewCountdownDriftManager(api SheetsAPI) *CountdownDriftManager {
+	return &CountdownDriftManager{
+		api: api,
+	}
+}
+
+// GenerateCountdownDriftHeaders creates the headers for the countdown drift sheet
+func (m *CountdownDriftManager) GenerateCountdownDriftHeaders() [][]interface{} {
+	return [][]interface{}{
+		{
+			"Timestamp",
+			"Faction ID",
+			"Member ID",
+			"Member Name",
+			"Location",
+			"Original Countdown",
+			"Calculated Countdown",
+			"Drift (s)",
+		},
+	}
+}
+
+// AppendCountdownDrift appends one row per drift measurement, creating the sheet if needed
+func (m *CountdownDriftManager) AppendCountdownDrift(ctx context.Context, spreadsheetID string, drifts []app.CountdownDrift) error {
+	if len(drifts) == 0 {
+		return nil
+	}
+
+	exists, err := m.api.SheetExists(ctx, spreadsheetID, CountdownDriftSheetName)
+	if err != nil {
+		return fmt.Errorf("failed to check if countdown drift sheet exists: %w", err)
+	}
+
+	if !exists {
+		log.Info().
+			Str("sheet_name", CountdownDriftSheetName).
+			Msg("Creating countdown drift sheet")
+
+		if err := m.api.CreateSheet(ctx, spreadsheetID, CountdownDriftSheetName); err != nil {
+			return fmt.Errorf("failed to create countdown drift sheet: %w", err)
+		}
+
+		rangeSpec := fmt.Sprintf("'%s'!A1", CountdownDriftSheetName)
+		if err := m.api.UpdateRange(ctx, spreadsheetID, rangeSpec, m.GenerateCountdownDriftHeaders()); err != nil {
+			return fmt.Errorf("failed to write countdown drift headers: %w", err)
+		}
+	}
+
+	rangeSpec := fmt.Sprintf("'%s'!A:H", CountdownDriftSheetName)
+	if err := m.api.AppendRows(ctx, spreadsheetID, rangeSpec, m.ConvertCountdownDriftToRows(drifts)); err != nil {
+		return fmt.Errorf("failed to append countdown drift: %w", err)
+	}
+
+	log.Debug().
+		Int("rows", len(drifts)).
+		Msg("Appended countdown drift rows")
+
+	return nil
+}
+
+// ConvertCountdownDriftToRows converts drift measurements to sheet rows
+func (m *CountdownDriftManager) ConvertCountdownDriftToRows(drifts []app.CountdownDrift) [][]interface{} {
+	rows := make([][]interface{}, len(drifts))
+	for i, drift := range drifts {
+		rows[i] = []interface{}{
+			drift.Timestamp.UTC().Format("2006-01-02 15:04:05"),
+			drift.FactionID,
+			drift.MemberID,
+			drift.MemberName,
+			drift.Location,
+			drift.OriginalCountdown,
+			drift.CalculatedCountdown,
+			drift.DriftSeconds,
+		}
+	}
+	return rows
+}
@@ -0,0 +1,44 @@
+package sheets
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"torn_rw_stats/internal/app"
+)
+
+func TestCountdownDriftManagerAppendCountdownDrift(t *testing.T) {
+	mockAPI := NewMockSheetsAPI()
+	manager := NewCountdownDriftManager(mockAPI)
+
+	drifts := []app.CountdownDrift{
+		{
+			Timestamp:           time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC),
+			FactionID:           200,
+			MemberID:            "1",
+			MemberName:          "Enemy1",
+			Location:            "Mexico",
+			OriginalCountdown:   "0:20:00",
+			CalculatedCountdown: "'00:21:30",
+			DriftSeconds:        90,
+		},
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := manager.AppendCountdownDrift(context.Background(), "test-sheet-id", drifts); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	rows := mockAPI.GetSheetData(CountdownDriftSheetName)
+	if len(rows) != 3 {
+		t.Fatalf("Expected header plus 2 drift rows, got %d rows", len(rows))
+	}
+	if rows[0][7] != "Drift (s)" {
+		t.Errorf("Unexpected header row: %v", rows[0])
+	}
+	if rows[2][0] != "2026-01-02 12:00:00" || rows[2][4] != "Mexico" || rows[2][7] != 90 {
+		t.Errorf("Unexpected drift row: %v", rows[2])
+	}
+}
@@ -0,0 +1,79 @@
+package sheets
+
+import (
+	"context"
+	"fmt"
+
+	"torn_rw_stats/internal/app"
+
+	"github.com/rs/zerolog/log"
+)
+
+// CounterattacksManager handles the per-war counterattacks sheets
+type CounterattacksManager struct {
+	api SheetsAPI
+}
+
+// NewCounterattacksManager creates a new counterattacks manager with the given API client
+func NewCounterattacksManager(api SheetsAPI) *CounterattacksManager {
+	return &CounterattacksManager{
+		api: api,
+	}
+}
+
+// GenerateCounterattacksTabName creates a standardized counterattacks tab name for a war
+func (m *CounterattacksManager) GenerateCounterattacksTabName(warID int) string {
+	return fmt.Sprintf("Counterattacks - %d", warID)
+}
+
+// GenerateCounterattacksHeaders creates the headers for counterattacks sheets
+func (m *CounterattacksManager) GenerateCounterattacksHeaders() [][]interface{} {
+	return [][]interface{}{
+		{
+			"Member Name",
+			"Opponent Name",
+			"Attacked At",
+			"Attack Result",
+			"Counterattacked At",
+			"Counterattack Result",
+			"Retaliation Modifier",
+		},
+	}
+}
+
+// UpdateCounterattacks rewrites the counterattacks sheet for a war, creating it if needed
+func (m *CounterattacksManager) UpdateCounterattacks(ctx context.Context, spreadsheetID string, warID int, counterattacks []app.Counterattack) error {
+	sheetName := m.GenerateCounterattacksTabName(warID)
+
+	rows := m.ConvertCounterattacksToRows(counterattacks)
+	if err := rewritePerWarSheet(ctx, m.api, spreadsheetID, sheetName, m.GenerateCounterattacksHeaders(), rows); err != nil {
+		return fmt.Errorf("failed to update counterattacks: %w", err)
+	}
+
+	log.Debug().
+		Int("war_id", warID).
+		Str("sheet_name", sheetName).
+		Int("counterattacks", len(rows)).
+		Msg("Updated counterattacks sheet")
+
+	return nil
+}
+
+// ConvertCounterattacksToRows converts counterattacks into spreadsheet row format
+func (m *CounterattacksManager) ConvertCounterattacksToRows(counterattacks []app.Counterattack) [][]interface{} {
+	rows := make([][]interface{}, len(counterattacks))
+
+	for i, counterattack := range counterattacks {
+		rows[i] = []interface{}{
+			counterattack.MemberName,
+			counterattack.OpponentName,
+			counterattack.AttackedAt.UTC().Format("2006-01-02 15:04:05"),
+			counterattack.AttackResult,
+			counterattack.CounterattackedAt.UTC().Format("2006-01-02 15:04:05"),
+			counterattack.CounterattackResult,
+			fmt.Sprintf("%.2f", counterattack.RetaliationModifier),
+		}
+	}
+
+	return rows
+}
@@ -0,0 +1,100 @@
+package sheets
+
+import (
+	"context"
+	"fmt"
+
+	"torn_rw_stats/internal/app"
+
+	"github.com/rs/zerolog/log"
+)
+
+// DashboardSheetName is the single sheet summarizing every active war
+const DashboardSheetName = "Dashboard"
+
+// DashboardManager handles the combined dashboard sheet for all active wars
+type DashboardManager struct {
+	api SheetsAPI
+}
+
+// NewDashboardManager creates a new dashboard manager with the given API client
+func NewDashboardManager(api SheetsAPI) *DashboardManager {
+	return &DashboardManager{
+		api: api,
+	}
+}
+
+// GenerateDashboardHeaders creates the headers for the dashboard sheet
+func (m *DashboardManager) GenerateDashboardHeaders() []interface{} {
+	return []interface{}{
+		"War ID",
+		"Type",
+		"Enemy",
+		"Our Score",
+		"Enemy Score",
+		"Attacks Won",
+		"Attacks Lost",
+		"Status",
+	}
+}
+
+// UpdateDashboard clears the dashboard sheet and rewrites it with one row per war,
+// creating the sheet if needed. Wars that are no longer active drop off each cycle.
+func (m *DashboardManager) UpdateDashboard(ctx context.Context, spreadsheetID string, rows []app.WarDashboardRow) error {
+	exists, err := m.api.SheetExists(ctx, spreadsheetID, DashboardSheetName)
+	if err != nil {
+		return fmt.Errorf("failed to check if dashboard sheet exists: %w", err)
+	}
+
+	if !exists {
+		log.Info().
+			Str("sheet_name", DashboardSheetName).
+			Msg("Creating dashboard sheet")
+
+		if err := m.api.CreateSheet(ctx, spreadsheetID, DashboardSheetName); err != nil {
+			return fmt.Errorf("failed to create dashboard sheet: %w", err)
+		}
+	}
+
+	if err := m.api.ClearRange(ctx, spreadsheetID, fmt.Sprintf("'%s'!A:H", DashboardSheetName)); err != nil {
+		return fmt.Errorf("failed to clear dashboard: %w", err)
+	}
+
+	values := append([][]interface{}{m.GenerateDashboardHeaders()}, m.ConvertDashboardToRows(rows)...)
+
+	if err := m.api.EnsureSheetCapacity(ctx, spreadsheetID, DashboardSheetName, len(values), 8); err != nil {
+		return fmt.Errorf("failed to ensure sheet capacity: %w", err)
+	}
+
+	rangeSpec := fmt.Sprintf("'%s'!A1:H%d", DashboardSheetName, len(values))
+	if err := m.api.UpdateRange(ctx, spreadsheetID, rangeSpec, values); err != nil {
+		return fmt.Errorf("failed to update dashboard: %w", err)
+	}
+
+	log.Debug().
+		Str("sheet_name", DashboardSheetName).
+		Int("wars", len(rows)).
+		Msg("Updated dashboard sheet")
+
+	return nil
+}
+
+// ConvertDashboardToRows converts dashboard rows into spreadsheet row format
+func (m *DashboardManager) ConvertDashboardToRows(rows []app.WarDashboardRow) [][]interface{} {
+	values := make([][]interface{}, len(rows))
+
+	for i, row := range rows {
+		values[i] = []interface{}{
+			row.WarID,
+			row.WarType,
+			row.EnemyName,
+			row.OurScore,
+			row.EnemyScore,
+			row.AttacksWon,
+			row.AttacksLost,
+			row.Status,
+		}
+	}
+
+	return values
+}
@@ -0,0 +1,40 @@
+package sheets
+
+import (
+	"context"
+	"testing"
+
+	"torn_rw_stats/internal/app"
+)
+
+func TestDashboardManagerUpdateDashboard(t *testing.T) {
+	mockAPI := NewMockSheetsAPI()
+	manager := NewDashboardManager(mockAPI)
+
+	rows := []app.WarDashboardRow{
+		{WarID: 111, WarType: app.WarTypeRanked, EnemyName: "Them", OurScore: 1200, EnemyScore: 900, AttacksWon: 40, AttacksLost: 5, Status: "Active"},
+		{WarID: 222, WarType: app.WarTypeRaid, EnemyName: "Others", OurScore: 300, EnemyScore: 0, AttacksWon: 12, AttacksLost: 1, Status: "Active"},
+	}
+
+	if err := manager.UpdateDashboard(context.Background(), "test-sheet-id", rows); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !mockAPI.sheets[DashboardSheetName] {
+		t.Error("Expected dashboard sheet to be created")
+	}
+	if mockAPI.lastUpdateRange != "'Dashboard'!A1:H3" {
+		t.Errorf("Expected header and rows written to A1:H3, got %s", mockAPI.lastUpdateRange)
+	}
+
+	data := mockAPI.GetSheetData(DashboardSheetName)
+	if len(data) != 3 {
+		t.Fatalf("Expected header plus 2 rows, got %d rows", len(data))
+	}
+	if data[0][0] != "War ID" {
+		t.Errorf("Expected header row first, got %v", data[0])
+	}
+	if data[1][0] != 111 || data[2][0] != 222 || data[2][1] != app.WarTypeRaid {
+		t.Errorf("Unexpected war rows: %v, %v", data[1], data[2])
+	}
+}
@@ -0,0 +1,75 @@
+package sheets
+
+import (
+	"context"
+	"fmt"
+
+	"torn_rw_stats/internal/app"
+
+	"github.com/rs/zerolog/log"
+)
+
+// EnemyHospitalManager handles the per-faction hospitalized enemy sheets
+type EnemyHospitalManager struct {
+	api SheetsAPI
+}
+
+// NewEnemyHospitalManager creates a new enemy hospital manager with the given API client
+func NewEnemyHospitalManager(api SheetsAPI) *EnemyHospitalManager {
+	return &EnemyHospitalManager{
+		api: api,
+	}
+}
+
+// GenerateEnemyHospitalTabName creates a standardized enemy hospital tab name for a faction
+func (m *EnemyHospitalManager) GenerateEnemyHospitalTabName(factionID int) string {
+	return fmt.Sprintf("Enemy Hospital - %d", factionID)
+}
+
+// GenerateEnemyHospitalHeaders creates the headers for enemy hospital sheets
+func (m *EnemyHospitalManager) GenerateEnemyHospitalHeaders() [][]interface{} {
+	return [][]interface{}{
+		{
+			"Name",
+			"Level",
+			"Out At",
+			"Countdown",
+		},
+	}
+}
+
+// UpdateEnemyHospital rewrites the hospitalized members of a faction, soonest out first,
+// creating the sheet if needed
+func (m *EnemyHospitalManager) UpdateEnemyHospital(ctx context.Context, spreadsheetID string, factionID int, entries []app.EnemyHospitalEntry) error {
+	sheetName := m.GenerateEnemyHospitalTabName(factionID)
+
+	rows := m.ConvertEnemyHospitalToRows(entries)
+	if err := rewritePerWarSheet(ctx, m.api, spreadsheetID, sheetName, m.GenerateEnemyHospitalHeaders(), rows); err != nil {
+		return fmt.Errorf("failed to update enemy hospital: %w", err)
+	}
+
+	log.Debug().
+		Int("faction_id", factionID).
+		Str("sheet_name", sheetName).
+		Int("hospitalized", len(rows)).
+		Msg("Updated enemy hospital sheet")
+
+	return nil
+}
+
+// ConvertEnemyHospitalToRows converts hospitalized members into spreadsheet row format.
+// Countdowns get a leading apostrophe so Sheets keeps them as text.
+func (m *EnemyHospitalManager) ConvertEnemyHospitalToRows(entries []app.EnemyHospitalEntry) [][]interface{} {
+	rows := make([][]interface{}, len(entries))
+
+	for i, entry := range entries {
+		rows[i] = []interface{}{
+			entry.Name,
+			entry.Level,
+			entry.OutAt.UTC().Format("2006-01-02 15:04:05"),
+			"'" + entry.Countdown,
+		}
+	}
+
+	return rows
+}
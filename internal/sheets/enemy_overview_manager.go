@@ -0,0 +1,93 @@
+package sheets
+
+import (
+	"context"
+	"fmt"
+
+	"torn_rw_stats/internal/app"
+
+	"github.com/rs/zerolog/log"
+)
+
+// EnemyOverviewManager handles the per-faction member state overview sheets
+type EnemyOverviewManager struct {
+	api SheetsAPI
+}
+
+// NewEnemyOverviewManager creates a new enemy overview manager with the given API client
+func NewEnemyOverviewManager(api SheetsAPI) *EnemyOverviewManager {
+	return &EnemyOverviewManager{
+		api: api,
+	}
+}
+
+// GenerateEnemyOverviewTabName creates a standardized overview tab name for a faction
+func (m *EnemyOverviewManager) GenerateEnemyOverviewTabName(factionID int) string {
+	return fmt.Sprintf("Enemy Overview - %d", factionID)
+}
+
+// GenerateEnemyOverviewHeaders creates the headers for enemy overview sheets
+func (m *EnemyOverviewManager) GenerateEnemyOverviewHeaders() [][]interface{} {
+	return [][]interface{}{
+		{
+			"Updated",
+			"Online",
+			"Idle",
+			"Offline",
+			"Hospital",
+			"Traveling",
+			"Jail",
+		},
+	}
+}
+
+// UpdateEnemyOverview overwrites the single overview row for a faction, creating the sheet if needed
+func (m *EnemyOverviewManager) UpdateEnemyOverview(ctx context.Context, spreadsheetID string, factionID int, overview app.EnemyOverview) error {
+	sheetName := m.GenerateEnemyOverviewTabName(factionID)
+
+	exists, err := m.api.SheetExists(ctx, spreadsheetID, sheetName)
+	if err != nil {
+		return fmt.Errorf("failed to check if enemy overview sheet exists: %w", err)
+	}
+
+	if !exists {
+		log.Info().
+			Str("sheet_name", sheetName).
+			Msg("Creating enemy overview sheet")
+
+		if err := m.api.CreateSheet(ctx, spreadsheetID, sheetName); err != nil {
+			return fmt.Errorf("failed to create enemy overview sheet: %w", err)
+		}
+
+		rangeSpec := fmt.Sprintf("'%s'!A1", sheetName)
+		if err := m.api.UpdateRange(ctx, spreadsheetID, rangeSpec, m.GenerateEnemyOverviewHeaders()); err != nil {
+			return fmt.Errorf("failed to write enemy overview headers: %w", err)
+		}
+	}
+
+	rangeSpec := fmt.Sprintf("'%s'!A2:G2", sheetName)
+	if err := m.api.UpdateRange(ctx, spreadsheetID, rangeSpec, m.ConvertEnemyOverviewToRows(overview)); err != nil {
+		return fmt.Errorf("failed to update enemy overview: %w", err)
+	}
+
+	log.Debug().
+		Str("sheet_name", sheetName).
+		Msg("Updated enemy overview sheet")
+
+	return nil
+}
+
+// ConvertEnemyOverviewToRows converts an overview into spreadsheet row format
+func (m *EnemyOverviewManager) ConvertEnemyOverviewToRows(overview app.EnemyOverview) [][]interface{} {
+	return [][]interface{}{
+		{
+			overview.Updated.UTC().Format("2006-01-02 15:04:05"),
+			overview.Online,
+			overview.Idle,
+			overview.Offline,
+			overview.Hospital,
+			overview.Traveling,
+			overview.Jail,
+		},
+	}
+}
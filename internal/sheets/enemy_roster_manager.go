@@ -0,0 +1,72 @@
+package sheets
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// EnemyRosterManager handles the per-faction sheets tracking enemy member counts over time
+type EnemyRosterManager struct {
+	api SheetsAPI
+}
+
+// NewEnemyRosterManager creates a new enemy roster manager with the given API client
+func NewEnemyRosterManager(api SheetsAPI) *EnemyRosterManager {
+	return &EnemyRosterManager{
+		api: api,
+	}
+}
+
+// GenerateEnemyRosterSheetName creates a standardized roster size sheet name for a faction
+func (m *EnemyRosterManager) GenerateEnemyRosterSheetName(factionID int) string {
+	return fmt.Sprintf("Enemy Roster Size - %d", factionID)
+}
+
+// GenerateEnemyRosterHeaders creates the headers for enemy roster size sheets
+func (m *EnemyRosterManager) GenerateEnemyRosterHeaders() [][]interface{} {
+	return [][]interface{}{
+		{"Timestamp", "Members"},
+	}
+}
+
+// AppendEnemyRosterSize appends one timestamped member count row to the faction's
+// roster size sheet, creating the sheet if needed
+func (m *EnemyRosterManager) AppendEnemyRosterSize(ctx context.Context, spreadsheetID string, factionID int, timestamp time.Time, memberCount int) error {
+	sheetName := m.GenerateEnemyRosterSheetName(factionID)
+
+	exists, err := m.api.SheetExists(ctx, spreadsheetID, sheetName)
+	if err != nil {
+		return fmt.Errorf("failed to check if enemy roster sheet exists: %w", err)
+	}
+
+	if !exists {
+		log.Info().
+			Str("sheet_name", sheetName).
+			Int("faction_id", factionID).
+			Msg("Creating enemy roster size sheet")
+
+		if err := m.api.CreateSheet(ctx, spreadsheetID, sheetName); err != nil {
+			return fmt.Errorf("failed to create enemy roster sheet: %w", err)
+		}
+
+		rangeSpec := fmt.Sprintf("'%s'!A1", sheetName)
+		if err := m.api.UpdateRange(ctx, spreadsheetID, rangeSpec, m.GenerateEnemyRosterHeaders()); err != nil {
+			return fmt.Errorf("failed to write enemy roster headers: %w", err)
+		}
+	}
+
+	row := []interface{}{timestamp.UTC().Format("2006-01-02 15:04:05"), memberCount}
+	if err := m.api.AppendRows(ctx, spreadsheetID, fmt.Sprintf("'%s'!A:B", sheetName), [][]interface{}{row}); err != nil {
+		return fmt.Errorf("failed to append enemy roster size: %w", err)
+	}
+
+	log.Debug().
+		Str("sheet_name", sheetName).
+		Int("members", memberCount).
+		Msg("Appended enemy roster size")
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package sheets
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestEnemyRosterManagerAppendEnemyRosterSize(t *testing.T) {
+	mockAPI := NewMockSheetsAPI()
+	manager := NewEnemyRosterManager(mockAPI)
+
+	cycles := []struct {
+		timestamp   time.Time
+		memberCount int
+	}{
+		{timestamp: time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC), memberCount: 100},
+		{timestamp: time.Date(2026, 1, 2, 12, 5, 0, 0, time.UTC), memberCount: 97},
+	}
+
+	for _, cycle := range cycles {
+		if err := manager.AppendEnemyRosterSize(context.Background(), "test-sheet-id", 200, cycle.timestamp, cycle.memberCount); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	rows := mockAPI.GetSheetData("Enemy Roster Size - 200")
+	if len(rows) != 3 {
+		t.Fatalf("Expected header plus 2 roster rows, got %d rows", len(rows))
+	}
+	if rows[0][0] != "Timestamp" || rows[0][1] != "Members" {
+		t.Errorf("Unexpected header row: %v", rows[0])
+	}
+	if rows[1][0] != "2026-01-02 12:00:00" || rows[1][1] != 100 {
+		t.Errorf("Unexpected first roster row: %v", rows[1])
+	}
+	if rows[2][0] != "2026-01-02 12:05:00" || rows[2][1] != 97 {
+		t.Errorf("Unexpected second roster row: %v", rows[2])
+	}
+}
@@ -0,0 +1,71 @@
+package sheets
+
+import (
+	"context"
+	"fmt"
+
+	"torn_rw_stats/internal/app"
+
+	"github.com/rs/zerolog/log"
+)
+
+// IncomingThreatsManager handles the per-war incoming threats sheets
+type IncomingThreatsManager struct {
+	api SheetsAPI
+}
+
+// NewIncomingThreatsManager creates a new incoming threats manager with the given API client
+func NewIncomingThreatsManager(api SheetsAPI) *IncomingThreatsManager {
+	return &IncomingThreatsManager{
+		api: api,
+	}
+}
+
+// GenerateIncomingThreatsTabName creates a standardized incoming threats tab name for a war
+func (m *IncomingThreatsManager) GenerateIncomingThreatsTabName(warID int) string {
+	return fmt.Sprintf("Incoming Threats - %d", warID)
+}
+
+// GenerateIncomingThreatsHeaders creates the headers for incoming threats sheets
+func (m *IncomingThreatsManager) GenerateIncomingThreatsHeaders() [][]interface{} {
+	return [][]interface{}{
+		{
+			"Attacker Name",
+			"Attacks Against Us",
+			"Respect They Gained",
+		},
+	}
+}
+
+// UpdateIncomingThreats rewrites the incoming threats sheet for a war, creating it if needed
+func (m *IncomingThreatsManager) UpdateIncomingThreats(ctx context.Context, spreadsheetID string, warID int, entries []app.IncomingThreatEntry) error {
+	sheetName := m.GenerateIncomingThreatsTabName(warID)
+
+	rows := m.ConvertIncomingThreatsToRows(entries)
+	if err := rewritePerWarSheet(ctx, m.api, spreadsheetID, sheetName, m.GenerateIncomingThreatsHeaders(), rows); err != nil {
+		return fmt.Errorf("failed to update incoming threats: %w", err)
+	}
+
+	log.Debug().
+		Int("war_id", warID).
+		Str("sheet_name", sheetName).
+		Int("attackers", len(rows)).
+		Msg("Updated incoming threats sheet")
+
+	return nil
+}
+
+// ConvertIncomingThreatsToRows converts incoming threat entries into spreadsheet row format
+func (m *IncomingThreatsManager) ConvertIncomingThreatsToRows(entries []app.IncomingThreatEntry) [][]interface{} {
+	rows := make([][]interface{}, len(entries))
+
+	for i, entry := range entries {
+		rows[i] = []interface{}{
+			entry.AttackerName,
+			entry.AttacksAgainstUs,
+			fmt.Sprintf("%.2f", entry.RespectTheyGained),
+		}
+	}
+
+	return rows
+}
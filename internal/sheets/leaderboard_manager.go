@@ -0,0 +1,75 @@
+package sheets
+
+import (
+	"context"
+	"fmt"
+
+	"torn_rw_stats/internal/app"
+
+	"github.com/rs/zerolog/log"
+)
+
+// LeaderboardManager handles the per-war member leaderboard sheets
+type LeaderboardManager struct {
+	api SheetsAPI
+}
+
+// NewLeaderboardManager creates a new leaderboard manager with the given API client
+func NewLeaderboardManager(api SheetsAPI) *LeaderboardManager {
+	return &LeaderboardManager{
+		api: api,
+	}
+}
+
+// GenerateLeaderboardTabName creates a standardized leaderboard tab name for a war
+func (m *LeaderboardManager) GenerateLeaderboardTabName(warID int) string {
+	return fmt.Sprintf("Leaderboard - %d", warID)
+}
+
+// GenerateLeaderboardHeaders creates the headers for leaderboard sheets
+func (m *LeaderboardManager) GenerateLeaderboardHeaders() [][]interface{} {
+	return [][]interface{}{
+		{
+			"Member Name",
+			"Attacks Made",
+			"Attacks Won",
+			"Respect Gained",
+			"Avg Fair Fight",
+		},
+	}
+}
+
+// UpdateLeaderboard rewrites the leaderboard sheet for a war, creating it if needed
+func (m *LeaderboardManager) UpdateLeaderboard(ctx context.Context, spreadsheetID string, warID int, entries []app.MemberLeaderboardEntry) error {
+	sheetName := m.GenerateLeaderboardTabName(warID)
+
+	rows := m.ConvertLeaderboardToRows(entries)
+	if err := rewritePerWarSheet(ctx, m.api, spreadsheetID, sheetName, m.GenerateLeaderboardHeaders(), rows); err != nil {
+		return fmt.Errorf("failed to update leaderboard: %w", err)
+	}
+
+	log.Debug().
+		Int("war_id", warID).
+		Str("sheet_name", sheetName).
+		Int("members", len(rows)).
+		Msg("Updated leaderboard sheet")
+
+	return nil
+}
+
+// ConvertLeaderboardToRows converts leaderboard entries into spreadsheet row format
+func (m *LeaderboardManager) ConvertLeaderboardToRows(entries []app.MemberLeaderboardEntry) [][]interface{} {
+	rows := make([][]interface{}, len(entries))
+
+	for i, entry := range entries {
+		rows[i] = []interface{}{
+			entry.MemberName,
+			entry.AttacksMade,
+			entry.AttacksWon,
+			fmt.Sprintf("%.2f", entry.RespectGained),
+			fmt.Sprintf("%.2f", entry.AvgFairFight),
+		}
+	}
+
+	return rows
+}
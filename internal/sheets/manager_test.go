@@ -2,6 +2,9 @@ package sheets
 
 import (
 	"context"
+	"fmt"
+	"reflect"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -17,6 +20,17 @@ type MockSheetsAPI struct {
 	lastReadRange   string
 	lastUpdateRange string
 	lastUpdateData  [][]interface{}
+	updateRanges    []string // every range passed to UpdateRange, in call order
+	capacityCalls   []capacityCall
+}
+
+// capacityCall records an EnsureSheetCapacity call and how many UpdateRange calls
+// preceded it
+type capacityCall struct {
+	sheetName     string
+	requiredRows  int
+	requiredCols  int
+	updatesBefore int
 }
 
 func NewMockSheetsAPI() *MockSheetsAPI {
@@ -42,17 +56,41 @@ func (m *MockSheetsAPI) ReadSheet(ctx context.Context, spreadsheetID, range_ str
 	sheetName = strings.Trim(sheetName, "'\"")
 
 	if data, exists := m.data[sheetName]; exists {
-		return data, nil
+		return rowsInRange(data, range_), nil
 	}
 	return [][]interface{}{}, nil
 }
 
+// rowsInRange returns the rows an A1 range such as "'Sheet'!A2:F" or "'Sheet'!A1:F1"
+// covers; ranges without row numbers cover every row
+func rowsInRange(data [][]interface{}, range_ string) [][]interface{} {
+	_, cells, found := strings.Cut(range_, "!")
+	if !found {
+		return data
+	}
+	rowOf := func(cell string) int {
+		row, _ := strconv.Atoi(strings.TrimLeft(cell, "ABCDEFGHIJKLMNOPQRSTUVWXYZ"))
+		return row
+	}
+	start, end, _ := strings.Cut(cells, ":")
+	startRow, endRow := max(rowOf(start), 1), rowOf(end)
+
+	if endRow > 0 && endRow < len(data) {
+		data = data[:endRow]
+	}
+	if startRow > len(data) {
+		return [][]interface{}{}
+	}
+	return data[startRow-1:]
+}
+
 func (m *MockSheetsAPI) UpdateRange(ctx context.Context, spreadsheetID, range_ string, values [][]interface{}) error {
 	if m.shouldError {
 		return &mockError{msg: "mock update error"}
 	}
 	m.lastUpdateRange = range_
 	m.lastUpdateData = values
+	m.updateRanges = append(m.updateRanges, range_)
 
 	// Extract sheet name and store data
 	sheetName := range_
@@ -98,6 +136,19 @@ func (m *MockSheetsAPI) AppendRows(ctx context.Context, spreadsheetID, range_ st
 	return nil
 }
 
+func (m *MockSheetsAPI) DeleteRows(ctx context.Context, spreadsheetID, sheetName string, startRow, count int) error {
+	if m.shouldError {
+		return &mockError{msg: "mock delete error"}
+	}
+	data := m.data[sheetName]
+	if startRow-1 >= len(data) {
+		return nil
+	}
+	end := min(startRow-1+count, len(data))
+	m.data[sheetName] = append(data[:startRow-1:startRow-1], data[end:]...)
+	return nil
+}
+
 func (m *MockSheetsAPI) CreateSheet(ctx context.Context, spreadsheetID, sheetName string) error {
 	if m.shouldError {
 		return &mockError{msg: "mock create error"}
@@ -117,6 +168,12 @@ func (m *MockSheetsAPI) EnsureSheetCapacity(ctx context.Context, spreadsheetID,
 	if m.shouldError {
 		return &mockError{msg: "mock capacity error"}
 	}
+	m.capacityCalls = append(m.capacityCalls, capacityCall{
+		sheetName:     sheetName,
+		requiredRows:  requiredRows,
+		requiredCols:  requiredCols,
+		updatesBefore: len(m.updateRanges),
+	})
 	// For testing, just mark that the sheet exists
 	m.sheets[sheetName] = true
 	return nil
@@ -235,7 +292,7 @@ func TestWarSheetsManagerGenerateHeaders(t *testing.T) {
 		t.Error("Expected records headers to be generated")
 	}
 
-	// Check that all 32 columns are present and in correct order
+	// Check that all 38 columns are present and in correct order
 	headerRow := recordsHeaders[0]
 	expectedCols := []string{
 		"Attack ID", "Code", "Started", "Ended", "Direction",
@@ -245,7 +302,8 @@ func TestWarSheetsManagerGenerateHeaders(t *testing.T) {
 		"Is Interrupted", "Is Stealthed", "Is Raid", "Is Ranked War",
 		"Modifier Fair Fight", "Modifier War", "Modifier Retaliation", "Modifier Group",
 		"Modifier Overseas", "Modifier Chain", "Modifier Warlord",
-		"Finishing Hit Name", "Finishing Hit Value",
+		"Finishing Hit Name", "Finishing Hit Value", "Level Difference", "Third Party Faction",
+		"Attacker Faction Tag", "Defender Faction Tag", "Is First Blood", "Is Last Hit",
 	}
 
 	if len(headerRow) != len(expectedCols) {
@@ -263,6 +321,187 @@ func TestWarSheetsManagerGenerateHeaders(t *testing.T) {
 	}
 }
 
+func TestWarSheetsManagerConvertSummaryToRows(t *testing.T) {
+	manager := NewWarSheetsManager(NewMockSheetsAPI())
+
+	summary := &app.WarSummary{
+		WarID:                123,
+		TotalAttacks:         10,
+		AttacksWon:           6,
+		AttacksLost:          4,
+		AttacksDrawn:         2,
+		WinRate:              70,
+		StrictWinRate:        60,
+		HalfCreditWinRate:    70,
+		DefensiveAttacks:     4,
+		DefensiveSuccessRate: 25,
+		WarType:              app.WarTypeTerritory,
+	}
+
+	rows := manager.ConvertSummaryToRows(summary)
+
+	// Values are written from row 3, so they must line up with the labels below the title rows
+	headers := manager.GenerateSummarySheetHeaders()
+	if len(rows) != len(headers)-2 {
+		t.Fatalf("Expected %d summary values, got %d", len(headers)-2, len(rows))
+	}
+
+	expected := map[string]string{
+		"Win Rate":               "70.0%",
+		"Strict Win Rate":        "60.0%",
+		"Half-Credit Win Rate":   "70.0%",
+		"Defensive Success Rate": "25.0%",
+		"War Type":               "territory",
+		"Target Score":           "",
+		"Progress":               "",
+	}
+	for i, header := range headers[2:] {
+		if len(header) == 0 {
+			continue
+		}
+		if want, ok := expected[header[0].(string)]; ok && rows[i] != want {
+			t.Errorf("Expected %s value %s, got %v", header[0], want, rows[i])
+		}
+	}
+}
+
+func TestWarSheetsManagerUpdateWarSummary_RewritesLabels(t *testing.T) {
+	mockAPI := NewMockSheetsAPI()
+	manager := NewWarSheetsManager(mockAPI)
+	config := &app.SheetConfig{WarID: 5, SummaryTabName: "Summary - 5"}
+	summary := &app.WarSummary{WarID: 5, AttacksToOvertake: 3}
+
+	if err := manager.UpdateWarSummary(context.Background(), "test-sheet-id", config, summary); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Labels and values go out together, so sheets created before a label existed get it
+	rows := manager.ConvertSummaryToLabelledRows(summary)
+	want := fmt.Sprintf("Summary - 5!A3:B%d", 2+len(rows))
+	if len(mockAPI.updateRanges) == 0 || mockAPI.updateRanges[0] != want {
+		t.Fatalf("Expected summary written to %s, got ranges %v", want, mockAPI.updateRanges)
+	}
+
+	last := rows[len(rows)-1]
+	if last[0] != "Attacks to Overtake" || last[1] != 3 {
+		t.Errorf("Expected the last row labelled Attacks to Overtake with 3, got %v", last)
+	}
+	if rows[0][0] != "War ID" || rows[0][1] != 5 {
+		t.Errorf("Expected the first row labelled War ID with 5, got %v", rows[0])
+	}
+}
+
+func TestWarSheetsManagerConvertSummaryToRows_RaidProgress(t *testing.T) {
+	manager := NewWarSheetsManager(NewMockSheetsAPI())
+
+	summary := &app.WarSummary{
+		WarID:           123,
+		WarType:         app.WarTypeRaid,
+		TargetScore:     5000,
+		ProgressPercent: 60,
+	}
+
+	rows := manager.ConvertSummaryToRows(summary)
+	values := summaryValuesByLabel(manager, rows)
+	if values["Target Score"] != 5000 || values["Progress"] != "60.0%" {
+		t.Errorf("Expected target 5000 and progress 60.0%%, got %v and %v", values["Target Score"], values["Progress"])
+	}
+}
+
+func TestWarSheetsManagerConvertSummaryToRows_AttackDirections(t *testing.T) {
+	manager := NewWarSheetsManager(NewMockSheetsAPI())
+
+	summary := &app.WarSummary{OutgoingAttacks: 2, IncomingAttacks: 1, UnknownDirectionAttacks: 1}
+
+	values := summaryValuesByLabel(manager, manager.ConvertSummaryToRows(summary))
+	if values["Outgoing Attacks"] != 2 || values["Incoming Attacks"] != 1 || values["Unknown Direction Attacks"] != 1 {
+		t.Errorf("Expected direction counts 2/1/1, got %v/%v/%v",
+			values["Outgoing Attacks"], values["Incoming Attacks"], values["Unknown Direction Attacks"])
+	}
+}
+
+func TestWarSheetsManagerConvertSummaryToRows_NegativeNetRespect(t *testing.T) {
+	manager := NewWarSheetsManager(NewMockSheetsAPI())
+
+	summary := &app.WarSummary{RespectGained: 20, RespectLost: 32.5, NetRespect: -12.5}
+
+	values := summaryValuesByLabel(manager, manager.ConvertSummaryToRows(summary))
+	if values["Net Respect"] != -12.5 {
+		t.Errorf("Expected net respect -12.5, got %v", values["Net Respect"])
+	}
+}
+
+func TestWarSheetsManagerConvertSummaryToRows_AttacksToOvertake(t *testing.T) {
+	manager := NewWarSheetsManager(NewMockSheetsAPI())
+
+	values := summaryValuesByLabel(manager, manager.ConvertSummaryToRows(&app.WarSummary{AttacksToOvertake: 4}))
+	if values["Attacks to Overtake"] != 4 {
+		t.Errorf("Expected 4 attacks to overtake, got %v", values["Attacks to Overtake"])
+	}
+
+	values = summaryValuesByLabel(manager, manager.ConvertSummaryToRows(&app.WarSummary{AttacksToOvertake: -1}))
+	if values["Attacks to Overtake"] != "" {
+		t.Errorf("Expected a blank cell when the count is unknown, got %v", values["Attacks to Overtake"])
+	}
+}
+
+func TestWarSheetsManagerConvertOutcomeDistributionToRows(t *testing.T) {
+	manager := NewWarSheetsManager(NewMockSheetsAPI())
+
+	summary := &app.WarSummary{OutcomeCounts: map[string]int{
+		"Mugged":       4,
+		"Hospitalized": 12,
+		"Stalemate":    1,
+		"Escape":       1,
+		"Lost":         3,
+	}}
+
+	rows := manager.ConvertOutcomeDistributionToRows(summary)
+	expected := [][]interface{}{
+		{"Attack Outcomes", ""},
+		{"Hospitalized", 12},
+		{"Mugged", 4},
+		{"Lost", 3},
+		{"Escape", 1},
+		{"Stalemate", 1},
+	}
+	if !reflect.DeepEqual(rows, expected) {
+		t.Errorf("Expected outcome rows %v, got %v", expected, rows)
+	}
+
+	if rows := manager.ConvertOutcomeDistributionToRows(&app.WarSummary{}); len(rows) != 1 {
+		t.Errorf("Expected only the title row without outcomes, got %v", rows)
+	}
+}
+
+func TestWarSheetsManagerConvertSummaryToRows_AverageModifiers(t *testing.T) {
+	manager := NewWarSheetsManager(NewMockSheetsAPI())
+
+	summary := &app.WarSummary{
+		WarID:            123,
+		AverageModifiers: app.AttackModifiers{FairFight: 2.5, War: 2, Chain: 1.25},
+	}
+
+	values := summaryValuesByLabel(manager, manager.ConvertSummaryToRows(summary))
+	expected := map[string]string{"Fair Fight": "2.50", "War": "2.00", "Chain": "1.25", "Warlord": "0.00"}
+	for label, want := range expected {
+		if values[label] != want {
+			t.Errorf("Expected %s average %s, got %v", label, want, values[label])
+		}
+	}
+}
+
+// summaryValuesByLabel pairs each summary value with its label, skipping the title rows
+func summaryValuesByLabel(manager *WarSheetsManager, rows []interface{}) map[string]interface{} {
+	values := make(map[string]interface{})
+	for i, header := range manager.GenerateSummarySheetHeaders()[2:] {
+		if len(header) > 0 && i < len(rows) {
+			values[header[0].(string)] = rows[i]
+		}
+	}
+	return values
+}
+
 func TestWarSheetsManagerWithAPIError(t *testing.T) {
 	mockAPI := NewMockSheetsAPI()
 	mockAPI.SetError(true)
@@ -284,6 +523,7 @@ func TestAttackRecordsProcessorReadExistingRecords(t *testing.T) {
 
 	// Set up mock data with attack records (ID, Code, Started timestamp)
 	mockAPI.SetSheetData("test_sheet", [][]interface{}{
+		recordsHeaderRow(),
 		{100001, "attack_code_1", "2024-01-01 10:16:40", "2024-01-01 10:17:40"},
 		{100002, "attack_code_2", "2024-01-01 10:33:20", "2024-01-01 10:34:20"},
 		{100003, "attack_code_3", "2024-01-01 10:25:00", "2024-01-01 10:26:00"},
@@ -380,8 +620,8 @@ func TestAttackRecordsProcessorConvertRecordsToRows(t *testing.T) {
 	}
 
 	row := rows[0]
-	if len(row) != 32 {
-		t.Fatalf("Expected 32 columns, got %d", len(row))
+	if len(row) != 38 {
+		t.Fatalf("Expected 38 columns, got %d", len(row))
 	}
 
 	// Check key fields in new format
@@ -402,6 +642,53 @@ func TestAttackRecordsProcessorConvertRecordsToRows(t *testing.T) {
 	}
 }
 
+func TestAttackRecordsProcessorConvertRecordsToRowsLevelDifference(t *testing.T) {
+	processor := NewAttackRecordsProcessor(NewMockSheetsAPI())
+
+	records := []app.AttackRecord{
+		{AttackID: 1, AttackerLevel: 55, DefenderLevel: 40, LevelDifference: 15},
+		{AttackID: 2, AttackerLevel: 20, DefenderLevel: 35, LevelDifference: -15},
+	}
+
+	rows := processor.ConvertRecordsToRows(records)
+
+	if rows[0][32] != 15 {
+		t.Errorf("Expected level difference 15, got %v", rows[0][32])
+	}
+	if rows[1][32] != -15 {
+		t.Errorf("Expected negative level difference -15, got %v", rows[1][32])
+	}
+}
+
+func TestAttackRecordsProcessorConvertRecordsToRowsFinishingHitEffects(t *testing.T) {
+	processor := NewAttackRecordsProcessor(NewMockSheetsAPI())
+
+	records := []app.AttackRecord{
+		{
+			AttackID:          1,
+			FinishingHitName:  "Critical Hit",
+			FinishingHitValue: 1.5,
+			FinishingHitEffects: []app.FinishingHitEffect{
+				{Name: "Critical Hit", Value: 1.5},
+				{Name: "Temporary Weapon", Value: 0.25},
+			},
+		},
+		{AttackID: 2, FinishingHitEffects: []app.FinishingHitEffect{}},
+	}
+
+	rows := processor.ConvertRecordsToRows(records)
+
+	if rows[0][30] != "Critical Hit; Temporary Weapon" {
+		t.Errorf("Expected both finishing hit names, got %v", rows[0][30])
+	}
+	if rows[0][31] != 1.5 {
+		t.Errorf("Expected first finishing hit value 1.5, got %v", rows[0][31])
+	}
+	if rows[1][30] != "" {
+		t.Errorf("Expected empty cell for no finishing hit effects, got %v", rows[1][30])
+	}
+}
+
 // Test ParseValue functions
 
 func TestParseStringValue(t *testing.T) {
@@ -483,3 +770,117 @@ func TestParseInt64PointerValue(t *testing.T) {
 		t.Error("Expected nil for 0 input")
 	}
 }
+
+func TestWarSheetsManagerEnsureDirectionSheets(t *testing.T) {
+	mockAPI := NewMockSheetsAPI()
+	manager := NewWarSheetsManager(mockAPI)
+
+	config := &app.SheetConfig{WarID: 123, RecordsTabName: "Records - 123"}
+	if err := manager.EnsureDirectionSheets(context.Background(), "test_spreadsheet", config); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if config.OutgoingTabName != "Outgoing - 123" || config.IncomingTabName != "Incoming - 123" {
+		t.Errorf("Unexpected direction tab names: %q, %q", config.OutgoingTabName, config.IncomingTabName)
+	}
+	if !config.IsSplitByDirection() {
+		t.Error("Expected config to be split by direction")
+	}
+	if !mockAPI.sheets["Outgoing - 123"] || !mockAPI.sheets["Incoming - 123"] {
+		t.Error("Expected outgoing and incoming sheets to be created")
+	}
+}
+
+func TestAttackRecordsProcessorUpdateAttackRecordsSplitByDirection(t *testing.T) {
+	mockAPI := NewMockSheetsAPI()
+	processor := NewAttackRecordsProcessor(mockAPI)
+
+	config := &app.SheetConfig{
+		WarID:           123,
+		RecordsTabName:  "Records - 123",
+		OutgoingTabName: "Outgoing - 123",
+		IncomingTabName: "Incoming - 123",
+	}
+
+	// The outgoing sheet already holds one attack, which must be deduplicated per sheet
+	mockAPI.SetSheetData("Outgoing - 123", [][]interface{}{
+		recordsHeaderRow(),
+		{100001, "out_existing", "2024-01-01 10:00:00"},
+	})
+
+	base := time.Date(2024, 1, 1, 11, 0, 0, 0, time.UTC)
+	records := []app.AttackRecord{
+		{AttackID: 1, Code: "out_existing", Direction: "Outgoing", Started: base},
+		{AttackID: 2, Code: "out_new", Direction: "Outgoing", Started: base.Add(time.Minute)},
+		{AttackID: 3, Code: "in_new", Direction: "Incoming", Started: base.Add(2 * time.Minute)},
+		{AttackID: 4, Code: "unknown_new", Direction: "Unknown", Started: base.Add(3 * time.Minute)},
+	}
+
+	if err := processor.UpdateAttackRecords(context.Background(), "test_spreadsheet", config, records); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := map[string][]string{
+		"Outgoing - 123": {"out_new"},
+		"Incoming - 123": {"in_new"},
+		"Records - 123":  {"unknown_new"},
+	}
+
+	for sheetName, codes := range expected {
+		rows := mockAPI.GetSheetData(sheetName)
+		if len(rows) != len(codes) {
+			t.Errorf("Expected %d rows written to %s, got %d", len(codes), sheetName, len(rows))
+			continue
+		}
+		for i, code := range codes {
+			if rows[i][1] != code {
+				t.Errorf("Expected %s row %d to have code %s, got %v", sheetName, i, code, rows[i][1])
+			}
+		}
+	}
+}
+
+func TestAttackRecordsProcessorUpdateAttackRecordsCombinedByDefault(t *testing.T) {
+	mockAPI := NewMockSheetsAPI()
+	processor := NewAttackRecordsProcessor(mockAPI)
+
+	config := &app.SheetConfig{WarID: 123, RecordsTabName: "Records - 123"}
+	records := []app.AttackRecord{
+		{AttackID: 1, Code: "out_new", Direction: "Outgoing", Started: time.Unix(1000, 0)},
+		{AttackID: 2, Code: "in_new", Direction: "Incoming", Started: time.Unix(2000, 0)},
+	}
+
+	if err := processor.UpdateAttackRecords(context.Background(), "test_spreadsheet", config, records); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if rows := mockAPI.GetSheetData("Records - 123"); len(rows) != 2 {
+		t.Errorf("Expected both records in the combined sheet, got %d rows", len(rows))
+	}
+	if mockAPI.lastUpdateRange != "'Records - 123'!A2:AL3" {
+		t.Errorf("Expected rows written to A2:AL3, got %s", mockAPI.lastUpdateRange)
+	}
+}
+
+func TestStateTransitionManagerAppendStateTransition(t *testing.T) {
+	mockAPI := NewMockSheetsAPI()
+	manager := NewStateTransitionManager(mockAPI)
+
+	transition := app.WarStateTransition{
+		Timestamp: time.Date(2024, 1, 2, 12, 5, 0, 0, time.UTC),
+		FromState: "NoWars",
+		ToState:   "PreWar",
+		WarID:     777,
+	}
+	if err := manager.AppendStateTransition(context.Background(), "test-sheet-id", transition); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	rows := mockAPI.GetSheetData(StateTransitionTabName)
+	if len(rows) != 2 {
+		t.Fatalf("Expected header plus 1 row, got %d rows", len(rows))
+	}
+	if rows[1][0] != "2024-01-02 12:05:00" || rows[1][1] != "NoWars" || rows[1][2] != "PreWar" || rows[1][3] != 777 {
+		t.Errorf("Unexpected transition row: %v", rows[1])
+	}
+}
@@ -0,0 +1,75 @@
+package sheets
+
+import (
+	"context"
+	"fmt"
+
+	"torn_rw_stats/internal/app"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ParticipationManager handles the per-war participation flags sheets
+type ParticipationManager struct {
+	api SheetsAPI
+}
+
+// NewParticipationManager creates a new participation manager with the given API client
+func NewParticipationManager(api SheetsAPI) *ParticipationManager {
+	return &ParticipationManager{
+		api: api,
+	}
+}
+
+// GenerateParticipationTabName creates a standardized participation flags tab name for a war
+func (m *ParticipationManager) GenerateParticipationTabName(warID int) string {
+	return fmt.Sprintf("Participation Flags - %d", warID)
+}
+
+// GenerateParticipationHeaders creates the headers for participation flags sheets
+func (m *ParticipationManager) GenerateParticipationHeaders() [][]interface{} {
+	return [][]interface{}{
+		{
+			"Member Name",
+			"Member ID",
+			"Attacks",
+			"Deviation",
+			"Flag",
+		},
+	}
+}
+
+// UpdateParticipationFlags rewrites the participation flags sheet for a war, creating it if needed
+func (m *ParticipationManager) UpdateParticipationFlags(ctx context.Context, spreadsheetID string, warID int, flags []app.ParticipationFlag) error {
+	sheetName := m.GenerateParticipationTabName(warID)
+
+	rows := m.ConvertParticipationToRows(flags)
+	if err := rewritePerWarSheet(ctx, m.api, spreadsheetID, sheetName, m.GenerateParticipationHeaders(), rows); err != nil {
+		return fmt.Errorf("failed to update participation flags: %w", err)
+	}
+
+	log.Debug().
+		Int("war_id", warID).
+		Str("sheet_name", sheetName).
+		Int("flagged_members", len(rows)).
+		Msg("Updated participation flags sheet")
+
+	return nil
+}
+
+// ConvertParticipationToRows converts participation flags into spreadsheet row format
+func (m *ParticipationManager) ConvertParticipationToRows(flags []app.ParticipationFlag) [][]interface{} {
+	rows := make([][]interface{}, len(flags))
+
+	for i, flag := range flags {
+		rows[i] = []interface{}{
+			flag.MemberName,
+			flag.MemberID,
+			flag.Attacks,
+			fmt.Sprintf("%+.1f%%", flag.DeviationPercent),
+			flag.Flag,
+		}
+	}
+
+	return rows
+}
@@ -0,0 +1,53 @@
+package sheets
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+)
+
+// rewritePerWarSheet replaces every data row of a per-war or per-faction sheet that is
+// rebuilt in full each run, creating the sheet with its headers if needed. The old rows
+// are cleared first so entries that dropped off don't linger.
+func rewritePerWarSheet(ctx context.Context, api SheetsAPI, spreadsheetID, sheetName string, headers, rows [][]interface{}) error {
+	exists, err := api.SheetExists(ctx, spreadsheetID, sheetName)
+	if err != nil {
+		return fmt.Errorf("failed to check if sheet %s exists: %w", sheetName, err)
+	}
+
+	if !exists {
+		log.Info().
+			Str("sheet_name", sheetName).
+			Msg("Creating sheet")
+
+		if err := api.CreateSheet(ctx, spreadsheetID, sheetName); err != nil {
+			return fmt.Errorf("failed to create sheet %s: %w", sheetName, err)
+		}
+
+		rangeSpec := fmt.Sprintf("'%s'!A1", sheetName)
+		if err := api.UpdateRange(ctx, spreadsheetID, rangeSpec, headers); err != nil {
+			return fmt.Errorf("failed to write %s headers: %w", sheetName, err)
+		}
+	}
+
+	lastColumn := columnLetter(len(headers[0]) - 1)
+	if err := api.ClearRange(ctx, spreadsheetID, fmt.Sprintf("'%s'!A2:%s", sheetName, lastColumn)); err != nil {
+		return fmt.Errorf("failed to clear %s data: %w", sheetName, err)
+	}
+
+	if len(rows) == 0 {
+		return nil
+	}
+
+	if err := api.EnsureSheetCapacity(ctx, spreadsheetID, sheetName, len(rows)+1, len(headers[0])); err != nil {
+		return fmt.Errorf("failed to ensure sheet capacity: %w", err)
+	}
+
+	rangeSpec := fmt.Sprintf("'%s'!A2:%s%d", sheetName, lastColumn, len(rows)+1)
+	if err := api.UpdateRange(ctx, spreadsheetID, rangeSpec, rows); err != nil {
+		return fmt.Errorf("failed to write %s rows: %w", sheetName, err)
+	}
+
+	return nil
+}
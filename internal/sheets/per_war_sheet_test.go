@@ -0,0 +1,158 @@
+package sheets
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"torn_rw_stats/internal/app"
+)
+
+func TestPerWarSheetManagers(t *testing.T) {
+	at := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		update    func(api SheetsAPI) error
+		sheetName string
+		wantRange string
+		wantRows  [][]interface{}
+	}{
+		{
+			name: "incoming threats",
+			update: func(api SheetsAPI) error {
+				return NewIncomingThreatsManager(api).UpdateIncomingThreats(context.Background(), "test-sheet-id", 12345, []app.IncomingThreatEntry{
+					{AttackerID: 9, AttackerName: "Mallory", AttacksAgainstUs: 2, RespectTheyGained: 4.0},
+					{AttackerID: 8, AttackerName: "Eve", AttacksAgainstUs: 1, RespectTheyGained: 2.5},
+				})
+			},
+			sheetName: "Incoming Threats - 12345",
+			wantRange: "'Incoming Threats - 12345'!A2:C3",
+			wantRows:  [][]interface{}{{"Mallory", 2, "4.00"}, {"Eve", 1, "2.50"}},
+		},
+		{
+			name: "leaderboard",
+			update: func(api SheetsAPI) error {
+				return NewLeaderboardManager(api).UpdateLeaderboard(context.Background(), "test-sheet-id", 12345, []app.MemberLeaderboardEntry{
+					{MemberID: 2, MemberName: "Bob", AttacksMade: 3, AttacksWon: 2, RespectGained: 7.5, AvgFairFight: 2.25},
+					{MemberID: 1, MemberName: "Alice", AttacksMade: 1, AttacksWon: 1, RespectGained: 1.0, AvgFairFight: 1.0},
+				})
+			},
+			sheetName: "Leaderboard - 12345",
+			wantRange: "'Leaderboard - 12345'!A2:E3",
+			wantRows:  [][]interface{}{{"Bob", 3, 2, "7.50", "2.25"}, {"Alice", 1, 1, "1.00", "1.00"}},
+		},
+		{
+			name: "respect trend",
+			update: func(api SheetsAPI) error {
+				return NewRespectTrendManager(api).UpdateRespectTrend(context.Background(), "test-sheet-id", 12345, []app.RespectTrendWindow{
+					{WindowStart: at, AvgRespect: 4.0, AttackCount: 3},
+					{WindowStart: at.Add(time.Hour)},
+				})
+			},
+			sheetName: "Respect Trend - 12345",
+			wantRange: "'Respect Trend - 12345'!A2:C3",
+			wantRows:  [][]interface{}{{"2026-01-01 12:00:00", 3, "4.00"}, {"2026-01-01 13:00:00", 0, "0.00"}},
+		},
+		{
+			name: "participation flags",
+			update: func(api SheetsAPI) error {
+				return NewParticipationManager(api).UpdateParticipationFlags(context.Background(), "test-sheet-id", 12345, []app.ParticipationFlag{
+					{MemberID: 2, MemberName: "Bob", Attacks: 12, DeviationPercent: 140, Flag: app.ParticipationHigh},
+					{MemberID: 1, MemberName: "Alice", Attacks: 0, DeviationPercent: -100, Flag: app.ParticipationLow},
+				})
+			},
+			sheetName: "Participation Flags - 12345",
+			wantRange: "'Participation Flags - 12345'!A2:E3",
+			wantRows:  [][]interface{}{{"Bob", 2, 12, "+140.0%", "High"}, {"Alice", 1, 0, "-100.0%", "Low"}},
+		},
+		{
+			name: "counterattacks",
+			update: func(api SheetsAPI) error {
+				return NewCounterattacksManager(api).UpdateCounterattacks(context.Background(), "test-sheet-id", 12345, []app.Counterattack{
+					{
+						MemberName:          "Alice",
+						OpponentName:        "Enemy",
+						AttackedAt:          at,
+						AttackResult:        "Hospitalized",
+						CounterattackedAt:   at.Add(3 * time.Minute),
+						CounterattackResult: "Hospitalized",
+						RetaliationModifier: 1.5,
+					},
+				})
+			},
+			sheetName: "Counterattacks - 12345",
+			wantRange: "'Counterattacks - 12345'!A2:G2",
+			wantRows: [][]interface{}{
+				{"Alice", "Enemy", "2026-01-01 12:00:00", "Hospitalized", "2026-01-01 12:03:00", "Hospitalized", "1.50"},
+			},
+		},
+		{
+			name: "enemy hospital",
+			update: func(api SheetsAPI) error {
+				return NewEnemyHospitalManager(api).UpdateEnemyHospital(context.Background(), "test-sheet-id", 777, []app.EnemyHospitalEntry{
+					{MemberID: 2, Name: "Soon", Level: 40, OutAt: at.Add(5 * time.Minute), Countdown: "0:05:00"},
+					{MemberID: 1, Name: "Slow", Level: 50, OutAt: at.Add(65 * time.Minute), Countdown: "1:05:00"},
+				})
+			},
+			sheetName: "Enemy Hospital - 777",
+			wantRange: "'Enemy Hospital - 777'!A2:D3",
+			wantRows:  [][]interface{}{{"Soon", 40, "2026-01-01 12:05:00", "'0:05:00"}, {"Slow", 50, "2026-01-01 13:05:00", "'1:05:00"}},
+		},
+		{
+			name: "target priority",
+			update: func(api SheetsAPI) error {
+				return NewTargetPriorityManager(api).UpdateTargetPriority(context.Background(), "test-sheet-id", 777, []app.TargetPriorityEntry{
+					{MemberID: 2, Name: "Target", Level: 80, Online: "Online", State: "Okay", Attackable: true, TargetScore: 130},
+					{MemberID: 1, Name: "Hospitalized", Level: 100, Online: "Online", State: "Hospital", TargetScore: 0},
+				})
+			},
+			sheetName: "Target Priority - 777",
+			wantRange: "'Target Priority - 777'!A2:F3",
+			wantRows:  [][]interface{}{{"Target", 2, 80, "Online", "Okay", 130}, {"Hospitalized", 1, 100, "Online", "Hospital", 0}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAPI := NewMockSheetsAPI()
+			if err := tt.update(mockAPI); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if !mockAPI.sheets[tt.sheetName] {
+				t.Errorf("Expected sheet %q to be created", tt.sheetName)
+			}
+			if mockAPI.lastUpdateRange != tt.wantRange {
+				t.Errorf("Expected rows written to %s, got %s", tt.wantRange, mockAPI.lastUpdateRange)
+			}
+			if rows := mockAPI.GetSheetData(tt.sheetName); !reflect.DeepEqual(rows, tt.wantRows) {
+				t.Errorf("Expected rows %v, got %v", tt.wantRows, rows)
+			}
+
+			mockAPI.SetError(true)
+			if err := tt.update(mockAPI); err == nil {
+				t.Error("Expected error when API fails")
+			}
+		})
+	}
+}
+
+func TestRewritePerWarSheetClearsStaleRows(t *testing.T) {
+	mockAPI := NewMockSheetsAPI()
+	mockAPI.sheets["Leaderboard - 1"] = true
+	mockAPI.SetSheetData("Leaderboard - 1", [][]interface{}{{"Name"}, {"Stale"}})
+
+	headers := [][]interface{}{{"Name", "Attacks"}}
+	if err := rewritePerWarSheet(context.Background(), mockAPI, "test-sheet-id", "Leaderboard - 1", headers, nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if rows := mockAPI.GetSheetData("Leaderboard - 1"); len(rows) != 0 {
+		t.Errorf("Expected the stale rows to be cleared, got %v", rows)
+	}
+	if len(mockAPI.updateRanges) != 0 {
+		t.Errorf("Expected no writes for an existing sheet without rows, got %v", mockAPI.updateRanges)
+	}
+}
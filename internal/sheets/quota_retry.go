@@ -0,0 +1,74 @@
+package sheets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"torn_rw_stats/internal/config"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/api/googleapi"
+)
+
+// rateLimitReasons are the error reasons Google APIs use for per-user rate limits,
+// which some endpoints report with HTTP 403 instead of 429
+var rateLimitReasons = map[string]bool{
+	"rateLimitExceeded":     true,
+	"userRateLimitExceeded": true,
+}
+
+// IsQuotaError reports whether err is the Sheets API rejecting a request for exceeding
+// its per-minute quota. These are temporary, unlike other API errors.
+func IsQuotaError(err error) bool {
+	var apiErr *googleapi.Error
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	if apiErr.Code == http.StatusTooManyRequests {
+		return true
+	}
+	for _, item := range apiErr.Errors {
+		if rateLimitReasons[item.Reason] {
+			return true
+		}
+	}
+	return false
+}
+
+// retryOnQuota runs call, retrying it with exponential backoff while the Sheets API
+// reports quota errors, up to retry.MaxAttempts attempts in total. Other errors are
+// returned at once. call must send the same request each time, so a retried write
+// lands with exactly the values of the rejected one.
+func retryOnQuota(ctx context.Context, retry config.RetryConfig, operation string, call func() error) error {
+	wait := retry.InitialWait
+	for attempt := 1; ; attempt++ {
+		err := call()
+		if err == nil || !IsQuotaError(err) {
+			return err
+		}
+		if attempt >= retry.MaxAttempts {
+			return fmt.Errorf("sheets quota still exceeded after %d attempts: %w", attempt, err)
+		}
+
+		log.Warn().
+			Err(err).
+			Str("operation", operation).
+			Int("attempt", attempt).
+			Dur("backoff", wait).
+			Msg("Sheets API quota exceeded - backing off before retrying")
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("cancelled while backing off from sheets quota error: %w", err)
+		case <-time.After(wait):
+		}
+
+		wait = time.Duration(float64(wait) * retry.Multiplier)
+		if wait > retry.MaxWait {
+			wait = retry.MaxWait
+		}
+	}
+}
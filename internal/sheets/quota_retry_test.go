@@ -0,0 +1,77 @@
+package sheets
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"torn_rw_stats/internal/config"
+
+	"google.golang.org/api/googleapi"
+)
+
+// flakySheetsAPI fails its first writes with the given errors, then records the values written
+type flakySheetsAPI struct {
+	failures []error
+	calls    int
+	written  [][]interface{}
+}
+
+func (f *flakySheetsAPI) UpdateRange(ctx context.Context, spreadsheetID, range_ string, values [][]interface{}) error {
+	f.calls++
+	if f.calls <= len(f.failures) {
+		return f.failures[f.calls-1]
+	}
+	f.written = values
+	return nil
+}
+
+func TestRetryOnQuota(t *testing.T) {
+	retry := config.RetryConfig{MaxAttempts: 4, InitialWait: time.Millisecond, MaxWait: 2 * time.Millisecond, Multiplier: 2}
+	quotaErr := &googleapi.Error{Code: http.StatusTooManyRequests, Message: "Quota exceeded"}
+	values := [][]interface{}{{"Attack ID", "Respect"}, {int64(123), 4.25}}
+
+	tests := []struct {
+		name      string
+		failures  []error
+		wantCalls int
+		wantErr   bool
+	}{
+		{"quota errors twice then success", []error{quotaErr, quotaErr}, 3, false},
+		{"rate limit reason on 403", []error{&googleapi.Error{Code: http.StatusForbidden, Errors: []googleapi.ErrorItem{{Reason: "rateLimitExceeded"}}}}, 2, false},
+		{"permanent error is not retried", []error{&googleapi.Error{Code: http.StatusBadRequest}}, 1, true},
+		{"quota still exceeded at the cap", []error{quotaErr, quotaErr, quotaErr, quotaErr}, 4, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := &flakySheetsAPI{failures: tt.failures}
+			err := retryOnQuota(context.Background(), retry, "update range", func() error {
+				return api.UpdateRange(context.Background(), "spreadsheet-id", "'Records - 1'!A1:B2", values)
+			})
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if api.calls != tt.wantCalls {
+				t.Errorf("expected %d calls, got %d", tt.wantCalls, api.calls)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(api.written, values) {
+				t.Errorf("expected the original values written, got %v", api.written)
+			}
+		})
+	}
+}
+
+func TestIsQuotaError(t *testing.T) {
+	wrapped := errors.Join(errors.New("failed to update range"), &googleapi.Error{Code: http.StatusTooManyRequests})
+	if !IsQuotaError(wrapped) {
+		t.Error("expected a wrapped 429 to be a quota error")
+	}
+	if IsQuotaError(errors.New("connection reset")) {
+		t.Error("expected a non-API error not to be a quota error")
+	}
+}
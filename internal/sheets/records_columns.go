@@ -0,0 +1,105 @@
+package sheets
+
+import (
+	"fmt"
+	"slices"
+)
+
+// recordsColumns lists the attack records sheet columns in their default order
+var recordsColumns = []string{
+	"Attack ID",
+	"Code",
+	"Started",
+	"Ended",
+	"Direction",
+	"Attacker ID",
+	"Attacker Name",
+	"Attacker Level",
+	"Attacker Faction ID",
+	"Attacker Faction Name",
+	"Defender ID",
+	"Defender Name",
+	"Defender Level",
+	"Defender Faction ID",
+	"Defender Faction Name",
+	"Result",
+	"Respect Gain",
+	"Respect Loss",
+	"Chain",
+	"Is Interrupted",
+	"Is Stealthed",
+	"Is Raid",
+	"Is Ranked War",
+	"Modifier Fair Fight",
+	"Modifier War",
+	"Modifier Retaliation",
+	"Modifier Group",
+	"Modifier Overseas",
+	"Modifier Chain",
+	"Modifier Warlord",
+	"Finishing Hit Name",
+	"Finishing Hit Value",
+	"Level Difference",
+	"Third Party Faction",
+	"Attacker Faction Tag",
+	"Defender Faction Tag",
+	"Is First Blood",
+	"Is Last Hit",
+}
+
+// requiredRecordsColumns must be kept in any custom order: existing records are
+// deduplicated by code and new ones are only appended after the latest start time
+var requiredRecordsColumns = []string{"Code", "Started"}
+
+// ValidateRecordsColumnOrder checks that a custom records column order only names known
+// columns, names each at most once and keeps the columns deduplication relies on.
+// An empty order is valid and keeps the default layout.
+func ValidateRecordsColumnOrder(order []string) error {
+	if len(order) == 0 {
+		return nil
+	}
+
+	seen := make(map[string]bool, len(order))
+	for _, name := range order {
+		if !slices.Contains(recordsColumns, name) {
+			return fmt.Errorf("unknown records column %q", name)
+		}
+		if seen[name] {
+			return fmt.Errorf("records column %q is listed more than once", name)
+		}
+		seen[name] = true
+	}
+
+	for _, name := range requiredRecordsColumns {
+		if !seen[name] {
+			return fmt.Errorf("records column order must include %q", name)
+		}
+	}
+
+	return nil
+}
+
+// recordsColumnNames returns the records sheet headers for a column order, falling
+// back to the default layout when the order is empty
+func recordsColumnNames(order []string) []string {
+	if len(order) == 0 {
+		return recordsColumns
+	}
+	return order
+}
+
+// applyRecordsColumnOrder reorders and subsets a full-layout records row to match a
+// column order. Rows are returned unchanged when the order is empty.
+func applyRecordsColumnOrder(row []interface{}, order []string) []interface{} {
+	if len(order) == 0 {
+		return row
+	}
+
+	ordered := make([]interface{}, len(order))
+	for i, name := range order {
+		if index := slices.Index(recordsColumns, name); index >= 0 && index < len(row) {
+			ordered[i] = row[index]
+		}
+	}
+	return ordered
+}
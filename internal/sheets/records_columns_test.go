@@ -0,0 +1,89 @@
+package sheets
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"torn_rw_stats/internal/app"
+)
+
+func TestValidateRecordsColumnOrder(t *testing.T) {
+	tests := []struct {
+		name      string
+		order     []string
+		expectErr bool
+	}{
+		{name: "empty keeps default", order: nil},
+		{name: "reordered subset", order: []string{"Started", "Code", "Result"}},
+		{name: "unknown column", order: []string{"Code", "Started", "Bogus"}, expectErr: true},
+		{name: "duplicate column", order: []string{"Code", "Started", "Code"}, expectErr: true},
+		{name: "missing code", order: []string{"Attack ID", "Started"}, expectErr: true},
+		{name: "missing started", order: []string{"Attack ID", "Code"}, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateRecordsColumnOrder(tt.order)
+			if (err != nil) != tt.expectErr {
+				t.Errorf("expected error %v, got %v", tt.expectErr, err)
+			}
+		})
+	}
+}
+
+func TestRecordsColumnOrderDefaultLayout(t *testing.T) {
+	headers := NewWarSheetsManager(nil).GenerateRecordsSheetHeaders()[0]
+	if len(headers) != 38 || headers[0] != "Attack ID" || headers[33] != "Third Party Faction" ||
+		headers[35] != "Defender Faction Tag" || headers[37] != "Is Last Hit" {
+		t.Errorf("unexpected default headers: %v", headers)
+	}
+}
+
+func TestRecordsColumnOrderCustom(t *testing.T) {
+	order := []string{"Started", "Code", "Attacker Name", "Respect Gain", "Attack ID"}
+	started := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	record := app.AttackRecord{
+		AttackID:     111,
+		Code:         "abc",
+		Started:      started,
+		AttackerName: "Player1",
+		DefenderName: "Target1",
+		RespectGain:  2.5,
+	}
+
+	manager := NewWarSheetsManager(nil)
+	manager.SetRecordsColumnOrder(order)
+	headers := manager.GenerateRecordsSheetHeaders()[0]
+	if !reflect.DeepEqual(headers, []interface{}{"Started", "Code", "Attacker Name", "Respect Gain", "Attack ID"}) {
+		t.Errorf("unexpected headers: %v", headers)
+	}
+
+	mockAPI := NewMockSheetsAPI()
+	processor := NewAttackRecordsProcessor(mockAPI)
+	processor.SetRecordsColumnOrder(order)
+
+	row := processor.ConvertRecordsToRows([]app.AttackRecord{record})[0]
+	expected := []interface{}{"2024-01-02 03:04:05", "abc", "Player1", "2.50", int64(111)}
+	if !reflect.DeepEqual(row, expected) {
+		t.Errorf("expected row %v, got %v", expected, row)
+	}
+
+	config := &app.SheetConfig{WarID: 123, RecordsTabName: "Records - 123"}
+	mockAPI.SetSheetData("Records - 123", [][]interface{}{headers})
+	if err := processor.UpdateAttackRecords(context.Background(), "test_spreadsheet", config, []app.AttackRecord{record}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The mock replaces the sheet with the written rows, so restore the header before
+	// writing the same record again
+	mockAPI.SetSheetData("Records - 123", append([][]interface{}{headers}, mockAPI.GetSheetData("Records - 123")...))
+	if err := processor.UpdateAttackRecords(context.Background(), "test_spreadsheet", config, []app.AttackRecord{record}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(mockAPI.updateRanges, []string{"'Records - 123'!A2:E2"}) {
+		t.Errorf("expected a single write to A2:E2 with the second deduplicated, got %v", mockAPI.updateRanges)
+	}
+}
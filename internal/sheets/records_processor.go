@@ -3,7 +3,9 @@ package sheets
 import (
 	"context"
 	"fmt"
+	"slices"
 	"sort"
+	"strings"
 	"time"
 
 	"torn_rw_stats/internal/app"
@@ -14,22 +16,120 @@ import (
 // AttackRecordsProcessor handles business logic for attack records management
 // Separated from infrastructure concerns for better testability
 type AttackRecordsProcessor struct {
-	api SheetsAPI
+	api                  SheetsAPI
+	respectDecimalPlaces int
+	recordsColumnOrder   []string
+	location             *time.Location // timezone Started and Ended are written in
+	writeBatchSize       int            // rows written per UpdateRange call
 }
 
+// DefaultRespectDecimalPlaces is how many decimal places respect is written with by default
+const DefaultRespectDecimalPlaces = 2
+
+// DefaultSheetWriteBatchSize is how many attack record rows are written per request by default
+const DefaultSheetWriteBatchSize = 1000
+
+// maxRespectDecimalPlaces is the most decimal places respect is written with
+const maxRespectDecimalPlaces = 4
+
 // NewAttackRecordsProcessor creates a new attack records processor with the given API client
 func NewAttackRecordsProcessor(api SheetsAPI) *AttackRecordsProcessor {
 	return &AttackRecordsProcessor{
-		api: api,
+		api:                  api,
+		respectDecimalPlaces: DefaultRespectDecimalPlaces,
+		location:             time.UTC,
+		writeBatchSize:       DefaultSheetWriteBatchSize,
 	}
 }
 
+// SetRespectDecimalPlaces sets how many decimal places RespectGain and RespectLoss are
+// formatted with, clamped to 0-4
+func (p *AttackRecordsProcessor) SetRespectDecimalPlaces(places int) {
+	p.respectDecimalPlaces = clampRespectDecimalPlaces(places)
+}
+
+// SetRecordsColumnOrder sets the column order records are written and read in; an empty
+// order keeps the default layout. Callers are expected to validate it with ValidateRecordsColumnOrder.
+func (p *AttackRecordsProcessor) SetRecordsColumnOrder(order []string) {
+	p.recordsColumnOrder = order
+}
+
+// ColumnNames returns the records column headers in the configured order
+func (p *AttackRecordsProcessor) ColumnNames() []string {
+	return recordsColumnNames(p.recordsColumnOrder)
+}
+
+// SetDisplayLocation sets the timezone Started and Ended are written in, with their UTC
+// offset so they read back correctly after the timezone changes; nil keeps UTC
+func (p *AttackRecordsProcessor) SetDisplayLocation(location *time.Location) {
+	if location != nil {
+		p.location = location
+	}
+}
+
+// SetWriteBatchSize sets how many rows are written per request when appending records;
+// a non-positive size keeps the default
+func (p *AttackRecordsProcessor) SetWriteBatchSize(size int) {
+	if size > 0 {
+		p.writeBatchSize = size
+	}
+}
+
+// clampRespectDecimalPlaces limits a respect precision to the supported 0-4 places
+func clampRespectDecimalPlaces(places int) int {
+	return min(max(places, 0), maxRespectDecimalPlaces)
+}
+
 // RecordsInfo contains information about existing records in a sheet
 type RecordsInfo struct {
-	AttackCodes      map[string]bool
-	LatestTimestamp  int64 // For compatibility with existing usage
-	RecordCount      int
-	LastRowProcessed int
+	AttackCodes       map[string]bool
+	AttackIDs         map[int64]bool // Codes are occasionally reused or missing, so IDs are tracked too
+	LatestTimestamp   int64          // For compatibility with existing usage
+	EarliestTimestamp int64          // Oldest Started on the sheet; zero when it has no records
+	RecordCount       int
+	LastRowProcessed  int
+	Columns           []string // Column layout from the sheet's header row, used for writes
+	FirstBloodRows    []int    // Sheet rows currently marked as first blood
+	LastHitRows       []int    // Sheet rows currently marked as the last hit
+}
+
+// sheetColumns returns the column layout of a records sheet from its header row, so rows
+// are read and appended where the sheet has them even after the configured order changes.
+// A sheet without a header yet uses the configured order. A header without Code and
+// Started columns is refused, as existing records could not be matched against it.
+func (p *AttackRecordsProcessor) sheetColumns(ctx context.Context, spreadsheetID, sheetName string) ([]string, error) {
+	values, err := p.api.ReadSheet(ctx, spreadsheetID, fmt.Sprintf("'%s'!1:1", sheetName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read records header: %w", err)
+	}
+
+	configured := recordsColumnNames(p.recordsColumnOrder)
+	if len(values) == 0 || len(values[0]) == 0 {
+		return configured, nil
+	}
+
+	header := make([]string, len(values[0]))
+	for i, cell := range values[0] {
+		header[i] = NewCell(cell).String()
+	}
+	// The Sheets API omits trailing empty cells, but a cleared header may still hold some
+	for len(header) > 0 && header[len(header)-1] == "" {
+		header = header[:len(header)-1]
+	}
+
+	if !slices.Contains(header, "Code") || !slices.Contains(header, "Started") {
+		return nil, fmt.Errorf("records sheet %s header has no Code and Started columns: %q", sheetName, header)
+	}
+
+	if !slices.Equal(header, configured) {
+		log.Warn().
+			Str("sheet_name", sheetName).
+			Strs("sheet_columns", header).
+			Strs("configured_columns", configured).
+			Msg("Records sheet layout differs from the configured column order - using the sheet's header")
+	}
+
+	return header, nil
 }
 
 // ReadExistingRecords reads existing attack records from a sheet to determine what's already there
@@ -38,8 +138,18 @@ func (p *AttackRecordsProcessor) ReadExistingRecords(ctx context.Context, spread
 		Str("sheet_name", sheetName).
 		Msg("Reading existing attack records")
 
+	columns, err := p.sheetColumns(ctx, spreadsheetID, sheetName)
+	if err != nil {
+		return nil, err
+	}
+	idColumn := slices.Index(columns, "Attack ID")
+	codeColumn := slices.Index(columns, "Code")
+	startedColumn := slices.Index(columns, "Started")
+	firstBloodColumn := slices.Index(columns, "Is First Blood")
+	lastHitColumn := slices.Index(columns, "Is Last Hit")
+
 	// Read all data from the sheet (starting from row 2 to skip headers)
-	rangeSpec := fmt.Sprintf("'%s'!A2:AF", sheetName)
+	rangeSpec := fmt.Sprintf("'%s'!A2:%s", sheetName, columnLetter(len(columns)-1))
 	values, err := p.api.ReadSheet(ctx, spreadsheetID, rangeSpec)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read existing records: %w", err)
@@ -47,31 +157,50 @@ func (p *AttackRecordsProcessor) ReadExistingRecords(ctx context.Context, spread
 
 	info := &RecordsInfo{
 		AttackCodes:      make(map[string]bool),
+		AttackIDs:        make(map[int64]bool),
 		LatestTimestamp:  0,
 		RecordCount:      len(values),
 		LastRowProcessed: 1, // Header is row 1
+		Columns:          columns,
 	}
 
 	validRows := 0
-	for _, row := range values {
-		if len(row) < 3 { // Need at least Code and Started timestamp
+	for i, row := range values {
+		sheetRow := i + 2 // +2 for header row and 1-based indexing
+		if firstBloodColumn >= 0 && firstBloodColumn < len(row) && NewCell(row[firstBloodColumn]).Bool() {
+			info.FirstBloodRows = append(info.FirstBloodRows, sheetRow)
+		}
+		if lastHitColumn >= 0 && lastHitColumn < len(row) && NewCell(row[lastHitColumn]).Bool() {
+			info.LastHitRows = append(info.LastHitRows, sheetRow)
+		}
+
+		if len(row) <= max(codeColumn, startedColumn) { // Need at least Code and Started timestamp
 			continue
 		}
 
-		// Parse Attack Code (column B) - always a string
-		codeStr := NewCell(row[1]).String()
+		// Parse Attack ID, when the layout includes it
+		if idColumn >= 0 && idColumn < len(row) {
+			if attackID := NewCell(row[idColumn]).Int64(); attackID != 0 {
+				info.AttackIDs[attackID] = true
+			}
+		}
+
+		// Parse Attack Code - always a string
+		codeStr := NewCell(row[codeColumn]).String()
 		if codeStr != "" {
 			info.AttackCodes[codeStr] = true
 			validRows++
 		}
 
-		// Parse Started timestamp (column C) to find latest
-		startedStr := NewCell(row[2]).String()
-		if startedTime, err := time.Parse("2006-01-02 15:04:05", startedStr); err == nil {
+		// Parse Started timestamp to find latest
+		if startedTime, err := ParseSheetTime(NewCell(row[startedColumn]).String()); err == nil {
 			timestamp := startedTime.Unix()
 			if timestamp > info.LatestTimestamp {
 				info.LatestTimestamp = timestamp
 			}
+			if info.EarliestTimestamp == 0 || timestamp < info.EarliestTimestamp {
+				info.EarliestTimestamp = timestamp
+			}
 		}
 	}
 
@@ -97,32 +226,267 @@ func (p *AttackRecordsProcessor) ReadExistingRecords(ctx context.Context, spread
 	return info, nil
 }
 
-// UpdateAttackRecords updates the attack records sheet with new records
+// ReadAttackRecords reads every attack record on a records sheet back, so sheets
+// rebuilt each cycle can cover the whole war rather than the latest batch. Rows
+// without a code are skipped; columns the layout leaves out stay zero.
+func (p *AttackRecordsProcessor) ReadAttackRecords(ctx context.Context, spreadsheetID, sheetName string) ([]app.AttackRecord, error) {
+	columns, err := p.sheetColumns(ctx, spreadsheetID, sheetName)
+	if err != nil {
+		return nil, err
+	}
+	rangeSpec := fmt.Sprintf("'%s'!A2:%s", sheetName, columnLetter(len(columns)-1))
+	values, err := p.api.ReadSheet(ctx, spreadsheetID, rangeSpec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read attack records: %w", err)
+	}
+
+	records := make([]app.AttackRecord, 0, len(values))
+	for _, row := range values {
+		record := p.parseRecordRow(columns, row)
+		if record.Code == "" {
+			continue
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// parseRecordRow converts a records sheet row laid out in columns back into a record
+func (p *AttackRecordsProcessor) parseRecordRow(columns []string, row []interface{}) app.AttackRecord {
+	var record app.AttackRecord
+	factionID := func(cell Cell) *int {
+		if id := cell.Int(); id != 0 {
+			return &id
+		}
+		return nil
+	}
+	sheetTime := func(cell Cell) time.Time {
+		parsed, _ := ParseSheetTime(cell.String())
+		return parsed
+	}
+
+	for i, name := range columns {
+		if i >= len(row) {
+			break
+		}
+		cell := NewCell(row[i])
+		switch name {
+		case "Attack ID":
+			record.AttackID = cell.Int64()
+		case "Code":
+			record.Code = cell.String()
+		case "Started":
+			record.Started = sheetTime(cell)
+		case "Ended":
+			record.Ended = sheetTime(cell)
+		case "Direction":
+			record.Direction = cell.String()
+		case "Attacker ID":
+			record.AttackerID = cell.Int()
+		case "Attacker Name":
+			record.AttackerName = cell.String()
+		case "Attacker Level":
+			record.AttackerLevel = cell.Int()
+		case "Attacker Faction ID":
+			record.AttackerFactionID = factionID(cell)
+		case "Attacker Faction Name":
+			record.AttackerFactionName = cell.String()
+		case "Defender ID":
+			record.DefenderID = cell.Int()
+		case "Defender Name":
+			record.DefenderName = cell.String()
+		case "Defender Level":
+			record.DefenderLevel = cell.Int()
+		case "Defender Faction ID":
+			record.DefenderFactionID = factionID(cell)
+		case "Defender Faction Name":
+			record.DefenderFactionName = cell.String()
+		case "Result":
+			record.Result = cell.String()
+		case "Respect Gain":
+			record.RespectGain = cell.Float64()
+		case "Respect Loss":
+			record.RespectLoss = cell.Float64()
+		case "Chain":
+			record.Chain = cell.Int()
+		case "Is Interrupted":
+			record.IsInterrupted = cell.Bool()
+		case "Is Stealthed":
+			record.IsStealthed = cell.Bool()
+		case "Is Raid":
+			record.IsRaid = cell.Bool()
+		case "Is Ranked War":
+			record.IsRankedWar = cell.Bool()
+		case "Modifier Fair Fight":
+			record.ModifierFairFight = cell.Float64()
+		case "Modifier War":
+			record.ModifierWar = cell.Float64()
+		case "Modifier Retaliation":
+			record.ModifierRetaliation = cell.Float64()
+		case "Modifier Group":
+			record.ModifierGroup = cell.Float64()
+		case "Modifier Overseas":
+			record.ModifierOverseas = cell.Float64()
+		case "Modifier Chain":
+			record.ModifierChain = cell.Float64()
+		case "Modifier Warlord":
+			record.ModifierWarlord = cell.Float64()
+		case "Finishing Hit Name":
+			record.FinishingHitName = cell.String()
+		case "Finishing Hit Value":
+			record.FinishingHitValue = cell.Float64()
+		case "Level Difference":
+			record.LevelDifference = cell.Int()
+		case "Third Party Faction":
+			record.ThirdPartyFactionName = cell.String()
+		case "Attacker Faction Tag":
+			record.AttackerFactionTag = cell.String()
+		case "Defender Faction Tag":
+			record.DefenderFactionTag = cell.String()
+		case "Is First Blood":
+			record.IsFirstBlood = cell.Bool()
+		case "Is Last Hit":
+			record.IsLastHit = cell.Bool()
+		}
+	}
+	return record
+}
+
+// sheetTimeLayout is how sheet times are written in UTC; other timezones append
+// the UTC offset (sheetTimeOffsetLayout) so a cell reads back the same instant whatever
+// timezone is configured when it is read
+const (
+	sheetTimeLayout       = "2006-01-02 15:04:05"
+	sheetTimeOffsetLayout = "2006-01-02 15:04:05 -07:00"
+)
+
+// FormatSheetTime formats a time cell in the given timezone, appending the UTC offset
+// unless the timezone is UTC
+func FormatSheetTime(t time.Time, location *time.Location) string {
+	if location == nil || location == time.UTC {
+		return t.UTC().Format(sheetTimeLayout)
+	}
+	return t.In(location).Format(sheetTimeOffsetLayout)
+}
+
+// ParseSheetTime parses a time cell written by FormatSheetTime. Cells without an offset
+// were written in UTC, as every release before timezones were configurable did.
+func ParseSheetTime(value string) (time.Time, error) {
+	if t, err := time.Parse(sheetTimeOffsetLayout, value); err == nil {
+		return t, nil
+	}
+	return time.ParseInLocation(sheetTimeLayout, value, time.UTC)
+}
+
+// UpdateAttackRecords updates the attack records sheet with new records.
+// When the config is split by direction, outgoing and incoming attacks are written
+// to their own sheets and any attack without a known direction stays in the records sheet.
+// When a new first blood or last hit is written, the previous one is unmarked in every
+// records sheet.
 func (p *AttackRecordsProcessor) UpdateAttackRecords(ctx context.Context, spreadsheetID string, config *app.SheetConfig, records []app.AttackRecord) error {
-	if len(records) == 0 {
+	return p.writeAttackRecords(ctx, spreadsheetID, config, records, false)
+}
+
+// BackfillAttackRecords writes records the same way as UpdateAttackRecords, but for
+// attacks that may be older than the newest row already on the sheet, such as those
+// recovered after an interrupted fetch. Existing records are deduplicated by code and
+// attack ID only, without skipping records started before the latest timestamp.
+func (p *AttackRecordsProcessor) BackfillAttackRecords(ctx context.Context, spreadsheetID string, config *app.SheetConfig, records []app.AttackRecord) error {
+	return p.writeAttackRecords(ctx, spreadsheetID, config, records, true)
+}
+
+// writeAttackRecords writes records to the war's records sheets, splitting them by
+// direction when configured; backfill drops the latest-timestamp cutoff
+func (p *AttackRecordsProcessor) writeAttackRecords(ctx context.Context, spreadsheetID string, config *app.SheetConfig, records []app.AttackRecord, backfill bool) error {
+	markers := newMarkers{
+		firstBlood: slices.ContainsFunc(records, func(record app.AttackRecord) bool { return record.IsFirstBlood }),
+		lastHit:    slices.ContainsFunc(records, func(record app.AttackRecord) bool { return record.IsLastHit }),
+	}
+
+	if !config.IsSplitByDirection() {
+		return p.updateRecordsSheet(ctx, spreadsheetID, config.WarID, config.RecordsTabName, records, markers, backfill)
+	}
+
+	outgoing, incoming, other := SplitRecordsByDirection(records)
+
+	if err := p.updateRecordsSheet(ctx, spreadsheetID, config.WarID, config.OutgoingTabName, outgoing, markers, backfill); err != nil {
+		return fmt.Errorf("failed to update outgoing records: %w", err)
+	}
+	if err := p.updateRecordsSheet(ctx, spreadsheetID, config.WarID, config.IncomingTabName, incoming, markers, backfill); err != nil {
+		return fmt.Errorf("failed to update incoming records: %w", err)
+	}
+	if err := p.updateRecordsSheet(ctx, spreadsheetID, config.WarID, config.RecordsTabName, other, markers, backfill); err != nil {
+		return fmt.Errorf("failed to update records without direction: %w", err)
+	}
+
+	return nil
+}
+
+// SplitRecordsByDirection partitions records into outgoing, incoming and those with
+// any other direction, preserving their order
+func SplitRecordsByDirection(records []app.AttackRecord) (outgoing, incoming, other []app.AttackRecord) {
+	for _, record := range records {
+		switch record.Direction {
+		case "Outgoing":
+			outgoing = append(outgoing, record)
+		case "Incoming":
+			incoming = append(incoming, record)
+		default:
+			other = append(other, record)
+		}
+	}
+	return outgoing, incoming, other
+}
+
+// newMarkers says which hit markers a batch of records carries, so the rows holding
+// the previous ones are unmarked first
+type newMarkers struct {
+	firstBlood bool
+	lastHit    bool
+}
+
+// updateRecordsSheet appends new, deduplicated records to a single records sheet,
+// first clearing the sheet's existing markers of the kinds the batch carries
+func (p *AttackRecordsProcessor) updateRecordsSheet(ctx context.Context, spreadsheetID string, warID int, sheetName string, records []app.AttackRecord, markers newMarkers, backfill bool) error {
+	if len(records) == 0 && !markers.firstBlood && !markers.lastHit {
 		return nil
 	}
 
 	log.Info().
-		Int("war_id", config.WarID).
-		Str("sheet_name", config.RecordsTabName).
+		Int("war_id", warID).
+		Str("sheet_name", sheetName).
 		Int("records_count", len(records)).
 		Msg("=== ENTERING UpdateAttackRecords ===")
 
 	// Read existing records to determine update strategy
-	existing, err := p.ReadExistingRecords(ctx, spreadsheetID, config.RecordsTabName)
+	existing, err := p.ReadExistingRecords(ctx, spreadsheetID, sheetName)
 	if err != nil {
 		return fmt.Errorf("failed to read existing records: %w", err)
 	}
 
+	if markers.firstBlood {
+		if err := p.clearMarkers(ctx, spreadsheetID, sheetName, existing.Columns, "Is First Blood", existing.FirstBloodRows); err != nil {
+			return err
+		}
+	}
+	if markers.lastHit {
+		if err := p.clearMarkers(ctx, spreadsheetID, sheetName, existing.Columns, "Is Last Hit", existing.LastHitRows); err != nil {
+			return err
+		}
+	}
+	if len(records) == 0 {
+		return nil
+	}
+
 	// Filter out duplicate attacks and sort chronologically
 	log.Debug().
 		Int("input_records", len(records)).
 		Int("existing_attack_codes", len(existing.AttackCodes)).
 		Int("existing_record_count", existing.RecordCount).
+		Bool("backfill", backfill).
 		Msg("Starting deduplication")
 
-	newRecords := p.FilterAndSortRecords(records, existing)
+	newRecords := p.filterAndSortRecords(records, existing, !backfill)
 
 	if len(newRecords) == 0 {
 		log.Info().Msg("=== EXITING UpdateAttackRecords - No new records after deduplication ===")
@@ -135,31 +499,29 @@ func (p *AttackRecordsProcessor) UpdateAttackRecords(ctx context.Context, spread
 		Int("existing_records", existing.RecordCount).
 		Msg("Processed records for update")
 
-	// Convert to spreadsheet format
-	rows := p.ConvertRecordsToRows(newRecords)
+	// Convert to spreadsheet format, in the layout the sheet already has
+	rows := p.convertRecordsToRows(newRecords, existing.Columns)
 
 	// Calculate required sheet dimensions (matching wars_api.go approach)
 	startRow := existing.RecordCount + 2 // +2 for header row and 1-based indexing
 	endRow := startRow + len(rows) - 1
 	requiredRows := endRow
-	requiredCols := 32 // AF column = 32
+	requiredCols := len(existing.Columns)
 
 	// Ensure sheet has sufficient capacity
-	if err := p.api.EnsureSheetCapacity(ctx, spreadsheetID, config.RecordsTabName, requiredRows, requiredCols); err != nil {
+	if err := p.api.EnsureSheetCapacity(ctx, spreadsheetID, sheetName, requiredRows, requiredCols); err != nil {
 		return fmt.Errorf("failed to ensure sheet capacity: %w", err)
 	}
 
 	// Append new rows to the sheet
-	rangeSpec := fmt.Sprintf("'%s'!A%d:AF%d", config.RecordsTabName, startRow, endRow)
+	lastColumn := columnLetter(requiredCols - 1)
+	rangeSpec := fmt.Sprintf("'%s'!A%d:%s%d", sheetName, startRow, lastColumn, endRow)
 
 	// Log first few rows being written to detect duplicates at write time
 	sampleRows := make([]string, 0, 3)
-	for i, row := range rows {
-		if i < 3 && len(row) >= 2 {
-			attackID := row[0]
-			if code, ok := row[1].(string); ok {
-				sampleRows = append(sampleRows, fmt.Sprintf("ID:%v Code:%s", attackID, code))
-			}
+	for i, record := range newRecords {
+		if i < 3 {
+			sampleRows = append(sampleRows, fmt.Sprintf("ID:%v Code:%s", record.AttackID, record.Code))
 		}
 	}
 
@@ -169,14 +531,20 @@ func (p *AttackRecordsProcessor) UpdateAttackRecords(ctx context.Context, spread
 		Strs("sample_rows", sampleRows).
 		Msg("=== WRITING TO SHEET ===")
 
-	// Use UpdateRange instead of AppendRows for exact range specification
-	err = p.api.UpdateRange(ctx, spreadsheetID, rangeSpec, rows)
-	if err != nil {
-		return fmt.Errorf("failed to append attack records: %w", err)
+	// Use UpdateRange instead of AppendRows for exact range specification, in batches
+	// so a full population stays within the per-request value limits
+	for batchStart := 0; batchStart < len(rows); batchStart += p.writeBatchSize {
+		batch := rows[batchStart:min(batchStart+p.writeBatchSize, len(rows))]
+		batchFirstRow := startRow + batchStart
+		batchRange := fmt.Sprintf("'%s'!A%d:%s%d", sheetName, batchFirstRow, lastColumn, batchFirstRow+len(batch)-1)
+
+		if err := p.api.UpdateRange(ctx, spreadsheetID, batchRange, batch); err != nil {
+			return fmt.Errorf("failed to append attack records %s: %w", batchRange, err)
+		}
 	}
 
 	log.Info().
-		Int("war_id", config.WarID).
+		Int("war_id", warID).
 		Int("records_appended", len(newRecords)).
 		Str("range", rangeSpec).
 		Msg("=== EXITING UpdateAttackRecords - Successfully appended records ===")
@@ -184,25 +552,62 @@ func (p *AttackRecordsProcessor) UpdateAttackRecords(ctx context.Context, spread
 	return nil
 }
 
+// clearMarkers unmarks the given column in the rows currently marked, so only the newly
+// written marker stays set. Only the marked cells are written, normally a single one.
+func (p *AttackRecordsProcessor) clearMarkers(ctx context.Context, spreadsheetID, sheetName string, columns []string, columnName string, rows []int) error {
+	column := slices.Index(columns, columnName)
+	if column < 0 {
+		return nil
+	}
+
+	letter := columnLetter(column)
+	for _, row := range rows {
+		rangeSpec := fmt.Sprintf("'%s'!%s%d", sheetName, letter, row)
+		if err := p.api.UpdateRange(ctx, spreadsheetID, rangeSpec, [][]interface{}{{false}}); err != nil {
+			return fmt.Errorf("failed to clear previous %s marker in %s: %w", columnName, sheetName, err)
+		}
+	}
+	return nil
+}
+
 // FilterAndSortRecords filters out existing records and sorts by timestamp
 func (p *AttackRecordsProcessor) FilterAndSortRecords(records []app.AttackRecord, existing *RecordsInfo) []app.AttackRecord {
+	return p.filterAndSortRecords(records, existing, true)
+}
+
+// FilterAndSortBackfillRecords filters out existing records by attack code and ID only,
+// keeping records older than the sheet's latest timestamp, and sorts by timestamp
+func (p *AttackRecordsProcessor) FilterAndSortBackfillRecords(records []app.AttackRecord, existing *RecordsInfo) []app.AttackRecord {
+	return p.filterAndSortRecords(records, existing, false)
+}
+
+// filterAndSortRecords filters out existing records, and with useCutoff also records
+// started at or before the sheet's latest timestamp, then sorts by timestamp
+func (p *AttackRecordsProcessor) filterAndSortRecords(records []app.AttackRecord, existing *RecordsInfo, useCutoff bool) []app.AttackRecord {
 	var newRecords []app.AttackRecord
 
-	// Filter out duplicates using attack codes AND records older than existing timestamp
+	// Filter out duplicates using attack codes or IDs, both against the sheet and within
+	// this batch, AND records older than existing timestamp unless backfilling
+	seenCodes := make(map[string]bool)
+	seenIDs := make(map[int64]bool)
 	duplicates := 0
 	for _, record := range records {
-		// Skip if duplicate attack code
-		if existing.AttackCodes[record.Code] {
+		// Skip if duplicate attack code or attack ID
+		duplicateCode := record.Code != "" && (existing.AttackCodes[record.Code] || seenCodes[record.Code])
+		duplicateID := record.AttackID != 0 && (existing.AttackIDs[record.AttackID] || seenIDs[record.AttackID])
+		if duplicateCode || duplicateID {
 			duplicates++
 			log.Debug().
 				Str("attack_code", record.Code).
 				Int64("attack_id", record.AttackID).
+				Bool("duplicate_code", duplicateCode).
+				Bool("duplicate_id", duplicateID).
 				Msg("Filtered duplicate attack")
 			continue
 		}
 
 		// Skip if record is older than or equal to existing timestamp (already processed)
-		if record.Started.Unix() <= existing.LatestTimestamp {
+		if useCutoff && record.Started.Unix() <= existing.LatestTimestamp {
 			duplicates++
 			log.Debug().
 				Int64("attack_id", record.AttackID).
@@ -213,6 +618,8 @@ func (p *AttackRecordsProcessor) FilterAndSortRecords(records []app.AttackRecord
 		}
 
 		// Record is new and recent enough
+		seenCodes[record.Code] = true
+		seenIDs[record.AttackID] = true
 		newRecords = append(newRecords, record)
 	}
 
@@ -247,6 +654,12 @@ func (p *AttackRecordsProcessor) FilterAndSortRecords(records []app.AttackRecord
 
 // ConvertRecordsToRows converts attack records into spreadsheet row format
 func (p *AttackRecordsProcessor) ConvertRecordsToRows(records []app.AttackRecord) [][]interface{} {
+	return p.convertRecordsToRows(records, p.recordsColumnOrder)
+}
+
+// convertRecordsToRows converts attack records into rows laid out in the given column
+// order; an empty order gives the default layout
+func (p *AttackRecordsProcessor) convertRecordsToRows(records []app.AttackRecord, order []string) [][]interface{} {
 	var rows [][]interface{}
 
 	for _, record := range records {
@@ -261,8 +674,8 @@ func (p *AttackRecordsProcessor) ConvertRecordsToRows(records []app.AttackRecord
 		row := []interface{}{
 			record.AttackID,
 			record.Code,
-			record.Started.UTC().Format("2006-01-02 15:04:05"),
-			record.Ended.UTC().Format("2006-01-02 15:04:05"),
+			FormatSheetTime(record.Started, p.location),
+			FormatSheetTime(record.Ended, p.location),
 			record.Direction,
 			record.AttackerID,
 			record.AttackerName,
@@ -275,8 +688,8 @@ func (p *AttackRecordsProcessor) ConvertRecordsToRows(records []app.AttackRecord
 			factionID(record.DefenderFactionID),
 			record.DefenderFactionName,
 			record.Result,
-			fmt.Sprintf("%.2f", record.RespectGain),
-			fmt.Sprintf("%.2f", record.RespectLoss),
+			fmt.Sprintf("%.*f", p.respectDecimalPlaces, record.RespectGain),
+			fmt.Sprintf("%.*f", p.respectDecimalPlaces, record.RespectLoss),
 			record.Chain,
 			record.IsInterrupted,
 			record.IsStealthed,
@@ -289,15 +702,36 @@ func (p *AttackRecordsProcessor) ConvertRecordsToRows(records []app.AttackRecord
 			record.ModifierOverseas,
 			record.ModifierChain,
 			record.ModifierWarlord,
-			record.FinishingHitName,
+			FormatFinishingHitNames(record),
 			record.FinishingHitValue,
+			record.LevelDifference,
+			record.ThirdPartyFactionName,
+			record.AttackerFactionTag,
+			record.DefenderFactionTag,
+			record.IsFirstBlood,
+			record.IsLastHit,
 		}
-		rows = append(rows, row)
+		rows = append(rows, applyRecordsColumnOrder(row, order))
 	}
 
 	return rows
 }
 
+// FormatFinishingHitNames joins all finishing hit effect names into a single
+// semicolon-separated cell, falling back to the single name field for records
+// built without the effect list
+func FormatFinishingHitNames(record app.AttackRecord) string {
+	if len(record.FinishingHitEffects) == 0 {
+		return record.FinishingHitName
+	}
+
+	names := make([]string, len(record.FinishingHitEffects))
+	for i, effect := range record.FinishingHitEffects {
+		names[i] = effect.Name
+	}
+	return strings.Join(names, "; ")
+}
+
 // ParseValue functions for reading data from sheets
 // These are pure functions that can be easily unit tested
 // Note: Using functions from wars.go to avoid duplication
@@ -2,12 +2,20 @@ package sheets
 
 import (
 	"context"
+	"fmt"
+	"reflect"
+	"slices"
 	"testing"
 	"time"
 
 	"torn_rw_stats/internal/app"
 )
 
+// recordsHeaderRow returns the header row of a records sheet in the default layout
+func recordsHeaderRow() []interface{} {
+	return NewWarSheetsManager(nil).GenerateRecordsSheetHeaders()[0]
+}
+
 func TestAttackRecordsProcessorReadExistingRecordsDetailed(t *testing.T) {
 	mockAPI := NewMockSheetsAPI()
 	processor := NewAttackRecordsProcessor(mockAPI)
@@ -64,7 +72,11 @@ func TestAttackRecordsProcessorReadExistingRecordsDetailed(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			mockAPI.SetSheetData("test_sheet", tc.data)
+			data := tc.data
+			if len(data) > 0 {
+				data = append([][]interface{}{recordsHeaderRow()}, data...)
+			}
+			mockAPI.SetSheetData("test_sheet", data)
 
 			info, err := processor.ReadExistingRecords(context.Background(), "test_spreadsheet", "test_sheet")
 
@@ -264,6 +276,107 @@ func TestAttackRecordsProcessorConvertRecordsToRowsComprehensive(t *testing.T) {
 	}
 }
 
+func TestAttackRecordsProcessorRespectDecimalPlaces(t *testing.T) {
+	record := app.AttackRecord{AttackID: 1, Code: "abc", RespectGain: 3.14159, RespectLoss: 0.5}
+
+	tests := []struct {
+		name         string
+		places       int
+		expectedGain string
+		expectedLoss string
+	}{
+		{name: "default two places", places: DefaultRespectDecimalPlaces, expectedGain: "3.14", expectedLoss: "0.50"},
+		{name: "three places", places: 3, expectedGain: "3.142", expectedLoss: "0.500"},
+		{name: "negative clamps to zero", places: -1, expectedGain: "3", expectedLoss: "0"},
+		{name: "above four clamps to four", places: 9, expectedGain: "3.1416", expectedLoss: "0.5000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			processor := NewAttackRecordsProcessor(NewMockSheetsAPI())
+			processor.SetRespectDecimalPlaces(tt.places)
+
+			row := processor.ConvertRecordsToRows([]app.AttackRecord{record})[0]
+			if row[16] != tt.expectedGain || row[17] != tt.expectedLoss {
+				t.Errorf("expected respect %q/%q, got %v/%v", tt.expectedGain, tt.expectedLoss, row[16], row[17])
+			}
+		})
+	}
+}
+
+func TestAttackRecordsProcessorFactionTagColumns(t *testing.T) {
+	processor := NewAttackRecordsProcessor(NewMockSheetsAPI())
+	headers := NewWarSheetsManager(nil).GenerateRecordsSheetHeaders()[0]
+
+	records := []app.AttackRecord{
+		{AttackID: 1, Code: "tagged", AttackerFactionTag: "OUR", DefenderFactionTag: "ENMY"},
+		{AttackID: 2, Code: "untagged", AttackerFactionTag: "ENMY"},
+	}
+	rows := processor.ConvertRecordsToRows(records)
+
+	column := func(row []interface{}, name string) interface{} {
+		for i, header := range headers {
+			if header == name {
+				return row[i]
+			}
+		}
+		t.Fatalf("no %q column in records headers", name)
+		return nil
+	}
+
+	if got := column(rows[0], "Attacker Faction Tag"); got != "OUR" {
+		t.Errorf("expected attacker tag OUR, got %v", got)
+	}
+	if got := column(rows[0], "Defender Faction Tag"); got != "ENMY" {
+		t.Errorf("expected defender tag ENMY, got %v", got)
+	}
+	if got := column(rows[1], "Defender Faction Tag"); got != "" {
+		t.Errorf("expected missing defender tag to be empty, got %v", got)
+	}
+}
+
+func TestAttackRecordsProcessorDisplayLocation(t *testing.T) {
+	location, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+
+	started := time.Date(2024, 3, 1, 15, 30, 0, 0, time.UTC)
+	record := app.AttackRecord{AttackID: 1, Code: "abc", Started: started, Ended: started.Add(time.Minute)}
+
+	mockAPI := NewMockSheetsAPI()
+	processor := NewAttackRecordsProcessor(mockAPI)
+	processor.SetDisplayLocation(location)
+
+	row := processor.ConvertRecordsToRows([]app.AttackRecord{record})[0]
+	if row[2] != "2024-03-01 10:30:00 -05:00" || row[3] != "2024-03-01 10:31:00 -05:00" {
+		t.Errorf("expected Started/Ended five hours behind UTC with their offset, got %v/%v", row[2], row[3])
+	}
+
+	// The offset keeps Started the same instant whichever timezone reads it back, so
+	// incremental appends still deduplicate after the timezone changes
+	mockAPI.data["Records - 1"] = [][]interface{}{recordsHeaderRow(), row}
+	for _, reader := range []*AttackRecordsProcessor{processor, NewAttackRecordsProcessor(mockAPI)} {
+		info, err := reader.ReadExistingRecords(context.Background(), "test_spreadsheet", "Records - 1")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if info.LatestTimestamp != started.Unix() {
+			t.Errorf("expected latest timestamp %d in %s, got %d", started.Unix(), reader.location, info.LatestTimestamp)
+		}
+	}
+
+	// Rows without an offset were written in UTC and are read as UTC in any timezone
+	mockAPI.data["Records - 1"] = [][]interface{}{recordsHeaderRow(), NewAttackRecordsProcessor(nil).ConvertRecordsToRows([]app.AttackRecord{record})[0]}
+	info, err := processor.ReadExistingRecords(context.Background(), "test_spreadsheet", "Records - 1")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if info.LatestTimestamp != started.Unix() {
+		t.Errorf("expected a UTC row read as UTC, got latest timestamp %d", info.LatestTimestamp)
+	}
+}
+
 func TestAttackRecordsProcessorUpdateAttackRecords(t *testing.T) {
 	mockAPI := NewMockSheetsAPI()
 	processor := NewAttackRecordsProcessor(mockAPI)
@@ -313,6 +426,189 @@ func TestAttackRecordsProcessorUpdateAttackRecords(t *testing.T) {
 	}
 }
 
+func TestAttackRecordsProcessorUpdateAttackRecordsClearsPreviousLastHit(t *testing.T) {
+	mockAPI := NewMockSheetsAPI()
+	processor := NewAttackRecordsProcessor(mockAPI)
+
+	config := &app.SheetConfig{
+		WarID:           123,
+		RecordsTabName:  "Records - 123",
+		OutgoingTabName: "Outgoing - 123",
+		IncomingTabName: "Incoming - 123",
+	}
+	header := recordsHeaderRow()
+	lastHitColumn := slices.Index(header, interface{}("Is Last Hit"))
+	previousLastHit := make([]interface{}, len(header))
+	copy(previousLastHit, []interface{}{int64(3), "old_in_2", "2024-01-01 10:10:00"})
+	previousLastHit[lastHitColumn] = "TRUE"
+
+	mockAPI.SetSheetData("Outgoing - 123", [][]interface{}{header, {int64(1), "old_out", "2024-01-01 10:00:00"}})
+	mockAPI.SetSheetData("Incoming - 123", [][]interface{}{
+		header,
+		{int64(2), "old_in_1", "2024-01-01 10:05:00"},
+		previousLastHit,
+	})
+
+	records := []app.AttackRecord{{
+		AttackID:  4,
+		Code:      "new_out",
+		Started:   time.Date(2024, 1, 1, 11, 0, 0, 0, time.UTC),
+		Direction: "Outgoing",
+		IsLastHit: true,
+	}}
+	if err := processor.UpdateAttackRecords(context.Background(), "test_spreadsheet", config, records); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Only the marked cell is cleared, even on the sheet without new records
+	expectedRanges := []string{
+		"'Outgoing - 123'!A3:AL3",
+		"'Incoming - 123'!AL3",
+	}
+	if !reflect.DeepEqual(mockAPI.updateRanges, expectedRanges) {
+		t.Fatalf("Expected writes %v, got %v", expectedRanges, mockAPI.updateRanges)
+	}
+	if cleared := mockAPI.GetSheetData("Incoming - 123"); len(cleared) != 1 || cleared[0][0] != false {
+		t.Errorf("Expected the previous last hit cleared, got %v", cleared)
+	}
+}
+
+func TestAttackRecordsProcessorReadExistingRecordsTracksMarkers(t *testing.T) {
+	mockAPI := NewMockSheetsAPI()
+	processor := NewAttackRecordsProcessor(mockAPI)
+
+	header := recordsHeaderRow()
+	row := func(id int64, code, started string, marker string) []interface{} {
+		cells := make([]interface{}, len(header))
+		copy(cells, []interface{}{id, code, started})
+		if marker != "" {
+			cells[slices.Index(header, interface{}(marker))] = "TRUE"
+		}
+		return cells
+	}
+	mockAPI.SetSheetData("Records - 123", [][]interface{}{
+		header,
+		row(2, "second", "2024-01-01 10:05:00", ""),
+		row(1, "first", "2024-01-01 10:00:00", "Is First Blood"),
+		row(3, "third", "2024-01-01 10:10:00", "Is Last Hit"),
+	})
+
+	info, err := processor.ReadExistingRecords(context.Background(), "test_spreadsheet", "Records - 123")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(info.FirstBloodRows, []int{3}) || !reflect.DeepEqual(info.LastHitRows, []int{4}) {
+		t.Errorf("Expected first blood on row 3 and last hit on row 4, got %v and %v", info.FirstBloodRows, info.LastHitRows)
+	}
+	earliest := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC).Unix()
+	if info.EarliestTimestamp != earliest {
+		t.Errorf("Expected earliest timestamp %d, got %d", earliest, info.EarliestTimestamp)
+	}
+}
+
+func TestAttackRecordsProcessorUpdateAttackRecordsBatches(t *testing.T) {
+	mockAPI := NewMockSheetsAPI()
+	processor := NewAttackRecordsProcessor(mockAPI)
+	processor.SetWriteBatchSize(1000)
+
+	start := time.Unix(1640995200, 0)
+	records := make([]app.AttackRecord, 2500)
+	for i := range records {
+		records[i] = app.AttackRecord{
+			AttackID: int64(2500 - i),
+			Code:     fmt.Sprintf("code-%d", 2500-i),
+			Started:  start.Add(time.Duration(2500-i) * time.Second),
+		}
+	}
+
+	config := &app.SheetConfig{WarID: 123, RecordsTabName: "Records - 123"}
+	if err := processor.UpdateAttackRecords(context.Background(), "test_spreadsheet", config, records); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expectedRanges := []string{
+		"'Records - 123'!A2:AL1001",
+		"'Records - 123'!A1002:AL2001",
+		"'Records - 123'!A2002:AL2501",
+	}
+	if !reflect.DeepEqual(mockAPI.updateRanges, expectedRanges) {
+		t.Fatalf("Expected writes %v, got %v", expectedRanges, mockAPI.updateRanges)
+	}
+
+	// Records are written oldest first across batches, so the last batch ends with the newest
+	lastBatch := mockAPI.lastUpdateData
+	if len(lastBatch) != 500 || lastBatch[0][0] != int64(2001) || lastBatch[499][0] != int64(2500) {
+		t.Errorf("Expected last batch to hold attacks 2001-2500, got %d rows starting %v", len(lastBatch), lastBatch[0][0])
+	}
+}
+
+func TestAttackRecordsProcessorUpdateAttackRecordsEnsuresCapacity(t *testing.T) {
+	mockAPI := NewMockSheetsAPI()
+	processor := NewAttackRecordsProcessor(mockAPI)
+
+	start := time.Unix(1640995200, 0)
+	records := make([]app.AttackRecord, 5000)
+	for i := range records {
+		records[i] = app.AttackRecord{
+			AttackID: int64(i + 1),
+			Code:     fmt.Sprintf("code-%d", i+1),
+			Started:  start.Add(time.Duration(i) * time.Second),
+		}
+	}
+
+	config := &app.SheetConfig{WarID: 123, RecordsTabName: "Records - 123"}
+	if err := processor.UpdateAttackRecords(context.Background(), "test_spreadsheet", config, records); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(mockAPI.capacityCalls) != 1 {
+		t.Fatalf("Expected one capacity check, got %d", len(mockAPI.capacityCalls))
+	}
+	call := mockAPI.capacityCalls[0]
+	if call.sheetName != "Records - 123" || call.requiredRows != 5001 || call.requiredCols != len(recordsColumnNames(nil)) {
+		t.Errorf("Expected capacity for 5001 rows and all columns on Records - 123, got %+v", call)
+	}
+	if call.updatesBefore != 0 {
+		t.Errorf("Expected capacity to be ensured before any rows are written, got %d writes first", call.updatesBefore)
+	}
+	if len(mockAPI.updateRanges) != 5 {
+		t.Errorf("Expected 5000 rows written in 5 batches, got %d writes", len(mockAPI.updateRanges))
+	}
+}
+
+func TestAttackRecordsProcessorUpdateAttackRecordsDeduplicatesByAttackID(t *testing.T) {
+	mockAPI := NewMockSheetsAPI()
+	processor := NewAttackRecordsProcessor(mockAPI)
+
+	testTime := time.Unix(1640995200, 0)
+	config := &app.SheetConfig{
+		WarID:          123,
+		RecordsTabName: "Records - 123",
+	}
+
+	// The API reported the same attack twice under different codes
+	records := []app.AttackRecord{
+		{AttackID: 111, Code: "code-a", Started: testTime},
+		{AttackID: 111, Code: "code-b", Started: testTime},
+	}
+
+	if err := processor.UpdateAttackRecords(context.Background(), "test_spreadsheet", config, records); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	sheetData := mockAPI.GetSheetData("Records - 123")
+	if len(sheetData) != 1 {
+		t.Fatalf("Expected 1 row for the shared attack ID, got %d", len(sheetData))
+	}
+
+	// An attack ID already in the sheet is filtered even under a new code
+	existing := &RecordsInfo{AttackIDs: map[int64]bool{111: true}}
+	later := []app.AttackRecord{{AttackID: 111, Code: "code-c", Started: testTime.Add(time.Hour)}}
+	if filtered := processor.FilterAndSortRecords(later, existing); len(filtered) != 0 {
+		t.Errorf("Expected existing attack ID to be filtered, got %d records", len(filtered))
+	}
+}
+
 func TestAttackRecordsProcessorUpdateAttackRecordsEmpty(t *testing.T) {
 	mockAPI := NewMockSheetsAPI()
 	processor := NewAttackRecordsProcessor(mockAPI)
@@ -417,3 +713,129 @@ func TestAttackRecordsProcessorTimeFormatting(t *testing.T) {
 		}
 	}
 }
+
+func TestAttackRecordsProcessorFilterAndSortBackfillRecords(t *testing.T) {
+	processor := NewAttackRecordsProcessor(NewMockSheetsAPI())
+	latest := time.Unix(1640995200, 0)
+	existing := &RecordsInfo{
+		AttackCodes:     map[string]bool{"code-written": true},
+		AttackIDs:       map[int64]bool{100: true},
+		LatestTimestamp: latest.Unix(),
+	}
+
+	records := []app.AttackRecord{
+		{AttackID: 300, Code: "code-newer", Started: latest.Add(time.Hour)},
+		{AttackID: 200, Code: "code-older", Started: latest.Add(-70 * time.Hour)},
+		{AttackID: 100, Code: "code-written", Started: latest},
+		{AttackID: 200, Code: "code-older", Started: latest.Add(-70 * time.Hour)},
+	}
+
+	// The regular filter drops anything at or before the latest timestamp
+	if filtered := processor.FilterAndSortRecords(records, existing); len(filtered) != 1 || filtered[0].AttackID != 300 {
+		t.Errorf("Expected only the newer attack past the cutoff, got %v", filtered)
+	}
+
+	// Backfilling keeps older attacks and only drops those already written or repeated
+	filtered := processor.FilterAndSortBackfillRecords(records, existing)
+	if len(filtered) != 2 || filtered[0].AttackID != 200 || filtered[1].AttackID != 300 {
+		t.Errorf("Expected the older and newer attacks in chronological order, got %v", filtered)
+	}
+}
+
+func TestAttackRecordsProcessorReadAttackRecords(t *testing.T) {
+	mockAPI := NewMockSheetsAPI()
+	processor := NewAttackRecordsProcessor(mockAPI)
+	factionID := 100
+	started := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	written := app.AttackRecord{
+		AttackID:          111,
+		Code:              "code-a",
+		Started:           started,
+		Ended:             started.Add(time.Minute),
+		Direction:         "Outgoing",
+		AttackerID:        1,
+		AttackerName:      "Ours",
+		AttackerFactionID: &factionID,
+		DefenderID:        2,
+		Result:            "Hospitalized",
+		RespectGain:       2.5,
+		ModifierFairFight: 3,
+		IsStealthed:       true,
+	}
+
+	mockAPI.SetSheetData("Records - 123", append([][]interface{}{recordsHeaderRow()}, processor.ConvertRecordsToRows([]app.AttackRecord{written, {AttackID: 222}})...))
+	records, err := processor.ReadAttackRecords(context.Background(), "test_spreadsheet", "Records - 123")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// The row without a code is skipped
+	if len(records) != 1 {
+		t.Fatalf("Expected 1 record, got %d", len(records))
+	}
+	record := records[0]
+	if record.AttackID != 111 || record.Code != "code-a" || !record.Started.Equal(started) || record.Direction != "Outgoing" {
+		t.Errorf("Unexpected identity fields: %+v", record)
+	}
+	if record.AttackerID != 1 || record.AttackerFactionID == nil || *record.AttackerFactionID != 100 || record.DefenderFactionID != nil {
+		t.Errorf("Unexpected attacker and defender fields: %+v", record)
+	}
+	if record.RespectGain != 2.5 || record.ModifierFairFight != 3 || !record.IsStealthed || record.Result != "Hospitalized" {
+		t.Errorf("Unexpected result fields: %+v", record)
+	}
+}
+
+func TestAttackRecordsProcessorUsesSheetHeaderLayout(t *testing.T) {
+	started := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	defaultLayout := NewAttackRecordsProcessor(nil)
+	existing := app.AttackRecord{AttackID: 111, Code: "code-a", Started: started, AttackerName: "Ours"}
+
+	mockAPI := NewMockSheetsAPI()
+	mockAPI.SetSheetData("Records - 123", append([][]interface{}{recordsHeaderRow()}, defaultLayout.ConvertRecordsToRows([]app.AttackRecord{existing})...))
+
+	// The column order changed after the sheet was created
+	processor := NewAttackRecordsProcessor(mockAPI)
+	processor.SetRecordsColumnOrder([]string{"Started", "Code", "Attack ID"})
+
+	info, err := processor.ReadExistingRecords(context.Background(), "test_spreadsheet", "Records - 123")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !info.AttackCodes["code-a"] || !info.AttackIDs[111] || info.LatestTimestamp != started.Unix() {
+		t.Errorf("Expected the existing record found by the sheet's header, got %+v", info)
+	}
+
+	records, err := processor.ReadAttackRecords(context.Background(), "test_spreadsheet", "Records - 123")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(records) != 1 || records[0].AttackerName != "Ours" {
+		t.Errorf("Expected the record read in the sheet's layout, got %+v", records)
+	}
+
+	// New rows follow the sheet's layout rather than the configured one
+	config := &app.SheetConfig{WarID: 123, RecordsTabName: "Records - 123"}
+	added := app.AttackRecord{AttackID: 222, Code: "code-b", Started: started.Add(time.Minute), AttackerName: "Also ours"}
+	if err := processor.UpdateAttackRecords(context.Background(), "test_spreadsheet", config, []app.AttackRecord{existing, added}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if mockAPI.lastUpdateRange != "'Records - 123'!A3:AL3" {
+		t.Errorf("Expected one row written across the full layout, got %s", mockAPI.lastUpdateRange)
+	}
+	if written := mockAPI.GetSheetData("Records - 123"); len(written) != 1 || written[0][1] != "code-b" || written[0][6] != "Also ours" {
+		t.Errorf("Expected the new record in the default layout, got %v", written)
+	}
+}
+
+func TestAttackRecordsProcessorRefusesUnknownHeader(t *testing.T) {
+	mockAPI := NewMockSheetsAPI()
+	mockAPI.SetSheetData("Records - 123", [][]interface{}{{"ID", "Attack Code", "Time"}, {111, "code-a", "2024-01-15 12:00:00"}})
+	processor := NewAttackRecordsProcessor(mockAPI)
+
+	if _, err := processor.ReadExistingRecords(context.Background(), "test_spreadsheet", "Records - 123"); err == nil {
+		t.Error("Expected an error for a header without Code and Started columns")
+	}
+	if _, err := processor.ReadAttackRecords(context.Background(), "test_spreadsheet", "Records - 123"); err == nil {
+		t.Error("Expected an error reading records under an unknown header")
+	}
+}
@@ -0,0 +1,71 @@
+package sheets
+
+import (
+	"context"
+	"fmt"
+
+	"torn_rw_stats/internal/app"
+
+	"github.com/rs/zerolog/log"
+)
+
+// RespectTrendManager handles the per-war respect trend sheets
+type RespectTrendManager struct {
+	api SheetsAPI
+}
+
+// NewRespectTrendManager creates a new respect trend manager with the given API client
+func NewRespectTrendManager(api SheetsAPI) *RespectTrendManager {
+	return &RespectTrendManager{
+		api: api,
+	}
+}
+
+// GenerateRespectTrendTabName creates a standardized respect trend tab name for a war
+func (m *RespectTrendManager) GenerateRespectTrendTabName(warID int) string {
+	return fmt.Sprintf("Respect Trend - %d", warID)
+}
+
+// GenerateRespectTrendHeaders creates the headers for respect trend sheets
+func (m *RespectTrendManager) GenerateRespectTrendHeaders() [][]interface{} {
+	return [][]interface{}{
+		{
+			"Window Start",
+			"Attacks",
+			"Avg Respect",
+		},
+	}
+}
+
+// UpdateRespectTrend rewrites the respect trend sheet for a war, creating it if needed
+func (m *RespectTrendManager) UpdateRespectTrend(ctx context.Context, spreadsheetID string, warID int, trend []app.RespectTrendWindow) error {
+	sheetName := m.GenerateRespectTrendTabName(warID)
+
+	rows := m.ConvertRespectTrendToRows(trend)
+	if err := rewritePerWarSheet(ctx, m.api, spreadsheetID, sheetName, m.GenerateRespectTrendHeaders(), rows); err != nil {
+		return fmt.Errorf("failed to update respect trend: %w", err)
+	}
+
+	log.Debug().
+		Int("war_id", warID).
+		Str("sheet_name", sheetName).
+		Int("windows", len(rows)).
+		Msg("Updated respect trend sheet")
+
+	return nil
+}
+
+// ConvertRespectTrendToRows converts respect trend windows into spreadsheet row format
+func (m *RespectTrendManager) ConvertRespectTrendToRows(trend []app.RespectTrendWindow) [][]interface{} {
+	rows := make([][]interface{}, len(trend))
+
+	for i, window := range trend {
+		rows[i] = []interface{}{
+			window.WindowStart.UTC().Format("2006-01-02 15:04:05"),
+			window.AttackCount,
+			fmt.Sprintf("%.2f", window.AvgRespect),
+		}
+	}
+
+	return rows
+}
@@ -9,6 +9,9 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
+// DefaultMaxStateChangeRows is the data row limit used when pruning without a configured limit
+const DefaultMaxStateChangeRows = 40000
+
 // StateChangeManager handles business logic for state change tracking
 // Separated from infrastructure concerns for better testability
 type StateChangeManager struct {
@@ -129,3 +132,44 @@ func (m *StateChangeManager) ConvertStateChangeToRow(record app.StateChangeRecor
 		record.StatusDescription, // Description
 	}
 }
+
+// PruneStateChangeRecords trims the oldest rows from a state change sheet once it holds more
+// than maxRows data rows, keeping the header and the newest maxRows rows. A non-positive
+// maxRows uses DefaultMaxStateChangeRows. Returns the number of rows dropped.
+func (m *StateChangeManager) PruneStateChangeRecords(ctx context.Context, spreadsheetID, sheetName string, maxRows int) (int, error) {
+	return PruneOldestRows(ctx, m.api, spreadsheetID, sheetName, maxRows)
+}
+
+// PruneOldestRows deletes the oldest rows from an append-only sheet once it holds more
+// than maxRows data rows, keeping its header row and the newest maxRows rows. Rows are
+// appended in time order, so the oldest are at the top. Only column A is read to count
+// the rows, and the kept rows are never rewritten. A non-positive maxRows uses
+// DefaultMaxStateChangeRows. Returns the number of rows dropped.
+func PruneOldestRows(ctx context.Context, api RowsAPI, spreadsheetID, sheetName string, maxRows int) (int, error) {
+	if maxRows <= 0 {
+		maxRows = DefaultMaxStateChangeRows
+	}
+
+	values, err := api.ReadSheet(ctx, spreadsheetID, fmt.Sprintf("'%s'!A:A", sheetName))
+	if err != nil {
+		return 0, fmt.Errorf("failed to read %s row count: %w", sheetName, err)
+	}
+
+	// First row is the header
+	if len(values) <= maxRows+1 {
+		return 0, nil
+	}
+
+	dropCount := len(values) - 1 - maxRows
+	if err := api.DeleteRows(ctx, spreadsheetID, sheetName, 2, dropCount); err != nil {
+		return 0, fmt.Errorf("failed to delete oldest rows from %s: %w", sheetName, err)
+	}
+
+	log.Info().
+		Str("sheet_name", sheetName).
+		Int("rows_dropped", dropCount).
+		Int("rows_kept", maxRows).
+		Msg("Pruned oldest sheet rows")
+
+	return dropCount, nil
+}
@@ -0,0 +1,59 @@
+package sheets
+
+import (
+	"context"
+	"testing"
+)
+
+func TestStateChangeManagerPruneStateChangeRecords(t *testing.T) {
+	mockAPI := NewMockSheetsAPI()
+	manager := NewStateChangeManager(mockAPI)
+	sheetName := manager.GenerateStateChangeSheetName(200)
+
+	data := [][]interface{}{manager.GenerateStateChangeHeaders()[0]}
+	for i := 0; i < 50; i++ {
+		data = append(data, []interface{}{int64(1700000000 + i), "2023-11-14", "22:13:20", i})
+	}
+	mockAPI.SetSheetData(sheetName, data)
+
+	dropped, err := manager.PruneStateChangeRecords(context.Background(), "test-sheet-id", sheetName, 30)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if dropped != 20 {
+		t.Errorf("Expected 20 rows dropped, got %d", dropped)
+	}
+
+	rows := mockAPI.GetSheetData(sheetName)
+	if len(rows) != 31 {
+		t.Fatalf("Expected header plus 30 rows, got %d rows", len(rows))
+	}
+	if rows[0][0] != "Timestamp" {
+		t.Errorf("Expected header to be preserved, got %v", rows[0])
+	}
+	// The 20 oldest rows are dropped, so the first data row is the 21st original one
+	if rows[1][3] != 20 || rows[30][3] != 49 {
+		t.Errorf("Expected rows 20..49 to remain, got first %v and last %v", rows[1][3], rows[30][3])
+	}
+	// Only the row count is read and the kept rows are never rewritten
+	if mockAPI.lastReadRange != "'"+sheetName+"'!A:A" || len(mockAPI.updateRanges) != 0 {
+		t.Errorf("Expected a column A read and no rewrite, read %q and updated %v", mockAPI.lastReadRange, mockAPI.updateRanges)
+	}
+}
+
+func TestStateChangeManagerPruneStateChangeRecords_UnderLimit(t *testing.T) {
+	mockAPI := NewMockSheetsAPI()
+	manager := NewStateChangeManager(mockAPI)
+	sheetName := manager.GenerateStateChangeSheetName(200)
+
+	data := [][]interface{}{manager.GenerateStateChangeHeaders()[0], {int64(1700000000)}}
+	mockAPI.SetSheetData(sheetName, data)
+
+	dropped, err := manager.PruneStateChangeRecords(context.Background(), "test-sheet-id", sheetName, 0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if dropped != 0 || mockAPI.lastUpdateRange != "" {
+		t.Errorf("Expected no rewrite under the limit, dropped %d and updated %q", dropped, mockAPI.lastUpdateRange)
+	}
+}
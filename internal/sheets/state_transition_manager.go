@@ -0,0 +1,90 @@
+package sheets
+
+import (
+	"context"
+	"fmt"
+
+	"torn_rw_stats/internal/app"
+
+	"github.com/rs/zerolog/log"
+)
+
+// StateTransitionTabName is the sheet holding the timeline of our war state transitions
+const StateTransitionTabName = "State Transitions"
+
+// StateTransitionManager handles the war state transition log sheet
+type StateTransitionManager struct {
+	api SheetsAPI
+}
+
+// NewStateTransitionManager creates a new state transition manager with the given API client
+func NewStateTransitionManager(api SheetsAPI) *StateTransitionManager {
+	return &StateTransitionManager{
+		api: api,
+	}
+}
+
+// GenerateStateTransitionHeaders creates the headers for the state transition log sheet
+func (m *StateTransitionManager) GenerateStateTransitionHeaders() [][]interface{} {
+	return [][]interface{}{
+		{
+			"Timestamp",
+			"From State",
+			"To State",
+			"War ID",
+		},
+	}
+}
+
+// AppendStateTransition appends a transition row to the log sheet, creating it if needed
+func (m *StateTransitionManager) AppendStateTransition(ctx context.Context, spreadsheetID string, transition app.WarStateTransition) error {
+	exists, err := m.api.SheetExists(ctx, spreadsheetID, StateTransitionTabName)
+	if err != nil {
+		return fmt.Errorf("failed to check if state transition sheet exists: %w", err)
+	}
+
+	if !exists {
+		log.Info().
+			Str("sheet_name", StateTransitionTabName).
+			Msg("Creating state transition sheet")
+
+		if err := m.api.CreateSheet(ctx, spreadsheetID, StateTransitionTabName); err != nil {
+			return fmt.Errorf("failed to create state transition sheet: %w", err)
+		}
+
+		rangeSpec := fmt.Sprintf("'%s'!A1", StateTransitionTabName)
+		if err := m.api.UpdateRange(ctx, spreadsheetID, rangeSpec, m.GenerateStateTransitionHeaders()); err != nil {
+			return fmt.Errorf("failed to write state transition headers: %w", err)
+		}
+	}
+
+	rangeSpec := fmt.Sprintf("'%s'!A:D", StateTransitionTabName)
+	if err := m.api.AppendRows(ctx, spreadsheetID, rangeSpec, m.ConvertStateTransitionToRows(transition)); err != nil {
+		return fmt.Errorf("failed to append state transition: %w", err)
+	}
+
+	log.Debug().
+		Str("from_state", transition.FromState).
+		Str("to_state", transition.ToState).
+		Int("war_id", transition.WarID).
+		Msg("Logged war state transition")
+
+	return nil
+}
+
+// ConvertStateTransitionToRows converts a state transition into spreadsheet row format
+func (m *StateTransitionManager) ConvertStateTransitionToRows(transition app.WarStateTransition) [][]interface{} {
+	warID := interface{}("")
+	if transition.WarID != 0 {
+		warID = transition.WarID
+	}
+
+	return [][]interface{}{
+		{
+			transition.Timestamp.UTC().Format("2006-01-02 15:04:05"),
+			transition.FromState,
+			transition.ToState,
+			warID,
+		},
+	}
+}
@@ -0,0 +1,100 @@
+package sheets
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"torn_rw_stats/internal/app"
+
+	"github.com/rs/zerolog/log"
+)
+
+// DefaultMaxStatusHistoryRows is the data row limit for status history sheets used
+// when no limit is configured
+const DefaultMaxStatusHistoryRows = 20000
+
+// StatusHistoryManager handles the per-faction sheets of timestamped Status v2 snapshots
+type StatusHistoryManager struct {
+	api      SheetsAPI
+	statusV2 *StatusV2Manager
+}
+
+// NewStatusHistoryManager creates a new status history manager with the given API client
+func NewStatusHistoryManager(api SheetsAPI) *StatusHistoryManager {
+	return &StatusHistoryManager{
+		api:      api,
+		statusV2: NewStatusV2Manager(api),
+	}
+}
+
+// GenerateStatusHistorySheetName creates a standardized status history sheet name for a faction
+func (m *StatusHistoryManager) GenerateStatusHistorySheetName(factionID int) string {
+	return fmt.Sprintf("Status History - %d", factionID)
+}
+
+// GenerateStatusHistoryHeaders creates the headers for status history sheets: the
+// snapshot time followed by the Status v2 columns
+func (m *StatusHistoryManager) GenerateStatusHistoryHeaders() [][]interface{} {
+	headers := append([]interface{}{"Snapshot"}, m.statusV2.GenerateStatusV2Headers()[0]...)
+	return [][]interface{}{headers}
+}
+
+// AppendStatusSnapshot appends one block of rows, all stamped with the snapshot time, to
+// the faction's status history sheet, creating it if needed. The oldest rows are then
+// dropped so at most maxRows data rows remain; a non-positive maxRows uses
+// DefaultMaxStatusHistoryRows.
+func (m *StatusHistoryManager) AppendStatusSnapshot(ctx context.Context, spreadsheetID string, factionID int, snapshotTime time.Time, records []app.StatusV2Record, maxRows int) error {
+	if len(records) == 0 {
+		return nil
+	}
+	if maxRows <= 0 {
+		maxRows = DefaultMaxStatusHistoryRows
+	}
+
+	sheetName := m.GenerateStatusHistorySheetName(factionID)
+
+	exists, err := m.api.SheetExists(ctx, spreadsheetID, sheetName)
+	if err != nil {
+		return fmt.Errorf("failed to check if status history sheet exists: %w", err)
+	}
+
+	if !exists {
+		log.Info().
+			Str("sheet_name", sheetName).
+			Int("faction_id", factionID).
+			Msg("Creating status history sheet")
+
+		if err := m.api.CreateSheet(ctx, spreadsheetID, sheetName); err != nil {
+			return fmt.Errorf("failed to create status history sheet: %w", err)
+		}
+
+		rangeSpec := fmt.Sprintf("'%s'!A1", sheetName)
+		if err := m.api.UpdateRange(ctx, spreadsheetID, rangeSpec, m.GenerateStatusHistoryHeaders()); err != nil {
+			return fmt.Errorf("failed to write status history headers: %w", err)
+		}
+	}
+
+	snapshot := snapshotTime.UTC().Format("2006-01-02 15:04:05")
+	statusRows := m.statusV2.ConvertStatusV2RecordsToRows(records)
+	rows := make([][]interface{}, len(statusRows))
+	for i, row := range statusRows {
+		rows[i] = append([]interface{}{snapshot}, row...)
+	}
+
+	if err := m.api.AppendRows(ctx, spreadsheetID, fmt.Sprintf("'%s'!A:M", sheetName), rows); err != nil {
+		return fmt.Errorf("failed to append status snapshot: %w", err)
+	}
+
+	if _, err := PruneOldestRows(ctx, m.api, spreadsheetID, sheetName, maxRows); err != nil {
+		return fmt.Errorf("failed to prune status history sheet: %w", err)
+	}
+
+	log.Debug().
+		Str("sheet_name", sheetName).
+		Str("snapshot", snapshot).
+		Int("members", len(rows)).
+		Msg("Appended status history snapshot")
+
+	return nil
+}
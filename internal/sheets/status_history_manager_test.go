@@ -0,0 +1,66 @@
+package sheets
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"torn_rw_stats/internal/app"
+)
+
+func TestStatusHistoryManagerAppendStatusSnapshot(t *testing.T) {
+	mockAPI := NewMockSheetsAPI()
+	manager := NewStatusHistoryManager(mockAPI)
+	sheetName := manager.GenerateStatusHistorySheetName(200)
+
+	records := []app.StatusV2Record{
+		{Name: "Enemy1", MemberID: "1", Level: 50, Location: "Torn"},
+		{Name: "Enemy2", MemberID: "2", Level: 40, Location: "Mexico"},
+	}
+	first := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	second := first.Add(5 * time.Minute)
+
+	for _, snapshot := range []time.Time{first, second} {
+		if err := manager.AppendStatusSnapshot(context.Background(), "test-sheet-id", 200, snapshot, records, 0); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	rows := mockAPI.GetSheetData(sheetName)
+	if len(rows) != 5 {
+		t.Fatalf("Expected header plus two blocks of 2 rows, got %d rows", len(rows))
+	}
+	if rows[0][0] != "Snapshot" || rows[0][1] != "Player Name" {
+		t.Errorf("Unexpected header row: %v", rows[0])
+	}
+	if rows[1][0] != "2025-01-01 12:00:00" || rows[2][0] != "2025-01-01 12:00:00" {
+		t.Errorf("Expected first block stamped 12:00:00, got %v and %v", rows[1][0], rows[2][0])
+	}
+	if rows[3][0] != "2025-01-01 12:05:00" || rows[4][1] != "Enemy2" {
+		t.Errorf("Unexpected second block: %v, %v", rows[3], rows[4])
+	}
+}
+
+func TestStatusHistoryManagerAppendStatusSnapshot_Prunes(t *testing.T) {
+	mockAPI := NewMockSheetsAPI()
+	manager := NewStatusHistoryManager(mockAPI)
+	sheetName := manager.GenerateStatusHistorySheetName(200)
+
+	records := []app.StatusV2Record{{Name: "Enemy1", MemberID: "1"}, {Name: "Enemy2", MemberID: "2"}}
+	first := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 3; i++ {
+		snapshot := first.Add(time.Duration(i) * 5 * time.Minute)
+		if err := manager.AppendStatusSnapshot(context.Background(), "test-sheet-id", 200, snapshot, records, 4); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	rows := mockAPI.GetSheetData(sheetName)
+	if len(rows) != 5 {
+		t.Fatalf("Expected header plus the newest 4 rows, got %d rows", len(rows))
+	}
+	if rows[1][0] != "2025-01-01 12:05:00" {
+		t.Errorf("Expected the oldest snapshot to be dropped, first row is %v", rows[1])
+	}
+}
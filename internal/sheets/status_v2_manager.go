@@ -87,6 +87,8 @@ func (m *StatusV2Manager) GenerateStatusV2Headers() [][]interface{} {
 			"Arrival",
 			"BusinessArrival", // Alternative arrival time for business class detection
 			"Until",           // StatusUntil timestamp
+			"Online",          // Online/Idle/Offline from LastAction
+			"Last Action",     // Time since last action, e.g. "5 minutes ago"
 		},
 	}
 }
@@ -109,20 +111,20 @@ func (m *StatusV2Manager) UpdateStatusV2(ctx context.Context, spreadsheetID, she
 	rows := m.ConvertStatusV2RecordsToRows(records)
 
 	// Clear existing content (except headers) and write new data
-	rangeSpec := fmt.Sprintf("%s!A2:J", sheetName)
+	rangeSpec := fmt.Sprintf("%s!A2:L", sheetName)
 	if err := m.api.ClearRange(ctx, spreadsheetID, rangeSpec); err != nil {
 		return fmt.Errorf("failed to clear Status v2 data: %w", err)
 	}
 
 	// Ensure sheet has enough capacity
 	requiredRows := len(rows) + 1 // +1 for header
-	requiredCols := 10            // Updated for BusinessArrival column
+	requiredCols := 12            // Updated for Last Action column
 	if err := m.api.EnsureSheetCapacity(ctx, spreadsheetID, sheetName, requiredRows, requiredCols); err != nil {
 		return fmt.Errorf("failed to ensure sheet capacity: %w", err)
 	}
 
 	// Write the data starting from row 2 using UpdateRange to avoid blank row accumulation
-	dataRangeSpec := fmt.Sprintf("%s!A2:J%d", sheetName, len(rows)+1)
+	dataRangeSpec := fmt.Sprintf("%s!A2:L%d", sheetName, len(rows)+1)
 	if err := m.api.UpdateRange(ctx, spreadsheetID, dataRangeSpec, rows); err != nil {
 		return fmt.Errorf("failed to update Status v2 records: %w", err)
 	}
@@ -156,16 +158,18 @@ func (m *StatusV2Manager) ConvertStatusV2RecordsToRows(records []app.StatusV2Rec
 		}
 
 		rows[i] = []interface{}{
-			record.Name,            // Player Name
-			record.Level,           // Level
-			record.State,           // State (LastActionStatus)
-			record.Status,          // Status (Status Description)
-			record.Location,        // Location
-			record.Countdown,       // Countdown (calculated from StatusUntil)
-			record.Departure,       // Departure time (manual adjustment preserved)
-			record.Arrival,         // Arrival time (manual adjustment preserved)
-			record.BusinessArrival, // Business class arrival time
-			untilStr,               // Until timestamp
+			record.Name,               // Player Name
+			record.Level,              // Level
+			record.State,              // State (LastActionStatus)
+			record.Status,             // Status (Status Description)
+			record.Location,           // Location
+			record.Countdown,          // Countdown (calculated from StatusUntil)
+			record.Departure,          // Departure time (manual adjustment preserved)
+			record.Arrival,            // Arrival time (manual adjustment preserved)
+			record.BusinessArrival,    // Business class arrival time
+			untilStr,                  // Until timestamp
+			record.Online,             // Online/Idle/Offline
+			record.LastActionRelative, // Time since last action
 		}
 	}
 
@@ -0,0 +1,78 @@
+package sheets
+
+import (
+	"context"
+	"fmt"
+
+	"torn_rw_stats/internal/app"
+
+	"github.com/rs/zerolog/log"
+)
+
+// TargetPriorityManager handles the per-faction enemy target priority sheets
+type TargetPriorityManager struct {
+	api SheetsAPI
+}
+
+// NewTargetPriorityManager creates a new target priority manager with the given API client
+func NewTargetPriorityManager(api SheetsAPI) *TargetPriorityManager {
+	return &TargetPriorityManager{
+		api: api,
+	}
+}
+
+// GenerateTargetPriorityTabName creates a standardized target priority tab name for a faction
+func (m *TargetPriorityManager) GenerateTargetPriorityTabName(factionID int) string {
+	return fmt.Sprintf("Target Priority - %d", factionID)
+}
+
+// GenerateTargetPriorityHeaders creates the headers for target priority sheets
+func (m *TargetPriorityManager) GenerateTargetPriorityHeaders() [][]interface{} {
+	return [][]interface{}{
+		{
+			"Name",
+			"Member ID",
+			"Level",
+			"Online",
+			"Status",
+			"Score",
+		},
+	}
+}
+
+// UpdateTargetPriority rewrites the ranked enemy members of a faction, best target
+// first, creating the sheet if needed
+func (m *TargetPriorityManager) UpdateTargetPriority(ctx context.Context, spreadsheetID string, factionID int, entries []app.TargetPriorityEntry) error {
+	sheetName := m.GenerateTargetPriorityTabName(factionID)
+
+	rows := m.ConvertTargetPriorityToRows(entries)
+	if err := rewritePerWarSheet(ctx, m.api, spreadsheetID, sheetName, m.GenerateTargetPriorityHeaders(), rows); err != nil {
+		return fmt.Errorf("failed to update target priority: %w", err)
+	}
+
+	log.Debug().
+		Int("faction_id", factionID).
+		Str("sheet_name", sheetName).
+		Int("members", len(rows)).
+		Msg("Updated target priority sheet")
+
+	return nil
+}
+
+// ConvertTargetPriorityToRows converts ranked members into spreadsheet row format
+func (m *TargetPriorityManager) ConvertTargetPriorityToRows(entries []app.TargetPriorityEntry) [][]interface{} {
+	rows := make([][]interface{}, len(entries))
+
+	for i, entry := range entries {
+		rows[i] = []interface{}{
+			entry.Name,
+			entry.MemberID,
+			entry.Level,
+			entry.Online,
+			entry.State,
+			entry.TargetScore,
+		}
+	}
+
+	return rows
+}
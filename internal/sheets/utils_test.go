@@ -250,3 +250,54 @@ func TestStringConversion(t *testing.T) {
 		}
 	})
 }
+
+// TestCellFloat64 tests Cell.Float64() with various inputs
+func TestCellFloat64(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    interface{}
+		expected float64
+	}{
+		{"nil input", nil, 0},
+		{"float64 input", 45.67, 45.67},
+		{"int input", 42, 42},
+		{"int64 input", int64(7), 7},
+		{"string number", "1.25", 1.25},
+		{"string non-number", "not_a_number", 0},
+		{"bool input", true, 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := NewCell(tc.input).Float64()
+			if result != tc.expected {
+				t.Errorf("Expected %v, got %v", tc.expected, result)
+			}
+		})
+	}
+}
+
+// TestCellBool tests Cell.Bool() with various inputs
+func TestCellBool(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    interface{}
+		expected bool
+	}{
+		{"nil input", nil, false},
+		{"bool input", true, true},
+		{"sheets text", "TRUE", true},
+		{"false text", "FALSE", false},
+		{"other text", "yes", false},
+		{"int input", 1, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := NewCell(tc.input).Bool()
+			if result != tc.expected {
+				t.Errorf("Expected %v, got %v", tc.expected, result)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package sheets
 import (
 	"context"
 	"fmt"
+	"sort"
 
 	"torn_rw_stats/internal/app"
 
@@ -12,7 +13,8 @@ import (
 // WarSheetsManager handles business logic for war sheet management
 // Separated from infrastructure concerns for better testability
 type WarSheetsManager struct {
-	api SheetsAPI
+	api                SheetsAPI
+	recordsColumnOrder []string
 }
 
 // NewWarSheetsManager creates a new war sheets manager with the given API client
@@ -22,6 +24,12 @@ func NewWarSheetsManager(api SheetsAPI) *WarSheetsManager {
 	}
 }
 
+// SetRecordsColumnOrder sets the column order of records sheet headers; an empty order
+// keeps the default layout. Callers are expected to validate it with ValidateRecordsColumnOrder.
+func (m *WarSheetsManager) SetRecordsColumnOrder(order []string) {
+	m.recordsColumnOrder = order
+}
+
 // EnsureWarSheets creates summary and records sheets for a war if they don't exist
 func (m *WarSheetsManager) EnsureWarSheets(ctx context.Context, spreadsheetID string, war *app.War) (*app.SheetConfig, error) {
 	summaryTabName := m.GenerateSummaryTabName(war.ID)
@@ -54,33 +62,58 @@ func (m *WarSheetsManager) EnsureWarSheets(ctx context.Context, spreadsheetID st
 		}
 	}
 
-	// Check if records sheet exists
-	recordsExists, err := m.api.SheetExists(ctx, spreadsheetID, recordsTabName)
+	if err := m.ensureRecordsSheet(ctx, spreadsheetID, recordsTabName); err != nil {
+		return nil, err
+	}
+
+	return &app.SheetConfig{
+		WarID:          war.ID,
+		SummaryTabName: summaryTabName,
+		RecordsTabName: recordsTabName,
+		SpreadsheetID:  spreadsheetID,
+	}, nil
+}
+
+// EnsureDirectionSheets creates the outgoing and incoming records sheets for a war
+// if they don't exist and records their names on the config
+func (m *WarSheetsManager) EnsureDirectionSheets(ctx context.Context, spreadsheetID string, config *app.SheetConfig) error {
+	outgoingTabName := m.GenerateOutgoingTabName(config.WarID)
+	incomingTabName := m.GenerateIncomingTabName(config.WarID)
+
+	for _, tabName := range []string{outgoingTabName, incomingTabName} {
+		if err := m.ensureRecordsSheet(ctx, spreadsheetID, tabName); err != nil {
+			return err
+		}
+	}
+
+	config.OutgoingTabName = outgoingTabName
+	config.IncomingTabName = incomingTabName
+	return nil
+}
+
+// ensureRecordsSheet creates and initializes a records sheet if it doesn't exist
+func (m *WarSheetsManager) ensureRecordsSheet(ctx context.Context, spreadsheetID, tabName string) error {
+	recordsExists, err := m.api.SheetExists(ctx, spreadsheetID, tabName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to check if records sheet exists: %w", err)
+		return fmt.Errorf("failed to check if records sheet exists: %w", err)
 	}
 
 	if !recordsExists {
 		log.Info().
-			Str("sheet_name", recordsTabName).
+			Str("sheet_name", tabName).
 			Msg("Creating records sheet")
 
-		if err := m.api.CreateSheet(ctx, spreadsheetID, recordsTabName); err != nil {
-			return nil, fmt.Errorf("failed to create records sheet: %w", err)
+		if err := m.api.CreateSheet(ctx, spreadsheetID, tabName); err != nil {
+			return fmt.Errorf("failed to create records sheet: %w", err)
 		}
 
 		// Initialize records sheet with headers
-		if err := m.InitializeRecordsSheet(ctx, spreadsheetID, recordsTabName); err != nil {
-			return nil, fmt.Errorf("failed to initialize records sheet: %w", err)
+		if err := m.InitializeRecordsSheet(ctx, spreadsheetID, tabName); err != nil {
+			return fmt.Errorf("failed to initialize records sheet: %w", err)
 		}
 	}
 
-	return &app.SheetConfig{
-		WarID:          war.ID,
-		SummaryTabName: summaryTabName,
-		RecordsTabName: recordsTabName,
-		SpreadsheetID:  spreadsheetID,
-	}, nil
+	return nil
 }
 
 // GenerateSummaryTabName creates a standardized summary tab name for a war
@@ -93,6 +126,16 @@ func (m *WarSheetsManager) GenerateRecordsTabName(warID int) string {
 	return fmt.Sprintf("Records - %d", warID)
 }
 
+// GenerateOutgoingTabName creates a standardized outgoing records tab name for a war
+func (m *WarSheetsManager) GenerateOutgoingTabName(warID int) string {
+	return fmt.Sprintf("Outgoing - %d", warID)
+}
+
+// GenerateIncomingTabName creates a standardized incoming records tab name for a war
+func (m *WarSheetsManager) GenerateIncomingTabName(warID int) string {
+	return fmt.Sprintf("Incoming - %d", warID)
+}
+
 // InitializeSummarySheet sets up headers and initial content for a summary sheet
 func (m *WarSheetsManager) InitializeSummarySheet(ctx context.Context, spreadsheetID, sheetName string) error {
 	headers := m.GenerateSummarySheetHeaders()
@@ -137,6 +180,38 @@ func (m *WarSheetsManager) GenerateSummarySheetHeaders() [][]interface{} {
 		{"Respect Gained", ""},
 		{"Respect Lost", ""},
 		{"Net Respect", ""},
+		{},
+		{"Win Rate Breakdown"},
+		{"Strict Win Rate", ""},
+		{"Half-Credit Win Rate", ""},
+		{},
+		{"Defensive Statistics"},
+		{"Defensive Attacks", ""},
+		{"Defensive Success Rate", ""},
+		{},
+		{"War Type", ""},
+		{},
+		{"Raid Progress"},
+		{"Target Score", ""},
+		{"Progress", ""},
+		{},
+		{"Average Modifiers"},
+		{"Fair Fight", ""},
+		{"War", ""},
+		{"Retaliation", ""},
+		{"Group", ""},
+		{"Overseas", ""},
+		{"Chain", ""},
+		{"Warlord", ""},
+		{},
+		{"Overseas Attacks", ""},
+		{"Interrupted Attacks", ""},
+		{},
+		{"Outgoing Attacks", ""},
+		{"Incoming Attacks", ""},
+		{"Unknown Direction Attacks", ""},
+		{},
+		{"Attacks to Overtake", ""},
 	}
 }
 
@@ -157,73 +232,101 @@ func (m *WarSheetsManager) InitializeRecordsSheet(ctx context.Context, spreadshe
 	return nil
 }
 
-// GenerateRecordsSheetHeaders creates the standard headers for attack records sheets
+// GenerateRecordsSheetHeaders creates the headers for attack records sheets in the
+// configured column order
 func (m *WarSheetsManager) GenerateRecordsSheetHeaders() [][]interface{} {
-	return [][]interface{}{
-		{
-			"Attack ID",
-			"Code",
-			"Started",
-			"Ended",
-			"Direction",
-			"Attacker ID",
-			"Attacker Name",
-			"Attacker Level",
-			"Attacker Faction ID",
-			"Attacker Faction Name",
-			"Defender ID",
-			"Defender Name",
-			"Defender Level",
-			"Defender Faction ID",
-			"Defender Faction Name",
-			"Result",
-			"Respect Gain",
-			"Respect Loss",
-			"Chain",
-			"Is Interrupted",
-			"Is Stealthed",
-			"Is Raid",
-			"Is Ranked War",
-			"Modifier Fair Fight",
-			"Modifier War",
-			"Modifier Retaliation",
-			"Modifier Group",
-			"Modifier Overseas",
-			"Modifier Chain",
-			"Modifier Warlord",
-			"Finishing Hit Name",
-			"Finishing Hit Value",
-		},
+	names := recordsColumnNames(m.recordsColumnOrder)
+	headers := make([]interface{}, len(names))
+	for i, name := range names {
+		headers[i] = name
 	}
+	return [][]interface{}{headers}
 }
 
 // UpdateWarSummary updates the summary sheet with current war statistics
 func (m *WarSheetsManager) UpdateWarSummary(ctx context.Context, spreadsheetID string, config *app.SheetConfig, summary *app.WarSummary) error {
-	// Generate summary data rows
-	summaryData := m.ConvertSummaryToRows(summary)
-
-	// Update the summary data (starting from row 3, column B to avoid overwriting labels)
-	rangeSpec := fmt.Sprintf("%s!B3:B%d", config.SummaryTabName, 2+len(summaryData))
-
-	// Convert to the format expected by UpdateRange
-	values := make([][]interface{}, len(summaryData))
-	for i, row := range summaryData {
-		values[i] = []interface{}{row}
-	}
+	// Labels are rewritten with the values so sheets created before a row was added
+	// pick up its label instead of showing it unlabelled or under a stale one
+	values := m.ConvertSummaryToLabelledRows(summary)
+	rangeSpec := fmt.Sprintf("%s!A3:B%d", config.SummaryTabName, 2+len(values))
 
 	if err := m.api.UpdateRange(ctx, spreadsheetID, rangeSpec, values); err != nil {
 		return fmt.Errorf("failed to update war summary: %w", err)
 	}
 
+	if err := m.updateOutcomeDistribution(ctx, spreadsheetID, config.SummaryTabName, summary); err != nil {
+		return err
+	}
+
 	log.Debug().
 		Int("war_id", config.WarID).
 		Str("sheet_name", config.SummaryTabName).
-		Int("data_rows", len(summaryData)).
+		Int("data_rows", len(values)).
 		Msg("Updated war summary sheet")
 
 	return nil
 }
 
+// ConvertSummaryToLabelledRows pairs each summary value with its label from the summary
+// sheet headers, giving the label and value rows written from row 3 onwards
+func (m *WarSheetsManager) ConvertSummaryToLabelledRows(summary *app.WarSummary) [][]interface{} {
+	// The first two header rows are the title and a blank row
+	headers := m.GenerateSummarySheetHeaders()[2:]
+	summaryData := m.ConvertSummaryToRows(summary)
+
+	rows := make([][]interface{}, len(summaryData))
+	for i, value := range summaryData {
+		var label interface{} = ""
+		if i < len(headers) && len(headers[i]) > 0 {
+			label = headers[i][0]
+		}
+		rows[i] = []interface{}{label, value}
+	}
+	return rows
+}
+
+// updateOutcomeDistribution rewrites the outcome distribution block below the fixed
+// summary labels. The set of results varies between wars, so the block writes its own
+// labels and is cleared first to drop rows left over from a longer previous block.
+func (m *WarSheetsManager) updateOutcomeDistribution(ctx context.Context, spreadsheetID, sheetName string, summary *app.WarSummary) error {
+	// One blank row separates the block from the fixed labels
+	startRow := len(m.GenerateSummarySheetHeaders()) + 2
+	rows := m.ConvertOutcomeDistributionToRows(summary)
+
+	if err := m.api.ClearRange(ctx, spreadsheetID, fmt.Sprintf("%s!A%d:B", sheetName, startRow)); err != nil {
+		return fmt.Errorf("failed to clear outcome distribution: %w", err)
+	}
+
+	rangeSpec := fmt.Sprintf("%s!A%d:B%d", sheetName, startRow, startRow+len(rows)-1)
+	if err := m.api.UpdateRange(ctx, spreadsheetID, rangeSpec, rows); err != nil {
+		return fmt.Errorf("failed to update outcome distribution: %w", err)
+	}
+
+	return nil
+}
+
+// ConvertOutcomeDistributionToRows converts a summary's outcome counts into a titled
+// block of label and count rows, most common result first
+func (m *WarSheetsManager) ConvertOutcomeDistributionToRows(summary *app.WarSummary) [][]interface{} {
+	results := make([]string, 0, len(summary.OutcomeCounts))
+	for result := range summary.OutcomeCounts {
+		results = append(results, result)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if summary.OutcomeCounts[results[i]] != summary.OutcomeCounts[results[j]] {
+			return summary.OutcomeCounts[results[i]] > summary.OutcomeCounts[results[j]]
+		}
+		return results[i] < results[j]
+	})
+
+	rows := make([][]interface{}, 0, len(results)+1)
+	rows = append(rows, []interface{}{"Attack Outcomes", ""})
+	for _, result := range results {
+		rows = append(rows, []interface{}{result, summary.OutcomeCounts[result]})
+	}
+	return rows
+}
+
 // ConvertSummaryToRows converts a WarSummary into spreadsheet row format
 func (m *WarSheetsManager) ConvertSummaryToRows(summary *app.WarSummary) []interface{} {
 	endTimeStr := "Ongoing"
@@ -231,33 +334,73 @@ func (m *WarSheetsManager) ConvertSummaryToRows(summary *app.WarSummary) []inter
 		endTimeStr = summary.EndTime.UTC().Format("2006-01-02 15:04:05")
 	}
 
-	winRate := 0.0
-	if summary.TotalAttacks > 0 {
-		winRate = float64(summary.AttacksWon) / float64(summary.TotalAttacks) * 100
+	// Raid progress only applies to raid wars; other wars leave the cells blank
+	var targetScore, progress interface{} = "", ""
+	if summary.WarType == app.WarTypeRaid {
+		targetScore = summary.TargetScore
+		progress = fmt.Sprintf("%.1f%%", summary.ProgressPercent)
+	}
+
+	// The overtake count is unknown until we have a successful attack to average
+	var attacksToOvertake interface{} = ""
+	if summary.AttacksToOvertake >= 0 {
+		attacksToOvertake = summary.AttacksToOvertake
 	}
 
 	return []interface{}{
 		summary.WarID,  // War ID
 		summary.Status, // Status
 		summary.StartTime.UTC().Format("2006-01-02 15:04:05"), // Start Time
-		endTimeStr,                     // End Time
-		"",                             // Empty row
-		summary.OurFaction.Name,        // Our Faction Name
-		summary.EnemyFaction.Name,      // Enemy Faction Name
-		"",                             // Empty row
-		"",                             // Current Scores header
-		summary.OurFaction.Score,       // Our Score
-		summary.EnemyFaction.Score,     // Enemy Score
-		"",                             // Empty row
-		"",                             // Attack Statistics header
-		summary.TotalAttacks,           // Total Attacks
-		summary.AttacksWon,             // Attacks Won
-		summary.AttacksLost,            // Attacks Lost
-		fmt.Sprintf("%.1f%%", winRate), // Win Rate
-		"",                             // Empty row
-		"",                             // Respect Statistics header
-		summary.RespectGained,          // Respect Gained
-		summary.RespectLost,            // Respect Lost
-		summary.RespectGained - summary.RespectLost, // Net Respect
+		endTimeStr,                             // End Time
+		"",                                     // Empty row
+		summary.OurFaction.Name,                // Our Faction Name
+		summary.EnemyFaction.Name,              // Enemy Faction Name
+		"",                                     // Empty row
+		"",                                     // Current Scores header
+		summary.OurFaction.Score,               // Our Score
+		summary.EnemyFaction.Score,             // Enemy Score
+		"",                                     // Empty row
+		"",                                     // Attack Statistics header
+		summary.TotalAttacks,                   // Total Attacks
+		summary.AttacksWon,                     // Attacks Won
+		summary.AttacksLost,                    // Attacks Lost
+		fmt.Sprintf("%.1f%%", summary.WinRate), // Win Rate
+		"",                                     // Empty row
+		"",                                     // Respect Statistics header
+		summary.RespectGained,                  // Respect Gained
+		summary.RespectLost,                    // Respect Lost
+		summary.NetRespect,                     // Net Respect
+		"",                                     // Empty row
+		"",                                     // Win Rate Breakdown header
+		fmt.Sprintf("%.1f%%", summary.StrictWinRate),     // Strict Win Rate
+		fmt.Sprintf("%.1f%%", summary.HalfCreditWinRate), // Half-Credit Win Rate
+		"",                       // Empty row
+		"",                       // Defensive Statistics header
+		summary.DefensiveAttacks, // Defensive Attacks
+		fmt.Sprintf("%.1f%%", summary.DefensiveSuccessRate), // Defensive Success Rate
+		"",              // Empty row
+		summary.WarType, // War Type
+		"",              // Empty row
+		"",              // Raid Progress header
+		targetScore,     // Target Score
+		progress,        // Progress
+		"",              // Empty row
+		"",              // Average Modifiers header
+		fmt.Sprintf("%.2f", summary.AverageModifiers.FairFight),   // Fair Fight
+		fmt.Sprintf("%.2f", summary.AverageModifiers.War),         // War
+		fmt.Sprintf("%.2f", summary.AverageModifiers.Retaliation), // Retaliation
+		fmt.Sprintf("%.2f", summary.AverageModifiers.Group),       // Group
+		fmt.Sprintf("%.2f", summary.AverageModifiers.Overseas),    // Overseas
+		fmt.Sprintf("%.2f", summary.AverageModifiers.Chain),       // Chain
+		fmt.Sprintf("%.2f", summary.AverageModifiers.Warlord),     // Warlord
+		"",                              // Empty row
+		summary.OverseasAttacks,         // Overseas Attacks
+		summary.InterruptedAttacks,      // Interrupted Attacks
+		"",                              // Empty row
+		summary.OutgoingAttacks,         // Outgoing Attacks
+		summary.IncomingAttacks,         // Incoming Attacks
+		summary.UnknownDirectionAttacks, // Unknown Direction Attacks
+		"",                              // Empty row
+		attacksToOvertake,               // Attacks to Overtake
 	}
 }
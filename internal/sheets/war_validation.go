@@ -0,0 +1,115 @@
+package sheets
+
+import (
+	"context"
+	"fmt"
+
+	"torn_rw_stats/internal/app"
+)
+
+// ValidateWarSheets compares the header row of each records sheet in the config and the
+// label column of the summary sheet against the expected layout, returning a description
+// of every mismatch found. An empty result means the sheets are well-formed.
+func (m *WarSheetsManager) ValidateWarSheets(ctx context.Context, spreadsheetID string, config *app.SheetConfig) ([]string, error) {
+	var mismatches []string
+
+	expectedRecords := m.GenerateRecordsSheetHeaders()[0]
+	for _, tabName := range []string{config.RecordsTabName, config.OutgoingTabName, config.IncomingTabName} {
+		if tabName == "" {
+			continue
+		}
+
+		lastColumn := columnLetter(len(expectedRecords) - 1)
+		values, err := m.api.ReadSheet(ctx, spreadsheetID, fmt.Sprintf("'%s'!A1:%s1", tabName, lastColumn))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read header row of %s: %w", tabName, err)
+		}
+
+		var actual []interface{}
+		if len(values) > 0 {
+			actual = values[0]
+		}
+		for _, mismatch := range CompareHeaderRow(expectedRecords, actual) {
+			mismatches = append(mismatches, fmt.Sprintf("%s: %s", tabName, mismatch))
+		}
+	}
+
+	expectedSummary := m.GenerateSummarySheetHeaders()
+	values, err := m.api.ReadSheet(ctx, spreadsheetID, fmt.Sprintf("'%s'!A1:A%d", config.SummaryTabName, len(expectedSummary)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read labels of %s: %w", config.SummaryTabName, err)
+	}
+
+	// Labels run down column A, so compare them as a single header row
+	expectedLabels := make([]interface{}, len(expectedSummary))
+	actualLabels := make([]interface{}, len(values))
+	for i, row := range expectedSummary {
+		expectedLabels[i] = firstCell(row)
+	}
+	for i, row := range values {
+		actualLabels[i] = firstCell(row)
+	}
+	for _, mismatch := range compareCells(expectedLabels, actualLabels, "row", func(i int) string { return fmt.Sprintf("%d", i+1) }) {
+		mismatches = append(mismatches, fmt.Sprintf("%s: %s", config.SummaryTabName, mismatch))
+	}
+
+	return mismatches, nil
+}
+
+// CompareHeaderRow compares an actual header row against the expected one, returning a
+// description of each column whose header differs, is missing or is unexpected.
+func CompareHeaderRow(expected, actual []interface{}) []string {
+	return compareCells(expected, actual, "column", columnLetter)
+}
+
+// compareCells compares two lines of cells position by position, naming positions with
+// the given unit and label function. Trailing empty cells are ignored, as the Sheets API
+// omits them when reading.
+func compareCells(expected, actual []interface{}, unit string, label func(int) string) []string {
+	var mismatches []string
+
+	for i := 0; i < max(len(expected), len(actual)); i++ {
+		want := cellString(expected, i)
+		got := cellString(actual, i)
+		if want == got {
+			continue
+		}
+
+		switch {
+		case got == "":
+			mismatches = append(mismatches, fmt.Sprintf("%s %s: missing %q", unit, label(i), want))
+		case want == "":
+			mismatches = append(mismatches, fmt.Sprintf("%s %s: unexpected %q", unit, label(i), got))
+		default:
+			mismatches = append(mismatches, fmt.Sprintf("%s %s: expected %q, got %q", unit, label(i), want, got))
+		}
+	}
+
+	return mismatches
+}
+
+// cellString returns the cell at index i as a string, or "" when out of range
+func cellString(cells []interface{}, i int) string {
+	if i >= len(cells) || cells[i] == nil {
+		return ""
+	}
+	return fmt.Sprint(cells[i])
+}
+
+// firstCell returns the first cell of a row, or "" for an empty row
+func firstCell(row []interface{}) interface{} {
+	if len(row) == 0 {
+		return ""
+	}
+	return row[0]
+}
+
+// columnLetter converts a zero-based column index to its A1 notation letters
+func columnLetter(index int) string {
+	letters := ""
+	for index >= 0 {
+		letters = string(rune('A'+index%26)) + letters
+		index = index/26 - 1
+	}
+	return letters
+}
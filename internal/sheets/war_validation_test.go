@@ -0,0 +1,88 @@
+package sheets
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"torn_rw_stats/internal/app"
+)
+
+func TestCompareHeaderRow(t *testing.T) {
+	expected := []interface{}{"Attack ID", "Code", "Started", "Ended"}
+
+	tests := []struct {
+		name     string
+		actual   []interface{}
+		expected []string
+	}{
+		{
+			name:   "matching headers",
+			actual: []interface{}{"Attack ID", "Code", "Started", "Ended"},
+		},
+		{
+			name:     "wrong header",
+			actual:   []interface{}{"Attack ID", "Attack Code", "Started", "Ended"},
+			expected: []string{`column B: expected "Code", got "Attack Code"`},
+		},
+		{
+			name:     "missing trailing columns",
+			actual:   []interface{}{"Attack ID", "Code"},
+			expected: []string{`column C: missing "Started"`, `column D: missing "Ended"`},
+		},
+		{
+			name:     "unexpected extra column",
+			actual:   []interface{}{"Attack ID", "Code", "Started", "Ended", "Notes"},
+			expected: []string{`column E: unexpected "Notes"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CompareHeaderRow(expected, tt.actual); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("CompareHeaderRow() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestColumnLetter(t *testing.T) {
+	for index, expected := range map[int]string{0: "A", 25: "Z", 26: "AA", 33: "AH", 701: "ZZ", 702: "AAA"} {
+		if got := columnLetter(index); got != expected {
+			t.Errorf("columnLetter(%d) = %q, expected %q", index, got, expected)
+		}
+	}
+}
+
+func TestValidateWarSheets(t *testing.T) {
+	mockAPI := NewMockSheetsAPI()
+	manager := NewWarSheetsManager(mockAPI)
+	ctx := context.Background()
+
+	config, err := manager.EnsureWarSheets(ctx, "test-sheet-id", &app.War{ID: 123})
+	if err != nil {
+		t.Fatalf("EnsureWarSheets() returned unexpected error: %v", err)
+	}
+
+	mismatches, err := manager.ValidateWarSheets(ctx, "test-sheet-id", config)
+	if err != nil {
+		t.Fatalf("ValidateWarSheets() returned unexpected error: %v", err)
+	}
+	if len(mismatches) != 0 {
+		t.Errorf("expected freshly created sheets to validate, got %q", mismatches)
+	}
+
+	// Corrupt one records header
+	headers := manager.GenerateRecordsSheetHeaders()
+	headers[0][1] = "Attack Code"
+	mockAPI.data[config.RecordsTabName] = headers
+
+	mismatches, err = manager.ValidateWarSheets(ctx, "test-sheet-id", config)
+	if err != nil {
+		t.Fatalf("ValidateWarSheets() returned unexpected error: %v", err)
+	}
+	expected := []string{`Records - 123: column B: expected "Code", got "Attack Code"`}
+	if !reflect.DeepEqual(mismatches, expected) {
+		t.Errorf("ValidateWarSheets() = %q, expected %q", mismatches, expected)
+	}
+}
@@ -2,6 +2,7 @@ package sheets
 
 import (
 	"context"
+	"time"
 
 	"torn_rw_stats/internal/app"
 )
@@ -12,9 +13,24 @@ import (
 // EnsureWarSheets creates summary and records sheets for a war if they don't exist
 func (c *Client) EnsureWarSheets(ctx context.Context, spreadsheetID string, war *app.War) (*app.SheetConfig, error) {
 	manager := NewWarSheetsManager(c)
+	manager.SetRecordsColumnOrder(c.recordsColumnOrder)
 	return manager.EnsureWarSheets(ctx, spreadsheetID, war)
 }
 
+// EnsureDirectionSheets creates outgoing and incoming records sheets for a war if they don't exist
+func (c *Client) EnsureDirectionSheets(ctx context.Context, spreadsheetID string, config *app.SheetConfig) error {
+	manager := NewWarSheetsManager(c)
+	manager.SetRecordsColumnOrder(c.recordsColumnOrder)
+	return manager.EnsureDirectionSheets(ctx, spreadsheetID, config)
+}
+
+// ValidateWarSheets reports mismatches between a war's sheets and the expected headers and labels
+func (c *Client) ValidateWarSheets(ctx context.Context, spreadsheetID string, config *app.SheetConfig) ([]string, error) {
+	manager := NewWarSheetsManager(c)
+	manager.SetRecordsColumnOrder(c.recordsColumnOrder)
+	return manager.ValidateWarSheets(ctx, spreadsheetID, config)
+}
+
 // UpdateWarSummary updates the summary sheet with current war statistics
 func (c *Client) UpdateWarSummary(ctx context.Context, spreadsheetID string, config *app.SheetConfig, summary *app.WarSummary) error {
 	manager := NewWarSheetsManager(c)
@@ -23,14 +39,75 @@ func (c *Client) UpdateWarSummary(ctx context.Context, spreadsheetID string, con
 
 // ReadExistingRecords analyzes existing attack records in the sheet
 func (c *Client) ReadExistingRecords(ctx context.Context, spreadsheetID, sheetName string) (*RecordsInfo, error) {
-	processor := NewAttackRecordsProcessor(c)
-	return processor.ReadExistingRecords(ctx, spreadsheetID, sheetName)
+	return c.NewAttackRecordsProcessor().ReadExistingRecords(ctx, spreadsheetID, sheetName)
+}
+
+// ReadAttackRecords reads every attack record on a records sheet
+func (c *Client) ReadAttackRecords(ctx context.Context, spreadsheetID, sheetName string) ([]app.AttackRecord, error) {
+	return c.NewAttackRecordsProcessor().ReadAttackRecords(ctx, spreadsheetID, sheetName)
 }
 
 // UpdateAttackRecords updates the records sheet with new attack data using append strategy
 func (c *Client) UpdateAttackRecords(ctx context.Context, spreadsheetID string, config *app.SheetConfig, records []app.AttackRecord) error {
+	return c.NewAttackRecordsProcessor().UpdateAttackRecords(ctx, spreadsheetID, config, records)
+}
+
+// BackfillAttackRecords appends attack data that may predate the sheet's newest record
+func (c *Client) BackfillAttackRecords(ctx context.Context, spreadsheetID string, config *app.SheetConfig, records []app.AttackRecord) error {
+	return c.NewAttackRecordsProcessor().BackfillAttackRecords(ctx, spreadsheetID, config, records)
+}
+
+// NewAttackRecordsProcessor creates an attack records processor with the client's
+// configured respect precision, column order, display timezone and write batch size
+func (c *Client) NewAttackRecordsProcessor() *AttackRecordsProcessor {
 	processor := NewAttackRecordsProcessor(c)
-	return processor.UpdateAttackRecords(ctx, spreadsheetID, config, records)
+	processor.SetRespectDecimalPlaces(c.respectDecimalPlaces)
+	processor.SetRecordsColumnOrder(c.recordsColumnOrder)
+	processor.SetDisplayLocation(c.displayLocation)
+	processor.SetWriteBatchSize(c.writeBatchSize)
+	return processor
+}
+
+// UpdateLeaderboard rewrites the member leaderboard sheet for a war
+func (c *Client) UpdateLeaderboard(ctx context.Context, spreadsheetID string, warID int, entries []app.MemberLeaderboardEntry) error {
+	manager := NewLeaderboardManager(c)
+	return manager.UpdateLeaderboard(ctx, spreadsheetID, warID, entries)
+}
+
+// UpdateRespectTrend rewrites the respect-per-hit trend sheet for a war
+func (c *Client) UpdateRespectTrend(ctx context.Context, spreadsheetID string, warID int, trend []app.RespectTrendWindow) error {
+	manager := NewRespectTrendManager(c)
+	return manager.UpdateRespectTrend(ctx, spreadsheetID, warID, trend)
+}
+
+// UpdateIncomingThreats rewrites the incoming threats sheet for a war
+func (c *Client) UpdateIncomingThreats(ctx context.Context, spreadsheetID string, warID int, entries []app.IncomingThreatEntry) error {
+	manager := NewIncomingThreatsManager(c)
+	return manager.UpdateIncomingThreats(ctx, spreadsheetID, warID, entries)
+}
+
+// UpdateParticipationFlags rewrites the participation flags sheet for a war
+func (c *Client) UpdateParticipationFlags(ctx context.Context, spreadsheetID string, warID int, flags []app.ParticipationFlag) error {
+	manager := NewParticipationManager(c)
+	return manager.UpdateParticipationFlags(ctx, spreadsheetID, warID, flags)
+}
+
+// UpdateCounterattacks rewrites the counterattacks sheet for a war
+func (c *Client) UpdateCounterattacks(ctx context.Context, spreadsheetID string, warID int, counterattacks []app.Counterattack) error {
+	manager := NewCounterattacksManager(c)
+	return manager.UpdateCounterattacks(ctx, spreadsheetID, warID, counterattacks)
+}
+
+// UpdateDashboard rewrites the combined dashboard sheet for all active wars
+func (c *Client) UpdateDashboard(ctx context.Context, spreadsheetID string, rows []app.WarDashboardRow) error {
+	manager := NewDashboardManager(c)
+	return manager.UpdateDashboard(ctx, spreadsheetID, rows)
+}
+
+// AppendStateTransition appends an accepted war state transition to the state transition log sheet
+func (c *Client) AppendStateTransition(ctx context.Context, spreadsheetID string, transition app.WarStateTransition) error {
+	manager := NewStateTransitionManager(c)
+	return manager.AppendStateTransition(ctx, spreadsheetID, transition)
 }
 
 // Travel and State Management Functions - delegate to specialized managers
@@ -46,3 +123,45 @@ func (c *Client) UpdateStatusV2(ctx context.Context, spreadsheetID, sheetName st
 	manager := NewStatusV2Manager(c)
 	return manager.UpdateStatusV2(ctx, spreadsheetID, sheetName, records)
 }
+
+// AppendStatusHistory appends a timestamped Status v2 snapshot to a faction's status history sheet
+func (c *Client) AppendStatusHistory(ctx context.Context, spreadsheetID string, factionID int, snapshotTime time.Time, records []app.StatusV2Record, maxRows int) error {
+	manager := NewStatusHistoryManager(c)
+	return manager.AppendStatusSnapshot(ctx, spreadsheetID, factionID, snapshotTime, records, maxRows)
+}
+
+// AppendCountdownDrift appends travel countdown drift measurements to the countdown drift sheet
+func (c *Client) AppendCountdownDrift(ctx context.Context, spreadsheetID string, drifts []app.CountdownDrift) error {
+	manager := NewCountdownDriftManager(c)
+	return manager.AppendCountdownDrift(ctx, spreadsheetID, drifts)
+}
+
+// AppendAlert appends an alert to the alerts sheet
+func (c *Client) AppendAlert(ctx context.Context, spreadsheetID string, alert app.Alert) error {
+	manager := NewAlertsManager(c)
+	return manager.AppendAlert(ctx, spreadsheetID, alert)
+}
+
+// UpdateEnemyHospital rewrites the hospitalized members sheet for an enemy faction
+func (c *Client) UpdateEnemyHospital(ctx context.Context, spreadsheetID string, factionID int, entries []app.EnemyHospitalEntry) error {
+	manager := NewEnemyHospitalManager(c)
+	return manager.UpdateEnemyHospital(ctx, spreadsheetID, factionID, entries)
+}
+
+// UpdateTargetPriority rewrites the ranked target sheet for an enemy faction
+func (c *Client) UpdateTargetPriority(ctx context.Context, spreadsheetID string, factionID int, entries []app.TargetPriorityEntry) error {
+	manager := NewTargetPriorityManager(c)
+	return manager.UpdateTargetPriority(ctx, spreadsheetID, factionID, entries)
+}
+
+// AppendEnemyRosterSize appends an enemy faction's current member count to its roster size sheet
+func (c *Client) AppendEnemyRosterSize(ctx context.Context, spreadsheetID string, factionID int, timestamp time.Time, memberCount int) error {
+	manager := NewEnemyRosterManager(c)
+	return manager.AppendEnemyRosterSize(ctx, spreadsheetID, factionID, timestamp, memberCount)
+}
+
+// UpdateEnemyOverview overwrites the member state overview row for an enemy faction
+func (c *Client) UpdateEnemyOverview(ctx context.Context, spreadsheetID string, factionID int, overview app.EnemyOverview) error {
+	manager := NewEnemyOverviewManager(c)
+	return manager.UpdateEnemyOverview(ctx, spreadsheetID, factionID, overview)
+}
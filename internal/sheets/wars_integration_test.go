@@ -56,8 +56,8 @@ func TestConvertRecordsToRows(t *testing.T) {
 	}
 
 	row := rows[0]
-	if len(row) != 32 {
-		t.Fatalf("Expected 32 columns, got %d", len(row))
+	if len(row) != 38 {
+		t.Fatalf("Expected 38 columns, got %d", len(row))
 	}
 
 	// Test specific values
@@ -251,8 +251,8 @@ func TestUpdateAttackRecords(t *testing.T) {
 		}
 
 		row := rows[0]
-		if len(row) < 32 {
-			t.Errorf("Expected at least 32 columns, got %d", len(row))
+		if len(row) < 34 {
+			t.Errorf("Expected at least 34 columns, got %d", len(row))
 		}
 
 		// Verify key fields
@@ -350,6 +350,12 @@ func convertRecordsToRows(records []app.AttackRecord) [][]interface{} {
 			record.ModifierWarlord,
 			record.FinishingHitName,
 			record.FinishingHitValue,
+			record.LevelDifference,
+			record.ThirdPartyFactionName,
+			record.AttackerFactionTag,
+			record.DefenderFactionTag,
+			record.IsFirstBlood,
+			record.IsLastHit,
 		}
 
 		rows = append(rows, row)
@@ -20,22 +20,43 @@ const (
 )
 
 // Client is an HTTP client for the Torn API that handles authentication,
-// request formatting, and API call tracking.
+// request formatting, rate limiting, and API call tracking.
 type Client struct {
 	apiKey       string
 	client       *http.Client
+	limiter      *rateLimiter
 	apiCallCount int64
 	apiCallMutex sync.Mutex
+	dumper       *rawDumper // nil = raw response dumps disabled
 }
 
 // NewClient creates a new Torn API client with the provided API key.
-// The client is configured with a 30-second timeout for all requests.
+// The client is configured with a 30-second timeout for all requests
+// and the default request rate limit.
 func NewClient(apiKey string) *Client {
+	return NewClientWithConfig(&app.Config{TornAPIKey: apiKey})
+}
+
+// NewClientWithConfig creates a new Torn API client using the API key and request
+// rate limit from config. A non-positive APIRequestsPerMinute uses DefaultRequestsPerMinute.
+func NewClientWithConfig(config *app.Config) *Client {
+	requestsPerMinute := config.APIRequestsPerMinute
+	if requestsPerMinute <= 0 {
+		requestsPerMinute = DefaultRequestsPerMinute
+	}
+
+	var dumper *rawDumper
+	if config.RawDumpDir != "" {
+		dumper = newRawDumper(config.RawDumpDir, config.TornAPIKey)
+	}
+
 	return &Client{
-		apiKey: apiKey,
+		apiKey: config.TornAPIKey,
 		client: &http.Client{
 			Timeout: HTTPClientTimeout,
 		},
+		limiter: newRateLimiter(requestsPerMinute, time.Minute),
+		dumper:  dumper,
 	}
 }
 
@@ -67,6 +88,11 @@ func (c *Client) makeAPIRequest(ctx context.Context, url string) (*http.Response
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
+	// Block until the rate limit allows another request
+	if err := c.limiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("failed waiting for rate limit: %w", err)
+	}
+
 	resp, err := c.client.Do(req)
 	if err != nil {
 		log.Debug().
@@ -80,7 +106,8 @@ func (c *Client) makeAPIRequest(ctx context.Context, url string) (*http.Response
 	return resp, nil
 }
 
-// handleAPIResponse processes the HTTP response and returns the body bytes
+// handleAPIResponse processes the HTTP response and returns the body bytes. Errors the
+// API reports inside a 200 response are returned as *APIError.
 func (c *Client) handleAPIResponse(resp *http.Response) ([]byte, error) {
 	defer resp.Body.Close()
 
@@ -94,6 +121,14 @@ func (c *Client) handleAPIResponse(resp *http.Response) ([]byte, error) {
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 
+	if c.dumper != nil && resp.Request != nil {
+		c.dumper.Dump(resp.Request.URL, body)
+	}
+
+	if apiErr := parseAPIError(body); apiErr != nil {
+		return nil, apiErr
+	}
+
 	return body, nil
 }
 
@@ -127,6 +162,33 @@ func (c *Client) GetFactionWars(ctx context.Context) (*app.WarResponse, error) {
 	return &warResponse, nil
 }
 
+// GetFactionWarsByID fetches another faction's wars from the API
+func (c *Client) GetFactionWarsByID(ctx context.Context, factionID int) (*app.WarResponse, error) {
+	url := fmt.Sprintf("https://api.torn.com/v2/faction/%d/wars?key=%s", factionID, c.apiKey)
+
+	log.Debug().
+		Str("url", url).
+		Int("faction_id", factionID).
+		Msg("Fetching faction wars by ID")
+
+	resp, err := c.makeAPIRequest(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.handleAPIResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	var warResponse app.WarResponse
+	if err := json.Unmarshal(body, &warResponse); err != nil {
+		return nil, fmt.Errorf("failed to decode war response: %w", err)
+	}
+
+	return &warResponse, nil
+}
+
 // GetFactionAttacks fetches faction attacks from the API using timestamp pagination
 func (c *Client) GetFactionAttacks(ctx context.Context, from, to int64) (*app.AttackResponse, error) {
 	url := fmt.Sprintf("https://api.torn.com/v2/faction/attacks?key=%s&from=%d&to=%d", c.apiKey, from, to)
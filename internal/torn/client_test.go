@@ -2,11 +2,16 @@ package torn
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"math"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
+
+	"torn_rw_stats/internal/app"
 )
 
 func TestNewClient(t *testing.T) {
@@ -137,4 +142,107 @@ func TestHandleAPIResponse(t *testing.T) {
 			t.Errorf("Expected error to contain 'Invalid API key', got: %s", err.Error())
 		}
 	})
+
+	t.Run("APIErrorPayload", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"error": {"code": 16, "error": "Access level of this key is not high enough"}}`))
+		}))
+		defer server.Close()
+
+		resp, err := http.Get(server.URL)
+		if err != nil {
+			t.Fatalf("Failed to make HTTP request: %v", err)
+		}
+		defer resp.Body.Close()
+
+		_, err = client.handleAPIResponse(resp)
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.Code != 16 {
+			t.Fatalf("Expected API error code 16, got %v", err)
+		}
+		if !IsPermissionError(fmt.Errorf("wrapped: %w", err)) {
+			t.Error("Expected access level error to be a permission error")
+		}
+		if IsPermissionError(errors.New("connection refused")) {
+			t.Error("Expected a network error not to be a permission error")
+		}
+	})
+}
+
+func TestNewClientWithConfigRateLimit(t *testing.T) {
+	// A full bucket plus a minute of refills is the most a minute can see
+	perMinute := func(limiter *rateLimiter) float64 {
+		return limiter.capacity + limiter.refillRate*60
+	}
+
+	client := NewClientWithConfig(&app.Config{TornAPIKey: "test_api_key", APIRequestsPerMinute: 30})
+	if got := perMinute(client.limiter); math.Abs(got-30) > 1e-9 {
+		t.Errorf("Expected at most 30 requests per minute, got %v", got)
+	}
+
+	client = NewClient("test_api_key")
+	if got := perMinute(client.limiter); math.Abs(got-DefaultRequestsPerMinute) > 1e-9 {
+		t.Errorf("Expected at most %d requests per minute, got %v", DefaultRequestsPerMinute, got)
+	}
+}
+
+func TestRateLimiterStartsWithSmallBurst(t *testing.T) {
+	limiter := newRateLimiter(90, time.Hour)
+
+	// Only a tenth of the limit is available at startup; the rest refills over the period
+	for i := 0; i < 9; i++ {
+		if err := limiter.Wait(context.Background()); err != nil {
+			t.Fatalf("Request %d: expected a burst token, got %v", i, err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := limiter.Wait(ctx); err == nil {
+		t.Error("Expected the burst to be exhausted after 9 requests")
+	}
+}
+
+func TestMakeAPIRequestThrottling(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	// A bucket of 5 per minute, scaled down so the test stays fast
+	const period = 500 * time.Millisecond
+	client := NewClient("test_api_key")
+	client.limiter = newRateLimiter(5, period)
+
+	start := time.Now()
+	for i := 0; i < 10; i++ {
+		resp, err := client.makeAPIRequest(context.Background(), server.URL)
+		if err != nil {
+			t.Fatalf("Request %d: expected no error, got %v", i, err)
+		}
+		resp.Body.Close()
+	}
+	elapsed := time.Since(start)
+
+	// The first request drains the burst; the other 9 each wait for a refill
+	if elapsed < period*9/10 {
+		t.Errorf("Expected throttling to take at least ~%v, took %v", period, elapsed)
+	}
+	if count := client.GetAPICallCount(); count != 10 {
+		t.Errorf("Expected API call count 10, got %d", count)
+	}
+}
+
+func TestRateLimiterWaitHonorsContext(t *testing.T) {
+	limiter := newRateLimiter(1, time.Hour)
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatalf("Expected first token immediately, got %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := limiter.Wait(ctx); err == nil {
+		t.Error("Expected error when context expires before a token is available")
+	}
 }
@@ -0,0 +1,55 @@
+package torn
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// APIError is an error reported by the Torn API in an otherwise successful response,
+// e.g. {"error": {"code": 16, "error": "Access level of this key is not high enough"}}
+type APIError struct {
+	Code    int
+	Message string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("Torn API error %d: %s", e.Code, e.Message)
+}
+
+// keyErrorCodes are the Torn API error codes caused by the API key itself - missing,
+// invalid, disabled, paused or lacking the access level for the selection
+var keyErrorCodes = map[int]bool{
+	1:  true, // Key is empty
+	2:  true, // Incorrect key
+	10: true, // Key owner is in federal jail
+	13: true, // Key disabled due to owner inactivity
+	16: true, // Access level of this key is not high enough
+	18: true, // API key has been paused by the owner
+}
+
+// IsPermission reports whether the error is caused by the API key rather than the request
+func (e *APIError) IsPermission() bool {
+	return keyErrorCodes[e.Code]
+}
+
+// IsPermissionError reports whether err wraps a Torn API error caused by the API key
+func IsPermissionError(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.IsPermission()
+}
+
+// parseAPIError returns the API error embedded in a response body, or nil when the body
+// is not an error payload
+func parseAPIError(body []byte) *APIError {
+	var payload struct {
+		Error *struct {
+			Code    int    `json:"code"`
+			Message string `json:"error"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil || payload.Error == nil {
+		return nil
+	}
+	return &APIError{Code: payload.Error.Code, Message: payload.Error.Message}
+}
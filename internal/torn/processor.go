@@ -2,6 +2,7 @@ package torn
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -19,16 +20,42 @@ const (
 // AttackProcessor handles business logic for processing attacks
 // Separated from infrastructure concerns for better testability
 type AttackProcessor struct {
-	api TornAPI
+	api               TornAPI
+	incrementalBuffer time.Duration // zero uses attack.DefaultIncrementalFetchBuffer
+	pagination        attack.PaginationConfig
+	onPage            PageHandler // nil = pages are only returned together
 }
 
+// PageHandler is called with each page of a paginated fetch once it is gathered: the
+// page's relevant attacks, oldest first, and the unix time of the newest attack not yet
+// fetched. An error stops the fetch and is returned.
+type PageHandler func(ctx context.Context, attacks []app.Attack, missingUntil int64) error
+
 // NewAttackProcessor creates a new attack processor with the given API client
 func NewAttackProcessor(api TornAPI) *AttackProcessor {
 	return &AttackProcessor{
-		api: api,
+		api:        api,
+		pagination: attack.DefaultPaginationConfig(),
 	}
 }
 
+// SetPagination sets the pagination limits for large time ranges; zero limits keep
+// the defaults
+func (p *AttackProcessor) SetPagination(pagination attack.PaginationConfig) {
+	p.pagination = pagination
+}
+
+// SetPageHandler sets a handler called after each page of a paginated fetch; nil disables it
+func (p *AttackProcessor) SetPageHandler(handler PageHandler) {
+	p.onPage = handler
+}
+
+// SetIncrementalFetchBuffer sets how far before the latest existing attack incremental
+// fetches start; zero keeps the default buffer
+func (p *AttackProcessor) SetIncrementalFetchBuffer(buffer time.Duration) {
+	p.incrementalBuffer = buffer
+}
+
 // TimeRange holds the calculated time range and update mode for fetching attacks.
 // FromTime and ToTime are Unix timestamps. UpdateMode indicates whether this is a
 // "full" fetch or an "incremental" update.
@@ -58,6 +85,28 @@ type PageResult struct {
 	TotalAttacksCount int
 }
 
+// ErrMaxPagesReached is wrapped in the PartialFetchError returned when a paginated
+// fetch stops at the configured page cap with older attacks still to fetch
+var ErrMaxPagesReached = errors.New("reached the maximum number of attack pages")
+
+// PartialFetchError is returned when paginated fetching fails after some pages were
+// already gathered. Attacks holds the chronologically sorted attacks fetched so far,
+// which are also returned alongside the error so callers can still record them.
+// Pagination runs newest first, so only attacks up to MissingUntil are missing.
+type PartialFetchError struct {
+	Attacks      []app.Attack
+	MissingUntil int64 // unix time of the newest attack not fetched
+	Err          error
+}
+
+func (e *PartialFetchError) Error() string {
+	return fmt.Sprintf("partial fetch with %d attacks: %v", len(e.Attacks), e.Err)
+}
+
+func (e *PartialFetchError) Unwrap() error {
+	return e.Err
+}
+
 // GetAllAttacksForWar fetches all attacks for a specific war timeframe
 func (p *AttackProcessor) GetAllAttacksForWar(ctx context.Context, war *app.War) ([]app.Attack, error) {
 	return p.GetAttacksForTimeRange(ctx, war, war.Start, nil)
@@ -70,17 +119,41 @@ func (p *AttackProcessor) GetAttacksForTimeRange(ctx context.Context, war *app.W
 	}
 
 	// Functional core: Calculate time range and update mode
-	timeRangeResult := attack.CalculateTimeRange(war, latestExistingTimestamp, time.Now().Unix())
+	timeRangeResult := attack.CalculateTimeRangeWithBuffer(war, latestExistingTimestamp, time.Now().Unix(), p.incrementalBuffer)
 	timeRange := TimeRange{
 		FromTime:   timeRangeResult.FromTime,
 		ToTime:     timeRangeResult.ToTime,
 		UpdateMode: timeRangeResult.UpdateMode,
 	}
 
+	return p.fetchAttacksInRange(ctx, war, timeRange)
+}
+
+// GetAttacksBetween fetches attacks for a war within an explicit window, regardless of
+// what has already been recorded. Used for one-shot re-pulls of a past window.
+func (p *AttackProcessor) GetAttacksBetween(ctx context.Context, war *app.War, from, to time.Time) ([]app.Attack, error) {
+	if war == nil {
+		return nil, fmt.Errorf("war cannot be nil")
+	}
+	if !to.After(from) {
+		return nil, fmt.Errorf("end of range %s must be after start %s", to.Format(time.RFC3339), from.Format(time.RFC3339))
+	}
+
+	timeRange := TimeRange{
+		FromTime:   from.Unix(),
+		ToTime:     to.Unix(),
+		UpdateMode: "range",
+	}
+
+	return p.fetchAttacksInRange(ctx, war, timeRange)
+}
+
+// fetchAttacksInRange chooses a fetch strategy for the time range and executes it
+func (p *AttackProcessor) fetchAttacksInRange(ctx context.Context, war *app.War, timeRange TimeRange) ([]app.Attack, error) {
 	// Functional core: Determine fetch strategy
 	startTime := time.Unix(timeRange.FromTime, 0)
 	endTime := time.Unix(timeRange.ToTime, 0)
-	strategy := attack.DetermineFetchStrategy(startTime, endTime)
+	strategy := attack.DetermineFetchStrategy(startTime, endTime, p.pagination)
 
 	// Log strategy and estimated API calls for observability
 	estimatedCalls := attack.EstimateAPICallsRequired(strategy)
@@ -123,20 +196,38 @@ func (p *AttackProcessor) fetchAttacksSimple(ctx context.Context, war *app.War,
 }
 
 // fetchAttacksPaginated fetches attacks using backwards pagination (for large time ranges)
-func (p *AttackProcessor) fetchAttacksPaginated(ctx context.Context, war *app.War, timeRange TimeRange) ([]app.Attack, error) {
+func (p *AttackProcessor) fetchAttacksPaginated(ctx context.Context, war *app.War, timeRange TimeRange, pagination attack.PaginationConfig) ([]app.Attack, error) {
 	var allAttacks []app.Attack
 	currentTo := timeRange.ToTime
 
-	for {
+	for page := 1; ; page++ {
 		// Fetch one page of attacks
 		pageResult, err := p.fetchAttacksPage(ctx, war, timeRange.FromTime, currentTo)
 		if err != nil {
-			return nil, err
+			if len(allAttacks) == 0 {
+				return nil, err
+			}
+
+			// Keep the pages already gathered rather than losing them with the failed one
+			allAttacks = attack.SortAttacksChronologically(allAttacks)
+			log.Warn().
+				Err(err).
+				Int("war_id", war.ID).
+				Int("attacks_gathered", len(allAttacks)).
+				Msg("Paginated attack fetch failed part way, returning partial results")
+			return allAttacks, &PartialFetchError{Attacks: allAttacks, MissingUntil: currentTo, Err: err}
 		}
 
 		// Add relevant attacks to our collection
 		allAttacks = append(allAttacks, pageResult.RelevantAttacks...)
 
+		if p.onPage != nil {
+			relevant := attack.SortAttacksChronologically(pageResult.RelevantAttacks)
+			if err := p.onPage(ctx, relevant, pageResult.OldestAttackTime-1); err != nil {
+				return nil, fmt.Errorf("failed to handle attack page: %w", err)
+			}
+		}
+
 		// Check if we should stop pagination
 		if p.shouldStopPagination(pageResult, timeRange.FromTime) {
 			break
@@ -145,6 +236,18 @@ func (p *AttackProcessor) fetchAttacksPaginated(ctx context.Context, war *app.Wa
 		// Set up next page
 		currentTo = pageResult.OldestAttackTime - 1
 
+		// The older attacks are left for the caller to fetch later, as after a failed page
+		if page >= pagination.MaxPages {
+			allAttacks = attack.SortAttacksChronologically(allAttacks)
+			log.Warn().
+				Int("war_id", war.ID).
+				Int("max_pages", pagination.MaxPages).
+				Int64("oldest_fetched", pageResult.OldestAttackTime).
+				Int64("fetch_from", timeRange.FromTime).
+				Msg("Reached maximum attack pages - returning partial results")
+			return allAttacks, &PartialFetchError{Attacks: allAttacks, MissingUntil: currentTo, Err: ErrMaxPagesReached}
+		}
+
 		log.Debug().
 			Int64("next_to", currentTo).
 			Str("next_to_str", time.Unix(currentTo, 0).Format("2006-01-02 15:04:05")).
@@ -215,7 +318,7 @@ func (p *AttackProcessor) executeFetchStrategy(
 	case attack.FetchMethodSimple:
 		return p.fetchAttacksSimple(ctx, war, timeRange)
 	case attack.FetchMethodPaginated:
-		return p.fetchAttacksPaginated(ctx, war, timeRange)
+		return p.fetchAttacksPaginated(ctx, war, timeRange, strategy.Pagination)
 	default:
 		return nil, fmt.Errorf("unknown fetch method: %s", strategy.Method)
 	}
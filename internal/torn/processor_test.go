@@ -2,6 +2,7 @@ package torn
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -332,6 +333,43 @@ func TestGetAttacksForTimeRangeNilWar(t *testing.T) {
 	}
 }
 
+func TestGetAttacksBetween(t *testing.T) {
+	mockAPI := &MockTornAPI{
+		attackResponse: &app.AttackResponse{
+			Attacks: []app.Attack{
+				{
+					ID:       1,
+					Started:  1000,
+					Attacker: app.User{Faction: &app.Faction{ID: 1001}},
+					Defender: app.User{Faction: &app.Faction{ID: 1002}},
+				},
+			},
+		},
+	}
+	processor := NewAttackProcessor(mockAPI)
+
+	war := &app.War{
+		ID:    123,
+		Start: 900,
+		Factions: []app.Faction{
+			{ID: 1001, Name: "Faction A"},
+			{ID: 1002, Name: "Faction B"},
+		},
+	}
+
+	attacks, err := processor.GetAttacksBetween(context.Background(), war, time.Unix(950, 0), time.Unix(1050, 0))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(attacks) != 1 {
+		t.Errorf("Expected 1 attack, got %d", len(attacks))
+	}
+
+	if _, err := processor.GetAttacksBetween(context.Background(), war, time.Unix(1050, 0), time.Unix(950, 0)); err == nil {
+		t.Error("Expected error for a range that ends before it starts, got nil")
+	}
+}
+
 func TestProcessAttacksPage(t *testing.T) {
 	mockAPI := &MockTornAPI{}
 	processor := NewAttackProcessor(mockAPI)
@@ -432,3 +470,115 @@ func TestShouldStopPagination(t *testing.T) {
 		})
 	}
 }
+
+// pagingTornAPI serves attack pages in order, then fails once they run out
+type pagingTornAPI struct {
+	MockTornAPI
+	pages [][]app.Attack
+	calls int
+}
+
+func (m *pagingTornAPI) GetFactionAttacks(ctx context.Context, from, to int64) (*app.AttackResponse, error) {
+	if m.calls >= len(m.pages) {
+		return nil, &mockError{msg: "api unavailable"}
+	}
+	page := m.pages[m.calls]
+	m.calls++
+	return &app.AttackResponse{Attacks: page}, nil
+}
+
+func TestGetAllAttacksForWarReturnsPartialResults(t *testing.T) {
+	now := time.Now().Unix()
+	war := &app.War{
+		ID:    123,
+		Start: now - 3*24*3600,
+		Factions: []app.Faction{
+			{ID: 1001, Name: "Faction A"},
+			{ID: 1002, Name: "Faction B"},
+		},
+	}
+
+	// Two full pages walking backwards in time, so pagination asks for a third
+	fullPage := func(newest int64, firstID int64) []app.Attack {
+		page := make([]app.Attack, TornAPIPageSize)
+		for i := range page {
+			page[i] = app.Attack{
+				ID:       firstID + int64(i),
+				Started:  newest - int64(i)*60,
+				Attacker: app.User{Faction: &app.Faction{ID: 1001}},
+				Defender: app.User{Faction: &app.Faction{ID: 1002}},
+			}
+		}
+		return page
+	}
+	mockAPI := &pagingTornAPI{
+		pages: [][]app.Attack{
+			fullPage(now-60, 1),
+			fullPage(now-60-TornAPIPageSize*60, TornAPIPageSize+1),
+		},
+	}
+	processor := NewAttackProcessor(mockAPI)
+
+	attacks, err := processor.GetAllAttacksForWar(context.Background(), war)
+	if err == nil {
+		t.Fatal("Expected an error from the failed third page, got nil")
+	}
+
+	var partial *PartialFetchError
+	if !errors.As(err, &partial) {
+		t.Fatalf("Expected a PartialFetchError, got %T: %v", err, err)
+	}
+	if len(attacks) != 2*TornAPIPageSize || len(partial.Attacks) != 2*TornAPIPageSize {
+		t.Errorf("Expected %d partial attacks, got %d returned and %d in error", 2*TornAPIPageSize, len(attacks), len(partial.Attacks))
+	}
+	if attacks[0].Started > attacks[len(attacks)-1].Started {
+		t.Error("Expected partial attacks sorted oldest first")
+	}
+}
+
+func TestGetAllAttacksForWarStopsAtMaxPages(t *testing.T) {
+	now := time.Now().Unix()
+	war := &app.War{
+		ID:    123,
+		Start: now - 3*24*3600,
+		Factions: []app.Faction{
+			{ID: 1001, Name: "Faction A"},
+			{ID: 1002, Name: "Faction B"},
+		},
+	}
+
+	// Every page is full and reaches back no further than the war start
+	var pages [][]app.Attack
+	for p := 0; p < 3; p++ {
+		page := make([]app.Attack, TornAPIPageSize)
+		for i := range page {
+			id := int64(p*TornAPIPageSize + i + 1)
+			page[i] = app.Attack{
+				ID:       id,
+				Started:  now - id*60,
+				Attacker: app.User{Faction: &app.Faction{ID: 1001}},
+				Defender: app.User{Faction: &app.Faction{ID: 1002}},
+			}
+		}
+		pages = append(pages, page)
+	}
+	mockAPI := &pagingTornAPI{pages: pages}
+	processor := NewAttackProcessor(mockAPI)
+	processor.SetPagination(attack.PaginationConfig{MaxPages: 2})
+
+	attacks, err := processor.GetAllAttacksForWar(context.Background(), war)
+	var partial *PartialFetchError
+	if !errors.As(err, &partial) || !errors.Is(err, ErrMaxPagesReached) {
+		t.Fatalf("Expected a partial fetch error for the page cap, got %v", err)
+	}
+	if mockAPI.calls != 2 {
+		t.Errorf("Expected pagination to stop after 2 pages, got %d requests", mockAPI.calls)
+	}
+	if len(attacks) != 2*TornAPIPageSize {
+		t.Errorf("Expected %d attacks, got %d", 2*TornAPIPageSize, len(attacks))
+	}
+	// The oldest fetched attack started 200 minutes ago, so older ones are still missing
+	if want := now - 2*TornAPIPageSize*60 - 1; partial.MissingUntil != want {
+		t.Errorf("Expected attacks missing until %d, got %d", want, partial.MissingUntil)
+	}
+}
@@ -0,0 +1,74 @@
+package torn
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultRequestsPerMinute keeps us safely under Torn's limit of ~100 requests
+// per minute per API key
+const DefaultRequestsPerMinute = 90
+
+// rateLimiter is a token bucket that holds up to capacity tokens and refills
+// them at refillRate. Each API request consumes one token.
+type rateLimiter struct {
+	mu         sync.Mutex
+	capacity   float64
+	tokens     float64
+	refillRate float64 // tokens per second
+	lastRefill time.Time
+}
+
+// newRateLimiter creates a token bucket allowing requests calls per period. The
+// bucket holds a burst of a tenth of the limit and refills the rest evenly, so no
+// window of one period, including the first after startup, sees more than requests calls.
+func newRateLimiter(requests int, period time.Duration) *rateLimiter {
+	burst := max(requests/10, 1)
+	refill := requests - burst
+	if refill < 1 {
+		// A single request per period still has to refill
+		refill = requests
+	}
+
+	return &rateLimiter{
+		capacity:   float64(burst),
+		tokens:     float64(burst),
+		refillRate: float64(refill) / period.Seconds(),
+		lastRefill: time.Now(),
+	}
+}
+
+// Wait blocks until a token is available or the context is cancelled.
+// It is safe for concurrent use, so paginated fetches share one budget.
+func (l *rateLimiter) Wait(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		l.refill()
+		if l.tokens >= 1 {
+			l.tokens--
+			l.mu.Unlock()
+			return nil
+		}
+		wait := time.Duration((1 - l.tokens) / l.refillRate * float64(time.Second))
+		l.mu.Unlock()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// refill adds the tokens earned since the last refill; callers must hold mu
+func (l *rateLimiter) refill() {
+	now := time.Now()
+	l.tokens += now.Sub(l.lastRefill).Seconds() * l.refillRate
+	if l.tokens > l.capacity {
+		l.tokens = l.capacity
+	}
+	l.lastRefill = now
+}
@@ -0,0 +1,96 @@
+package torn
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// DefaultRawDumpInterval is the minimum time between raw response dumps of the same
+// endpoint, so a busy cycle writes a sample rather than every page
+const DefaultRawDumpInterval = time.Minute
+
+// rawDumpRedaction replaces the API key wherever it appears in a dump
+const rawDumpRedaction = "REDACTED"
+
+// rawDumper writes raw API response bodies to timestamped files for debugging,
+// redacting the API key and dumping each endpoint at most once per interval
+type rawDumper struct {
+	dir      string
+	apiKey   string
+	interval time.Duration
+	now      func() time.Time
+
+	mu       sync.Mutex
+	lastDump map[string]time.Time // last dump time per endpoint
+}
+
+// newRawDumper creates a dumper writing into dir
+func newRawDumper(dir, apiKey string) *rawDumper {
+	return &rawDumper{
+		dir:      dir,
+		apiKey:   apiKey,
+		interval: DefaultRawDumpInterval,
+		now:      time.Now,
+		lastDump: make(map[string]time.Time),
+	}
+}
+
+// Dump writes body to a file named after the request's endpoint and the current time,
+// unless the endpoint was dumped within the interval. Failures are logged, never
+// returned, since dumps are a debugging aid.
+func (d *rawDumper) Dump(requestURL *url.URL, body []byte) {
+	endpoint := rawDumpEndpoint(requestURL)
+	now := d.now()
+
+	d.mu.Lock()
+	if last, ok := d.lastDump[endpoint]; ok && now.Sub(last) < d.interval {
+		d.mu.Unlock()
+		return
+	}
+	d.lastDump[endpoint] = now
+	d.mu.Unlock()
+
+	if d.apiKey != "" {
+		body = bytes.ReplaceAll(body, []byte(d.apiKey), []byte(rawDumpRedaction))
+	}
+
+	if err := os.MkdirAll(d.dir, 0o755); err != nil {
+		log.Warn().Err(err).Str("dir", d.dir).Msg("Failed to create raw dump directory")
+		return
+	}
+
+	filename := fmt.Sprintf("%s_%s.json", now.UTC().Format("20060102T150405.000Z"), endpoint)
+	path := filepath.Join(d.dir, filename)
+	if err := os.WriteFile(path, body, 0o600); err != nil {
+		log.Warn().Err(err).Str("path", path).Msg("Failed to write raw API response dump")
+		return
+	}
+
+	log.Debug().
+		Str("path", path).
+		Str("endpoint", endpoint).
+		Int("bytes", len(body)).
+		Msg("Dumped raw API response")
+}
+
+// rawDumpEndpoint turns a request path such as /v2/faction/123/basic into a file-safe
+// endpoint name like faction_123_basic; the query string, which holds the key, is dropped
+func rawDumpEndpoint(requestURL *url.URL) string {
+	if requestURL == nil {
+		return "unknown"
+	}
+
+	path := strings.Trim(strings.TrimPrefix(requestURL.Path, "/v2"), "/")
+	if path == "" {
+		return "root"
+	}
+	return strings.ReplaceAll(path, "/", "_")
+}
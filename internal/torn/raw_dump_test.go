@@ -0,0 +1,78 @@
+package torn
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"torn_rw_stats/internal/app"
+)
+
+func TestRawResponseDump(t *testing.T) {
+	const apiKey = "s3cretTornKey123"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Echo the key back, as Torn does in some links, to check it is redacted
+		_, _ = w.Write([]byte(`{"wars":{"ranked":null},"_metadata":{"next":"/v2/faction/wars?key=` + r.URL.Query().Get("key") + `"}}`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	client := NewClientWithConfig(&app.Config{TornAPIKey: apiKey, RawDumpDir: dir})
+	now := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+	client.dumper.now = func() time.Time { return now }
+
+	fetch := func() {
+		t.Helper()
+		resp, err := client.makeAPIRequest(context.Background(), server.URL+"/v2/faction/wars?key="+apiKey)
+		if err != nil {
+			t.Fatalf("makeAPIRequest() returned unexpected error: %v", err)
+		}
+		if _, err := client.handleAPIResponse(resp); err != nil {
+			t.Fatalf("handleAPIResponse() returned unexpected error: %v", err)
+		}
+	}
+
+	fetch()
+	// A second response within the interval is not dumped
+	now = now.Add(10 * time.Second)
+	fetch()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("Failed to read dump directory: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 dump file within the interval, got %d", len(entries))
+	}
+	if name := entries[0].Name(); name != "20260102T120000.000Z_faction_wars.json" {
+		t.Errorf("Unexpected dump file name %q", name)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, entries[0].Name()))
+	if err != nil {
+		t.Fatalf("Failed to read dump file: %v", err)
+	}
+	if strings.Contains(string(data), apiKey) {
+		t.Errorf("Expected the API key to be redacted, got %s", data)
+	}
+	if !strings.Contains(string(data), `"ranked":null`) || !strings.Contains(string(data), "key="+rawDumpRedaction) {
+		t.Errorf("Expected the raw response with the key redacted, got %s", data)
+	}
+
+	// Once the interval has passed the endpoint is dumped again
+	now = now.Add(DefaultRawDumpInterval)
+	fetch()
+	entries, err = os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("Failed to read dump directory: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Errorf("Expected a second dump file after the interval, got %d", len(entries))
+	}
+}
@@ -11,6 +11,8 @@ import (
 	"torn_rw_stats/internal/app"
 	"torn_rw_stats/internal/application/services"
 	bqclient "torn_rw_stats/internal/bigquery"
+	"torn_rw_stats/internal/health"
+	"torn_rw_stats/internal/metrics"
 	"torn_rw_stats/internal/processing"
 	"torn_rw_stats/internal/sheets"
 	"torn_rw_stats/internal/torn"
@@ -20,21 +22,57 @@ import (
 
 const (
 	// Default timing constants
-	DefaultUpdateInterval = 5 * time.Minute // Default interval between war updates
-	MinCheckDuration      = time.Minute     // Minimum time between checks
+	DefaultUpdateInterval = 5 * time.Minute  // Default interval between war updates
+	MinCheckDuration      = time.Minute      // Minimum time between checks
+	MaxBackoffInterval    = 30 * time.Minute // Ceiling for the interval while the API keeps failing
 )
 
-func main() {
-	app.SetupEnvironment()
+// backoffInterval doubles the base interval for each consecutive failure, capped at
+// MaxBackoffInterval. The base interval, raised to at least MinCheckDuration, is the
+// floor, so it is returned unchanged when there are no failures or when it already
+// exceeds the ceiling.
+func backoffInterval(base time.Duration, failures int) time.Duration {
+	base = max(base, MinCheckDuration)
+	next := base
+	for i := 0; i < failures && next < MaxBackoffInterval; i++ {
+		next *= 2
+	}
+	if next > MaxBackoffInterval {
+		next = max(base, MaxBackoffInterval)
+	}
+	return next
+}
 
+func main() {
 	// Parse command line flags
+	configFile := flag.String("config", "", "Read settings from this YAML or JSON file (environment variables take precedence)")
 	interval := flag.Duration("interval", DefaultUpdateInterval, "Interval between war updates (e.g., 5m, 10m)")
 	runOnce := flag.Bool("once", false, "Run once and exit (don't start scheduler)")
+	statusOnly := flag.Int("status-only", 0, "Only track status for this faction ID (no war processing)")
+	from := flag.String("from", "", "Fetch attacks for the current war from this RFC3339 time, then exit (requires -to)")
+	to := flag.String("to", "", "Fetch attacks for the current war up to this RFC3339 time, then exit (requires -from)")
+	validate := flag.Bool("validate", false, "Check the current/upcoming war's sheets and headers, then exit (non-zero on mismatch)")
+	selfTest := flag.Bool("selftest", false, "Check Torn API connectivity and key permissions, then exit (non-zero on failure)")
 	flag.Parse()
 
+	unknownConfigKeys, configFileErr := app.SetupEnvironment(*configFile)
+
+	if configFileErr != nil {
+		log.Fatal().Err(configFileErr).Msg("Failed to load config file")
+	}
+	for _, key := range unknownConfigKeys {
+		log.Warn().Str("key", key).Str("file", *configFile).Msg("Ignoring unknown config file key")
+	}
+
+	attackRange, err := app.ParseAttackTimeRange(*from, *to)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid attack time range")
+	}
+
 	log.Info().
 		Dur("interval", *interval).
 		Bool("run_once", *runOnce).
+		Int("status_only_faction", *statusOnly).
 		Msg("Starting Torn RW Stats application")
 
 	// Load configuration
@@ -59,11 +97,40 @@ func main() {
 	}()
 
 	// Initialize clients
-	tornClient := torn.NewClient(config.TornAPIKey)
+	tornClient := torn.NewClientWithConfig(config)
+
+	// The self-test only needs the Torn API, so it runs before Sheets credentials are loaded
+	if *selfTest {
+		report := services.RunSelfTest(ctx, tornClient, time.Now())
+		for _, check := range report.Checks {
+			event := log.Info()
+			if check.Err != nil {
+				event = log.Error().Err(check.Err)
+			}
+			event.Str("check", check.Name).Str("status", check.Status()).Msg("Self-test check")
+		}
+		if report.FactionID != 0 {
+			log.Info().
+				Int("faction_id", report.FactionID).
+				Str("faction_name", report.FactionName).
+				Msg("Detected faction for API key")
+		}
+		if !report.Passed() {
+			log.Fatal().Msg("Self-test failed")
+		}
+		log.Info().Msg("Self-test passed")
+		return
+	}
 	sheetsClient, err := sheets.NewClient(ctx, config.CredentialsFile)
 	if err != nil {
 		log.Fatal().Err(err).Msg("Failed to create sheets client")
 	}
+	sheetsClient.SetRespectDecimalPlaces(config.RespectDecimalPlaces)
+	sheetsClient.SetDisplayLocation(app.DisplayLocation(config.DisplayTimezone))
+	sheetsClient.SetSheetWriteBatchSize(config.SheetWriteBatchSize)
+	if err := sheetsClient.SetRecordsColumnOrder(config.RecordsColumnOrder); err != nil {
+		log.Fatal().Err(err).Msg("Invalid RECORDS_COLUMN_ORDER")
+	}
 
 	// Optionally initialize BigQuery client (disabled if BIGQUERY_PROJECT_ID is unset)
 	var bqClient processing.BigQueryClientInterface
@@ -86,17 +153,73 @@ func main() {
 	// Initialize optimized war processor with state-based optimization
 	warProcessor := services.NewOptimizedProcessor(tornClient, sheetsClient, config, bqClient)
 
+	// Validation checks the war's sheet structure and exits without starting the scheduler
+	if *validate {
+		mismatches, err := warProcessor.ValidateSheets(ctx)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to validate war sheets")
+		}
+		for _, mismatch := range mismatches {
+			log.Error().Str("mismatch", mismatch).Msg("Sheet structure mismatch")
+		}
+		if len(mismatches) > 0 {
+			log.Fatal().Int("mismatches", len(mismatches)).Msg("War sheet validation failed")
+		}
+		log.Info().Msg("War sheets are well-formed")
+		return
+	}
+
+	// A time range runs a one-shot attack fetch and exits without starting the scheduler
+	if attackRange != nil {
+		if err := warProcessor.ProcessAttacksInRange(ctx, attackRange.From, attackRange.To); err != nil {
+			log.Fatal().Err(err).Msg("Failed to fetch attacks for time range")
+		}
+		log.Info().
+			Int64("api_calls", tornClient.GetAPICallCount()).
+			Msg("Completed one-shot attack fetch for time range")
+		return
+	}
+
+	// Health endpoint reports the last completed war processing cycle
+	if config.HealthPort > 0 {
+		health.Start(ctx, config.HealthPort, warProcessor)
+	}
+
+	// Metrics endpoint exposes API usage and cycle stats for Prometheus
+	if config.MetricsPort > 0 {
+		metrics.Start(ctx, config.MetricsPort, warProcessor)
+	}
+
 	// Define the main processing function that returns next check time
+	consecutiveFailures := 0
 	processWars := func() time.Duration {
 		log.Debug().Msg("Starting war processing cycle")
 
+		// Torn's API errors during scheduled maintenance, so wait it out instead
+		if windowEnd, ok := app.ActiveMaintenanceWindow(config.MaintenanceWindows, time.Now()); ok {
+			untilEnd := max(time.Until(windowEnd), time.Second)
+			log.Info().
+				Time("window_end", windowEnd).
+				Dur("next_check_in", untilEnd).
+				Msg("Skipping during maintenance window")
+			return untilEnd
+		}
+
 		// Reset API call counter at the start of each cycle
 		tornClient.ResetAPICallCount()
 
 		if err := warProcessor.ProcessActiveWars(ctx); err != nil {
-			log.Error().Err(err).Msg("Failed to process active wars")
-			return *interval // Use CLI interval as fallback on error
+			// Back off while the API stays unreachable instead of retrying at the normal interval
+			consecutiveFailures++
+			backoff := backoffInterval(*interval, consecutiveFailures)
+			log.Error().
+				Err(err).
+				Int("consecutive_failures", consecutiveFailures).
+				Dur("next_check_in", backoff).
+				Msg("Failed to process active wars")
+			return backoff
 		}
+		consecutiveFailures = 0
 
 		apiCalls := tornClient.GetAPICallCount()
 
@@ -120,9 +243,45 @@ func main() {
 		return nextCheckDuration
 	}
 
+	// Status-only mode tracks a single faction on the fixed interval, skipping wars entirely
+	process := processWars
+	if *statusOnly > 0 {
+		statusProcessor := services.NewStatusOnlyProcessor(tornClient, sheetsClient, config, bqClient, *statusOnly)
+		statusInterval := max(*interval, MinCheckDuration)
+
+		process = func() time.Duration {
+			log.Debug().Msg("Starting status-only processing cycle")
+
+			tornClient.ResetAPICallCount()
+
+			if err := statusProcessor.ProcessStatus(ctx); err != nil {
+				log.Error().Err(err).Int("faction_id", *statusOnly).Msg("Failed to process faction status")
+			}
+
+			log.Info().
+				Int64("api_calls", tornClient.GetAPICallCount()).
+				Dur("next_check_in", statusInterval).
+				Msg("Completed status-only processing cycle")
+
+			return statusInterval
+		}
+	}
+
+	// Faction watch polls rival factions on its own cached interval after each cycle
+	if len(config.WatchFactionIDs) > 0 {
+		watchService := services.NewFactionWatchService(tornClient, sheetsClient, config)
+		runCycle := process
+
+		process = func() time.Duration {
+			nextInterval := runCycle()
+			watchService.CheckWatchedFactions(ctx)
+			return nextInterval
+		}
+	}
+
 	// Run initial processing
-	log.Info().Msg("Running initial war processing")
-	nextInterval := processWars()
+	log.Info().Msg("Running initial processing")
+	nextInterval := process()
 
 	// Exit if run-once flag is set
 	if *runOnce {
@@ -142,7 +301,7 @@ func main() {
 	for {
 		select {
 		case <-ticker.C:
-			nextInterval = processWars()
+			nextInterval = process()
 			ticker.Reset(nextInterval)
 		case <-ctx.Done():
 			log.Info().Msg("Shutting down war processor")
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoffInterval(t *testing.T) {
+	tests := []struct {
+		name     string
+		base     time.Duration
+		failures int
+		expected time.Duration
+	}{
+		{name: "no failures uses the base interval", base: 5 * time.Minute, failures: 0, expected: 5 * time.Minute},
+		{name: "one failure doubles", base: 5 * time.Minute, failures: 1, expected: 10 * time.Minute},
+		{name: "three failures double three times", base: 2 * time.Minute, failures: 3, expected: 16 * time.Minute},
+		{name: "three failures hit the ceiling", base: 5 * time.Minute, failures: 3, expected: MaxBackoffInterval},
+		{name: "ten failures stay at the ceiling", base: 5 * time.Minute, failures: 10, expected: MaxBackoffInterval},
+		{name: "base above the ceiling is the floor", base: time.Hour, failures: 10, expected: time.Hour},
+		{name: "zero base uses the minimum check duration", base: 0, failures: 0, expected: MinCheckDuration},
+		{name: "zero base doubles from the minimum", base: 0, failures: 1, expected: 2 * MinCheckDuration},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := backoffInterval(tt.base, tt.failures); got != tt.expected {
+				t.Errorf("backoffInterval(%v, %d) = %v, want %v", tt.base, tt.failures, got, tt.expected)
+			}
+		})
+	}
+}